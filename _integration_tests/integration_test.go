@@ -22,6 +22,24 @@ func TestBootstrapReadme(t *testing.T) {
 	}
 }
 
+func TestBootstrapDebugListener(t *testing.T) {
+	defer os.RemoveAll("./readme/main.go")
+	defer os.RemoveAll("./readme/tool")
+	if err := goagen("./readme", "bootstrap", "-d", "github.com/goadesign/goa/_integration_tests/readme/design", "--debug-listener"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := gobuild("./readme"); err != nil {
+		t.Error(err.Error())
+	}
+	b, err := ioutil.ReadFile("./readme/main.go")
+	if err != nil {
+		t.Fatal("failed to load main.go")
+	}
+	if !strings.Contains(string(b), `flag.String("debug-addr", "",`) {
+		t.Errorf("debug listener not generated. Generated main:\n%s", string(b))
+	}
+}
+
 func TestDefaultMedia(t *testing.T) {
 	defer os.RemoveAll("./media/main.go")
 	defer os.RemoveAll("./media/tool")
@@ -48,6 +66,16 @@ type CreateGreetingPayload struct {
 	}
 }
 
+func TestSecurityResponses(t *testing.T) {
+	defer os.RemoveAll("./security/app")
+	if err := goagen("./security", "app", "-d", "github.com/goadesign/goa/_integration_tests/security/design"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := gotest("./security"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestCellar(t *testing.T) {
 	if err := os.MkdirAll("./goa-cellar", 0755); err != nil {
 		t.Error(err.Error())
@@ -92,3 +120,13 @@ func gobuild(dir string) error {
 	}
 	return nil
 }
+
+func gotest(dir string) error {
+	cmd := exec.Command("go", "test", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s\n%s", err.Error(), out)
+	}
+	return nil
+}
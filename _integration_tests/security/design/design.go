@@ -0,0 +1,38 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("secure", func() {
+	Title("The secure API")
+	Description("An API whose security scheme defines its unauthorized response")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var AuthErrorMedia = MediaType("application/vnd.auth-error+json", func() {
+	Description("AuthError describes why a request failed to authenticate")
+	Attributes(func() {
+		Attribute("reason", String, "Failure reason")
+		Required("reason")
+	})
+	View("default", func() {
+		Attribute("reason")
+	})
+})
+
+var JWT = JWTSecurity("jwt", func() {
+	Header("Authorization")
+	Response(Unauthorized, AuthErrorMedia)
+})
+
+var _ = Resource("secret", func() {
+	Security(JWT)
+	Action("show", func() {
+		Routing(GET("/secret"))
+		Description("show returns no content to authenticated requests")
+		Response(NoContent)
+	})
+})
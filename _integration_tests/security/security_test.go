@@ -0,0 +1,47 @@
+package security_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/security/app"
+)
+
+type secretController struct {
+	*goa.Controller
+}
+
+func (c *secretController) Show(ctx *app.ShowSecretContext) error {
+	return ctx.NoContent()
+}
+
+func TestMissingToken(t *testing.T) {
+	service := goa.New("secure")
+	auth := func(ctx context.Context, token string, scheme *goa.JWTSecurity) (context.Context, error) {
+		return ctx, nil
+	}
+	app.UseJWTMiddleware(service, app.NewJWTMiddleware(auth))
+	app.JWTUnauthorizedBody = func(err error) *app.AuthError {
+		return &app.AuthError{Reason: err.Error()}
+	}
+	app.MountSecretController(service, &secretController{service.NewController("SecretController")})
+
+	rw := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/secret", nil)
+	service.Mux.ServeHTTP(rw, req)
+
+	if rw.Code != 401 {
+		t.Fatalf("got status %d, expected 401", rw.Code)
+	}
+	var body app.AuthError
+	if err := json.Unmarshal(rw.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid response body %q: %s", rw.Body.String(), err)
+	}
+	if body.Reason == "" {
+		t.Errorf("got empty reason, expected the middleware error")
+	}
+}
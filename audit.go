@@ -0,0 +1,113 @@
+package goa
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+type (
+	// AuditRecord describes a request made to an audited action.
+	AuditRecord struct {
+		// Time is the time the request was received.
+		Time time.Time
+		// Principal identifies the authenticated client, empty if the request was not
+		// authenticated. Auth functions set it with WithPrincipal.
+		Principal string
+		// Controller is the name of the controller handling the request.
+		Controller string
+		// Action is the name of the action handling the request.
+		Action string
+		// Identifiers contains the values of the request attributes that identify the
+		// resources being accessed indexed by attribute name.
+		Identifiers map[string]interface{}
+		// Status is the response HTTP status code.
+		Status int
+		// Error is the message of the error returned by the action if any.
+		Error string
+	}
+
+	// AuditSink is the interface implemented by the audit record stores. Record is called
+	// once each audited request has been handled.
+	AuditSink interface {
+		// Record stores the audit record.
+		Record(ctx context.Context, r *AuditRecord) error
+	}
+)
+
+// UseAuditSink sets the sink used to store the records of the audited actions of the service.
+// It must be called before the controllers are created.
+func UseAuditSink(service *Service, sink AuditSink) {
+	service.Context = context.WithValue(service.Context, auditSinkKey, sink)
+}
+
+// WithPrincipal builds a context containing the authenticated principal and records it in the
+// audit record of the request if any. Auth functions call it once they have authenticated the
+// request.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	if r, ok := ctx.Value(auditRecordKey).(*AuditRecord); ok {
+		r.Principal = principal
+	}
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// ContextPrincipal extracts the authenticated principal from the given context, empty if there
+// is none.
+func ContextPrincipal(ctx context.Context) string {
+	if p := ctx.Value(principalKey); p != nil {
+		return p.(string)
+	}
+	return ""
+}
+
+// AuditIdentifier records the value of a request attribute identifying the resource being
+// accessed in the audit record of the request if any. Pointer values are dereferenced.
+func AuditIdentifier(ctx context.Context, name string, value interface{}) {
+	r, ok := ctx.Value(auditRecordKey).(*AuditRecord)
+	if !ok {
+		return
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		value = v.Elem().Interface()
+	}
+	if r.Identifiers == nil {
+		r.Identifiers = make(map[string]interface{})
+	}
+	r.Identifiers[name] = value
+}
+
+// AuditHandler returns a handler that records an audit record for each request handled by h in
+// the sink set with UseAuditSink. The record outcome is the response status and the error
+// returned by h if any. Errors returned by the sink are logged.
+func AuditHandler(h Handler) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		sink, ok := ctx.Value(auditSinkKey).(AuditSink)
+		if !ok {
+			return h(ctx, rw, req)
+		}
+		r := &AuditRecord{
+			Time:       time.Now(),
+			Controller: ContextController(ctx),
+			Action:     ContextAction(ctx),
+		}
+		err := h(context.WithValue(ctx, auditRecordKey, r), rw, req)
+		if resp := ContextResponse(ctx); resp != nil {
+			r.Status = resp.Status
+		}
+		if err != nil {
+			r.Status = http.StatusInternalServerError
+			if serr, ok := err.(ServiceError); ok {
+				r.Status = serr.ResponseStatus()
+			}
+			r.Error = err.Error()
+		}
+		if serr := sink.Record(ctx, r); serr != nil {
+			LogError(ctx, "audit", "err", serr)
+		}
+		return err
+	}
+}
@@ -0,0 +1,69 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type recordingSink struct {
+	records []*goa.AuditRecord
+}
+
+func (s *recordingSink) Record(ctx context.Context, r *goa.AuditRecord) error {
+	s.records = append(s.records, r)
+	return nil
+}
+
+var _ = Describe("AuditHandler", func() {
+	var service *goa.Service
+	var sink *recordingSink
+	var h goa.Handler
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		sink = &recordingSink{}
+		goa.UseAuditSink(service, sink)
+		h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			ctx = goa.WithPrincipal(ctx, "user")
+			goa.AuditIdentifier(ctx, "id", 42)
+			rw.WriteHeader(204)
+			return nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest("DELETE", "/42", nil)
+		rw := httptest.NewRecorder()
+		ctx := goa.NewContext(goa.WithAction(service.Context, "delete"), rw, req, nil)
+		goa.AuditHandler(h)(ctx, goa.ContextResponse(ctx), req)
+	})
+
+	It("records the request", func() {
+		Ω(sink.records).Should(HaveLen(1))
+		r := sink.records[0]
+		Ω(r.Principal).Should(Equal("user"))
+		Ω(r.Action).Should(Equal("delete"))
+		Ω(r.Identifiers).Should(Equal(map[string]interface{}{"id": 42}))
+		Ω(r.Status).Should(Equal(204))
+		Ω(r.Error).Should(BeEmpty())
+	})
+
+	Context("with a handler returning an error", func() {
+		BeforeEach(func() {
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return goa.ErrUnauthorized("invalid token")
+			}
+		})
+
+		It("records the error", func() {
+			Ω(sink.records).Should(HaveLen(1))
+			Ω(sink.records[0].Status).Should(Equal(401))
+			Ω(sink.records[0].Error).Should(ContainSubstring("invalid token"))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package goa
+
+import (
+	"context"
+	"sync"
+)
+
+// Broadcaster fans out the values published for server events to all the subscribers of the
+// events. It provides the plumbing used by the actions defined with the Subscription DSL: the
+// generated action contexts subscribe to the broadcaster and stream the values they receive using
+// the action transport (e.g. WebSocket). Broadcaster is safe for concurrent use.
+type Broadcaster struct {
+	buffer int
+	mu     sync.Mutex
+	subs   map[string]map[chan interface{}]struct{}
+}
+
+// NewBroadcaster creates a broadcaster whose subscription channels buffer up to buffer values.
+// Values published while a subscriber channel buffer is full are dropped for that subscriber so
+// that slow subscribers do not block publishers.
+func NewBroadcaster(buffer int) *Broadcaster {
+	return &Broadcaster{buffer: buffer, subs: make(map[string]map[chan interface{}]struct{})}
+}
+
+// Subscribe returns a channel that receives the values published for the given event and a
+// function that cancels the subscription and closes the channel.
+func (b *Broadcaster) Subscribe(event string) (<-chan interface{}, func()) {
+	ch := make(chan interface{}, b.buffer)
+	b.mu.Lock()
+	subs, ok := b.subs[event]
+	if !ok {
+		subs = make(map[chan interface{}]struct{})
+		b.subs[event] = subs
+	}
+	subs[ch] = struct{}{}
+	b.mu.Unlock()
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[event], ch)
+			if len(b.subs[event]) == 0 {
+				delete(b.subs, event)
+			}
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// SubscribeContext is similar to Subscribe but cancels the subscription once ctx is done.
+func (b *Broadcaster) SubscribeContext(ctx context.Context, event string) <-chan interface{} {
+	ch, cancel := b.Subscribe(event)
+	go func() {
+		<-ctx.Done()
+		cancel()
+	}()
+	return ch
+}
+
+// Publish sends v to all the subscribers of the given event.
+func (b *Broadcaster) Publish(event string, v interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[event] {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of subscribers of the given event.
+func (b *Broadcaster) Subscribers(event string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[event])
+}
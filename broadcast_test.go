@@ -0,0 +1,63 @@
+package goa_test
+
+import (
+	"context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Broadcaster", func() {
+	var b *goa.Broadcaster
+
+	BeforeEach(func() {
+		b = goa.NewBroadcaster(1)
+	})
+
+	It("fans out the published values", func() {
+		ch1, cancel1 := b.Subscribe("event")
+		defer cancel1()
+		ch2, cancel2 := b.Subscribe("event")
+		defer cancel2()
+		other, cancel3 := b.Subscribe("other")
+		defer cancel3()
+
+		b.Publish("event", 42)
+
+		Ω(<-ch1).Should(Equal(42))
+		Ω(<-ch2).Should(Equal(42))
+		Ω(other).ShouldNot(Receive())
+	})
+
+	It("drops values for full subscribers", func() {
+		ch, cancel := b.Subscribe("event")
+		defer cancel()
+
+		b.Publish("event", 1)
+		b.Publish("event", 2)
+
+		Ω(<-ch).Should(Equal(1))
+		Ω(ch).ShouldNot(Receive())
+	})
+
+	It("closes the channel on cancel", func() {
+		ch, cancel := b.Subscribe("event")
+		cancel()
+		cancel()
+
+		Ω(ch).Should(BeClosed())
+		Ω(b.Subscribers("event")).Should(Equal(0))
+	})
+
+	It("cancels context subscriptions when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := b.SubscribeContext(ctx, "event")
+		Ω(b.Subscribers("event")).Should(Equal(1))
+
+		cancel()
+
+		Eventually(ch).Should(BeClosed())
+		Ω(b.Subscribers("event")).Should(Equal(0))
+	})
+})
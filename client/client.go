@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/http/httputil"
+	"sync"
 	"time"
 
 	"context"
@@ -36,15 +40,74 @@ type (
 	}
 )
 
+// DefaultMaxIdleConnsPerHost is the maximum number of idle connections kept per host by the
+// transports created with NewTransport. The value is higher than the net/http default of 2 so that
+// clients making concurrent requests to the same service reuse their connections.
+const DefaultMaxIdleConnsPerHost = 100
+
+var (
+	// sharedTransport is the transport used by the clients that do not specify one.
+	sharedTransport http.RoundTripper
+	// sharedTransportMu protects sharedTransport.
+	sharedTransportMu sync.Mutex
+)
+
 // New creates a new API client that wraps c.
-// If c is nil, the returned client wraps http.DefaultClient.
+// If c is nil, the returned client wraps an http.Client that uses the shared transport, see
+// SharedTransport.
 func New(c Doer) *Client {
 	if c == nil {
-		c = HTTPClientDoer(http.DefaultClient)
+		c = HTTPClientDoer(NewHTTPClient())
 	}
 	return &Client{Doer: c}
 }
 
+// NewHTTPClient returns an http.Client that uses the shared transport, see SharedTransport.
+func NewHTTPClient() *http.Client {
+	return &http.Client{Transport: SharedTransport()}
+}
+
+// NewTransport returns a transport tuned for making requests to services: HTTP/2 is enabled for
+// TLS connections and up to DefaultMaxIdleConnsPerHost idle connections are kept per host. The
+// other settings are the same as http.DefaultTransport.
+func NewTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          DefaultMaxIdleConnsPerHost,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		ForceAttemptHTTP2:     true,
+	}
+}
+
+// SharedTransport returns the transport used by the clients created with a nil Doer or with
+// NewHTTPClient. Sharing a single transport makes all the service clients of a process share a
+// single connection pool. The transport is created with NewTransport unless SetSharedTransport is
+// called first.
+func SharedTransport() http.RoundTripper {
+	sharedTransportMu.Lock()
+	defer sharedTransportMu.Unlock()
+	if sharedTransport == nil {
+		sharedTransport = NewTransport()
+	}
+	return sharedTransport
+}
+
+// SetSharedTransport sets the transport returned by SharedTransport. It only affects the clients
+// created after the call so it should be called during initialization. Use it to make all the
+// generated service clients of a process share a transport with custom settings.
+func SetSharedTransport(t http.RoundTripper) {
+	sharedTransportMu.Lock()
+	defer sharedTransportMu.Unlock()
+	sharedTransport = t
+}
+
 // HTTPClientDoer turns a stdlib http.Client into a Doer. Use it to enable to call New() with an http.Client.
 func HTTPClientDoer(hc *http.Client) Doer {
 	return doFunc(func(_ context.Context, req *http.Request) (*http.Response, error) {
@@ -52,6 +115,18 @@ func HTTPClientDoer(hc *http.Client) Doer {
 	})
 }
 
+// HandlerDoer turns a http.Handler into a Doer that serves requests in memory without going through
+// the network. Use it together with a goa service mux to test clients against a service instance.
+func HandlerDoer(h http.Handler) Doer {
+	return doFunc(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req.WithContext(ctx))
+		resp := rec.Result()
+		resp.Request = req
+		return resp, nil
+	})
+}
+
 // doFunc is the type definition of the Doer.Do method. It implements Doer.
 type doFunc func(context.Context, *http.Request) (*http.Response, error)
 
@@ -89,13 +164,19 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 	return resp, err
 }
 
-// Dump request if needed.
+// Dump request if needed. The request body is dumped as JSON with the sensitive values hidden if
+// the payload set with WithDumpPayload contains any.
 func (c *Client) dumpRequest(ctx context.Context, req *http.Request) {
 	reqBody, err := dumpReqBody(req)
 	if err != nil {
 		goa.LogError(ctx, "Failed to load request body for dump", "err", err.Error())
 	}
 	goa.LogInfo(ctx, "request headers", headersToSlice(req.Header)...)
+	if payload, ok := goa.Redact(ctx.Value(dumpPayloadKey)); ok {
+		if js, err := json.Marshal(payload); err == nil {
+			reqBody = js
+		}
+	}
 	if reqBody != nil {
 		goa.LogInfo(ctx, "request", "body", string(reqBody))
 	}
@@ -224,6 +305,9 @@ type clientKey int
 // ReqIDKey is the context key used to store the request ID value.
 const reqIDKey clientKey = 1
 
+// dumpPayloadKey is the context key used to store the request payload value.
+const dumpPayloadKey clientKey = 2
+
 // ContextRequestID extracts the Request ID from the context.
 func ContextRequestID(ctx context.Context) string {
 	var reqID string
@@ -249,3 +333,9 @@ func ContextWithRequestID(ctx context.Context) (context.Context, string) {
 func SetContextRequestID(ctx context.Context, reqID string) context.Context {
 	return context.WithValue(ctx, reqIDKey, reqID)
 }
+
+// WithDumpPayload returns a context holding the request payload. The client uses it to hide the
+// sensitive payload values when dumping the request.
+func WithDumpPayload(ctx context.Context, payload interface{}) context.Context {
+	return context.WithValue(ctx, dumpPayloadKey, payload)
+}
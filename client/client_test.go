@@ -2,6 +2,8 @@ package client_test
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/goadesign/goa/client"
 
@@ -45,4 +47,47 @@ var _ = Describe("client", func() {
 			})
 		})
 	})
+
+	Context("NewTransport", func() {
+		It("enables HTTP/2 and keeps idle connections", func() {
+			t := client.NewTransport()
+			Expect(t.MaxIdleConnsPerHost).To(Equal(client.DefaultMaxIdleConnsPerHost))
+			Expect(t.ForceAttemptHTTP2).To(BeTrue())
+		})
+	})
+
+	Context("SharedTransport", func() {
+		AfterEach(func() {
+			client.SetSharedTransport(nil)
+		})
+
+		It("returns the same transport", func() {
+			Expect(client.SharedTransport()).To(BeIdenticalTo(client.SharedTransport()))
+			Expect(client.NewHTTPClient().Transport).To(BeIdenticalTo(client.SharedTransport()))
+		})
+
+		It("can be overridden", func() {
+			t := &http.Transport{}
+			client.SetSharedTransport(t)
+			Expect(client.SharedTransport()).To(BeIdenticalTo(t))
+			Expect(client.NewHTTPClient().Transport).To(BeIdenticalTo(t))
+		})
+	})
+
+	Context("HandlerDoer", func() {
+		It("serves requests with the handler", func() {
+			h := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusTeapot)
+				rw.Write([]byte(req.URL.Path))
+			})
+			req, err := http.NewRequest("GET", "http://example.com/foo", nil)
+			Expect(err).ToNot(HaveOccurred())
+			resp, err := client.HandlerDoer(h).Do(context.Background(), req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal("/foo"))
+		})
+	})
 })
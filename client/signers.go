@@ -41,6 +41,14 @@ type (
 		TokenSource TokenSource
 	}
 
+	// CookieSigner implements session cookie auth.
+	CookieSigner struct {
+		// Name is the name of the session cookie.
+		Name string
+		// Value is the session cookie value, typically returned by the login action.
+		Value string
+	}
+
 	// OAuth2Signer adds a authorization header to the request using the given OAuth2 token
 	// source to produce the header value.
 	OAuth2Signer struct {
@@ -110,6 +118,14 @@ func (s *APIKeySigner) Sign(req *http.Request) error {
 	return nil
 }
 
+// Sign adds the session cookie.
+func (s *CookieSigner) Sign(req *http.Request) error {
+	if s.Value != "" {
+		req.AddCookie(&http.Cookie{Name: s.Name, Value: s.Value})
+	}
+	return nil
+}
+
 // Sign adds the JWT auth header.
 func (s *JWTSigner) Sign(req *http.Request) error {
 	return signFromSource(s.TokenSource, req)
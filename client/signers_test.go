@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"net/http"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("signers", func() {
+	var req *http.Request
+
+	BeforeEach(func() {
+		req, _ = http.NewRequest("GET", "http://example.com/foo", nil)
+	})
+
+	Context("APIKeySigner", func() {
+		It("sets the key header", func() {
+			signer := &client.APIKeySigner{KeyName: "X-Key", KeyValue: "secret", Format: "%s"}
+			Expect(signer.Sign(req)).To(Succeed())
+			Expect(req.Header.Get("X-Key")).To(Equal("secret"))
+		})
+
+		It("sets the key query string", func() {
+			signer := &client.APIKeySigner{SignQuery: true, KeyName: "key", KeyValue: "secret", Format: "%s"}
+			Expect(signer.Sign(req)).To(Succeed())
+			Expect(req.URL.Query().Get("key")).To(Equal("secret"))
+		})
+	})
+
+	Context("JWTSigner", func() {
+		It("sets the authorization header", func() {
+			source := &client.StaticTokenSource{StaticToken: &client.StaticToken{Value: "token"}}
+			signer := &client.JWTSigner{TokenSource: source}
+			Expect(signer.Sign(req)).To(Succeed())
+			Expect(req.Header.Get("Authorization")).To(Equal("Bearer token"))
+		})
+	})
+
+	Context("CookieSigner", func() {
+		It("sets the session cookie", func() {
+			signer := &client.CookieSigner{Name: "sid", Value: "session"}
+			Expect(signer.Sign(req)).To(Succeed())
+			cookie, err := req.Cookie("sid")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cookie.Value).To(Equal("session"))
+		})
+
+		It("does not set a cookie with no session", func() {
+			signer := &client.CookieSigner{Name: "sid"}
+			Expect(signer.Sign(req)).To(Succeed())
+			Expect(req.Header.Get("Cookie")).To(BeEmpty())
+		})
+	})
+})
@@ -1,9 +1,11 @@
 package goa
 
 import (
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 
 	"context"
 )
@@ -19,6 +21,11 @@ const (
 	logContextKey
 	errKey
 	securityScopesKey
+	grantedScopesKey
+	sessionKey
+	auditSinkKey
+	auditRecordKey
+	principalKey
 )
 
 type (
@@ -45,6 +52,8 @@ type (
 		Status int
 		// Length is the response body length.
 		Length int
+		// Body is the value encoded in the response body by Service.Send if any.
+		Body interface{}
 	}
 
 	// key is the type used to store internal values in the context.
@@ -168,3 +177,38 @@ func (r *ResponseData) Write(b []byte) (int, error) {
 	r.Length += len(b)
 	return r.ResponseWriter.Write(b)
 }
+
+// ReadFrom copies the data read from src to the underlying writer and records the amount of data
+// written. It lets io.Copy and http.ServeContent use the underlying writer ReadFrom method, which
+// sends files with sendfile when the connection supports it, and otherwise copies the data using
+// pooled buffers.
+func (r *ResponseData) ReadFrom(src io.Reader) (int64, error) {
+	if !r.Written() {
+		r.WriteHeader(http.StatusOK)
+	}
+	var n int64
+	var err error
+	if rf, ok := r.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = rf.ReadFrom(src)
+	} else {
+		buf := copyBufferPool.Get().(*[]byte)
+		n, err = io.CopyBuffer(writerOnly{r.ResponseWriter}, src, *buf)
+		copyBufferPool.Put(buf)
+	}
+	r.Length += int(n)
+	return n, err
+}
+
+// copyBufferPool holds the buffers used by ResponseData.ReadFrom.
+var copyBufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 32<<10)
+		return &b
+	},
+}
+
+// writerOnly hides the ReadFrom method of the underlying writer so that io.CopyBuffer uses the
+// given buffer.
+type writerOnly struct {
+	io.Writer
+}
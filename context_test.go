@@ -1,6 +1,8 @@
 package goa_test
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -55,4 +57,24 @@ var _ = Describe("ResponseData", func() {
 			Ω(data.Status).Should(Equal(status))
 		})
 	})
+
+	Context("ReadFrom", func() {
+		content := bytes.Repeat([]byte("goa"), 20000)
+
+		It("copies the data and records the length", func() {
+			n, err := data.ReadFrom(bytes.NewReader(content))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(n).Should(Equal(int64(len(content))))
+			Ω(data.Status).Should(Equal(http.StatusOK))
+			Ω(data.Length).Should(Equal(len(content)))
+			Ω(rw.(*TestResponseWriter).Body).Should(Equal(content))
+		})
+
+		It("is used by io.Copy", func() {
+			_, err := io.Copy(data, io.LimitReader(bytes.NewReader(content), 3))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(data.Length).Should(Equal(3))
+			Ω(string(rw.(*TestResponseWriter).Body)).Should(Equal("goa"))
+		})
+	})
 })
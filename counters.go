@@ -0,0 +1,28 @@
+package goa
+
+import "expvar"
+
+// The failure counters are published with expvar under the "goa" variable. The services generated
+// with "goagen main --debug" serve them on the debug listener together with the pprof endpoints.
+var (
+	// DecodeFailures counts the request bodies that could not be decoded.
+	DecodeFailures = new(expvar.Int)
+	// ValidationFailures counts the requests rejected because their parameters, headers or
+	// payload do not satisfy the design validations.
+	ValidationFailures = new(expvar.Int)
+	// EncodeFailures counts the response bodies that could not be encoded.
+	EncodeFailures = new(expvar.Int)
+)
+
+func init() {
+	counters := expvar.NewMap("goa")
+	counters.Set("decode_failures", DecodeFailures)
+	counters.Set("validation_failures", ValidationFailures)
+	counters.Set("encode_failures", EncodeFailures)
+}
+
+// isValidationError returns true if err was produced by the generated validation code.
+func isValidationError(err error) bool {
+	e, ok := err.(*ErrorResponse)
+	return ok && e.Code == "invalid_request"
+}
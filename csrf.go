@@ -0,0 +1,57 @@
+package goa
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+)
+
+const (
+	// CSRFCookieName is the name of the cookie holding the CSRF token.
+	CSRFCookieName = "csrf_token"
+	// CSRFHeaderName is the name of the header that must echo the CSRF token.
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+// ErrInvalidCSRFToken is the error produced when a request to an action protected against cross
+// site request forgery does not include a valid CSRF token.
+var ErrInvalidCSRFToken = NewErrorClass("invalid_csrf_token", 403)
+
+// CSRFHandler returns a handler that protects h against cross site request forgery using the
+// double submit cookie pattern: the value of the CSRFHeaderName request header must match the value
+// of the CSRFCookieName cookie. Browsers only let scripts running on the origin that received the
+// cookie read it, so that other origins cannot forge the header. CSRFHandler returns an
+// ErrInvalidCSRFToken error if the header is missing or does not match the cookie.
+func CSRFHandler(h Handler) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		cookie, err := req.Cookie(CSRFCookieName)
+		if err != nil || cookie.Value == "" {
+			return ErrInvalidCSRFToken("missing CSRF cookie", "cookie", CSRFCookieName)
+		}
+		token := req.Header.Get(CSRFHeaderName)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) != 1 {
+			return ErrInvalidCSRFToken("CSRF token mismatch", "header", CSRFHeaderName)
+		}
+		return h(ctx, rw, req)
+	}
+}
+
+// SetCSRFCookie generates a random CSRF token, sets the CSRFCookieName cookie to it and returns
+// it. Call it from an action that is not protected (e.g. the action that renders a form or returns
+// the current user) so that browsers may send the token back in the CSRFHeaderName header.
+func SetCSRFCookie(rw http.ResponseWriter) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	http.SetCookie(rw, &http.Cookie{
+		Name:   CSRFCookieName,
+		Value:  token,
+		Path:   "/",
+		Secure: true,
+	})
+	return token, nil
+}
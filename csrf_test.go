@@ -0,0 +1,81 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CSRFHandler", func() {
+	var req *http.Request
+	var handled bool
+	var err error
+
+	BeforeEach(func() {
+		handled = false
+		req, _ = http.NewRequest("POST", "/", nil)
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			handled = true
+			return nil
+		}
+		err = goa.CSRFHandler(h)(context.Background(), httptest.NewRecorder(), req)
+	})
+
+	Context("with a matching token", func() {
+		BeforeEach(func() {
+			req.AddCookie(&http.Cookie{Name: goa.CSRFCookieName, Value: "token"})
+			req.Header.Set(goa.CSRFHeaderName, "token")
+		})
+
+		It("runs the handler", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(handled).Should(BeTrue())
+		})
+	})
+
+	Context("with a token mismatch", func() {
+		BeforeEach(func() {
+			req.AddCookie(&http.Cookie{Name: goa.CSRFCookieName, Value: "token"})
+			req.Header.Set(goa.CSRFHeaderName, "other")
+		})
+
+		It("returns a 403", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(403))
+			Ω(handled).Should(BeFalse())
+		})
+	})
+
+	Context("with no cookie", func() {
+		BeforeEach(func() {
+			req.Header.Set(goa.CSRFHeaderName, "token")
+		})
+
+		It("returns a 403", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(403))
+			Ω(handled).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("SetCSRFCookie", func() {
+	It("sets the CSRF cookie", func() {
+		rw := httptest.NewRecorder()
+		token, err := goa.SetCSRFCookie(rw)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(token).ShouldNot(BeEmpty())
+		resp := http.Response{Header: rw.Header()}
+		cookies := resp.Cookies()
+		Ω(cookies).Should(HaveLen(1))
+		Ω(cookies[0].Name).Should(Equal(goa.CSRFCookieName))
+		Ω(cookies[0].Value).Should(Equal(token))
+	})
+})
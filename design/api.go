@@ -185,6 +185,23 @@ var (
 		AttributeDefinition: &AttributeDefinition{Type: errorMediaType},
 		Name:                "default",
 	}
+
+	// HALLink is the built-in type of the link objects rendered by the media types whose
+	// links use HAL, see https://tools.ietf.org/html/draft-kelly-json-hal.
+	HALLink = &UserTypeDefinition{
+		AttributeDefinition: &AttributeDefinition{
+			Type: Object{
+				"href": &AttributeDefinition{
+					Type:        String,
+					Description: "URL of the linked resource.",
+					Example:     "/bottles/1",
+				},
+			},
+			Description: "HAL link object",
+			Validation:  &dslengine.ValidationDefinition{Required: []string{"href"}},
+		},
+		TypeName: "HALLink",
+	}
 )
 
 func init() {
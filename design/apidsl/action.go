@@ -2,6 +2,7 @@ package apidsl
 
 import (
 	"fmt"
+	"time"
 	"unicode"
 
 	"github.com/goadesign/goa/design"
@@ -489,6 +490,239 @@ func MultipartForm() {
 	}
 }
 
+// Timeout can be used in: Action
+//
+// Timeout sets the maximum duration allowed for the action to complete. The generated code sets a
+// deadline on the request context before calling the controller action so that the cancellation is
+// propagated to it. If the deadline is exceeded before the action completes the request fails with a
+// 504 Gateway Timeout error, even if the action ignores the context. Actions that hijack the
+// connection, such as WebSocket actions, or that flush or stream their response are only notified
+// through the context once they started writing. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		Timeout(5 * time.Second)
+//	})
+//
+func Timeout(d time.Duration) {
+	if a, ok := actionDefinition(); ok {
+		if d <= 0 {
+			dslengine.ReportError("timeout must be strictly positive, got %s", d)
+			return
+		}
+		a.Timeout = d
+	}
+}
+
+// Topic can be used in: Action
+//
+// Topic binds the action to a message bus topic (or subject). The code generated by "goagen
+// pubsub" subscribes to the topic and dispatches the messages it receives to the action, the
+// message data is decoded into the action payload. The optional second argument is the name of the
+// topic the action response body is published to. The messages carry no path so the routes of
+// actions bound to a topic cannot have path parameters. Example:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Payload(BottlePayload)
+//		Topic("bottles.create", "bottles.created")
+//	})
+//
+func Topic(topic string, result ...string) {
+	if a, ok := actionDefinition(); ok {
+		if topic == "" {
+			dslengine.ReportError("topic name cannot be empty")
+			return
+		}
+		if len(result) > 1 {
+			dslengine.ReportError("too many arguments given to Topic")
+			return
+		}
+		a.Topic = topic
+		if len(result) > 0 {
+			a.ResultTopic = result[0]
+		}
+	}
+}
+
+// CloudEvents can be used in: Action
+//
+// CloudEvents wraps the messages exchanged on the topics of the action in CloudEvents envelopes
+// (https://cloudevents.io). The first argument is the content mode: design.CloudEventsStructured
+// encodes the event attributes and the message data in a JSON envelope while
+// design.CloudEventsBinary writes the attributes in the message headers. The optional second
+// argument is the event type of the messages published to the action topic, it defaults to
+// "<API name>.<resource name>.<action name>". The type of the events published to the result topic
+// is the event type suffixed with ".result".
+//
+// The code generated by "goagen pubsub" sets the "id", "source", "type" and "time" attributes of the
+// published events and maps the attributes of the received events to the "Ce-Id", "Ce-Source",
+// "Ce-Type" and "Ce-Time" request headers of the action. The action must be bound to a topic.
+// Example:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Payload(BottlePayload)
+//		Topic("bottles.create", "bottles.created")
+//		CloudEvents(design.CloudEventsBinary, "com.example.bottle.create")
+//	})
+//
+func CloudEvents(mode string, eventType ...string) {
+	if a, ok := actionDefinition(); ok {
+		if mode != design.CloudEventsStructured && mode != design.CloudEventsBinary {
+			dslengine.ReportError("invalid CloudEvents content mode %#v, must be %#v or %#v", mode, design.CloudEventsStructured, design.CloudEventsBinary)
+			return
+		}
+		if len(eventType) > 1 {
+			dslengine.ReportError("too many arguments given to CloudEvents")
+			return
+		}
+		a.CloudEvents = mode
+		if len(eventType) > 0 {
+			a.EventType = eventType[0]
+		}
+	}
+}
+
+// Async can be used in: Action
+//
+// Async marks the action as an asynchronous job. The code generated by "goagen jobs" includes
+// functions that enqueue the action payload in a job queue and a worker that consumes the queue and
+// runs the action for each job, reporting the action result or error. Example:
+//
+//	Action("import", func() {
+//		Routing(POST("/import"))
+//		Payload(ImportPayload)
+//		Async()
+//	})
+//
+func Async() {
+	if a, ok := actionDefinition(); ok {
+		a.Async = true
+	}
+}
+
+// Subscription can be used in: Action
+//
+// Subscription makes the action stream the results published by the server for the given event.
+// The generated action context exposes a Subscribe method that returns a channel receiving the
+// values published to a goa.Broadcaster for the event until the request completes. Transports that
+// support streaming such as WebSocket actions range over the channel to push the values to the
+// client. Example:
+//
+//	Action("watch", func() {
+//		Routing(GET("/:id/watch"))
+//		Scheme("ws")
+//		Subscription("bottle.updated")
+//		Response(SwitchingProtocols)
+//	})
+//
+func Subscription(event string) {
+	if a, ok := actionDefinition(); ok {
+		if event == "" {
+			dslengine.ReportError("subscription event name cannot be empty")
+			return
+		}
+		a.Subscription = event
+	}
+}
+
+// Webhook can be used in: Action
+//
+// Webhook describes an outbound request the service makes to a URL registered by the API
+// consumer, for example to notify it that the work started by the action is done. The first
+// argument is the webhook name, the second the type of the request body: a user type, a media type
+// or the name of one. The optional DSL may set the webhook description and the URL, an OpenAPI
+// runtime expression that defaults to "{$request.body#/callback_url}".
+//
+// "goagen app" generates a Webhooks struct with one method per webhook that sends the typed payload
+// with goa.WebhookSender, which signs the requests and retries the failed deliveries. The OpenAPI
+// specification documents the webhooks as callbacks of the action operation. Example:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Payload(BottlePayload)
+//		Webhook("bottleCreated", Bottle, func() {
+//			Description("Sent once the bottle is stored in the cellar")
+//			URL("{$request.body#/notify_url}")
+//		})
+//	})
+//
+func Webhook(name string, payload interface{}, dsls ...func()) {
+	if len(dsls) > 1 {
+		dslengine.ReportError("too many arguments given to Webhook")
+		return
+	}
+	if a, ok := actionDefinition(); ok {
+		if name == "" {
+			dslengine.ReportError("webhook name cannot be empty")
+			return
+		}
+		w := &design.WebhookDefinition{Name: name, URL: design.DefaultWebhookURL, Parent: a}
+		switch actual := payload.(type) {
+		case *design.UserTypeDefinition:
+			w.Payload = actual
+		case *design.MediaTypeDefinition:
+			w.Payload = actual
+		case string:
+			if ut, ok := design.Design.Types[actual]; ok {
+				w.Payload = ut
+			} else if mt := design.Design.MediaTypeWithIdentifier(actual); mt != nil {
+				w.Payload = mt
+			} else {
+				dslengine.ReportError("unknown webhook payload type %s", actual)
+				return
+			}
+		default:
+			dslengine.ReportError("invalid Webhook payload argument, must be a type, a media type or the name of one")
+			return
+		}
+		if len(dsls) == 1 && !dslengine.Execute(dsls[0], w) {
+			return
+		}
+		a.Webhooks = append(a.Webhooks, w)
+	}
+}
+
+// CSRF can be used in: Action
+//
+// CSRF protects the action against cross site request forgery. Use it on state changing actions
+// called by browsers that authenticate using cookies. The generated code checks that the value of
+// the "X-CSRF-Token" request header matches the value of the "csrf_token" cookie (double submit
+// cookie pattern), the cookie can be set with goa.SetCSRFCookie. Example:
+//
+//	Action("update", func() {
+//		Routing(PUT("/:id"))
+//		Payload(UpdatePayload)
+//		CSRF()
+//	})
+//
+func CSRF() {
+	if a, ok := actionDefinition(); ok {
+		a.CSRF = true
+	}
+}
+
+// Audit can be used in: Action
+//
+// Audit records the requests made to the action in the audit sink set with goa.UseAuditSink. Each
+// audit record contains the authenticated principal (set by the auth functions with
+// goa.WithPrincipal), the controller and action names, the values of the given params or payload
+// attributes that identify the resources being accessed and the outcome of the request. Example:
+//
+//	Action("delete", func() {
+//		Routing(DELETE("/:accountID"))
+//		Security("jwt")
+//		Audit("accountID")
+//	})
+//
+func Audit(attributes ...string) {
+	if a, ok := actionDefinition(); ok {
+		a.Audit = true
+		a.AuditAttributes = append(a.AuditAttributes, attributes...)
+	}
+}
+
 // newAttribute creates a new attribute definition using the media type with the given identifier
 // as base type.
 func newAttribute(baseMT string) *design.AttributeDefinition {
@@ -2,6 +2,7 @@ package apidsl_test
 
 import (
 	"strconv"
+	"time"
 
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
@@ -94,6 +95,289 @@ var _ = Describe("Action", func() {
 		})
 	})
 
+	Context("with a timeout", func() {
+		var timeout time.Duration
+
+		BeforeEach(func() {
+			name = "foo"
+			timeout = 5 * time.Second
+			dsl = func() {
+				Routing(GET("/:id"))
+				Timeout(timeout)
+			}
+		})
+
+		It("sets the action timeout", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Timeout).Should(Equal(timeout))
+		})
+
+		Context("that is not positive", func() {
+			BeforeEach(func() {
+				timeout = 0
+			})
+
+			It("fails", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a topic", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(POST(""))
+				Topic("foo.create", "foo.created")
+			}
+		})
+
+		It("sets the action topics", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Topic).Should(Equal("foo.create"))
+			Ω(action.ResultTopic).Should(Equal("foo.created"))
+		})
+
+		Context("with path parameters", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(POST(""), PUT("/:id"))
+					Topic("foo.create")
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(action.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with CloudEvents", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(POST(""))
+				Topic("foo.create")
+				CloudEvents(CloudEventsBinary, "com.example.foo")
+			}
+		})
+
+		It("sets the action CloudEvents mode and type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.CloudEvents).Should(Equal(CloudEventsBinary))
+			Ω(action.EventType).Should(Equal("com.example.foo"))
+		})
+	})
+
+	Context("with an invalid CloudEvents mode", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(POST(""))
+				Topic("foo.create")
+				CloudEvents("foo")
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with async", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(POST(""))
+				Async()
+			}
+		})
+
+		It("marks the action as async", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Async).Should(BeTrue())
+		})
+
+		Context("with path parameters", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(POST("/:id"))
+					Async()
+				}
+			})
+
+			It("produces an invalid action", func() {
+				Ω(action.Validate()).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a subscription", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(GET("/watch"))
+				Subscription("updated")
+			}
+		})
+
+		It("sets the subscription event", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Subscription).Should(Equal("updated"))
+		})
+
+		Context("with an empty event name", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(GET("/watch"))
+					Subscription("")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with CSRF protection", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(PUT("/:id"))
+				CSRF()
+			}
+		})
+
+		It("marks the action as CSRF protected", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.CSRF).Should(BeTrue())
+		})
+	})
+
+	Context("with a webhook", func() {
+		var webhookDSL func()
+
+		BeforeEach(func() {
+			name = "foo"
+			webhookDSL = nil
+			notification := Type("Notification", func() {
+				Attribute("id", String)
+			})
+			dsl = func() {
+				Routing(POST(""))
+				if webhookDSL != nil {
+					Webhook("created", notification, webhookDSL)
+				} else {
+					Webhook("created", notification)
+				}
+			}
+		})
+
+		It("sets the webhook with the default URL", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Webhooks).Should(HaveLen(1))
+			w := action.Webhooks[0]
+			Ω(w.Name).Should(Equal("created"))
+			Ω(w.URL).Should(Equal(DefaultWebhookURL))
+			Ω(w.Payload).Should(Equal(Design.Types["Notification"]))
+			Ω(w.Parent).Should(Equal(action))
+		})
+
+		Context("with a description and a URL", func() {
+			BeforeEach(func() {
+				webhookDSL = func() {
+					Description("desc")
+					URL("{$request.body#/notify_url}")
+				}
+			})
+
+			It("sets the webhook description and URL", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Webhooks).Should(HaveLen(1))
+				Ω(action.Webhooks[0].Description).Should(Equal("desc"))
+				Ω(action.Webhooks[0].URL).Should(Equal("{$request.body#/notify_url}"))
+			})
+		})
+
+		Context("with a payload type name", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(POST(""))
+					Webhook("created", "Notification")
+				}
+			})
+
+			It("looks up the payload type", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(action.Webhooks).Should(HaveLen(1))
+				Ω(action.Webhooks[0].Payload).Should(Equal(Design.Types["Notification"]))
+			})
+		})
+
+		Context("with an unknown payload type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(POST(""))
+					Webhook("created", "Unknown")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
+		Context("with an empty URL", func() {
+			BeforeEach(func() {
+				webhookDSL = func() {
+					URL("")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with audit", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Routing(DELETE("/:id"))
+				Audit("id")
+			}
+		})
+
+		It("records the audit attributes", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(action).ShouldNot(BeNil())
+			Ω(action.Audit).Should(BeTrue())
+			Ω(action.AuditAttributes).Should(Equal([]string{"id"}))
+		})
+
+		Context("with an unknown attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Routing(DELETE("/:id"))
+					Audit("unknown")
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+	})
+
 	Context("with a string payload", func() {
 		BeforeEach(func() {
 			name = "foo"
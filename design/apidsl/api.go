@@ -99,7 +99,7 @@ func Version(ver string) {
 	}
 }
 
-// Description can be used in: API, Resource, Action, MediaType, Attribute, Response or ResponseTemplate
+// Description can be used in: API, Resource, Action, MediaType, Attribute, Response, ResponseTemplate or Webhook
 //
 // Description sets the definition description.
 func Description(d string) {
@@ -122,6 +122,8 @@ func Description(d string) {
 		def.Description = d
 	case *design.SecuritySchemeDefinition:
 		def.Description = d
+	case *design.WebhookDefinition:
+		def.Description = d
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -361,9 +363,10 @@ func Email(email string) {
 	}
 }
 
-// URL can be used in: Contact, License, Docs
+// URL can be used in: Contact, License, Docs, Webhook
 //
-// URL sets the contact, license, or Docs URL.
+// URL sets the contact, license, or Docs URL. In a Webhook it sets the OpenAPI runtime expression
+// that computes the URL the webhook requests are sent to.
 func URL(url string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.ContactDefinition:
@@ -372,6 +375,8 @@ func URL(url string) {
 		def.URL = url
 	case *design.DocsDefinition:
 		def.URL = url
+	case *design.WebhookDefinition:
+		def.URL = url
 	default:
 		dslengine.IncompatibleDSL()
 	}
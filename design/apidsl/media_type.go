@@ -369,6 +369,44 @@ func Link(name string, view ...string) {
 	}
 }
 
+// HAL can be used in: Links
+//
+// HAL causes the media type links to be rendered using the HAL format
+// (https://tools.ietf.org/html/draft-kelly-json-hal). Views that list the "links" attribute then
+// render a "_links" object instead. The object contains a "self" link to the resource and one link
+// per Link definition. Each link is a HALLink object whose "href" field holds the URL of the
+// related resource. The views of the linked media types are not used. The "goagen app" generator
+// produces the <Resource>HALLink functions that build the link objects from the canonical action
+// paths of the resources. Example:
+//
+//	MediaType("application/vnd.goa.example.bottle", func() {
+//		Attributes(func() {
+//			Attribute("id", Integer)
+//			Attribute("account", Account)
+//		})
+//		Links(func() {
+//			HAL()
+//			Link("account")
+//		})
+//		View("default", func() {
+//			Attribute("id")
+//			Attribute("links") // Renders the "_links" object
+//		})
+//	})
+func HAL() {
+	if mt, ok := mediaTypeDefinition(); ok {
+		mt.HAL = true
+		if design.Design.Types == nil {
+			design.Design.Types = make(map[string]*design.UserTypeDefinition)
+		}
+		if ut, ok := design.Design.Types[design.HALLink.TypeName]; ok && ut != design.HALLink {
+			dslengine.ReportError("type %#v conflicts with the HAL link type", design.HALLink.TypeName)
+			return
+		}
+		design.Design.Types[design.HALLink.TypeName] = design.HALLink
+	}
+}
+
 // CollectionOf creates a collection media type from its element media type and an optional
 // identifier. A collection media type represents the content of responses that return a collection
 // of resources such as "list" actions. This function can be called from any place where a media
@@ -5,7 +5,9 @@ import (
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Metadata can be used in: Attributes, MediaType, Action, Response, Resource, API
+// Metadata can be used in: Attributes, MediaType, Action, Response, Resource, API, Security,
+// BasicAuthSecurity, APIKeySecurity, OAuth2Security, JWTSecurity, MutualTLSSecurity,
+// SessionSecurity
 //
 // Metadata is a set of key/value pairs that can be assigned to an object. Each value consists of a
 // slice of strings so that multiple invocation of the Metadata function on the same target using
@@ -35,6 +37,19 @@ import (
 //        Metadata("struct:tag:json", "myName,omitempty")
 //        Metadata("struct:tag:xml", "myName,attr")
 //
+// `sensitive`: marks the attribute as holding sensitive data such as passwords or tokens. The
+// generated struct fields are tagged with `sensitive:"true"` so that the request and response
+// logger middlewares and the client request dumps hide their values (see goa.Redact).
+// Applicable to attributes only.
+//
+//        Metadata("sensitive")
+//
+// `nullable`: specifies that the attribute value may be null. The generated JSON schemas
+// set the "x-nullable" field and the OpenAPI 3.1 specification lists "null" in the schema type.
+// Applicable to attributes only.
+//
+//        Metadata("nullable")
+//
 // `swagger:generate`: specifies whether Swagger specification should be generated. Defaults to
 // true.
 // Applicable to resources, actions and file servers.
@@ -67,6 +82,47 @@ import (
 //
 //        Metadata("swagger:extension:x-api", `{"foo":"bar"}`)
 //
+// `apigateway:authorizer:xxx`: configures the AWS API Gateway authorizer of a security scheme in
+// the specification generated by the apigateway command, see the genapigateway package.
+// Applicable to security schemes.
+//
+//        Metadata("apigateway:authorizer:uri", "arn:aws:apigateway:us-east-1:lambda:path/...")
+//
+// `goagen:validation:fail-fast` and `goagen:validation:max-errors`: limit the number of errors
+// reported by the validation code generated for the API types, payloads and parameters. With
+// fail-fast the validations stop at the first error, with max-errors they stop once the given number
+// of errors is reached. By default all the errors are reported.
+// Applicable to the API.
+//
+//        Metadata("goagen:validation:fail-fast")
+//        Metadata("goagen:validation:max-errors", "10")
+//
+// `goagen:encoding:marshal-json`: generates MarshalJSON and AppendJSON methods for the media type
+// and the media types it contains. The methods encode the responses without relying on reflection
+// which benefits endpoints where encoding dominates the CPU profile. Set on the API the metadata
+// applies to all the media types.
+// Applicable to media types and the API.
+//
+//        Metadata("goagen:encoding:marshal-json")
+//
+// `goagen:encoding:parallel-threshold`: sets the number of elements from which the AppendJSON
+// methods of collection media types encode the elements concurrently, each goroutine writing to its
+// own buffer. Only applies to the media types that get the methods generated by
+// `goagen:encoding:marshal-json`. The value is read from the collection, its element media type and
+// the API in this order.
+// Applicable to media types and the API.
+//
+//        Metadata("goagen:encoding:parallel-threshold", "1000")
+//
+// `goagen:payload:pool`: recycles the structs holding the action payloads with a sync.Pool. The
+// generated handler releases the payload once the action returns so the action implementation must
+// not retain any reference to the payload struct (the values of its fields are safe to keep). Set on
+// the API the metadata applies to all the actions. Only applies to object payloads that are not
+// multipart.
+// Applicable to actions and the API.
+//
+//        Metadata("goagen:payload:pool")
+//
 // The special key names listed above may be used as follows:
 //
 //        var Account = Type("Account", func() {
@@ -107,6 +163,8 @@ func Metadata(name string, value ...string) {
 		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	case *design.SecurityDefinition:
 		def.Scheme.Metadata = appendMetadata(def.Scheme.Metadata, name, value...)
+	case *design.SecuritySchemeDefinition:
+		def.Metadata = appendMetadata(def.Metadata, name, value...)
 	default:
 		dslengine.IncompatibleDSL()
 	}
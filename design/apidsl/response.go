@@ -5,7 +5,8 @@ import (
 	"github.com/goadesign/goa/dslengine"
 )
 
-// Response can be used in: Action, Resource
+// Response can be used in: Action, Resource, APIKeySecurity, BasicAuthSecurity, OAuth2Security,
+// JWTSecurity, MutualTLSSecurity, SessionSecurity
 //
 // Response implements the response definition DSL. Response takes the name of the response as
 // first parameter. goa defines all the standard HTTP status name as global variables so they can be
@@ -50,6 +51,15 @@ import (
 //                Status(200)
 //        })
 //
+// Responses defined in a security scheme (Unauthorized or Forbidden) replace the default error
+// responses sent when the scheme auth middleware rejects a request. They are added to all the
+// actions secured by the scheme that do not define a response with the same name:
+//
+//        JWTSecurity("jwt", func() {
+//                Header("Authorization")
+//                Response(Unauthorized, AuthErrorMedia)
+//        })
+//
 // goa defines a default response template for all the HTTP status code. The default template simply sets
 // the status code. So if an action can return NotFound for example all it has to do is specify
 // Response(NotFound) - there is no need to specify the status code as the default response already
@@ -108,6 +118,19 @@ func Response(name string, paramsAndDSL ...interface{}) {
 			def.Responses[name] = resp
 		}
 
+	case *design.SecuritySchemeDefinition:
+		if def.Responses == nil {
+			def.Responses = make(map[string]*design.ResponseDefinition)
+		}
+		if _, ok := def.Responses[name]; ok {
+			dslengine.ReportError("response %s is defined twice", name)
+			return
+		}
+		if resp := executeResponseDSL(name, paramsAndDSL...); resp != nil {
+			resp.Parent = def
+			def.Responses[name] = resp
+		}
+
 	default:
 		dslengine.IncompatibleDSL()
 	}
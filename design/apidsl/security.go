@@ -1,6 +1,8 @@
 package apidsl
 
 import (
+	"time"
+
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
@@ -67,7 +69,15 @@ func Security(scheme interface{}, dsl ...func()) {
 // NoSecurity can be used in: API, Action, Files, Resource
 //
 // NoSecurity resets the authentication schemes for an Action or a Resource. It also prevents
-// fallback to Resource or API-defined Security.
+// fallback to Resource or API-defined Security. When used at the API level it makes the absence of
+// default security explicit, resources and actions may still define their own Security. Typical
+// usage is to opt out health check or login actions of the API or resource security:
+//
+//    Action("health", func() {
+//        Routing(GET("/health"))
+//        NoSecurity()
+//    })
+//
 func NoSecurity() {
 	def := &design.SecurityDefinition{
 		Scheme: &design.SecuritySchemeDefinition{Kind: design.NoSecurityKind},
@@ -81,6 +91,8 @@ func NoSecurity() {
 		parent.Security = def
 	case *design.ResourceDefinition:
 		parent.Security = def
+	case *design.APIDefinition:
+		parent.Security = def
 	default:
 		dslengine.IncompatibleDSL()
 		return
@@ -123,6 +135,87 @@ func BasicAuthSecurity(name string, dsl ...func()) *design.SecuritySchemeDefinit
 	return def
 }
 
+// MutualTLSSecurity is a top level DSL.
+// MutualTLSSecurity defines a security scheme where the clients authenticate using TLS client
+// certificates verified by the server. The generated auth middleware gives access to the verified
+// client certificate so that the common name or subject alternative names can be checked.
+//
+// Example:
+//
+//     MutualTLSSecurity("mtls", func() {
+//          Description("Client certificates signed by the internal CA")
+//     })
+//
+func MutualTLSSecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition {
+	switch dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *dslengine.TopLevelDefinition:
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+
+	if securitySchemeRedefined(name) {
+		return nil
+	}
+
+	def := &design.SecuritySchemeDefinition{
+		Kind:       design.MutualTLSSecurityKind,
+		SchemeName: name,
+		Type:       "mutualTLS",
+	}
+
+	if len(dsl) != 0 {
+		def.DSLFunc = dsl[0]
+	}
+
+	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
+
+	return def
+}
+
+// SessionSecurity is a top level DSL.
+// SessionSecurity defines a security scheme where the clients authenticate using a session cookie
+// created when they log in. The generated auth middleware decodes the session from the cookie using
+// a pluggable goa.SessionStore, exposes it to the actions via goa.ContextSession and renews it when
+// used after half its lifetime. The cookie name defaults to "session".
+//
+// Example:
+//
+//     SessionSecurity("session", func() {
+//          Description("Browser session")
+//          Cookie("sid")
+//          SessionTTL(24 * time.Hour)
+//     })
+//
+func SessionSecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition {
+	switch dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition, *dslengine.TopLevelDefinition:
+	default:
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+
+	if securitySchemeRedefined(name) {
+		return nil
+	}
+
+	def := &design.SecuritySchemeDefinition{
+		Kind:       design.SessionSecurityKind,
+		SchemeName: name,
+		Type:       "session",
+		In:         "cookie",
+		Name:       "session",
+	}
+
+	if len(dsl) != 0 {
+		def.DSLFunc = dsl[0]
+	}
+
+	design.Design.SecuritySchemes = append(design.Design.SecuritySchemes, def)
+
+	return def
+}
+
 func securitySchemeRedefined(name string) bool {
 	for _, previousScheme := range design.Design.SecuritySchemes {
 		if previousScheme.SchemeName == name {
@@ -329,6 +422,37 @@ func Query(parameterName string) {
 	dslengine.IncompatibleDSL()
 }
 
+// Cookie can be used in: SessionSecurity
+//
+// Cookie sets the name of the session cookie.
+func Cookie(name string) {
+	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if current.Kind == design.SessionSecurityKind {
+			current.Name = name
+			return
+		}
+	}
+	dslengine.IncompatibleDSL()
+}
+
+// SessionTTL can be used in: SessionSecurity
+//
+// SessionTTL sets the lifetime of the sessions. Sessions used after half their lifetime are
+// renewed.
+func SessionTTL(ttl time.Duration) {
+	if current, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if current.Kind == design.SessionSecurityKind {
+			if ttl <= 0 {
+				dslengine.ReportError("session TTL must be strictly positive, got %s", ttl)
+				return
+			}
+			current.TTL = ttl
+			return
+		}
+	}
+	dslengine.IncompatibleDSL()
+}
+
 // AccessCodeFlow can be used in: OAuth2Security
 //
 // AccessCodeFlow defines an "access code" OAuth2 flow.  Use within an OAuth2Security definition.
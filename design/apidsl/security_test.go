@@ -1,6 +1,8 @@
 package apidsl_test
 
 import (
+	"time"
+
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -47,12 +49,16 @@ var _ = Describe("Security", func() {
 				Scope("user:read", "Read users")
 				Scope("user:write", "Write users")
 			})
+
+			MutualTLSSecurity("mtls", func() {
+				Description("desc")
+			})
 		})
 
 		dslengine.Run()
 
 		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
-		Ω(Design.SecuritySchemes).Should(HaveLen(4))
+		Ω(Design.SecuritySchemes).Should(HaveLen(5))
 
 		Ω(Design.SecuritySchemes[0].Kind).Should(Equal(BasicAuthSecurityKind))
 		Ω(Design.SecuritySchemes[0].Description).Should(Equal("desc"))
@@ -69,6 +75,10 @@ var _ = Describe("Security", func() {
 		Ω(Design.SecuritySchemes[3].Kind).Should(Equal(JWTSecurityKind))
 		Ω(Design.SecuritySchemes[3].TokenURL).Should(Equal("http://example.com/token"))
 		Ω(Design.SecuritySchemes[3].Scopes).Should(HaveLen(2))
+
+		Ω(Design.SecuritySchemes[4].Kind).Should(Equal(MutualTLSSecurityKind))
+		Ω(Design.SecuritySchemes[4].Type).Should(Equal("mutualTLS"))
+		Ω(Design.SecuritySchemes[4].Description).Should(Equal("desc"))
 	})
 
 	Context("with basic security", func() {
@@ -175,6 +185,104 @@ var _ = Describe("Security", func() {
 
 	})
 
+	Context("with security scheme responses", func() {
+		It("should add the responses to the secured actions", func() {
+			API("", func() {
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+					Response(Unauthorized, func() {
+						Media("text/plain")
+					})
+				})
+			})
+			Resource("one", func() {
+				Action("first", func() {
+					Routing(GET("/first"))
+					Security("jwt")
+				})
+				Action("second", func() {
+					Routing(GET("/second"))
+				})
+			})
+
+			dslengine.Run()
+
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.SecuritySchemes[0].Responses).Should(HaveKey("Unauthorized"))
+			resp := Design.Resources["one"].Actions["first"].Responses["Unauthorized"]
+			Ω(resp).ShouldNot(BeNil())
+			Ω(resp.Status).Should(Equal(401))
+			Ω(resp.MediaType).Should(Equal("text/plain"))
+			Ω(Design.Resources["one"].Actions["second"].Responses).ShouldNot(HaveKey("Unauthorized"))
+		})
+
+		It("should fail because of an invalid response status", func() {
+			API("", func() {
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+					Response(NotFound)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with security scheme metadata", func() {
+		It("should set the scheme metadata", func() {
+			API("", func() {
+				APIKeySecurity("key", func() {
+					Header("X-Shared-Secret")
+					Metadata("apigateway:authorizer:ttl", "60")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.SecuritySchemes).Should(HaveLen(1))
+			Ω(Design.SecuritySchemes[0].Metadata).Should(Equal(dslengine.MetadataDefinition{
+				"apigateway:authorizer:ttl": {"60"},
+			}))
+		})
+	})
+
+	Context("with session security", func() {
+		It("should set the cookie name and TTL", func() {
+			API("", func() {
+				SessionSecurity("session", func() {
+					Cookie("sid")
+					SessionTTL(time.Hour)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.SecuritySchemes).Should(HaveLen(1))
+			Ω(Design.SecuritySchemes[0].Kind).Should(Equal(SessionSecurityKind))
+			Ω(Design.SecuritySchemes[0].In).Should(Equal("cookie"))
+			Ω(Design.SecuritySchemes[0].Name).Should(Equal("sid"))
+			Ω(Design.SecuritySchemes[0].TTL).Should(Equal(time.Hour))
+		})
+
+		It("should fail because of a negative TTL", func() {
+			API("", func() {
+				SessionSecurity("session", func() {
+					SessionTTL(-time.Hour)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+
+		It("should fail because of invalid declaration of Cookie", func() {
+			API("", func() {
+				APIKeySecurity("key", func() {
+					Cookie("sid")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with resources and actions", func() {
 		It("should fallback properly to lower-level security", func() {
 			API("", func() {
@@ -236,5 +344,28 @@ var _ = Describe("Security", func() {
 			Ω(Design.Resources["auth"].Actions["auth"].Security).Should(BeNil())
 			Ω(Design.Resources["auth"].Actions["refresh"].Security.Scheme.SchemeName).Should(Equal("jwt"))
 		})
+
+		It("should support NoSecurity at the API level", func() {
+			API("", func() {
+				BasicAuthSecurity("password")
+
+				NoSecurity()
+			})
+			Resource("one", func() {
+				Action("first", func() {
+					Routing(GET("/first"))
+				})
+				Action("second", func() {
+					Routing(GET("/second"))
+					Security("password")
+				})
+			})
+
+			dslengine.Run()
+
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["one"].Actions["first"].Security).Should(BeNil())
+			Ω(Design.Resources["one"].Actions["second"].Security.Scheme.SchemeName).Should(Equal("password"))
+		})
 	})
 })
@@ -7,6 +7,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dimfeld/httppath"
 	"github.com/goadesign/goa/dslengine"
@@ -253,6 +254,49 @@ type (
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Timeout is the maximum duration allowed for the action to complete, zero means no
+		// timeout.
+		Timeout time.Duration
+		// Topic is the message bus topic the action subscribes to if any.
+		Topic string
+		// ResultTopic is the message bus topic the action response is published to if any.
+		ResultTopic string
+		// CloudEvents is the CloudEvents content mode used to wrap the messages exchanged on
+		// the action topics, one of CloudEventsStructured or CloudEventsBinary. The messages
+		// are not wrapped if empty.
+		CloudEvents string
+		// EventType is the CloudEvents type of the messages published to the action topic.
+		EventType string
+		// Async is true if the action is run as an asynchronous job.
+		Async bool
+		// Subscription is the name of the server event the action streams to the client if any.
+		Subscription string
+		// CSRF is true if the action is protected against cross site request forgery.
+		CSRF bool
+		// Audit is true if the requests made to the action are recorded in the audit sink.
+		Audit bool
+		// AuditAttributes lists the names of the params or payload attributes whose values
+		// identify the resources recorded in the audit records.
+		AuditAttributes []string
+		// Webhooks lists the outbound requests the action makes to the URLs registered by
+		// the API consumers.
+		Webhooks []*WebhookDefinition
+	}
+
+	// WebhookDefinition describes an outbound HTTP request made by the service to a URL
+	// registered by the API consumer, e.g. to notify it that an asynchronous operation completed.
+	WebhookDefinition struct {
+		// Name of the webhook, e.g. "bottleCreated"
+		Name string
+		// Description for docs
+		Description string
+		// URL is the OpenAPI runtime expression that evaluates to the URL the webhook
+		// requests are sent to, e.g. "{$request.body#/callback_url}".
+		URL string
+		// Payload is the type of the webhook request body, a user type or a media type.
+		Payload DataType
+		// Parent action
+		Parent *ActionDefinition
 	}
 
 	// FileServerDefinition defines an endpoint that servers static assets.
@@ -366,6 +410,20 @@ type (
 	ResponseIterator func(r *ResponseDefinition) error
 )
 
+// List of the CloudEvents content modes supported by the CloudEvents DSL.
+const (
+	// CloudEventsStructured wraps the message data and the event attributes in a JSON
+	// envelope.
+	CloudEventsStructured = "structured"
+	// CloudEventsBinary writes the event attributes in the message headers and leaves the
+	// message data unchanged.
+	CloudEventsBinary = "binary"
+)
+
+// DefaultWebhookURL is the runtime expression used to compute the URL of the webhooks that do not
+// define one: the value of the "callback_url" attribute of the request body.
+const DefaultWebhookURL = "{$request.body#/callback_url}"
+
 // NewAPIDefinition returns a new design with built-in response templates.
 func NewAPIDefinition() *APIDefinition {
 	api := &APIDefinition{
@@ -1373,6 +1431,20 @@ func (d *DocsDefinition) Context() string {
 	return fmt.Sprintf("documentation for %s", Design.Name)
 }
 
+// Context returns the generic definition name used in error messages.
+func (w *WebhookDefinition) Context() string {
+	var prefix, suffix string
+	if w.Name != "" {
+		prefix = fmt.Sprintf("webhook %#v", w.Name)
+	} else {
+		prefix = "unnamed webhook"
+	}
+	if w.Parent != nil {
+		suffix = fmt.Sprintf(" of %s", w.Parent.Context())
+	}
+	return prefix + suffix
+}
+
 // Context returns the generic definition name used in error messages.
 func (t *UserTypeDefinition) Context() string {
 	if t.TypeName != "" {
@@ -1602,6 +1674,7 @@ func (a *ActionDefinition) Finalize() {
 		a.Payload.Finalize()
 	}
 
+	a.mergeSecurityResponses()
 	a.mergeResponses()
 	a.initImplicitParams()
 	a.initQueryParams()
@@ -1665,6 +1738,25 @@ func (a *ActionDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
+// mergeSecurityResponses merges the responses defined by the action security scheme.
+func (a *ActionDefinition) mergeSecurityResponses() {
+	if a.Security == nil {
+		return
+	}
+	for name, resp := range a.Security.Scheme.Responses {
+		if _, ok := a.Responses[name]; ok {
+			continue
+		}
+		if a.Responses == nil {
+			a.Responses = make(map[string]*ResponseDefinition)
+		}
+		dup := resp.Dup()
+		dup.Type = resp.Type
+		dup.Parent = a
+		a.Responses[name] = dup
+	}
+}
+
 // mergeResponses merges the parent resource and design responses.
 func (a *ActionDefinition) mergeResponses() {
 	for name, resp := range a.Parent.Responses {
@@ -89,6 +89,7 @@ func (d *dupper) DupType(t DataType) DataType {
 		m := &MediaTypeDefinition{
 			Identifier: actual.Identifier,
 			Links:      actual.Links,
+			HAL:        actual.HAL,
 			Views:      actual.Views,
 			Resource:   actual.Resource,
 		}
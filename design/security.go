@@ -3,6 +3,7 @@ package design
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/goadesign/goa/dslengine"
 )
@@ -22,6 +23,10 @@ const (
 	JWTSecurityKind
 	// NoSecurityKind means to have no security for this endpoint.
 	NoSecurityKind
+	// MutualTLSSecurityKind means the clients authenticate with TLS client certificates.
+	MutualTLSSecurityKind
+	// SessionSecurityKind means the clients authenticate with a session cookie.
+	SessionSecurityKind
 )
 
 // SecurityDefinition defines security requirements for an Action
@@ -69,6 +74,11 @@ type SecuritySchemeDefinition struct {
 	TokenURL string `json:"token_url,omitempty"`
 	// AuthorizationURL holds URL for retrieving authorization codes with oauth2
 	AuthorizationURL string `json:"authorization_url,omitempty"`
+	// TTL is the session lifetime for session cookie schemes.
+	TTL time.Duration `json:"ttl,omitempty"`
+	// Responses lists the responses sent when the scheme auth middleware fails to
+	// authenticate (Unauthorized) or authorize (Forbidden) requests indexed by name.
+	Responses map[string]*ResponseDefinition `json:"-"`
 	// Metadata is a list of key/value pairs
 	Metadata dslengine.MetadataDefinition
 }
@@ -90,11 +100,16 @@ func (s *SecuritySchemeDefinition) Context() string {
 		dslFunc = "APIKeySecurity"
 	case JWTSecurityKind:
 		dslFunc = "JWTSecurity"
+	case MutualTLSSecurityKind:
+		dslFunc = "MutualTLSSecurity"
+	case SessionSecurityKind:
+		dslFunc = "SessionSecurity"
 	}
 	return dslFunc
 }
 
-// Validate ensures that TokenURL and AuthorizationURL are valid URLs.
+// Validate ensures that TokenURL and AuthorizationURL are valid URLs and that the scheme responses
+// use the 401 or 403 status.
 func (s *SecuritySchemeDefinition) Validate() error {
 	_, err := url.Parse(s.TokenURL)
 	if err != nil {
@@ -104,6 +119,11 @@ func (s *SecuritySchemeDefinition) Validate() error {
 	if err != nil {
 		return fmt.Errorf("invalid authorization URL %#v: %s", s.AuthorizationURL, err)
 	}
+	for name, resp := range s.Responses {
+		if resp.Status != 401 && resp.Status != 403 {
+			return fmt.Errorf("invalid status %d for response %#v, security scheme responses must use status 401 or 403", resp.Status, name)
+		}
+	}
 	return nil
 }
 
@@ -131,6 +131,9 @@ type (
 		ContentType string
 		// Links list the rendered links indexed by name.
 		Links map[string]*LinkDefinition
+		// HAL is true if the links are rendered in a HAL "_links" object of link objects
+		// rather than using the "link" views of the linked media types.
+		HAL bool
 		// Views list the supported views indexed by name.
 		Views map[string]*ViewDefinition
 		// Resource this media type is the canonical representation for if any
@@ -642,6 +645,49 @@ func hasFile(dt DataType, seen map[string]struct{}) bool {
 	return false
 }
 
+// HasSensitive returns true if the underlying type has any attribute that defines the "sensitive"
+// metadata.
+func HasSensitive(dt DataType) bool {
+	return hasSensitive(dt, nil)
+}
+
+func hasSensitive(dt DataType, seen map[string]struct{}) bool {
+	if dt == nil {
+		return false
+	}
+	switch {
+	case dt.IsPrimitive():
+		return false
+	case dt.IsArray():
+		elem := dt.ToArray().ElemType
+		if _, ok := elem.Metadata["sensitive"]; ok {
+			return true
+		}
+		return hasSensitive(elem.Type, seen)
+	case dt.IsHash():
+		return hasSensitive(dt.ToHash().ElemType.Type, seen)
+	case dt.IsObject():
+		if _, ok := seen[dt.Name()]; ok {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[string]struct{})
+		}
+		seen[dt.Name()] = struct{}{}
+		for _, att := range dt.ToObject() {
+			if _, ok := att.Metadata["sensitive"]; ok {
+				return true
+			}
+			if hasSensitive(att.Type, seen) {
+				return true
+			}
+		}
+	default:
+		panic("unknown type") // bug
+	}
+	return false
+}
+
 // ToSlice converts an ArrayVal to a slice.
 func (a ArrayVal) ToSlice() []interface{} {
 	arr := make([]interface{}, len(a))
@@ -854,6 +900,7 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 
 	p = &MediaTypeDefinition{
 		Identifier: m.projectIdentifier(view),
+		HAL:        m.HAL,
 		UserTypeDefinition: &UserTypeDefinition{
 			TypeName: m.projectTypeName(view),
 			AttributeDefinition: &AttributeDefinition{
@@ -876,6 +923,9 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 	for n := range viewObj {
 		if n == "links" && !hasAttNamedLinks {
 			linkObj := make(Object)
+			if m.HAL {
+				linkObj["self"] = &AttributeDefinition{Type: HALLink, Description: "Link to the resource itself"}
+			}
 			for n, link := range m.Links {
 				linkView := link.View
 				if linkView == "" {
@@ -885,6 +935,10 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 				if !ok {
 					return nil, nil, fmt.Errorf("unknown attribute %#v used in links", n)
 				}
+				if m.HAL {
+					linkObj[n] = &AttributeDefinition{Type: HALLink, Metadata: mtAtt.Metadata}
+					continue
+				}
 				mtt := mtAtt.Type.(*MediaTypeDefinition)
 				vl, _, err := mtt.Project(linkView)
 				if err != nil {
@@ -900,7 +954,12 @@ func (m *MediaTypeDefinition) projectSingle(view, canonical string) (p *MediaTyp
 				},
 				TypeName: lTypeName,
 			}
-			projectedObj[n] = &AttributeDefinition{Type: links, Description: "Links to related resources"}
+			name := n
+			if m.HAL {
+				name = "_links"
+				delete(projectedObj, n)
+			}
+			projectedObj[name] = &AttributeDefinition{Type: links, Description: "Links to related resources"}
 			ProjectedMediaTypes[canonical+"; links"] = &MediaTypeDefinition{UserTypeDefinition: links}
 		} else {
 			if at := mtObj[n]; at != nil {
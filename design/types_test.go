@@ -335,6 +335,51 @@ var _ = Describe("Project", func() {
 			})
 		})
 	})
+	Context("with a media type using HAL links", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {})
+			account := MediaType("vnd.application/account", func() {
+				TypeName("Account")
+				Attributes(func() {
+					Attribute("id", Integer)
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+			mt = MediaType("vnd.application/bottle", func() {
+				TypeName("Bottle")
+				Attributes(func() {
+					Attribute("id", Integer)
+					Attribute("account", account)
+				})
+				Links(func() {
+					HAL()
+					Link("account")
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("links")
+				})
+			})
+			err := dslengine.Run()
+			Ω(err).ShouldNot(HaveOccurred())
+			view = "default"
+		})
+
+		It("renders the links in a HAL _links object", func() {
+			Ω(prErr).ShouldNot(HaveOccurred())
+			Ω(Design.Types).Should(HaveKeyWithValue("HALLink", HALLink))
+			obj := projected.Type.ToObject()
+			Ω(obj).ShouldNot(HaveKey("links"))
+			Ω(obj).Should(HaveKey("_links"))
+			Ω(obj["_links"].Type).Should(Equal(links))
+			Ω(links.Type.ToObject()).Should(HaveLen(2))
+			Ω(links.Type.ToObject()["self"].Type).Should(Equal(HALLink))
+			Ω(links.Type.ToObject()["account"].Type).Should(Equal(HALLink))
+		})
+	})
 })
 
 var _ = Describe("UserTypes", func() {
@@ -87,9 +87,17 @@ func (a *APIDefinition) Validate() error {
 	a.validateOrigins(verr)
 
 	var allRoutes []*routeInfo
+	webhooks := make(map[string]*WebhookDefinition)
 	a.IterateResources(func(r *ResourceDefinition) error {
 		verr.Merge(r.Validate())
 		r.IterateActions(func(ac *ActionDefinition) error {
+			for _, w := range ac.Webhooks {
+				if other, ok := webhooks[w.Name]; ok {
+					verr.Add(w, "duplicate webhook name, %s defines a webhook with the same name", other.Parent.Context())
+					continue
+				}
+				webhooks[w.Name] = w
+			}
 			if ac.Docs != nil && ac.Docs.URL != "" {
 				if _, err := url.ParseRequestURI(ac.Docs.URL); err != nil {
 					verr.Add(ac, "invalid action docs URL value: %s", err)
@@ -319,6 +327,29 @@ func (enc *EncodingDefinition) Validate() *dslengine.ValidationErrors {
 	return verr
 }
 
+// hasAuditAttribute returns true if name is the name of an action param, path wildcard or payload
+// attribute.
+func (a *ActionDefinition) hasAuditAttribute(name string) bool {
+	for _, r := range a.Routes {
+		for _, wc := range r.Params() {
+			if wc == name {
+				return true
+			}
+		}
+	}
+	if params := a.AllParams(); params != nil {
+		if _, ok := params.Type.ToObject()[name]; ok {
+			return true
+		}
+	}
+	if a.Payload != nil && a.Payload.IsObject() {
+		if _, ok := a.Payload.ToObject()[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate tests whether the action definition is consistent: parameters have unique names and it has at least
 // one response.
 func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
@@ -369,7 +400,44 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 			verr.Add(a, "Param %s has an invalid type, action params must be primitives or arrays of primitives", n)
 		}
 	}
+	for _, n := range a.AuditAttributes {
+		if !a.hasAuditAttribute(n) {
+			verr.Add(a, "Audit attribute %s is not a param or payload attribute", n)
+		}
+	}
+	if a.CloudEvents != "" && a.Topic == "" {
+		verr.Add(a, "Actions using CloudEvents must be bound to a topic")
+	}
+	if a.Topic != "" || a.Async {
+		// The messages and jobs carry no path so the path parameters cannot be initialized.
+		for _, r := range a.Routes {
+			if wcs := ExtractWildcards(r.FullPath()); len(wcs) > 0 {
+				verr.Add(a, "Actions bound to a topic or run as async jobs cannot use path parameters (%s)", strings.Join(wcs, ", "))
+				break
+			}
+		}
+	}
+	for _, w := range a.Webhooks {
+		verr.Merge(w.Validate())
+	}
+
+	return verr.AsError()
+}
 
+// Validate checks the webhook has a name, a URL and an object payload.
+func (w *WebhookDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if w.Name == "" {
+		verr.Add(w, "Webhook name cannot be empty")
+	}
+	if w.URL == "" {
+		verr.Add(w, "Webhook URL cannot be empty")
+	}
+	if w.Payload == nil {
+		verr.Add(w, "Webhook payload is missing")
+	} else if !w.Payload.IsObject() {
+		verr.Add(w, "Webhook payload must be an object type")
+	}
 	return verr.AsError()
 }
 
@@ -619,7 +687,8 @@ func (l *LinkDefinition) Validate() *dslengine.ValidationErrors {
 		mediaType, ok := att.Type.(*MediaTypeDefinition)
 		if !ok {
 			verr.Add(l, "attribute type must be a media type")
-		} else {
+		} else if !l.Parent.HAL {
+			// HAL links are rendered as link objects and do not use a view.
 			viewFound := false
 			view := l.View
 			for v := range mediaType.Views {
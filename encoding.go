@@ -1,12 +1,16 @@
 package goa
 
 import (
+	"bytes"
 	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"mime"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -116,6 +120,11 @@ func (decoder *HTTPDecoder) Decode(v interface{}, body io.Reader, contentType st
 		}
 	}
 	p = decoder.pools[contentType]
+	if p == nil {
+		if base := suffixType(contentType); base != "" {
+			p = decoder.pools[base]
+		}
+	}
 	if p == nil {
 		p = decoder.pools["*/*"]
 	}
@@ -182,36 +191,102 @@ func (p *decoderPool) Put(d Decoder) {
 	p.pool.Put(d)
 }
 
-// Encode uses the registered encoders and given content type to marshal and write the given value
-// using the given writer.
+// Encode uses the registered encoders and given Accept header value to marshal and write the given
+// value using the given writer. See Negotiate for how the encoder is selected. The default encoder
+// registered under "*/*" is used if no encoder matches.
 func (encoder *HTTPEncoder) Encode(v interface{}, resp io.Writer, accept string) error {
 	now := time.Now()
-	if accept == "" {
-		accept = "*/*"
-	}
-	var contentType string
-	for _, t := range encoder.contentTypes {
-		if accept == "*/*" || accept == t {
-			contentType = accept
-			break
-		}
+	var p *encoderPool
+	contentType := encoder.Negotiate(accept)
+	if contentType != "" {
+		p = encoder.lookup(contentType)
+	} else {
+		contentType = "*/*"
 	}
 	defer MeasureSince([]string{"goa", "encode", contentType}, now)
-	p := encoder.pools[contentType]
-	if p == nil && contentType != "*/*" {
+	if p == nil {
 		p = encoder.pools["*/*"]
 	}
 	if p == nil {
 		return fmt.Errorf("No encoder registered for %s and no default encoder", contentType)
 	}
 
-	// the encoderPool will handle whether or not a pool is actually in use
-	e := p.Get(resp)
+	// Encode into a pooled buffer so that the body is written at once and nothing is written if
+	// encoding fails. The encoderPool will handle whether or not a pool is actually in use.
+	buf := getBuffer()
+	defer putBuffer(buf)
+	e := p.Get(buf)
 	if err := e.Encode(v); err != nil {
 		return err
 	}
 	p.Put(e)
 
+	_, err := buf.WriteTo(resp)
+	return err
+}
+
+// Negotiate returns the content type of the response body given the request Accept header value.
+// The media ranges listed in the header are considered by decreasing quality value. A media range
+// matches a registered encoder if it is the encoder content type, if it uses a structured syntax
+// suffix that is the subtype of the encoder content type (e.g. "application/vnd.example+cbor"
+// matches "application/cbor") or if it is a wildcard subtype (e.g. "application/*"). Negotiate
+// returns an empty string if the default encoder should be used.
+func (encoder *HTTPEncoder) Negotiate(accept string) string {
+	var (
+		best  string
+		bestQ float64
+	)
+	for _, r := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(r))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= bestQ {
+			continue
+		}
+		if mediaType == "*/*" {
+			best, bestQ = "", q
+			continue
+		}
+		if ct := encoder.match(mediaType); ct != "" {
+			best, bestQ = ct, q
+		}
+	}
+	return best
+}
+
+// match returns the content type of the response body given a media range of the Accept header,
+// an empty string if no registered encoder matches.
+func (encoder *HTTPEncoder) match(mediaType string) string {
+	if encoder.lookup(mediaType) != nil {
+		return mediaType
+	}
+	if strings.HasSuffix(mediaType, "/*") {
+		prefix := strings.TrimSuffix(mediaType, "*")
+		for _, ct := range encoder.contentTypes {
+			if strings.HasPrefix(ct, prefix) {
+				return ct
+			}
+		}
+	}
+	return ""
+}
+
+// lookup returns the pool of encoders registered for the given content type or its structured
+// syntax suffix, nil if there is none.
+func (encoder *HTTPEncoder) lookup(contentType string) *encoderPool {
+	if p, ok := encoder.pools[contentType]; ok {
+		return p
+	}
+	if base := suffixType(contentType); base != "" {
+		return encoder.pools[base]
+	}
 	return nil
 }
 
@@ -232,6 +307,7 @@ func (encoder *HTTPEncoder) Register(f EncoderFunc, contentTypes ...string) {
 	for contentType := range encoder.pools {
 		encoder.contentTypes = append(encoder.contentTypes, contentType)
 	}
+	sort.Strings(encoder.contentTypes)
 }
 
 // newEncodePool checks to see if the EncoderFactory returns reusable encoders and if so, creates
@@ -272,3 +348,60 @@ func (p *encoderPool) Put(e Encoder) {
 	}
 	p.pool.Put(e)
 }
+
+// maxPooledBufferSize is the capacity above which the encoding buffers are not returned to the pool
+// so that a few large response bodies do not retain memory.
+const maxPooledBufferSize = 64 << 10
+
+// bufferPool holds the buffers used to encode the response bodies.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns buf to the pool unless it grew too large.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// suffixType returns the content type corresponding to the structured syntax suffix of the given
+// media type, e.g. "application/cbor" for "application/vnd.example+cbor". It returns an empty
+// string if the media type has no suffix.
+func suffixType(mediaType string) string {
+	slash := strings.Index(mediaType, "/")
+	plus := strings.LastIndex(mediaType, "+")
+	if slash < 0 || plus < slash || plus == len(mediaType)-1 {
+		return ""
+	}
+	return mediaType[:slash+1] + mediaType[plus+1:]
+}
+
+// sameEncoding returns true if the response Content-Type header value contentType denotes the
+// same encoding as the negotiated content type ct. Media types with no structured syntax suffix
+// such as the goa media type identifiers are rendered in JSON.
+func sameEncoding(contentType, ct string) bool {
+	if contentType == "" {
+		return false
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	base := func(t string) string {
+		if s := suffixType(t); s != "" {
+			return s
+		}
+		return t
+	}
+	if contentType == ct || base(contentType) == base(ct) {
+		return true
+	}
+	return suffixType(contentType) == "" && base(ct) == "application/json"
+}
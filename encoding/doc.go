@@ -19,6 +19,11 @@ The built-in encoder and decoder media types are:
 	- application/binc and application/x-binc
 	- application/cbor and application/x-cbor
 
+The response encoder is selected using the request Accept header: media ranges are considered by
+decreasing quality value, wildcard subtypes such as "application/*" are supported and media types
+with a structured syntax suffix such as "application/vnd.example+cbor" use the encoder registered
+for the suffix ("application/cbor" in this example).
+
 External encoders and decoders can also be specified via the DSL:
 
 	Produces("application/json", func() {   // Custom encoder
@@ -29,5 +34,8 @@ The DSL above causes the generated code to use the JSON encoder implemented in t
 github.com/goadesign/goa/encoding/json rather than the stdlib JSON encoder. Third party encoders
 can easily be used via adapter packages that expose the NewDecoder and NewEcoder methods expected
 by the generated code, see the json package as an example.
+
+The protojson package encodes and decodes protobuf generated messages using the canonical protobuf
+JSON mapping, use it with the DSL above to exchange the same messages over HTTP as over gRPC.
 */
 package encoding
@@ -0,0 +1,100 @@
+// Package protojson implements goa encoders and decoders that use the canonical JSON mapping of
+// protocol buffers (https://developers.google.com/protocol-buffers/docs/proto3#json) to encode
+// and decode protobuf generated messages. This makes it possible for HTTP endpoints to use the
+// same message types as gRPC services with identical wire JSON:
+//
+//	Consumes("application/json", func() {
+//		Package("github.com/goadesign/goa/encoding/protojson")
+//	})
+//	Produces("application/json", func() {
+//		Package("github.com/goadesign/goa/encoding/protojson")
+//	})
+//
+// Values that are not protobuf messages such as the goa error responses are encoded and decoded
+// with the standard library JSON package.
+package protojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/goadesign/goa"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// MarshalOptions are the options used by the encoders to marshal messages.
+	MarshalOptions = protojson.MarshalOptions{}
+	// UnmarshalOptions are the options used by the decoders to unmarshal messages.
+	UnmarshalOptions = protojson.UnmarshalOptions{}
+
+	// Enforce that the encoder and decoder satisfy goa.ResettableDecoder and
+	// goa.ResettableEncoder at compile time
+	_ goa.ResettableDecoder = (*ProtoJSONDecoder)(nil)
+	_ goa.ResettableEncoder = (*ProtoJSONEncoder)(nil)
+)
+
+type (
+	// ProtoJSONDecoder stores state between Reset and Decode
+	ProtoJSONDecoder struct {
+		buf *bytes.Buffer
+		r   io.Reader
+	}
+
+	// ProtoJSONEncoder stores state between Reset and Encode
+	ProtoJSONEncoder struct {
+		w io.Writer
+	}
+)
+
+// NewDecoder returns a new protojson decoder that satisfies goa.Decoder
+func NewDecoder(r io.Reader) goa.Decoder {
+	return &ProtoJSONDecoder{buf: &bytes.Buffer{}, r: r}
+}
+
+// Decode unmarshals the JSON read from the reader into v using the protobuf JSON mapping if v is a
+// proto.Message and the standard JSON decoder otherwise.
+func (dec *ProtoJSONDecoder) Decode(v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return json.NewDecoder(dec.r).Decode(v)
+	}
+	dec.buf.Reset()
+	if _, err := dec.buf.ReadFrom(dec.r); err != nil {
+		return err
+	}
+	return UnmarshalOptions.Unmarshal(dec.buf.Bytes(), msg)
+}
+
+// Reset stores the new reader and resets its buffer
+func (dec *ProtoJSONDecoder) Reset(r io.Reader) {
+	dec.buf.Reset()
+	dec.r = r
+}
+
+// NewEncoder returns a new protojson encoder that satisfies goa.Encoder
+func NewEncoder(w io.Writer) goa.Encoder {
+	return &ProtoJSONEncoder{w: w}
+}
+
+// Encode writes the JSON encoding of v to the writer using the protobuf JSON mapping if v is a
+// proto.Message and the standard JSON encoder otherwise.
+func (enc *ProtoJSONEncoder) Encode(v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return json.NewEncoder(enc.w).Encode(v)
+	}
+	b, err := MarshalOptions.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.Write(b)
+	return err
+}
+
+// Reset stores the new writer
+func (enc *ProtoJSONEncoder) Reset(w io.Writer) {
+	enc.w = w
+}
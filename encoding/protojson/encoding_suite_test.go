@@ -0,0 +1,13 @@
+package protojson_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestProtoJSONEncoding(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ProtoJSON Encoding Suite")
+}
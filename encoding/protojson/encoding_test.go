@@ -0,0 +1,52 @@
+package protojson_test
+
+import (
+	"bytes"
+
+	"github.com/goadesign/goa/encoding/protojson"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var _ = Describe("ProtoJSONEncoding", func() {
+
+	Describe("handle protobuf messages", func() {
+		It("encode", func() {
+			var b bytes.Buffer
+			err := protojson.NewEncoder(&b).Encode(wrapperspb.Int64(42))
+
+			Ω(err).ShouldNot(HaveOccurred())
+			// The canonical JSON mapping encodes 64-bit integers as strings.
+			Ω(b.String()).Should(Equal(`"42"`))
+		})
+
+		It("round trip", func() {
+			data, err := structpb.NewStruct(map[string]interface{}{"name": "Test"})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var b bytes.Buffer
+			Ω(protojson.NewEncoder(&b).Encode(data)).ShouldNot(HaveOccurred())
+
+			var payload structpb.Struct
+			Ω(protojson.NewDecoder(&b).Decode(&payload)).ShouldNot(HaveOccurred())
+			Ω(payload.AsMap()).Should(Equal(data.AsMap()))
+		})
+	})
+
+	Describe("handle other values", func() {
+		type Payload struct {
+			Name string
+		}
+
+		It("round trip", func() {
+			var b bytes.Buffer
+			Ω(protojson.NewEncoder(&b).Encode(Payload{"Test"})).ShouldNot(HaveOccurred())
+
+			var payload Payload
+			Ω(protojson.NewDecoder(&b).Decode(&payload)).ShouldNot(HaveOccurred())
+			Ω(payload.Name).Should(Equal("Test"))
+		})
+	})
+})
@@ -0,0 +1,173 @@
+package goa_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// markerEncoder writes a fixed marker instead of encoding the value.
+type markerEncoder struct {
+	w      io.Writer
+	marker string
+}
+
+func (e *markerEncoder) Encode(v interface{}) error {
+	_, err := e.w.Write([]byte(e.marker))
+	return err
+}
+
+func newMarkerEncoder(marker string) goa.EncoderFunc {
+	return func(w io.Writer) goa.Encoder { return &markerEncoder{w: w, marker: marker} }
+}
+
+// chunkedEncoder writes the value in chunks and fails if the value is "fail" after writing the
+// first chunk.
+type chunkedEncoder struct {
+	w io.Writer
+}
+
+func (e *chunkedEncoder) Encode(v interface{}) error {
+	for _, c := range strings.Split(v.(string), "") {
+		if _, err := e.w.Write([]byte(c)); err != nil {
+			return err
+		}
+		if v == "fail" {
+			return errors.New("encoding failed")
+		}
+	}
+	return nil
+}
+
+// countingWriter counts the calls to Write.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(b)
+}
+
+var _ = Describe("HTTPEncoder", func() {
+	var encoder *goa.HTTPEncoder
+
+	BeforeEach(func() {
+		encoder = goa.NewHTTPEncoder()
+		encoder.Register(newMarkerEncoder("json"), "application/json", "*/*")
+		encoder.Register(newMarkerEncoder("cbor"), "application/cbor")
+		encoder.Register(newMarkerEncoder("xml"), "text/xml")
+	})
+
+	Describe("Negotiate", func() {
+		cases := []struct{ desc, accept, expected string }{
+			{"an empty header", "", ""},
+			{"any media type", "*/*", ""},
+			{"an exact match", "application/cbor", "application/cbor"},
+			{"an unknown media type", "application/msgpack", ""},
+			{"a structured suffix", "application/vnd.example+cbor", "application/vnd.example+cbor"},
+			{"a wildcard subtype", "text/*", "text/xml"},
+			{"media types of equal quality", "application/cbor, application/json", "application/cbor"},
+			{"quality values", "application/json;q=0.5, application/cbor;q=0.8", "application/cbor"},
+			{"any media type with the highest quality", "application/cbor;q=0.2, */*", ""},
+			{"an unknown media type with the highest quality", "application/msgpack, text/xml;q=0.1", "text/xml"},
+		}
+		for _, c := range cases {
+			c := c
+			It("selects the content type given "+c.desc, func() {
+				Ω(encoder.Negotiate(c.accept)).Should(Equal(c.expected))
+			})
+		}
+	})
+
+	Describe("Encode", func() {
+		var accept string
+		var buf *bytes.Buffer
+		var err error
+
+		JustBeforeEach(func() {
+			buf = new(bytes.Buffer)
+			err = encoder.Encode("foo", buf, accept)
+		})
+
+		Context("with an Accept header using a structured suffix", func() {
+			BeforeEach(func() {
+				accept = "application/vnd.example+cbor"
+			})
+
+			It("uses the encoder registered for the suffix", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(buf.String()).Should(Equal("cbor"))
+			})
+		})
+
+		Context("with an Accept header using quality values", func() {
+			BeforeEach(func() {
+				accept = "application/cbor;q=0.1, text/*;q=0.9"
+			})
+
+			It("uses the encoder with the highest quality", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(buf.String()).Should(Equal("xml"))
+			})
+		})
+
+		Context("with an Accept header that matches no encoder", func() {
+			BeforeEach(func() {
+				accept = "application/msgpack"
+			})
+
+			It("uses the default encoder", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(buf.String()).Should(Equal("json"))
+			})
+		})
+	})
+
+	Describe("Encode with an encoder writing in chunks", func() {
+		var w *countingWriter
+
+		BeforeEach(func() {
+			encoder = goa.NewHTTPEncoder()
+			encoder.Register(func(w io.Writer) goa.Encoder { return &chunkedEncoder{w: w} }, "*/*")
+			w = new(countingWriter)
+		})
+
+		It("writes the body at once", func() {
+			Ω(encoder.Encode("foo", w, "")).Should(Succeed())
+			Ω(w.String()).Should(Equal("foo"))
+			Ω(w.writes).Should(Equal(1))
+			Ω(encoder.Encode("bar", w, "")).Should(Succeed())
+			Ω(w.String()).Should(Equal("foobar"))
+		})
+
+		It("does not write anything when encoding fails", func() {
+			Ω(encoder.Encode("fail", w, "")).ShouldNot(Succeed())
+			Ω(w.writes).Should(Equal(0))
+		})
+	})
+})
+
+var _ = Describe("HTTPDecoder", func() {
+	var decoder *goa.HTTPDecoder
+
+	BeforeEach(func() {
+		decoder = goa.NewHTTPDecoder()
+		decoder.Register(goa.NewJSONDecoder, "application/json")
+	})
+
+	Context("with a Content-Type using a structured suffix", func() {
+		It("uses the decoder registered for the suffix", func() {
+			var v map[string]interface{}
+			err := decoder.Decode(&v, strings.NewReader(`{"foo":"bar"}`), "application/vnd.example+json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(v).Should(Equal(map[string]interface{}{"foo": "bar"}))
+		})
+	})
+})
@@ -58,6 +58,10 @@ var (
 	// MaxRequestBodyLength bytes.
 	ErrRequestBodyTooLarge = NewErrorClass("request_too_large", 413)
 
+	// ErrInsufficientScope is the error produced when the scopes granted to the request
+	// credentials do not include all the scopes required by the action.
+	ErrInsufficientScope = NewErrorClass("insufficient_scope", 403)
+
 	// ErrNoAuthMiddleware is the error produced when no auth middleware is mounted for a
 	// security scheme defined in the design.
 	ErrNoAuthMiddleware = NewErrorClass("no_auth_middleware", 500)
@@ -73,6 +77,10 @@ var (
 	// handler but not the HTTP method.
 	ErrMethodNotAllowed = NewErrorClass("method_not_allowed", 405)
 
+	// ErrTimeout is the error returned to requests whose handler did not complete before the
+	// timeout defined in the design expired.
+	ErrTimeout = NewErrorClass("timeout", 504)
+
 	// ErrInternal is the class of error used for uncaught errors.
 	ErrInternal = NewErrorClass("internal", 500)
 )
@@ -122,6 +130,8 @@ type (
 		Detail string `json:"detail" yaml:"detail" xml:"detail" form:"detail"`
 		// Meta contains additional key/value pairs useful to clients.
 		Meta map[string]interface{} `json:"meta,omitempty" yaml:"meta,omitempty" xml:"meta,omitempty" form:"meta,omitempty"`
+
+		merged int // Number of errors merged into this one by MergeErrors
 	}
 )
 
@@ -323,6 +333,7 @@ func MergeErrors(err, other error) error {
 		e.Code = "bad_request"
 	}
 	e.Detail = e.Detail + "; " + o.Detail
+	e.merged += o.merged + 1
 
 	if e.Meta == nil && len(o.Meta) > 0 {
 		e.Meta = make(map[string]interface{})
@@ -333,6 +344,19 @@ func MergeErrors(err, other error) error {
 	return e
 }
 
+// ErrorCount returns the number of errors combined into err by MergeErrors, 0 if err is nil. The
+// code generated for the validations uses it to stop validating once the maximum number of errors
+// configured in the design is reached.
+func ErrorCount(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(*ErrorResponse); ok {
+		return e.merged + 1
+	}
+	return 1
+}
+
 func asServiceError(err error) ServiceError {
 	e, ok := err.(ServiceError)
 	if !ok {
@@ -425,6 +425,11 @@ var _ = Describe("Merge", func() {
 					Ω(mErr.(*ErrorResponse).Code).Should(Equal(code))
 				})
 
+				It("counts the merged errors", func() {
+					Ω(ErrorCount(mErr)).Should(Equal(2))
+					Ω(ErrorCount(MergeErrors(mErr, errors.New("foo3")))).Should(Equal(3))
+				})
+
 				Context("with different code", func() {
 					BeforeEach(func() {
 						mErr2.Code = code + code
@@ -601,3 +606,14 @@ var _ = Describe("Merge", func() {
 	})
 
 })
+
+var _ = Describe("ErrorCount", func() {
+	It("returns 0 for a nil error", func() {
+		Ω(ErrorCount(nil)).Should(Equal(0))
+	})
+
+	It("returns 1 for an error that was not merged", func() {
+		Ω(ErrorCount(errors.New("foo"))).Should(Equal(1))
+		Ω(ErrorCount(ErrBadRequest("foo"))).Should(Equal(1))
+	})
+})
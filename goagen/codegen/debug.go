@@ -0,0 +1,131 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/goadesign/goa/design"
+)
+
+// DebugDir is the directory where Dump writes the template data. It is set by the generator tool
+// when goagen runs with the --debug flag, Dump does nothing when it is empty.
+var DebugDir string
+
+// Dump writes the JSON representation of the data given to a template to the file name.json in
+// DebugDir. name may contain slashes to group the dumps in sub-directories, e.g.
+// "app/contexts/ShowBottleContext". The definitions referenced multiple times (such as the parents
+// of the design definitions) are written once, the other occurrences are replaced with their
+// context, e.g. "<resource \"bottle\">". The API definition is only written when it is the dumped
+// data.
+func Dump(name string, data interface{}) error {
+	if DebugDir == "" {
+		return nil
+	}
+	seen := make(map[dumpKey]bool)
+	if api := design.Design; api != nil && data != interface{}(api) {
+		// Most template data refer to the API, do not repeat it in every dump.
+		seen[dumpKey{reflect.ValueOf(api).Pointer(), reflect.TypeOf(api)}] = true
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(dumpValue(reflect.ValueOf(data), seen)); err != nil {
+		return fmt.Errorf("failed to dump %s: %s", name, err)
+	}
+	path := filepath.Join(DebugDir, filepath.FromSlash(name)+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// dumpKey identifies a pointer visited by dumpValue.
+type dumpKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// dumpValue returns a value that encoding/json can marshal and that represents v. seen records the
+// pointers already dumped.
+func dumpValue(v reflect.Value, seen map[dumpKey]bool) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		key := dumpKey{v.Pointer(), v.Type()}
+		if seen[key] {
+			if c, ok := v.Interface().(interface{ Context() string }); ok {
+				return "<" + c.Context() + ">"
+			}
+			return "<" + v.Type().String() + ">"
+		}
+		seen[key] = true
+		return dumpValue(v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return dumpValue(v.Elem(), seen)
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			switch f.Type.Kind() {
+			case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+				continue
+			}
+			if f.Anonymous {
+				if embedded, ok := dumpValue(v.Field(i), seen).(map[string]interface{}); ok {
+					for k, val := range embedded {
+						if _, ok := fields[k]; !ok {
+							fields[k] = val
+						}
+					}
+					continue
+				}
+			}
+			fields[f.Name] = dumpValue(v.Field(i), seen)
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			m[fmt.Sprint(k.Interface())] = dumpValue(v.MapIndex(k), seen)
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = dumpValue(v.Index(i), seen)
+		}
+		return s
+	default:
+		if v.CanInterface() {
+			// Primitive data types are dumped using their names rather than their kinds.
+			if n, ok := v.Interface().(interface{ Name() string }); ok {
+				return n.Name()
+			}
+			return v.Interface()
+		}
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,93 @@
+package codegen_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dump", func() {
+	var (
+		debugDir string
+		name     string
+		data     interface{}
+		dumpErr  error
+	)
+
+	BeforeEach(func() {
+		var err error
+		debugDir, err = ioutil.TempDir("", "dump")
+		Ω(err).ShouldNot(HaveOccurred())
+		codegen.DebugDir = debugDir
+		name = "app/contexts/ShowBottleContext"
+	})
+
+	JustBeforeEach(func() {
+		dumpErr = codegen.Dump(name, data)
+	})
+
+	AfterEach(func() {
+		codegen.DebugDir = ""
+		os.RemoveAll(debugDir)
+	})
+
+	read := func() map[string]interface{} {
+		b, err := ioutil.ReadFile(filepath.Join(debugDir, "app", "contexts", "ShowBottleContext.json"))
+		Ω(err).ShouldNot(HaveOccurred())
+		var m map[string]interface{}
+		Ω(json.Unmarshal(b, &m)).Should(Succeed())
+		return m
+	}
+
+	Context("with definitions referring to their parents", func() {
+		BeforeEach(func() {
+			res := &design.ResourceDefinition{Name: "bottle"}
+			action := &design.ActionDefinition{Name: "show", Parent: res}
+			res.Actions = map[string]*design.ActionDefinition{"show": action}
+			data = struct {
+				Action   *design.ActionDefinition
+				Params   *design.AttributeDefinition
+				template func()
+			}{
+				Action: action,
+				Params: &design.AttributeDefinition{Type: design.Object{"id": {Type: design.Integer}}},
+			}
+		})
+
+		It("writes the parents once", func() {
+			Ω(dumpErr).ShouldNot(HaveOccurred())
+			m := read()
+			action := m["Action"].(map[string]interface{})
+			Ω(action["Name"]).Should(Equal("show"))
+			parent := action["Parent"].(map[string]interface{})
+			Ω(parent["Actions"]).Should(Equal(map[string]interface{}{"show": `<resource "bottle" action "show">`}))
+		})
+
+		It("writes the data types names", func() {
+			Ω(dumpErr).ShouldNot(HaveOccurred())
+			params := read()["Params"].(map[string]interface{})
+			id := params["Type"].(map[string]interface{})["id"].(map[string]interface{})
+			Ω(id["Type"]).Should(Equal("integer"))
+		})
+	})
+
+	Context("without debug directory", func() {
+		BeforeEach(func() {
+			codegen.DebugDir = ""
+			data = "foo"
+		})
+
+		It("does not write anything", func() {
+			Ω(dumpErr).ShouldNot(HaveOccurred())
+			files, err := ioutil.ReadDir(debugDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+})
@@ -171,3 +171,22 @@ func KebabCase(name string) string {
 	name = SnakeCase(name)
 	return strings.Replace(name, "_", "-", -1)
 }
+
+// OutputDir returns the path relative to the goagen output directory of the directory where the
+// generator identified by name writes its files, e.g. "app", "client" or "swagger". This is the
+// value of the "goagen:out:<name>" API metadata if set, def otherwise. The metadata makes it
+// possible to lay out the generated packages in different directories, for example:
+//
+//	Metadata("goagen:out:app", "internal/transport/rest")
+//	Metadata("goagen:out:swagger", "api")
+//
+// The generators that import the relocated packages use the same metadata so that the import
+// paths stay consistent.
+func OutputDir(api *design.APIDefinition, name, def string) string {
+	if api != nil {
+		if dir, ok := api.Metadata["goagen:out:"+name]; ok && len(dir) > 0 && dir[0] != "" {
+			return filepath.Clean(filepath.FromSlash(dir[0]))
+		}
+	}
+	return def
+}
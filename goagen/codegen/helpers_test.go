@@ -2,7 +2,10 @@ package codegen_test
 
 import (
 	"os"
+	"path/filepath"
 
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 
 	. "github.com/onsi/ginkgo"
@@ -59,4 +62,22 @@ var _ = Describe("Helpers", func() {
 			Expect(codegen.CommandLine()).To(Equal("$ foo\n\t--opt=/bar/xx/42"))
 		})
 	})
+
+	Describe("OutputDir", func() {
+		var api *design.APIDefinition
+		BeforeEach(func() {
+			api = &design.APIDefinition{Metadata: dslengine.MetadataDefinition{
+				"goagen:out:app": {"internal/transport/rest/"},
+			}}
+		})
+
+		It("returns the directory set in the metadata", func() {
+			Expect(codegen.OutputDir(api, "app", "app")).To(Equal(filepath.FromSlash("internal/transport/rest")))
+		})
+
+		It("defaults to the given directory", func() {
+			Expect(codegen.OutputDir(api, "client", "client")).To(Equal("client"))
+			Expect(codegen.OutputDir(nil, "app", "app")).To(Equal("app"))
+		})
+	})
 })
@@ -0,0 +1,252 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ScaffoldBaseDir is the name of the directory where the scaffolding generators running in merge
+// mode record the last generated version of each scaffold file. The directory is created next to
+// the scaffold files and should be committed with them.
+const ScaffoldBaseDir = ".goagen"
+
+// MergeConflictError is the error returned by MergeScaffold when the changes made to a scaffold
+// file conflict with the changes made to the generated code. The file contains the conflicting
+// lines delimited with conflict markers.
+type MergeConflictError struct {
+	// Filename is the path to the scaffold file.
+	Filename string
+}
+
+// Error returns the error message.
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("%s: merge conflict, resolve the conflict markers", e.Filename)
+}
+
+// ScaffoldBase returns the path to the file recording the last generated version of the given
+// scaffold file.
+func ScaffoldBase(filename string) string {
+	return filepath.Join(filepath.Dir(filename), ScaffoldBaseDir, filepath.Base(filename))
+}
+
+// MergeScaffold runs gen to generate the scaffold file filename and merges the result with the
+// existing file: the changes made to the file since it was last generated are re-applied on top of
+// the new version. gen must write the file at filename. The generated version is recorded in
+// ScaffoldBaseDir so that the next merge can tell the user changes from the generated code
+// changes. MergeScaffold returns false and leaves the file untouched if it exists but was not
+// generated in merge mode. It returns a *MergeConflictError if the changes conflict.
+func MergeScaffold(filename string, gen func() error) (bool, error) {
+	base := ScaffoldBase(filename)
+	current, err := ioutil.ReadFile(filename)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+		if err := gen(); err != nil {
+			return false, err
+		}
+		generated, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return false, err
+		}
+		return true, recordBase(base, generated)
+	}
+	original, err := ioutil.ReadFile(base)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		return false, err
+	}
+	// Move the file out of the way so that gen writes the new version, it is moved back if gen
+	// fails and removed once the merged version is written.
+	backup, err := backupFile(filename)
+	if err != nil {
+		return false, err
+	}
+	if err := gen(); err != nil {
+		if rerr := os.Rename(backup, filename); rerr != nil {
+			return false, fmt.Errorf("%s, failed to restore %s from %s: %s", err, filename, backup, rerr)
+		}
+		return false, err
+	}
+	generated, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return false, fmt.Errorf("%s, the previous version is saved in %s", err, backup)
+	}
+	merged, ok := Merge3(original, current, generated)
+	if err := ioutil.WriteFile(filename, merged, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("%s, the previous version is saved in %s", err, backup)
+	}
+	if err := os.Chmod(filename, info.Mode().Perm()); err != nil {
+		return false, err
+	}
+	if err := os.Remove(backup); err != nil {
+		return false, err
+	}
+	if err := recordBase(base, generated); err != nil {
+		return false, err
+	}
+	if !ok {
+		return true, &MergeConflictError{Filename: filename}
+	}
+	return true, nil
+}
+
+// backupFile moves filename to a new hidden file in the same directory and returns its path.
+func backupFile(filename string) (string, error) {
+	f, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".")
+	if err != nil {
+		return "", err
+	}
+	backup := f.Name()
+	f.Close()
+	if err := os.Rename(filename, backup); err != nil {
+		os.Remove(backup)
+		return "", err
+	}
+	return backup, nil
+}
+
+// recordBase writes the generated content of a scaffold file to base.
+func recordBase(base string, generated []byte) error {
+	if err := os.MkdirAll(filepath.Dir(base), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(base, generated, 0644)
+}
+
+// Merge3 merges the changes made to base in current and in generated line by line. It returns the
+// merged content and false if some changes conflict, in which case the conflicting lines are
+// delimited with the markers used by git:
+//
+//	<<<<<<< current
+//	lines of current
+//	=======
+//	lines of generated
+//	>>>>>>> generated
+func Merge3(base, current, generated []byte) ([]byte, bool) {
+	b, c, g := splitLines(base), splitLines(current), splitLines(generated)
+	mc, mg := matchLines(b, c), matchLines(b, g)
+	var buf bytes.Buffer
+	ok := true
+	i, ic, ig := 0, 0, 0
+	for i < len(b) || ic < len(c) || ig < len(g) {
+		if i < len(b) && mc[i] == ic && mg[i] == ig {
+			buf.WriteString(b[i])
+			i++
+			ic++
+			ig++
+			continue
+		}
+		// Find the next base line kept by both sides, the lines up to that point changed.
+		k, kc, kg := i, len(c), len(g)
+		for ; k < len(b); k++ {
+			if mc[k] >= 0 && mg[k] >= 0 {
+				kc, kg = mc[k], mg[k]
+				break
+			}
+		}
+		cb, cc, cg := b[i:k], c[ic:kc], g[ig:kg]
+		switch {
+		case sameLines(cc, cb):
+			writeLines(&buf, cg)
+		case sameLines(cg, cb), sameLines(cc, cg):
+			writeLines(&buf, cc)
+		default:
+			ok = false
+			buf.WriteString("<<<<<<< current\n")
+			writeLines(&buf, terminate(cc))
+			buf.WriteString("=======\n")
+			writeLines(&buf, terminate(cg))
+			buf.WriteString(">>>>>>> generated\n")
+		}
+		i, ic, ig = k, kc, kg
+	}
+	return buf.Bytes(), ok
+}
+
+// splitLines splits b into lines including the line terminators.
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// matchLines computes the longest common subsequence of a and b and returns, for each line of a,
+// the index of the matching line in b or -1.
+func matchLines(a, b []string) []int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		switch {
+		case j < len(b) && a[i] == b[j]:
+			matches[i] = j
+			i++
+			j++
+		case j < len(b) && lcs[i][j+1] > lcs[i+1][j]:
+			j++
+		default:
+			matches[i] = -1
+			i++
+		}
+	}
+	return matches
+}
+
+// sameLines returns true if a and b contain the same lines.
+func sameLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, l := range a {
+		if b[i] != l {
+			return false
+		}
+	}
+	return true
+}
+
+// terminate makes sure the last line of lines ends with a newline so that it can be followed by a
+// conflict marker.
+func terminate(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+	res := append([]string{}, lines...)
+	res[len(res)-1] += "\n"
+	return res
+}
+
+// writeLines writes lines to buf.
+func writeLines(buf *bytes.Buffer, lines []string) {
+	for _, l := range lines {
+		buf.WriteString(l)
+	}
+}
@@ -0,0 +1,186 @@
+package codegen_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge3", func() {
+	const base = "package main\n\nfunc a() {\n\t// TBD\n}\n\nfunc b() {\n\t// TBD\n}\n"
+
+	var current, generated string
+	var merged string
+	var ok bool
+
+	JustBeforeEach(func() {
+		var b []byte
+		b, ok = codegen.Merge3([]byte(base), []byte(current), []byte(generated))
+		merged = string(b)
+	})
+
+	Context("with changes to different lines", func() {
+		BeforeEach(func() {
+			current = "package main\n\nfunc a() {\n\tprintln(\"a\")\n}\n\nfunc b() {\n\t// TBD\n}\n"
+			generated = base + "\nfunc c() {\n\t// TBD\n}\n"
+		})
+
+		It("applies both changes", func() {
+			Ω(ok).Should(BeTrue())
+			Ω(merged).Should(Equal("package main\n\nfunc a() {\n\tprintln(\"a\")\n}\n\nfunc b() {\n\t// TBD\n}\n\nfunc c() {\n\t// TBD\n}\n"))
+		})
+	})
+
+	Context("with identical changes", func() {
+		BeforeEach(func() {
+			current = "package main\n"
+			generated = "package main\n"
+		})
+
+		It("applies the change once", func() {
+			Ω(ok).Should(BeTrue())
+			Ω(merged).Should(Equal("package main\n"))
+		})
+	})
+
+	Context("with conflicting changes", func() {
+		BeforeEach(func() {
+			current = "package main\n\nfunc a() {\n\tprintln(\"a\")\n}\n\nfunc b() {\n\t// TBD\n}\n"
+			generated = "package main\n\nfunc a() {\n\treturn\n}\n\nfunc b() {\n\t// TBD\n}\n"
+		})
+
+		It("delimits the conflicting lines", func() {
+			Ω(ok).Should(BeFalse())
+			Ω(merged).Should(Equal("package main\n\nfunc a() {\n<<<<<<< current\n\tprintln(\"a\")\n=======\n\treturn\n>>>>>>> generated\n}\n\nfunc b() {\n\t// TBD\n}\n"))
+		})
+	})
+})
+
+var _ = Describe("MergeScaffold", func() {
+	var dir, filename string
+	var content string
+	var generated bool
+	var genErr, mergeErr error
+
+	gen := func() error {
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			return err
+		}
+		return genErr
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "merge")
+		Ω(err).ShouldNot(HaveOccurred())
+		filename = filepath.Join(dir, "main.go")
+		content = "a\nb\nc\n"
+		genErr = nil
+	})
+
+	JustBeforeEach(func() {
+		generated, mergeErr = codegen.MergeScaffold(filename, gen)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("generates new files and records them", func() {
+		Ω(mergeErr).ShouldNot(HaveOccurred())
+		Ω(generated).Should(BeTrue())
+		b, err := ioutil.ReadFile(codegen.ScaffoldBase(filename))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(content))
+	})
+
+	Context("with a file generated without merge", func() {
+		BeforeEach(func() {
+			Ω(ioutil.WriteFile(filename, []byte("user\n"), 0644)).Should(Succeed())
+		})
+
+		It("leaves the file untouched", func() {
+			Ω(mergeErr).ShouldNot(HaveOccurred())
+			Ω(generated).Should(BeFalse())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal("user\n"))
+		})
+	})
+
+	Context("with a file edited since it was generated", func() {
+		BeforeEach(func() {
+			_, err := codegen.MergeScaffold(filename, gen)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ioutil.WriteFile(filename, []byte("a\nB\nc\n"), 0644)).Should(Succeed())
+		})
+
+		Context("and a compatible new version", func() {
+			BeforeEach(func() {
+				content = "a\nb\nc\nd\n"
+			})
+
+			It("merges the changes and records the new version", func() {
+				Ω(mergeErr).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(Equal("a\nB\nc\nd\n"))
+				b, err = ioutil.ReadFile(codegen.ScaffoldBase(filename))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(Equal(content))
+			})
+		})
+
+		Context("with a file mode", func() {
+			BeforeEach(func() {
+				Ω(os.Chmod(filename, 0755)).Should(Succeed())
+			})
+
+			It("keeps the file mode", func() {
+				Ω(mergeErr).ShouldNot(HaveOccurred())
+				info, err := os.Stat(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(info.Mode().Perm()).Should(Equal(os.FileMode(0755)))
+			})
+		})
+
+		Context("and a failing generation", func() {
+			BeforeEach(func() {
+				Ω(os.Chmod(filename, 0755)).Should(Succeed())
+				content = "partial\n"
+				genErr = errors.New("boom")
+			})
+
+			It("restores the file", func() {
+				Ω(mergeErr).Should(Equal(genErr))
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(Equal("a\nB\nc\n"))
+				info, err := os.Stat(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(info.Mode().Perm()).Should(Equal(os.FileMode(0755)))
+				backups, err := filepath.Glob(filepath.Join(dir, ".main.go.*"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(backups).Should(BeEmpty())
+			})
+		})
+
+		Context("and a conflicting new version", func() {
+			BeforeEach(func() {
+				content = "a\nβ\nc\n"
+			})
+
+			It("returns a merge conflict error", func() {
+				Ω(mergeErr).Should(Equal(&codegen.MergeConflictError{Filename: filename}))
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(ContainSubstring("<<<<<<< current\nB\n=======\nβ\n>>>>>>> generated\n"))
+			})
+		})
+	})
+})
@@ -26,6 +26,7 @@ func init() {
 		"add":                 Add,
 		"publicizer":          Publicizer,
 		"recursivePublicizer": RecursivePublicizer,
+		"inlineObject":        inlineObject,
 	}
 	if simplePublicizeT, err = template.New("simplePublicize").Funcs(fm).Parse(simplePublicizeTmpl); err != nil {
 		panic(err)
@@ -113,6 +114,20 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 	return publication
 }
 
+// inlineObject returns true if the attribute type is an object that is not a user type. The public
+// structs of such attributes are generated inline so that collections of them can be allocated at
+// once.
+func inlineObject(att *design.AttributeDefinition) bool {
+	if !att.Type.IsObject() {
+		return false
+	}
+	switch att.Type.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		return false
+	}
+	return true
+}
+
 const (
 	simplePublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= {{ if .dereference }}*{{ end }}{{ .sourceField }}`
 
@@ -121,23 +136,32 @@ const (
 	objectPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} = &{{ gotypedef .att .depth true false }}{}
 {{ recursivePublicizer .att .sourceField .targetField .depth }}`
 
-	arrayPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= make({{ gotyperef .att.Type .att.AllRequired .depth false }}, len({{ .sourceField }})){{/*
-*/}}{{ $i := printf "%s%d" "i" .depth }}{{ $elem := printf "%s%d" "elem" .depth }}
+	arrayPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= make({{ gotypedef .att .depth true false }}, len({{ .sourceField }})){{/*
+*/}}{{ $i := printf "%s%d" "i" .depth }}{{ $elem := printf "%s%d" "elem" .depth }}{{ $values := printf "%s%d" "values" .depth }}{{/*
+*/}}{{ if inlineObject .elemType }}
+{{ tabs .depth }}{{ $values }} := make([]{{ gotypedef .elemType .depth true false }}, len({{ .sourceField }})){{ end }}
 {{ tabs .depth }}for {{ $i }}, {{ $elem }} := range {{ .sourceField }} {
-{{ tabs .depth }}{{ publicizer .elemType $elem (printf "%s[%s]" .targetField $i) .dereference (add .depth 1) false }}
+{{ if inlineObject .elemType }}{{ tabs (add .depth 1) }}{{ printf "%s[%s]" .targetField $i }} = &{{ $values }}[{{ $i }}]
+{{ recursivePublicizer .elemType $elem (printf "%s[%s]" .targetField $i) (add .depth 1) }}{{/*
+*/}}{{ else }}{{ tabs .depth }}{{ publicizer .elemType $elem (printf "%s[%s]" .targetField $i) .dereference (add .depth 1) false }}{{ end }}
 {{ tabs .depth }}}`
 
-	hashPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= make({{ gotyperef .att.Type .att.AllRequired .depth false }}, len({{ .sourceField }})){{/*
-*/}}{{ $k := printf "%s%d" "k" .depth }}{{ $v := printf "%s%d" "v" .depth }}
+	hashPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= make({{ gotypedef .att .depth true false }}, len({{ .sourceField }})){{/*
+*/}}{{ $k := printf "%s%d" "k" .depth }}{{ $v := printf "%s%d" "v" .depth }}{{ $values := printf "%s%d" "values" .depth }}{{/*
+*/}}{{ if inlineObject .elemType }}
+{{ tabs .depth }}{{ $values }} := make([]{{ gotypedef .elemType .depth true false }}, 0, len({{ .sourceField }})){{ end }}
 {{ tabs .depth }}for {{ $k }}, {{ $v }} := range {{ .sourceField }} {
 {{ $pubk := printf "%s%s" "pub" $k }}{{ $pubv := printf "%s%s" "pub" $v }}{{/*
-*/}}{{ tabs (add .depth 1) }}{{ if .keyType.Type.IsObject }}var {{ $pubk }} {{ gotyperef .keyType.Type .AllRequired .depth false}}
+*/}}{{ tabs (add .depth 1) }}{{ if .keyType.Type.IsObject }}var {{ $pubk }} *{{ gotypedef .keyType .depth true false }}
 {{ tabs (add .depth 1) }}if {{ $k }} != nil {
 {{ tabs (add .depth 1) }}{{ publicizer .keyType $k $pubk .dereference (add .depth 1) false }}
 {{ tabs (add .depth 1) }}}{{ else }}{{ publicizer .keyType $k $pubk .dereference (add .depth 1) true }}{{ end }}
-{{ tabs (add .depth 1) }}{{if .elemType.Type.IsObject }}var {{ $pubv }} {{ gotyperef .elemType.Type .AllRequired .depth false }}
+{{ tabs (add .depth 1) }}{{if .elemType.Type.IsObject }}var {{ $pubv }} *{{ gotypedef .elemType (add .depth 1) true false }}
 {{ tabs (add .depth 1) }}if {{ $v }} != nil {
-{{ tabs (add .depth 1) }}{{ publicizer .elemType $v $pubv .dereference (add .depth 1) false }}
+{{ if inlineObject .elemType }}{{ tabs (add .depth 2) }}{{ $values }} = {{ $values }}[:len({{ $values }})+1]
+{{ tabs (add .depth 2) }}{{ $pubv }} = &{{ $values }}[len({{ $values }})-1]
+{{ recursivePublicizer .elemType $v $pubv (add .depth 2) }}{{/*
+*/}}{{ else }}{{ tabs (add .depth 1) }}{{ publicizer .elemType $v $pubv .dereference (add .depth 1) false }}{{ end }}
 {{ tabs (add .depth 1) }}}{{ else }}{{ publicizer .elemType $v $pubv .dereference (add .depth 1) true }}{{ end }}
 {{ tabs .depth }}	{{ printf "%s[%s]" .targetField $pubk }} = {{ $pubv }}
 {{ tabs .depth }}}`
@@ -115,6 +115,25 @@ var _ = Describe("Struct publicize code generation", func() {
 					Ω(publication).Should(Equal(arrayPublicizeCode))
 				})
 			})
+			Context("that contains inline objects", func() {
+				BeforeEach(func() {
+					att = &design.AttributeDefinition{
+						Type: &design.Array{
+							ElemType: &design.AttributeDefinition{
+								Type: design.Object{
+									"foo": &design.AttributeDefinition{Type: design.String},
+								},
+							},
+						},
+					}
+					sourceField = "source"
+					targetField = "target"
+				})
+				It("allocates the elements at once", func() {
+					publication := codegen.Publicizer(att, sourceField, targetField, false, 0, false)
+					Ω(publication).Should(Equal(arrayObjectPublicizeCode))
+				})
+			})
 		})
 		Context("given a hash field", func() {
 			Context("that contains primitive fields", func() {
@@ -171,6 +190,28 @@ var _ = Describe("Struct publicize code generation", func() {
 					Ω(publication).Should(Equal(hashPublicizeCode))
 				})
 			})
+			Context("that contains inline objects", func() {
+				BeforeEach(func() {
+					att = &design.AttributeDefinition{
+						Type: &design.Hash{
+							KeyType: &design.AttributeDefinition{
+								Type: design.String,
+							},
+							ElemType: &design.AttributeDefinition{
+								Type: design.Object{
+									"bar": &design.AttributeDefinition{Type: design.String},
+								},
+							},
+						},
+					}
+					sourceField = "source"
+					targetField = "target"
+				})
+				It("allocates the values at once", func() {
+					publication := codegen.Publicizer(att, sourceField, targetField, false, 0, false)
+					Ω(publication).Should(Equal(hashObjectPublicizeCode))
+				})
+			})
 		})
 	})
 })
@@ -208,4 +249,38 @@ for k0, v0 := range source {
 	}
 	target[pubk0] = pubv0
 }`
+
+	arrayObjectPublicizeCode = `target = make([]*struct {
+	Foo *string ` + "`" + `form:"foo,omitempty" json:"foo,omitempty" yaml:"foo,omitempty" xml:"foo,omitempty"` + "`" + `
+}, len(source))
+values0 := make([]struct {
+	Foo *string ` + "`" + `form:"foo,omitempty" json:"foo,omitempty" yaml:"foo,omitempty" xml:"foo,omitempty"` + "`" + `
+}, len(source))
+for i0, elem0 := range source {
+	target[i0] = &values0[i0]
+	if elem0.Foo != nil {
+		target[i0].Foo = elem0.Foo
+	}
+}`
+
+	hashObjectPublicizeCode = `target = make(map[string]*struct {
+	Bar *string ` + "`" + `form:"bar,omitempty" json:"bar,omitempty" yaml:"bar,omitempty" xml:"bar,omitempty"` + "`" + `
+}, len(source))
+values0 := make([]struct {
+	Bar *string ` + "`" + `form:"bar,omitempty" json:"bar,omitempty" yaml:"bar,omitempty" xml:"bar,omitempty"` + "`" + `
+}, 0, len(source))
+for k0, v0 := range source {
+		pubk0 := k0
+	var pubv0 *struct {
+		Bar *string ` + "`" + `form:"bar,omitempty" json:"bar,omitempty" yaml:"bar,omitempty" xml:"bar,omitempty"` + "`" + `
+	}
+	if v0 != nil {
+		values0 = values0[:len(values0)+1]
+		pubv0 = &values0[len(values0)-1]
+		if v0.Bar != nil {
+			pubv0.Bar = v0.Bar
+		}
+	}
+	target[pubk0] = pubv0
+}`
 )
@@ -154,16 +154,20 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 			elems = append(elems, fmt.Sprintf("%s:\"%s\"", name, value))
 		}
 	}
+	var sensitive string
+	if _, ok := att.Metadata["sensitive"]; ok {
+		sensitive = ` sensitive:"true"`
+	}
 	if len(elems) > 0 {
-		return " `" + strings.Join(elems, " ") + "`"
+		return " `" + strings.Join(elems, " ") + sensitive + "`"
 	}
 	// Default algorithm
 	var omit string
 	if private || (!parent.IsRequired(name) && !parent.HasDefaultValue(name)) {
 		omit = ",omitempty"
 	}
-	return fmt.Sprintf(" `form:\"%s%s\" json:\"%s%s\" yaml:\"%s%s\" xml:\"%s%s\"`",
-		name, omit, name, omit, name, omit, name, omit)
+	return fmt.Sprintf(" `form:\"%s%s\" json:\"%s%s\" yaml:\"%s%s\" xml:\"%s%s\"%s`",
+		name, omit, name, omit, name, omit, name, omit, sensitive)
 }
 
 // GoTypeRef returns the Go code that refers to the Go type which matches the given data type
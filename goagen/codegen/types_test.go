@@ -240,6 +240,16 @@ var _ = Describe("code generation", func() {
 					})
 				})
 
+				Context("using sensitive metadata", func() {
+					BeforeEach(func() {
+						object["bar"].Metadata = dslengine.MetadataDefinition{"sensitive": nil}
+					})
+
+					It("produces the sensitive struct tag", func() {
+						Ω(st).Should(ContainSubstring("	Bar *string `form:\"bar,omitempty\" json:\"bar,omitempty\" yaml:\"bar,omitempty\" xml:\"bar,omitempty\" sensitive:\"true\"`\n"))
+					})
+				})
+
 				Context("using struct field name metadata", func() {
 					BeforeEach(func() {
 						object["foo"].Metadata = dslengine.MetadataDefinition{
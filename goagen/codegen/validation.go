@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -18,18 +20,24 @@ var (
 	minMaxValT   *template.Template
 	lengthValT   *template.Template
 	requiredValT *template.Template
+
+	// MaxValidationErrors is the maximum number of errors reported by the generated validation
+	// code: the validations that follow are skipped once it is reached. 1 produces fail-fast
+	// validations and 0, the default, reports all the errors.
+	MaxValidationErrors int
 )
 
 //  init instantiates the templates.
 func init() {
 	var err error
 	fm := template.FuncMap{
-		"tabs":     Tabs,
-		"slice":    toSlice,
-		"oneof":    oneof,
-		"constant": constant,
-		"goifyAtt": GoifyAtt,
-		"add":      Add,
+		"tabs":       Tabs,
+		"slice":      toSlice,
+		"oneof":      oneof,
+		"constant":   constant,
+		"goifyAtt":   GoifyAtt,
+		"add":        Add,
+		"patternVar": PatternVar,
 	}
 	if enumValT, err = template.New("enum").Funcs(fm).Parse(enumValTmpl); err != nil {
 		panic(err)
@@ -119,10 +127,10 @@ func (v *Validator) arrayValCode(att *design.AttributeDefinition, nonzero, requi
 		switch a.ElemType.Type.(type) {
 		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 			// For user and media types, call the Validate method
-			val = RunTemplate(v.userValT, map[string]interface{}{
+			val = guardValidation(RunTemplate(v.userValT, map[string]interface{}{
 				"depth":  depth + 2,
 				"target": "e",
-			})
+			}), depth+2)
 			val = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), val, Tabs(depth+1))
 		}
 		data := map[string]interface{}{
@@ -164,10 +172,10 @@ func (v *Validator) hashValCode(att *design.AttributeDefinition, nonzero, requir
 		switch h.KeyType.Type.(type) {
 		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 			// For user and media types, call the Validate method
-			keyVal = RunTemplate(v.userValT, map[string]interface{}{
+			keyVal = guardValidation(RunTemplate(v.userValT, map[string]interface{}{
 				"depth":  depth + 2,
 				"target": "k",
-			})
+			}), depth+2)
 			keyVal = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), keyVal, Tabs(depth+1))
 		}
 	}
@@ -176,10 +184,10 @@ func (v *Validator) hashValCode(att *design.AttributeDefinition, nonzero, requir
 		switch h.ElemType.Type.(type) {
 		case *design.UserTypeDefinition, *design.MediaTypeDefinition:
 			// For user and media types, call the Validate method
-			elemVal = RunTemplate(v.userValT, map[string]interface{}{
+			elemVal = guardValidation(RunTemplate(v.userValT, map[string]interface{}{
 				"depth":  depth + 2,
 				"target": "e",
-			})
+			}), depth+2)
 			elemVal = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), elemVal, Tabs(depth+1))
 		}
 	}
@@ -290,10 +298,10 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 			return nil
 		})
 		if hasValidations {
-			validation = RunTemplate(v.userValT, map[string]interface{}{
+			validation = guardValidation(RunTemplate(v.userValT, map[string]interface{}{
 				"depth":  depth,
 				"target": fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
-			})
+			}), depth)
 		}
 	} else {
 		dp := depth
@@ -320,12 +328,65 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 	return validation
 }
 
+// PatternVar returns the name of the package-level variable that holds the compiled regular
+// expression p in the generated code. The name only depends on p so that all the validations that
+// use the same pattern share the same variable, see Patterns.
+func PatternVar(p string) string {
+	h := fnv.New32a()
+	h.Write([]byte(p))
+	return fmt.Sprintf("pattern%08x", h.Sum32())
+}
+
+// Patterns returns the sorted distinct regular expressions used by the pattern validations of the
+// API types, media types, action parameters, headers and payloads. The package that contains the
+// code produced by ValidationChecker must declare a variable named after PatternVar for each of
+// them.
+func Patterns(api *design.APIDefinition) []string {
+	seen := make(map[string]bool)
+	collect := func(att *design.AttributeDefinition) error {
+		if att.Validation != nil && att.Validation.Pattern != "" {
+			seen[att.Validation.Pattern] = true
+		}
+		return nil
+	}
+	walk := func(att *design.AttributeDefinition) {
+		if att != nil {
+			att.Walk(collect)
+		}
+	}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		return ut.Walk(collect)
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		return mt.Walk(collect)
+	})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		walk(r.Headers)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			walk(a.AllParams())
+			walk(a.Headers)
+			if a.Payload != nil {
+				a.Payload.Walk(collect)
+			}
+			return nil
+		})
+	})
+	patterns := make([]string, 0, len(seen))
+	for p := range seen {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
 // ValidationChecker produces Go code that runs the validation defined in the given attribute
 // definition against the content of the variable named target recursively.
 // context is used to keep track of recursion to produce helpful error messages in case of type
 // validation error.
 // The generated code assumes that there is a pre-existing "err" variable of type
-// error. It initializes that variable in case a validation fails.
+// error. It initializes that variable in case a validation fails. The pattern validations use
+// the package-level variables named by PatternVar to avoid compiling the regular expressions on
+// each call.
 // Note: we do not want to recurse here, recursion is done by the marshaler/unmarshaler code.
 func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
 	if att.Validation == nil {
@@ -354,6 +415,12 @@ func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDe
 }
 
 func validationsCode(att *design.AttributeDefinition, data map[string]interface{}) (res []string) {
+	depth := data["depth"].(int)
+	defer func() {
+		for i, val := range res {
+			res[i] = guardValidation(val, depth)
+		}
+	}()
 	validation := att.Validation
 	if values := validation.Values; values != nil {
 		data["values"] = values
@@ -414,19 +481,27 @@ func validationsCode(att *design.AttributeDefinition, data map[string]interface{
 		}
 	}
 	if required := validation.Required; len(required) > 0 {
-		var val string
-		for i, r := range required {
-			if i > 0 {
-				val += "\n"
-			}
+		for _, r := range required {
 			data["required"] = r
-			val += RunTemplate(requiredValT, data)
+			res = append(res, RunTemplate(requiredValT, data))
 		}
-		res = append(res, val)
 	}
 	return
 }
 
+// guardValidation wraps the validation code so that it only runs while the number of errors
+// reported so far is below MaxValidationErrors.
+func guardValidation(code string, depth int) string {
+	if MaxValidationErrors <= 0 || code == "" {
+		return code
+	}
+	cond := fmt.Sprintf("goa.ErrorCount(err) < %d", MaxValidationErrors)
+	if MaxValidationErrors == 1 {
+		cond = "err == nil"
+	}
+	return fmt.Sprintf("%sif %s {\n%s\n%s}", Tabs(depth), cond, code, Tabs(depth))
+}
+
 // renderInteger renders a max or min value properly, taking into account
 // overflows due to casting from a float value.
 func renderInteger(f float64) string {
@@ -504,7 +579,7 @@ const (
 
 	patternValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
-{{ end }}{{ tabs $depth }}if ok := goa.ValidatePattern(` + "`{{ .pattern }}`" + `, {{ .targetVal }}); !ok {
+{{ end }}{{ tabs $depth }}if ok := {{ patternVar .pattern }}.MatchString({{ .targetVal }}); !ok {
 {{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, ` + "`{{ .pattern }}`" + `))
 {{ tabs $depth }}}{{ if .isPointer }}
 {{ tabs .depth }}}{{ end }}`
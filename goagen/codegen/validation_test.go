@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	. "github.com/onsi/ginkgo"
@@ -351,6 +352,93 @@ var _ = Describe("validation code generation", func() {
 			})
 		})
 	})
+
+	Describe("MaxValidationErrors", func() {
+		var max int
+		var code string
+
+		BeforeEach(func() {
+			max = 1
+		})
+
+		JustBeforeEach(func() {
+			codegen.MaxValidationErrors = max
+			defer func() { codegen.MaxValidationErrors = 0 }()
+			minLength := 2
+			att := &design.AttributeDefinition{
+				Type: design.Object{
+					"name": &design.AttributeDefinition{
+						Type:       design.String,
+						Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+			}
+			code = codegen.NewValidator().Code(att, false, false, false, "val", "context", 1, true)
+		})
+
+		It("stops validating at the first error", func() {
+			Ω(code).Should(Equal(failFastValCode))
+		})
+
+		Context("greater than 1", func() {
+			BeforeEach(func() {
+				max = 10
+			})
+
+			It("stops validating once the maximum number of errors is reached", func() {
+				Ω(strings.Count(code, "if goa.ErrorCount(err) < 10 {")).Should(Equal(2))
+			})
+		})
+	})
+
+	Describe("Patterns", func() {
+		var patterns []string
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test", nil)
+			bottle := apidsl.Type("Bottle", func() {
+				apidsl.Attribute("name", design.String, func() {
+					apidsl.Pattern("^[a-z]+$")
+				})
+				apidsl.Attribute("tags", apidsl.HashOf(design.String, design.String, func() {
+					apidsl.Pattern("^tag")
+				}))
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Headers(func() {
+					apidsl.Header("X-Account", design.String, func() {
+						apidsl.Pattern("^[0-9]+$")
+					})
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.String, func() {
+							apidsl.Pattern("^[a-z]+$")
+						})
+					})
+					apidsl.Payload(bottle)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			patterns = codegen.Patterns(design.Design)
+		})
+
+		It("returns the sorted distinct patterns", func() {
+			Ω(patterns).Should(Equal([]string{"^[0-9]+$", "^[a-z]+$", "^tag"}))
+		})
+
+		It("names the pattern variables after the patterns", func() {
+			Ω(codegen.PatternVar(".*")).Should(Equal("pattern9fd4a0c1"))
+			Ω(codegen.PatternVar("^[a-z]+$")).ShouldNot(Equal(codegen.PatternVar("^[0-9]+$")))
+		})
+	})
 })
 
 const (
@@ -361,7 +449,7 @@ const (
 	}`
 
 	patternValCode = `	if val != nil {
-		if ok := goa.ValidatePattern(` + "`.*`" + `, *val); !ok {
+		if ok := pattern9fd4a0c1.MatchString(*val); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`context`" + `, *val, ` + "`.*`" + `))
 		}
 	}`
@@ -391,28 +479,28 @@ const (
 	}`
 
 	arrayElementsValCode = `	for _, e := range val {
-		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, e); !ok {
+		if ok := pattern9fd4a0c1.MatchString(e); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, e, ` + "`" + `.*` + "`" + `))
 		}
 	}`
 
 	hashKeyElemValCode = `	for k, e := range val {
-		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, k); !ok {
+		if ok := pattern9fd4a0c1.MatchString(k); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, k, ` + "`" + `.*` + "`" + `))
 		}
-		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, e); !ok {
+		if ok := pattern9fd4a0c1.MatchString(e); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, e, ` + "`" + `.*` + "`" + `))
 		}
 	}`
 
 	hashKeyValCode = `	for k, _ := range val {
-		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, k); !ok {
+		if ok := pattern9fd4a0c1.MatchString(k); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, k, ` + "`" + `.*` + "`" + `))
 		}
 	}`
 
 	hashElemValCode = `	for _, e := range val {
-		if ok := goa.ValidatePattern(` + "`" + `.*` + "`" + `, e); !ok {
+		if ok := pattern9fd4a0c1.MatchString(e); !ok {
 			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `context[*]` + "`" + `, e, ` + "`" + `.*` + "`" + `))
 		}
 	}`
@@ -469,4 +557,17 @@ const (
 			}
 		}
 	}`
+
+	failFastValCode = `	if err == nil {
+	if val.Name == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `context` + "`" + `, "name"))
+	}
+	}
+	if err == nil {
+	if val.Name != nil {
+		if utf8.RuneCountInString(*val.Name) < 2 {
+			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context.name` + "`" + `, *val.Name, utf8.RuneCountInString(*val.Name), 2, true))
+		}
+	}
+	}`
 )
@@ -51,6 +51,11 @@ type (
 )
 
 var (
+	// DesignHash is the hash of the design package sources recorded in the headers of the
+	// generated files. goagen sets it prior to running the generators, the headers do not
+	// record a hash when it is empty.
+	DesignHash string
+
 	// Template used to render Go source file headers.
 	headerTmpl = template.Must(template.New("header").Funcs(DefaultFuncMap).Parse(headerT))
 
@@ -239,6 +244,7 @@ func (f *SourceFile) WriteHeader(title, pack string, imports []*ImportSpec) erro
 		"ToolVersion": version.String(),
 		"Pkg":         pack,
 		"Imports":     imports,
+		"DesignHash":  DesignHash,
 	}
 	if err := headerTmpl.Execute(f, ctx); err != nil {
 		return fmt.Errorf("failed to generate contexts: %s", err)
@@ -368,6 +374,10 @@ func PackageName(path string) (string, error) {
 	return pkgNames[0], nil
 }
 
+// DesignHashComment is the prefix of the header line that records the design hash in the generated
+// files.
+const DesignHashComment = "// Design hash: "
+
 const (
 	headerT = `{{if .Title}}// Code generated by goagen {{.ToolVersion}}, DO NOT EDIT.
 //
@@ -375,7 +385,9 @@ const (
 //
 // Command:
 {{comment commandLine}}
-
+{{if .DesignHash}}//
+` + DesignHashComment + `{{.DesignHash}}
+{{end}}
 {{end}}package {{.Pkg}}
 
 {{if .Imports}}import {{if gt (len .Imports) 1}}(
@@ -0,0 +1,240 @@
+package genapigateway
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+const (
+	// IntegrationExtension is the name of the extension that describes how API Gateway forwards
+	// the requests made to an operation.
+	IntegrationExtension = "x-amazon-apigateway-integration"
+
+	// AuthTypeExtension is the name of the extension that describes the kind of authorizer of a
+	// security definition.
+	AuthTypeExtension = "x-amazon-apigateway-authtype"
+
+	// AuthorizerExtension is the name of the extension that describes the authorizer of a
+	// security definition.
+	AuthorizerExtension = "x-amazon-apigateway-authorizer"
+)
+
+// New creates the Swagger 2.0 specification of the given API annotated with the API Gateway
+// extensions. The operations proxy the requests to the service located at the given base URL,
+// e.g. "https://cellar.example.com".
+func New(api *design.APIDefinition, baseURL string) (*genswagger.Swagger, error) {
+	s, err := genswagger.New(api)
+	if err != nil {
+		return nil, err
+	}
+	if err := annotateOperations(api, s, strings.TrimSuffix(baseURL, "/")); err != nil {
+		return nil, err
+	}
+	if err := annotateSecurity(api, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// annotateOperations adds an HTTP proxy integration to each operation of the specification. The
+// path keys of the routes that end with a wildcard use the API Gateway greedy path variables so
+// that the wildcard matches multiple segments. The integrations defined explicitly in the design
+// with the "swagger:extension:x-amazon-apigateway-integration" metadata are left untouched.
+func annotateOperations(api *design.APIDefinition, s *genswagger.Swagger, baseURL string) error {
+	prefix := pathKey(s.BasePath, "")
+	if prefix == "/" {
+		prefix = ""
+	}
+	greedy := make(map[string]string)
+	err := api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			for _, route := range a.Routes {
+				key := pathKey(route.FullPath(), s.BasePath)
+				p, ok := s.Paths[key].(*genswagger.Path)
+				if !ok {
+					continue
+				}
+				op := operation(p, route.Verb)
+				if op == nil {
+					continue
+				}
+				if _, ok := op.Extensions[IntegrationExtension]; ok {
+					continue
+				}
+				uri := baseURL + prefix + key
+				if prefix != "" && key == "/" {
+					uri = baseURL + prefix
+				}
+				params := make(map[string]interface{})
+				for _, param := range append(p.Parameters, op.Parameters...) {
+					if param.In == "path" {
+						params["integration.request.path."+param.Name] = "method.request.path." + param.Name
+					}
+				}
+				integration := map[string]interface{}{
+					"type":                "http_proxy",
+					"httpMethod":          route.Verb,
+					"uri":                 uri,
+					"passthroughBehavior": "when_no_match",
+				}
+				if len(params) > 0 {
+					integration["requestParameters"] = params
+				}
+				if op.Extensions == nil {
+					op.Extensions = make(map[string]interface{})
+				}
+				op.Extensions[IntegrationExtension] = integration
+				if m := design.WildcardRegex.FindAllStringSubmatch(route.FullPath(), -1); len(m) > 0 {
+					last := m[len(m)-1]
+					if strings.HasPrefix(last[0], "/*") && strings.HasSuffix(key, "/{"+last[1]+"}") {
+						greedy[key] = strings.TrimSuffix(key, "}") + "+}"
+					}
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	for key, gkey := range greedy {
+		s.Paths[gkey] = s.Paths[key]
+		delete(s.Paths, key)
+	}
+	return nil
+}
+
+// annotateSecurity adds the API Gateway authorizers to the security definitions of the
+// specification. The authorizers are configured with the security scheme metadata:
+//
+//   - "apigateway:authorizer:uri" is the URI of the Lambda function that authorizes the
+//     requests.
+//   - "apigateway:authorizer:credentials" is the ARN of the IAM role API Gateway assumes to
+//     invoke the Lambda authorizer.
+//   - "apigateway:authorizer:ttl" is the number of seconds API Gateway caches the Lambda
+//     authorizer results.
+//   - "apigateway:authorizer:arns" lists the ARNs of the Amazon Cognito user pools that issue
+//     the OAuth2 and JWT tokens.
+//
+// The API key schemes that use the "x-api-key" header need no authorizer, API Gateway validates
+// the keys itself. The other schemes are left untouched.
+func annotateSecurity(api *design.APIDefinition, s *genswagger.Swagger) error {
+	for _, scheme := range api.SecuritySchemes {
+		def, ok := s.SecurityDefinitions[scheme.SchemeName]
+		if !ok {
+			continue
+		}
+		if _, ok := def.Extensions[AuthorizerExtension]; ok {
+			continue
+		}
+		uri := metadata(scheme, "uri")
+		arns := scheme.Metadata["apigateway:authorizer:arns"]
+		var authType string
+		var authorizer map[string]interface{}
+		switch {
+		case uri != "":
+			authType = "custom"
+			authorizer = map[string]interface{}{
+				"type":                         "token",
+				"authorizerUri":                uri,
+				"identitySource":               "method.request.header.Authorization",
+				"authorizerResultTtlInSeconds": 300,
+			}
+			if scheme.Kind == design.APIKeySecurityKind {
+				if scheme.In == "query" {
+					authorizer["type"] = "request"
+					authorizer["identitySource"] = "method.request.querystring." + scheme.Name
+				} else {
+					authorizer["identitySource"] = "method.request.header." + scheme.Name
+				}
+			}
+			if creds := metadata(scheme, "credentials"); creds != "" {
+				authorizer["authorizerCredentials"] = creds
+			}
+			if ttl := metadata(scheme, "ttl"); ttl != "" {
+				secs, err := strconv.Atoi(ttl)
+				if err != nil {
+					return fmt.Errorf("invalid apigateway:authorizer:ttl metadata value %q of security scheme %s, must be a number of seconds", ttl, scheme.SchemeName)
+				}
+				authorizer["authorizerResultTtlInSeconds"] = secs
+			}
+		case len(arns) > 0:
+			if scheme.Kind != design.OAuth2SecurityKind && scheme.Kind != design.JWTSecurityKind {
+				return fmt.Errorf("security scheme %s: Amazon Cognito user pools authorize OAuth2 and JWT schemes only", scheme.SchemeName)
+			}
+			authType = "cognito_user_pools"
+			authorizer = map[string]interface{}{
+				"type":         "cognito_user_pools",
+				"providerARNs": arns,
+			}
+			// API Gateway reads the Cognito tokens from an API key like definition.
+			*def = genswagger.SecurityDefinition{
+				Type:        "apiKey",
+				Description: def.Description,
+				Name:        "Authorization",
+				In:          "header",
+				Extensions:  def.Extensions,
+			}
+		default:
+			continue
+		}
+		if def.Extensions == nil {
+			def.Extensions = make(map[string]interface{})
+		}
+		def.Extensions[AuthTypeExtension] = authType
+		def.Extensions[AuthorizerExtension] = authorizer
+	}
+	return nil
+}
+
+// operation returns the operation of p corresponding to the given HTTP method, nil if there is
+// none.
+func operation(p *genswagger.Path, verb string) *genswagger.Operation {
+	switch verb {
+	case "GET":
+		return p.Get
+	case "PUT":
+		return p.Put
+	case "POST":
+		return p.Post
+	case "DELETE":
+		return p.Delete
+	case "OPTIONS":
+		return p.Options
+	case "HEAD":
+		return p.Head
+	case "PATCH":
+		return p.Patch
+	}
+	return nil
+}
+
+// pathKey returns the key of the given route path in the specification paths: the wildcards use
+// the Swagger syntax and the base path is trimmed.
+func pathKey(path, basePath string) string {
+	toSwagger := func(p string) string {
+		return design.WildcardRegex.ReplaceAllStringFunc(p, func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		})
+	}
+	key := toSwagger(path)
+	if bp := toSwagger(basePath); bp != "" && bp != "/" {
+		key = strings.TrimPrefix(key, bp)
+	}
+	if key == "" {
+		key = "/"
+	}
+	return key
+}
+
+// metadata returns the value of the "apigateway:authorizer:<name>" metadata of the scheme if any.
+func metadata(scheme *design.SecuritySchemeDefinition, name string) string {
+	if vals := scheme.Metadata["apigateway:authorizer:"+name]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
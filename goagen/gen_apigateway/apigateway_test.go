@@ -0,0 +1,129 @@
+package genapigateway_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_apigateway"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var spec *genswagger.Swagger
+	var newErr error
+
+	BeforeEach(func() {
+		spec = nil
+		newErr = nil
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		spec, newErr = genapigateway.New(Design, "https://cellar.example.com/")
+	})
+
+	Context("with a valid API definition", func() {
+		BeforeEach(func() {
+			jwt := JWTSecurity("jwt", func() {
+				Header("Authorization")
+				Metadata("apigateway:authorizer:arns", "arn:aws:cognito-idp:us-east-1:123456789012:userpool/us-east-1_ABC")
+			})
+			key := APIKeySecurity("key", func() {
+				Query("k")
+				Metadata("apigateway:authorizer:uri", "arn:aws:apigateway:us-east-1:lambda:path/auth")
+				Metadata("apigateway:authorizer:ttl", "60")
+			})
+			API("test", func() {
+				Host("goa.design")
+				Scheme("https")
+				BasePath("/api")
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Security(jwt)
+				Action("list", func() {
+					Routing(GET(""))
+					Response(OK)
+				})
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Security(key)
+					Response(OK)
+				})
+				Action("files", func() {
+					Routing(GET("/files/*path"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("proxies the requests to the service", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			list := spec.Paths["/bottles"].(*genswagger.Path).Get
+			Ω(list.Extensions).Should(HaveKeyWithValue(genapigateway.IntegrationExtension, map[string]interface{}{
+				"type":                "http_proxy",
+				"httpMethod":          "GET",
+				"uri":                 "https://cellar.example.com/api/bottles",
+				"passthroughBehavior": "when_no_match",
+			}))
+			show := spec.Paths["/bottles/{id}"].(*genswagger.Path).Get
+			Ω(show.Extensions).Should(HaveKeyWithValue(genapigateway.IntegrationExtension, map[string]interface{}{
+				"type":                "http_proxy",
+				"httpMethod":          "GET",
+				"uri":                 "https://cellar.example.com/api/bottles/{id}",
+				"passthroughBehavior": "when_no_match",
+				"requestParameters": map[string]interface{}{
+					"integration.request.path.id": "method.request.path.id",
+				},
+			}))
+		})
+
+		It("uses greedy path variables for wildcards", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(spec.Paths).ShouldNot(HaveKey("/bottles/files/{path}"))
+			Ω(spec.Paths).Should(HaveKey("/bottles/files/{path+}"))
+		})
+
+		It("defines the authorizers", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			jwt := spec.SecurityDefinitions["jwt"]
+			Ω(jwt.Type).Should(Equal("apiKey"))
+			Ω(jwt.Name).Should(Equal("Authorization"))
+			Ω(jwt.Extensions).Should(HaveKeyWithValue(genapigateway.AuthTypeExtension, "cognito_user_pools"))
+			Ω(jwt.Extensions).Should(HaveKeyWithValue(genapigateway.AuthorizerExtension, map[string]interface{}{
+				"type":         "cognito_user_pools",
+				"providerARNs": []string{"arn:aws:cognito-idp:us-east-1:123456789012:userpool/us-east-1_ABC"},
+			}))
+			key := spec.SecurityDefinitions["key"]
+			Ω(key.Extensions).Should(HaveKeyWithValue(genapigateway.AuthTypeExtension, "custom"))
+			Ω(key.Extensions).Should(HaveKeyWithValue(genapigateway.AuthorizerExtension, map[string]interface{}{
+				"type":                         "request",
+				"authorizerUri":                "arn:aws:apigateway:us-east-1:lambda:path/auth",
+				"identitySource":               "method.request.querystring.k",
+				"authorizerResultTtlInSeconds": 60,
+			}))
+		})
+	})
+
+	Context("with a Cognito authorizer on a basic auth scheme", func() {
+		BeforeEach(func() {
+			basic := BasicAuthSecurity("basic", func() {
+				Metadata("apigateway:authorizer:arns", "arn:aws:cognito-idp:us-east-1:123456789012:userpool/us-east-1_ABC")
+			})
+			API("test", func() {
+				Host("goa.design")
+				Security(basic)
+			})
+		})
+
+		It("returns an error", func() {
+			Ω(newErr).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,32 @@
+/*
+Package genapigateway generates the Swagger 2.0 specification of a goa API annotated with the
+Amazon API Gateway extensions so that the API can be imported into API Gateway directly, see
+https://docs.aws.amazon.com/apigateway/latest/developerguide/api-gateway-swagger-extensions.html.
+
+The generator produces the files apigateway/swagger.json and apigateway/swagger.yaml. Each operation
+has a x-amazon-apigateway-integration extension that proxies the requests to the service, the
+service URL is built from the --scheme and --host flags which default to the API scheme (HTTPS if
+available) and host. The path parameters are forwarded explicitly and the routes that end with a
+wildcard use greedy path variables. An operation integration may be overridden with the route
+"swagger:extension:x-amazon-apigateway-integration" metadata.
+
+The security schemes map to API Gateway authorizers configured with metadata:
+
+	var JWT = JWTSecurity("jwt", func() {
+		Header("Authorization")
+		// Amazon Cognito user pools authorizer, OAuth2 and JWT schemes only
+		Metadata("apigateway:authorizer:arns", "arn:aws:cognito-idp:us-east-1:123456789012:userpool/us-east-1_ABC")
+	})
+
+	var Key = APIKeySecurity("key", func() {
+		Header("X-Shared-Secret")
+		// Lambda authorizer
+		Metadata("apigateway:authorizer:uri", "arn:aws:apigateway:us-east-1:lambda:path/2015-03-31/functions/arn:aws:lambda:us-east-1:123456789012:function:auth/invocations")
+		Metadata("apigateway:authorizer:credentials", "arn:aws:iam::123456789012:role/apigateway") // Optional
+		Metadata("apigateway:authorizer:ttl", "60")                                              // Defaults to 300
+	})
+
+API key schemes that use the "x-api-key" header are validated by API Gateway natively and need no
+authorizer.
+*/
+package genapigateway
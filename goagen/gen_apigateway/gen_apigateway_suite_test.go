@@ -0,0 +1,13 @@
+package genapigateway_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenAPIGateway(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenAPIGateway Suite")
+}
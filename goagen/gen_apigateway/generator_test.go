@@ -0,0 +1,117 @@
+package genapigateway_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_apigateway"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("apigatewaytest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genapigateway.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an API host", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Host("goa.design")
+				apidsl.Scheme("http", "https")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/bottles/:id"))
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the specification proxying to the HTTPS endpoint", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "apigateway", "swagger.yaml"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("x-amazon-apigateway-integration:"))
+			Ω(string(content)).Should(ContainSubstring("uri: https://goa.design/bottles/{id}"))
+		})
+	})
+
+	Context("with no host", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genapigateway.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+		scheme string
+		host   string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+		scheme: "https",
+		host:   "cellar.example.com",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genapigateway.NewGenerator(
+				genapigateway.API(args.api),
+				genapigateway.OutDir(args.outDir),
+				genapigateway.Scheme(args.scheme),
+				genapigateway.Host(args.host),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Scheme).Should(Equal(args.scheme))
+			Ω(generator.Host).Should(Equal(args.host))
+		})
+	})
+})
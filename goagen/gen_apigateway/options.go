@@ -0,0 +1,34 @@
+package genapigateway
+
+import "github.com/goadesign/goa/design"
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+//Scheme Scheme used by API Gateway to proxy the requests to the service
+func Scheme(scheme string) Option {
+	return func(g *Generator) {
+		g.Scheme = scheme
+	}
+}
+
+//Host of the service API Gateway proxies the requests to
+func Host(host string) Option {
+	return func(g *Generator) {
+		g.Host = host
+	}
+}
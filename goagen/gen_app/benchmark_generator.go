@@ -0,0 +1,87 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateBenchmarks generates benchmarks that measure decoding the user types from JSON followed by
+// the Publicize transform applied to request payloads and encoding the media type views to JSON.
+// The benchmarks use the same goa JSON decoder and encoder as the service.
+func (g *Generator) generateBenchmarks() (err error) {
+	if g.API.NoExamples {
+		return nil
+	}
+	benchmarks := g.typeExamples()
+	if len(benchmarks) == 0 {
+		return nil
+	}
+
+	filename := filepath.Join(g.OutDir, "benchmark_test.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Types Benchmarks", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("testing"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	if err = file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	tmpl := template.Must(template.New("benchmark").Parse(benchmarkT))
+	for _, data := range benchmarks {
+		if err = tmpl.Execute(file, data); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// benchmarkT generates the benchmark of a type. User types are benchmarked on the request path
+// (decode and publicize) and media types on the response path (encode).
+// template input: *RoundTripTestData
+const benchmarkT = `{{ if .Publicize }}
+// Benchmark{{ .Name }}Decode measures decoding {{ .Name }} values from JSON and publicizing them.
+func Benchmark{{ .Name }}Decode(b *testing.B) {
+	data := []byte({{ printf "%q" .JSON }})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v {{ .TypeName }}
+		if err := goa.NewJSONDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+			b.Fatal(err)
+		}
+		v.Publicize()
+	}
+}
+{{ else }}
+// Benchmark{{ .Name }}Encode measures encoding {{ .Name }} values to JSON.
+func Benchmark{{ .Name }}Encode(b *testing.B) {
+	var v {{ .TypeName }}
+	if err := goa.NewJSONDecoder(bytes.NewReader([]byte({{ printf "%q" .JSON }}))).Decode(&v); err != nil {
+		b.Fatal(err)
+	}
+	enc := goa.NewJSONEncoder(ioutil.Discard)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := enc.Encode(v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+{{ end }}`
@@ -1,5 +1,54 @@
 /*
 Package genapp provides the generator for the handlers, context data structures and tests of a goa
 application. It generates the glue between user code and the low level router.
+
+The DesignVersion and DesignHash constants record the version of the API design and the hash of the
+design package sources the package was generated from. DesignVersionMiddleware reports them in the
+X-Design-Version response header so that running services can tell which contract they implement.
+
+The regular expressions of the pattern validations are compiled once into the package-level
+variables declared in patterns.go, the validation code of the contexts, media types and user types
+references them instead of compiling the expressions on each call.
+
+By default the validation code reports all the errors. The "goagen:validation:fail-fast" and
+"goagen:validation:max-errors" API metadata make it stop at the first error or once the given number
+of errors is reached, which bounds the cost of validating large invalid payloads.
+
+Media types with multiple views get a ProjectX method per view X that builds the view from the
+default view. The methods only copy the attributes rendered by the view and project nested media
+types and collections recursively rather than copying them whole. Views that render attributes
+missing from the default view have no such method.
+
+The media types that define the "goagen:encoding:marshal-json" metadata, or all of them if the API
+defines it, get MarshalJSON and AppendJSON methods that produce the same output as encoding/json
+without reflection. Attributes of type Any and user types are still encoded with encoding/json.
+The "goagen:encoding:parallel-threshold" metadata makes the collections of these media types encode
+their elements concurrently once they reach the given size.
+
+The actions or APIs that define the "goagen:payload:pool" metadata get their payload structs from
+sync.Pool instances: the handler releases the payload once the action returns, which removes the
+payload allocations from the request path.
+
+Responses whose media type is not defined in the design, for example file downloads, get an XStream
+helper that copies the body from a reader and, for status 200, an XContent helper that serves the
+body with http.ServeContent. Both avoid loading the content in memory and let the server use
+sendfile when the body is a file.
+
+The test package contains helpers that run the controller actions directly as well as a harness
+per resource that serves the actions on an httptest server backed by the mock controllers of the
+mocks package. The harnesses expose a typed method per action response that sends the request and
+decodes the response body. The test package also contains random value generators for the user
+types and action payloads: GenerateX returns an instance of X that satisfies the design validations
+and GenerateInvalidX an instance that violates a single validation rule. The generators describe the
+types with goatest schemas and are meant for property-based tests of the service implementation.
+
+Unless test generation is disabled the generator also produces table-driven tests for the Validate
+methods of the user types. Each test decodes a valid example of the type followed by variations that
+violate a single validation rule so that regressions in the generated validation code are caught
+after regeneration. Round-trip tests complete these: they decode an example of each user type and
+media type view, encode the result back to JSON and check that it matches the example. User type
+examples go through the Publicize transform used for request payloads. The same examples drive
+generated benchmarks that measure decoding and publicizing the user types and encoding the media
+types so that performance regressions in the generated code are measurable.
 */
 package genapp
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
@@ -29,9 +31,13 @@ type Generator struct {
 	API       *design.APIDefinition // The API definition
 	OutDir    string                // Path to output directory
 	Target    string                // Name of generated package
-	NoTest    bool                  // Whether to skip test generation
+	NoTest    bool                  // Whether to skip test helpers and mocks generation
 	genfiles  []string              // Generated files
 	validator *codegen.Validator    // Validation code generator
+
+	// MaxValidationErrors is the maximum number of errors reported by the generated validation
+	// code, 1 for fail-fast validations. It defaults to the value set in the API metadata.
+	MaxValidationErrors int
 }
 
 // Generate is the generator entry point called by the meta generator.
@@ -51,14 +57,16 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&notool, "notool", false, "")
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("force", false, "")
+	set.Bool("debug-listener", false, "")
 	set.Parse(os.Args[1:])
-	outDir = filepath.Join(outDir, target)
+	dir := codegen.OutputDir(design.Design, "app", target)
+	outDir = filepath.Join(outDir, dir)
 
 	if err := codegen.CheckVersion(ver); err != nil {
 		return nil, err
 	}
 
-	target = codegen.Goify(target, false)
+	target = codegen.Goify(filepath.Base(dir), false)
 	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, API: design.Design, validator: codegen.NewValidator()}
 
 	return g.Generate()
@@ -80,6 +88,15 @@ func (g *Generator) Generate() (_ []string, err error) {
 
 	codegen.Reserved[g.Target] = true
 
+	maxErrors := g.MaxValidationErrors
+	if maxErrors == 0 {
+		if maxErrors, err = maxValidationErrors(g.API); err != nil {
+			return nil, err
+		}
+	}
+	codegen.MaxValidationErrors = maxErrors
+	defer func() { codegen.MaxValidationErrors = 0 }()
+
 	os.RemoveAll(g.OutDir)
 
 	if err := os.MkdirAll(g.OutDir, 0755); err != nil {
@@ -95,6 +112,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateSecurity(); err != nil {
 		return nil, err
 	}
+	if err := g.generateWebhooks(); err != nil {
+		return nil, err
+	}
 	if err := g.generateHrefs(); err != nil {
 		return nil, err
 	}
@@ -104,15 +124,59 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateUserTypes(); err != nil {
 		return nil, err
 	}
+	if err := g.generateVersion(); err != nil {
+		return nil, err
+	}
+	if err := g.generatePatterns(); err != nil {
+		return nil, err
+	}
 	if !g.NoTest {
 		if err := g.generateResourceTest(); err != nil {
 			return nil, err
 		}
+		if err := g.generateMocks(); err != nil {
+			return nil, err
+		}
+		if err := g.generateHarnesses(); err != nil {
+			return nil, err
+		}
+		if err := g.generateValidationTests(); err != nil {
+			return nil, err
+		}
+		if err := g.generateRoundTripTests(); err != nil {
+			return nil, err
+		}
+		if err := g.generateBenchmarks(); err != nil {
+			return nil, err
+		}
+		if err := g.generatePayloadGenerators(); err != nil {
+			return nil, err
+		}
 	}
 
 	return g.genfiles, nil
 }
 
+// maxValidationErrors returns the maximum number of errors reported by the validation code set in
+// the API metadata, 0 if there is no limit:
+//
+//	Metadata("goagen:validation:fail-fast")        // stop at the first error
+//	Metadata("goagen:validation:max-errors", "10") // stop once 10 errors are reported
+func maxValidationErrors(api *design.APIDefinition) (int, error) {
+	if _, ok := api.Metadata["goagen:validation:fail-fast"]; ok {
+		return 1, nil
+	}
+	vals, ok := api.Metadata["goagen:validation:max-errors"]
+	if !ok || len(vals) == 0 {
+		return 0, nil
+	}
+	max, err := strconv.Atoi(vals[0])
+	if err != nil || max < 0 {
+		return 0, fmt.Errorf(`invalid "goagen:validation:max-errors" metadata value %q, must be a positive integer`, vals[0])
+	}
+	return max, nil
+}
+
 // Cleanup removes the entire "app" directory if it was created by this generator.
 func (g *Generator) Cleanup() {
 	if len(g.genfiles) == 0 {
@@ -145,6 +209,7 @@ func (g *Generator) generateContexts() (err error) {
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
@@ -207,6 +272,10 @@ func (g *Generator) generateContexts() (err error) {
 				API:          g.API,
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
+				Subscription: a.Subscription,
+			}
+			if err := codegen.Dump("app/contexts/"+ctxName, &ctxData); err != nil {
+				return err
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -244,8 +313,23 @@ func (g *Generator) generateControllers() (err error) {
 		codegen.SimpleImport("regexp"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
+	var pooled bool
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil && a.Payload.IsArray() {
+				// Array payload elements are decoded and validated by the unmarshal functions.
+				imports = codegen.AttributeImports(a.Payload.AttributeDefinition, imports, nil)
+			}
+			pooled = pooled || g.poolPayload(a)
+			return nil
+		})
+	})
+	if pooled {
+		imports = append(imports, codegen.SimpleImport("sync"))
+	}
 	encoders, err := BuildEncoders(g.API.Produces, true)
 	if err != nil {
 		return err
@@ -307,6 +391,11 @@ func (g *Generator) generateControllers() (err error) {
 		r.IterateActions(func(a *design.ActionDefinition) error {
 			context := fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			unmarshal := fmt.Sprintf("unmarshal%s%sPayload", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
+			var pool, rawPool string
+			if g.poolPayload(a) {
+				pool = fmt.Sprintf("%s%sPayloadPool", codegen.Goify(a.Name, false), codegen.Goify(r.Name, true))
+				rawPool = fmt.Sprintf("%s%sRawPayloadPool", codegen.Goify(a.Name, false), codegen.Goify(r.Name, true))
+			}
 			action := map[string]interface{}{
 				"Name":             codegen.Goify(a.Name, true),
 				"DesignName":       a.Name,
@@ -316,7 +405,13 @@ func (g *Generator) generateControllers() (err error) {
 				"Payload":          a.Payload,
 				"PayloadOptional":  a.PayloadOptional,
 				"PayloadMultipart": a.PayloadMultipart,
+				"PayloadPool":      pool,
+				"RawPayloadPool":   rawPool,
 				"Security":         a.Security,
+				"Timeout":          durationLiteral(a.Timeout),
+				"CSRF":             a.CSRF,
+				"Audit":            a.Audit,
+				"AuditIdentifiers": auditIdentifiers(a),
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -329,10 +424,46 @@ func (g *Generator) generateControllers() (err error) {
 		}
 		return nil
 	})
+	if err = codegen.Dump("app/controllers", controllersData); err != nil {
+		return
+	}
 	err = ctlWr.Execute(controllersData)
 	return
 }
 
+// poolPayload returns true if the structs holding the payloads of the action are recycled with a
+// sync.Pool, that is if the action or the API defines the "goagen:payload:pool" metadata and the
+// payload is an object decoded from the request body.
+func (g *Generator) poolPayload(a *design.ActionDefinition) bool {
+	if a.Payload == nil || !a.Payload.IsObject() || a.PayloadMultipart {
+		return false
+	}
+	if _, ok := a.Metadata["goagen:payload:pool"]; ok {
+		return true
+	}
+	_, ok := g.API.Metadata["goagen:payload:pool"]
+	return ok
+}
+
+// auditIdentifiers returns the names and action context field expressions of the action audit
+// attributes.
+func auditIdentifiers(a *design.ActionDefinition) []map[string]interface{} {
+	if len(a.AuditAttributes) == 0 {
+		return nil
+	}
+	params := a.AllParams().Type.ToObject()
+	res := make([]map[string]interface{}, len(a.AuditAttributes))
+	for i, n := range a.AuditAttributes {
+		_, isParam := params[n]
+		res[i] = map[string]interface{}{
+			"Name":    n,
+			"Field":   codegen.Goify(n, true),
+			"Payload": !isParam,
+		}
+	}
+	return res
+}
+
 // generateControllers iterates through the API resources and generates the low level
 // controllers.
 func (g *Generator) generateSecurity() (err error) {
@@ -362,17 +493,69 @@ func (g *Generator) generateSecurity() (err error) {
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("errors"),
 		codegen.SimpleImport("context"),
+		codegen.SimpleImport("crypto/x509"),
+		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 	}
 	if err = secWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, secFile)
+	if err = codegen.Dump("app/security", design.Design.SecuritySchemes); err != nil {
+		return
+	}
 	err = secWr.Execute(design.Design.SecuritySchemes)
 
 	return
 }
 
+// generateWebhooks generates the code that sends the webhook requests if the design defines any.
+func (g *Generator) generateWebhooks() (err error) {
+	var webhooks []*design.WebhookDefinition
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			webhooks = append(webhooks, a.Webhooks...)
+			return nil
+		})
+	})
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	var (
+		whFile string
+		whWr   *WebhooksWriter
+	)
+	{
+		whFile = filepath.Join(g.OutDir, "webhooks.go")
+		whWr, err = NewWebhooksWriter(whFile)
+		if err != nil {
+			return
+		}
+	}
+	defer func() {
+		whWr.Close()
+		if err == nil {
+			err = whWr.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Webhooks", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	if err = whWr.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, whFile)
+	if err = codegen.Dump("app/webhooks", webhooks); err != nil {
+		return
+	}
+	err = whWr.Execute(webhooks)
+
+	return
+}
+
 // generateHrefs iterates through the API resources and generates the href factory methods.
 func (g *Generator) generateHrefs() (err error) {
 	var (
@@ -416,6 +599,10 @@ func (g *Generator) generateHrefs() (err error) {
 			Type:              m,
 			CanonicalTemplate: codegen.CanonicalTemplate(r),
 			CanonicalParams:   codegen.CanonicalParams(r),
+			HAL:               g.API.Types[design.HALLink.TypeName] == design.HALLink,
+		}
+		if err := codegen.Dump("app/hrefs/"+data.Name, &data); err != nil {
+			return err
 		}
 		return resWr.Execute(&data)
 	})
@@ -453,6 +640,13 @@ func (g *Generator) generateMediaTypes() (err error) {
 	for _, v := range g.API.MediaTypes {
 		imports = codegen.AttributeImports(v.AttributeDefinition, imports, nil)
 	}
+	mtWr.MarshalJSON = marshalJSONTypes(g.API)
+	if mtWr.ParallelJSON, err = parallelJSONThresholds(g.API, mtWr.MarshalJSON); err != nil {
+		return err
+	}
+	if len(mtWr.MarshalJSON) > 0 {
+		imports = append(imports, codegen.SimpleImport("sort"), codegen.SimpleImport("strconv"))
+	}
 	if err = mtWr.WriteHeader(title, g.Target, imports); err != nil {
 		return err
 	}
@@ -462,6 +656,9 @@ func (g *Generator) generateMediaTypes() (err error) {
 			return nil
 		}
 		if mt.Type.IsObject() || mt.Type.IsArray() {
+			if err := codegen.Dump("app/media_types/"+mt.TypeName, mt); err != nil {
+				return err
+			}
 			return mtWr.Execute(mt)
 		}
 		return nil
@@ -506,7 +703,34 @@ func (g *Generator) generateUserTypes() (err error) {
 	}
 	g.genfiles = append(g.genfiles, utFile)
 	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		if err := codegen.Dump("app/user_types/"+t.TypeName, t); err != nil {
+			return err
+		}
 		return utWr.Execute(t)
 	})
 	return
 }
+
+// durationLiteral returns the Go expression for the given duration, e.g. "5 * time.Second". It
+// returns the empty string if d is zero.
+func durationLiteral(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("%d * time.Nanosecond", d)
+}
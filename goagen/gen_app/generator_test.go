@@ -9,6 +9,7 @@ import (
 	"text/template"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_app"
@@ -17,6 +18,13 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+// The DSL roots registered by the apidsl package, restored by the tests running a DSL as other
+// tests replace them.
+var (
+	dslDesign              = design.Design
+	dslGeneratedMediaTypes = design.GeneratedMediaTypes
+)
+
 var _ = Describe("Generate", func() {
 	var workspace *codegen.Workspace
 	var outDir string
@@ -50,12 +58,13 @@ var _ = Describe("Generate", func() {
 				Name:        "test api",
 				Title:       "dummy API with no resource",
 				Description: "I told you it's dummy",
+				Version:     "2.0",
 			}
 		})
 
 		It("generates correct empty files", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(6))
+			Ω(files).Should(HaveLen(7))
 			isEmptySource := func(filename string) {
 				contextsContent, err := ioutil.ReadFile(filepath.Join(outDir, "app", filename))
 				Ω(err).ShouldNot(HaveOccurred())
@@ -68,6 +77,15 @@ var _ = Describe("Generate", func() {
 			isEmptySource("hrefs.go")
 			isEmptySource("media_types.go")
 		})
+
+		It("generates the design version", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "version.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`DesignVersion = "2.0"`))
+			Ω(string(content)).Should(ContainSubstring(`DesignVersionHeader = "X-Design-Version"`))
+			Ω(string(content)).Should(ContainSubstring(`rw.Header().Set(DesignVersionHeader, "2.0")`))
+		})
 	})
 
 	Context("with a simple API", func() {
@@ -191,13 +209,34 @@ var _ = Describe("Generate", func() {
 
 			It("generates the corresponding code", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(8))
+				Ω(files).Should(HaveLen(12))
 
 				isSource("contexts.go", contextsCode)
 				isSource("controllers.go", controllersCode)
 				isSource("hrefs.go", hrefsCode)
 				isSource("media_types.go", mediaTypesCode)
 			})
+
+			It("generates the controller mocks", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "mocks", "widget_mock.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("type WidgetController struct {"))
+				Ω(string(content)).Should(MatchRegexp(`GetFunc\s+func\(\*app\.GetWidgetContext\) error`))
+				Ω(string(content)).Should(MatchRegexp(`GetCalls\s+\[\]\*app\.GetWidgetContext`))
+				Ω(string(content)).Should(ContainSubstring("func (m *WidgetController) Get(ctx *app.GetWidgetContext) error {"))
+			})
+
+			It("generates the test harness", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "widget_harness.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("type WidgetHarness struct {"))
+				Ω(string(content)).Should(ContainSubstring("ctrl := mocks.NewWidgetController(service)"))
+				Ω(string(content)).Should(ContainSubstring("app.MountWidgetController(service, ctrl)"))
+				Ω(string(content)).Should(MatchRegexp(`func \(h \*WidgetHarness\) GetOK\(t goatest\.TInterface, id string\) \(\*http\.Response, app\.\w+\) {`))
+				Ω(string(content)).Should(ContainSubstring(`Path: fmt.Sprintf("/%v", id),`))
+			})
 		})
 
 		Context("with a slice payload", func() {
@@ -222,6 +261,53 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with a pattern validation", func() {
+			BeforeEach(func() {
+				id := design.Design.Resources["Widget"].Actions["get"].Params.Type.ToObject()["id"]
+				id.Validation = &dslengine.ValidationDefinition{Pattern: "^[a-z]+$"}
+			})
+
+			It("compiles the pattern once in a package-level variable", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(13))
+
+				patternsContent, err := ioutil.ReadFile(filepath.Join(outDir, "app", "patterns.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				patternVar := codegen.PatternVar("^[a-z]+$")
+				Ω(string(patternsContent)).Should(ContainSubstring(patternVar + ` = regexp.MustCompile("^[a-z]+$")`))
+
+				contextsContent, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(contextsContent)).Should(ContainSubstring("if ok := " + patternVar + ".MatchString(rctx.ID); !ok {"))
+			})
+		})
+
+		Context("with fail-fast validations", func() {
+			BeforeEach(func() {
+				id := design.Design.Resources["Widget"].Actions["get"].Params.Type.ToObject()["id"]
+				id.Validation = &dslengine.ValidationDefinition{Pattern: "^[a-z]+$"}
+				design.Design.Metadata = dslengine.MetadataDefinition{"goagen:validation:fail-fast": nil}
+			})
+
+			It("stops validating at the first error", func() {
+				Ω(genErr).Should(BeNil())
+
+				contextsContent, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(contextsContent)).Should(ContainSubstring("if err == nil {\n\t\t\tif ok := " + codegen.PatternVar("^[a-z]+$") + ".MatchString(rctx.ID); !ok {"))
+			})
+
+			Context("with an invalid maximum number of errors", func() {
+				BeforeEach(func() {
+					design.Design.Metadata = dslengine.MetadataDefinition{"goagen:validation:max-errors": {"many"}}
+				})
+
+				It("fails", func() {
+					Ω(genErr).Should(MatchError(ContainSubstring(`invalid "goagen:validation:max-errors" metadata value "many"`)))
+				})
+			})
+		})
+
 		Context("with a optional payload", func() {
 			BeforeEach(func() {
 				elemType := &design.AttributeDefinition{Type: design.Integer}
@@ -245,6 +331,58 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with a pooled payload", func() {
+			BeforeEach(func() {
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"name": &design.AttributeDefinition{Type: design.String}},
+					},
+					TypeName: "WidgetPayload",
+				}
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Payload = payload
+				get.Metadata = dslengine.MetadataDefinition{"goagen:payload:pool": nil}
+			})
+
+			It("recycles the payload structs", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "controllers.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(controllersPooledPayloadCode))
+				Ω(string(content)).Should(ContainSubstring(controllersPooledUnmarshalCode))
+				Ω(string(content)).Should(ContainSubstring(controllersPooledReleaseCode))
+			})
+		})
+
+		Context("with a webhook", func() {
+			BeforeEach(func() {
+				notification := &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"id": &design.AttributeDefinition{Type: design.String}},
+					},
+					TypeName: "Notification",
+				}
+				get := design.Design.Resources["Widget"].Actions["get"]
+				get.Webhooks = []*design.WebhookDefinition{{
+					Name:        "widgetFetched",
+					Description: "Sent when a widget is fetched",
+					URL:         design.DefaultWebhookURL,
+					Payload:     notification,
+					Parent:      get,
+				}}
+			})
+
+			It("generates the webhooks code", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "webhooks.go")))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "webhooks.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(webhooksCode))
+			})
+		})
+
 		Context("with a multipart payload", func() {
 			BeforeEach(func() {
 				elemTypeInt := &design.AttributeDefinition{Type: design.Integer}
@@ -271,6 +409,188 @@ var _ = Describe("Generate", func() {
 				Ω(string(contextsContent)).Should(ContainSubstring(controllersMultipartPayloadCode))
 			})
 		})
+
+		Context("with a user type", func() {
+			BeforeEach(func() {
+				minLength := 2
+				user := &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{
+								Type:       design.String,
+								Example:    "abc",
+								Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+					},
+					TypeName: "User",
+				}
+				design.Design.Types = map[string]*design.UserTypeDefinition{"User": user}
+			})
+
+			It("generates the validation tests", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "user_types_test.go")))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "user_types_test.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(validationTestsCode))
+			})
+
+			It("generates the round-trip tests", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "round_trip_test.go")))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "round_trip_test.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(roundTripTestsCode))
+			})
+
+			It("generates the benchmarks", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "benchmark_test.go")))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "benchmark_test.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(benchmarksCode))
+			})
+
+			It("generates the payload generators", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "test", "generators.go")))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "generators.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(payloadGeneratorsCode))
+			})
+		})
+	})
+
+	Context("with a media type with views", func() {
+		BeforeEach(func() {
+			design.Design = dslDesign
+			design.GeneratedMediaTypes = dslGeneratedMediaTypes
+			dslengine.Reset()
+			apidsl.API("test api", func() {})
+			origin := apidsl.MediaType("application/vnd.origin", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("country", design.String)
+					apidsl.Attribute("region", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("country")
+					apidsl.Attribute("region")
+				})
+				apidsl.View("tiny", func() {
+					apidsl.Attribute("country")
+				})
+			})
+			apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("origins", apidsl.CollectionOf(origin))
+					apidsl.Attribute("extra", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("origins")
+				})
+				apidsl.View("tiny", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("origins", func() {
+						apidsl.View("tiny")
+					})
+				})
+				apidsl.View("full", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("extra")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the methods projecting the default view onto the other views", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "media_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(bottleProjectTinyCode))
+			Ω(string(content)).Should(ContainSubstring(originCollectionProjectTinyCode))
+			Ω(string(content)).Should(ContainSubstring("func (mt *Origin) ProjectTiny() *OriginTiny {"))
+		})
+
+		It("does not generate projections for views rendering attributes the default view omits", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "media_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).ShouldNot(ContainSubstring("ProjectFull"))
+		})
+	})
+
+	Context("with a media type using reflection-free JSON encoding", func() {
+		BeforeEach(func() {
+			design.Design = dslDesign
+			design.GeneratedMediaTypes = dslGeneratedMediaTypes
+			dslengine.Reset()
+			apidsl.API("test api", func() {})
+			origin := apidsl.MediaType("application/vnd.origin", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("country", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("country")
+				})
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Metadata("goagen:encoding:marshal-json")
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("origin", origin)
+					apidsl.Required("id")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("origin")
+				})
+			})
+			apidsl.CollectionOf(bottle)
+			apidsl.MediaType("application/vnd.other", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("name", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("name")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the encoding methods of the media type and of the media types it contains", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "media_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(bottleAppendJSONCode))
+			Ω(string(content)).Should(ContainSubstring("func (mt *Origin) AppendJSON(b []byte) ([]byte, error) {"))
+			Ω(string(content)).ShouldNot(ContainSubstring("func (mt *Other) AppendJSON"))
+		})
+
+		Context("with a parallel encoding threshold", func() {
+			BeforeEach(func() {
+				design.Design.Metadata = dslengine.MetadataDefinition{
+					"goagen:encoding:marshal-json":       {},
+					"goagen:encoding:parallel-threshold": {"100"},
+				}
+			})
+
+			It("encodes the collection elements concurrently", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "media_types.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(bottleCollectionAppendJSONCode))
+			})
+		})
 	})
 })
 
@@ -408,6 +728,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		// Build the context
 		rctx, err := NewGetWidgetContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		return ctrl.Get(rctx)
@@ -415,6 +736,24 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, nil))
 	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
 }
+
+// Controllers groups the implementations of all the API controllers. Use NewControllers as the
+// provider when wiring the service with a dependency injection framework such as Wire or fx.
+type Controllers struct {
+	Widget WidgetController
+}
+
+// NewControllers returns the Controllers struct initialized with the given controllers.
+func NewControllers(widget WidgetController) *Controllers {
+	return &Controllers{
+		Widget: widget,
+	}
+}
+
+// MountAll mounts all the controllers on the given service.
+func MountAll(service *goa.Service, ctrls *Controllers) {
+	MountWidgetController(service, ctrls.Widget)
+}
 `
 
 const hrefsCodeTmpl = `// Code generated by goagen {{.version}}, DO NOT EDIT.
@@ -468,6 +807,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		// Build the context
 		rctx, err := NewGetWidgetContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		// Build the payload
@@ -485,7 +825,15 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 // unmarshalGetWidgetPayload unmarshals the request body into the context request data Payload field.
 func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	var payload Collection
-	if err := service.DecodeRequest(req, &payload); err != nil {
+	err := service.DecodeRequestArray(req, &payload, func(decode func(interface{}) error) error {
+		var elem int
+		if err := decode(&elem); err != nil {
+			return err
+		}
+		payload = append(payload, elem)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 	goa.ContextRequest(ctx).Payload = payload
@@ -507,6 +855,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		// Build the context
 		rctx, err := NewGetWidgetContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		// Build the payload
@@ -522,7 +871,15 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 // unmarshalGetWidgetPayload unmarshals the request body into the context request data Payload field.
 func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	var payload Collection
-	if err := service.DecodeRequest(req, &payload); err != nil {
+	err := service.DecodeRequestArray(req, &payload, func(decode func(interface{}) error) error {
+		var elem int
+		if err := decode(&elem); err != nil {
+			return err
+		}
+		payload = append(payload, elem)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 	goa.ContextRequest(ctx).Payload = payload
@@ -544,6 +901,7 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		// Build the context
 		rctx, err := NewGetWidgetContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		// Build the payload
@@ -583,3 +941,172 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 	return nil
 }
 `
+
+const webhooksCode = `// Webhooks sends the webhook requests to the URLs registered by the API consumers. The requests
+// are signed and the failed deliveries retried by Sender.
+type Webhooks struct {
+	Sender *goa.WebhookSender
+}
+
+// NewWebhooks returns the webhooks that send their requests with sender.
+func NewWebhooks(sender *goa.WebhookSender) *Webhooks {
+	return &Webhooks{Sender: sender}
+}
+
+// WidgetFetched sends the "widgetFetched" webhook request to url.
+// Sent when a widget is fetched
+func (w *Webhooks) WidgetFetched(ctx context.Context, url string, payload *Notification) error {
+	return w.Sender.Send(ctx, url, "widgetFetched", payload)
+}
+`
+
+const validationTestsCode = `func TestUserValidate(t *testing.T) {
+	cases := []struct {
+		Name  string
+		JSON  string
+		Valid bool
+	}{
+		{"valid", "{\"name\":\"abc\"}", true},
+		{"name: missing", "{}", false},
+		{"name: too short", "{\"name\":\"a\"}", false},
+	}
+`
+
+const roundTripTestsCode = `func TestUserRoundTrip(t *testing.T) {
+	const example = "{\"name\":\"abc\"}"
+	var v user
+	if err := json.Unmarshal([]byte(example), &v); err != nil {
+		t.Fatalf("failed to decode %s: %s", example, err)
+	}
+	assertRoundTrip(t, example, v.Publicize())
+}
+`
+
+const benchmarksCode = `func BenchmarkUserDecode(b *testing.B) {
+	data := []byte("{\"name\":\"abc\"}")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v user
+		if err := goa.NewJSONDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+			b.Fatal(err)
+		}
+		v.Publicize()
+	}
+}
+`
+
+const payloadGeneratorsCode = `var userSchema = &goatest.Schema{
+	Type:     "object",
+	Required: []string{"name"},
+	Attributes: map[string]*goatest.Schema{
+		"name": &goatest.Schema{
+			Type:      "string",
+			MinLength: goatest.Int(2),
+		},
+	},
+}
+
+// GenerateUser returns a random User that satisfies the design validations.
+func GenerateUser(r *rand.Rand) *app.User {
+	var v *app.User
+	decodeGenerated(goatest.Generate(r, userSchema), &v)
+	return v
+}
+
+// GenerateInvalidUser returns a random User that violates a single design validation
+// and the description of the violated rule.
+func GenerateInvalidUser(r *rand.Rand) (*app.User, string) {
+	invalid, rule := goatest.GenerateInvalid(r, userSchema)
+	var v *app.User
+	decodeGenerated(invalid, &v)
+	return v, rule
+}
+`
+
+const bottleProjectTinyCode = `// ProjectTiny returns the "tiny" view of the media type. It only copies the
+// attributes rendered by the view.
+func (mt *Bottle) ProjectTiny() *BottleTiny {
+	if mt == nil {
+		return nil
+	}
+	res := &BottleTiny{}
+	res.ID = mt.ID
+	res.Origins = mt.Origins.ProjectTiny()
+	return res
+}`
+
+const originCollectionProjectTinyCode = `func (mt OriginCollection) ProjectTiny() OriginTinyCollection {
+	if mt == nil {
+		return nil
+	}
+	res := make(OriginTinyCollection, len(mt))
+	for i, e := range mt {
+		res[i] = e.ProjectTiny()
+	}
+	return res
+}`
+
+const bottleAppendJSONCode = `func (mt *Bottle) AppendJSON(b []byte) ([]byte, error) {
+	if mt == nil {
+		return append(b, "null"...), nil
+	}
+	var err error
+	start1 := len(b)
+	b = append(b, ",\"id\":"...)
+	b = strconv.AppendInt(b, int64(mt.ID), 10)
+	if mt.Origin != nil {
+		b = append(b, ",\"origin\":"...)
+		if b, err = mt.Origin.AppendJSON(b); err != nil {
+			return b, err
+		}
+	}
+	if len(b) == start1 {
+		b = append(b, "{}"...)
+	} else {
+		b[start1] = '{'
+		b = append(b, '}')
+	}
+	return b, nil
+}`
+
+const controllersPooledPayloadCode = `var (
+	getWidgetPayloadPool    = sync.Pool{New: func() interface{} { return new(WidgetPayload) }}
+	getWidgetRawPayloadPool = sync.Pool{New: func() interface{} { return new(widgetPayload) }}
+)`
+
+const controllersPooledUnmarshalCode = `	payload := getWidgetRawPayloadPool.Get().(*widgetPayload)
+	if err := service.DecodeRequest(req, payload); err != nil {
+		*payload = widgetPayload{}
+		getWidgetRawPayloadPool.Put(payload)
+		return err
+	}
+	pub := getWidgetPayloadPool.Get().(*WidgetPayload)
+	if payload.Name != nil {
+		pub.Name = payload.Name
+	}
+	*payload = widgetPayload{}
+	getWidgetRawPayloadPool.Put(payload)
+	goa.ContextRequest(ctx).Payload = pub
+	return nil`
+
+const controllersPooledReleaseCode = `		err = ctrl.Get(rctx)
+		if rctx.Payload != nil {
+			// Release the payload now that the action has returned
+			*rctx.Payload = WidgetPayload{}
+			getWidgetPayloadPool.Put(rctx.Payload)
+		}
+		return err`
+
+const bottleCollectionAppendJSONCode = `func (mt BottleCollection) AppendJSON(b []byte) ([]byte, error) {
+	if mt == nil {
+		return append(b, "null"...), nil
+	}
+	var err error
+	if b, err = goa.AppendJSONArray(b, len(mt), 100, func(b []byte, i int) ([]byte, error) {
+		return mt[i].AppendJSON(b)
+	}); err != nil {
+		return b, err
+	}
+	return b, nil
+}
+`
@@ -0,0 +1,229 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// HarnessData contains the information required to generate the integration test harness of a
+// resource.
+type HarnessData struct {
+	Resource string           // Controller name, e.g. "Bottle"
+	API      string           // API name used to create the service
+	AppPkg   string           // Name of the generated application package, e.g. "app"
+	Methods  []*HarnessMethod // Typed request helpers
+}
+
+// HarnessMethod contains the information required to generate a harness request helper.
+type HarnessMethod struct {
+	*TestMethod
+	HarnessName  string // Method name, e.g. "ShowOKTiny"
+	ResponseType string // Type the response body is decoded into, empty if there is no body
+}
+
+// generateHarnesses generates a harness per resource that serves the resource actions on an
+// httptest server backed by the generated mock controller. The harnesses live in the test package
+// next to the test helpers and expose a typed method per action response.
+func (g *Generator) generateHarnesses() error {
+	if len(g.API.Resources) == 0 {
+		return nil
+	}
+	funcs := template.FuncMap{
+		"isSlice": isSlice,
+	}
+	harnessTmpl := template.Must(template.New("harness").Funcs(funcs).Parse(harnessTmpl))
+	outDir := filepath.Join(g.OutDir, "test")
+	appPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("log"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strconv"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport(appPkg),
+		codegen.SimpleImport(appPkg + "/mocks"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("github.com/goadesign/goa/goatest"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+
+	return g.API.IterateResources(func(res *design.ResourceDefinition) (err error) {
+		data := &HarnessData{
+			Resource: codegen.Goify(res.Name, true),
+			API:      g.API.Name,
+			AppPkg:   g.Target,
+		}
+		err = res.IterateActions(func(action *design.ActionDefinition) error {
+			if action.PayloadMultipart {
+				// The harness only sends JSON payloads.
+				return nil
+			}
+			return action.IterateResponses(func(response *design.ResponseDefinition) error {
+				if response.Status == 101 { // SwitchingProtocols, WebSocket endpoints are not supported
+					return nil
+				}
+				for routeIndex, route := range action.Routes {
+					mediaType := design.Design.MediaTypeWithIdentifier(response.MediaType)
+					if mediaType == nil {
+						data.Methods = append(data.Methods, g.harnessMethod(res, action, response, route, routeIndex, nil, nil))
+						continue
+					}
+					if err := mediaType.IterateViews(func(view *design.ViewDefinition) error {
+						data.Methods = append(data.Methods, g.harnessMethod(res, action, response, route, routeIndex, mediaType, view))
+						return nil
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		if err != nil || len(data.Methods) == 0 {
+			return err
+		}
+
+		filename := filepath.Join(outDir, codegen.SnakeCase(res.Name)+"_harness.go")
+		var file *codegen.SourceFile
+		file, err = codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			file.Close()
+			if err == nil {
+				err = file.FormatCode()
+			}
+		}()
+		title := fmt.Sprintf("%s: %s Test Harness", g.API.Context(), res.Name)
+		if err = file.WriteHeader(title, "test", imports); err != nil {
+			return err
+		}
+		g.genfiles = append(g.genfiles, filename)
+		err = harnessTmpl.Execute(file, data)
+		return
+	})
+}
+
+// harnessMethod builds the data needed to render the harness request helper of the given action
+// response, route and view.
+func (g *Generator) harnessMethod(res *design.ResourceDefinition, action *design.ActionDefinition,
+	response *design.ResponseDefinition, route *design.RouteDefinition, routeIndex int,
+	mediaType *design.MediaTypeDefinition, view *design.ViewDefinition) *HarnessMethod {
+
+	tm := g.createTestMethod(res, action, response, route, routeIndex, mediaType, view)
+	m := &HarnessMethod{
+		TestMethod:  tm,
+		HarnessName: tm.ActionName + strings.TrimPrefix(tm.Name, tm.ActionName+tm.ResourceName),
+	}
+	if tm.ReturnType != nil {
+		m.ResponseType = tm.ReturnType.Pointer + tm.ReturnType.Type
+		if tm.ReturnsErrorMedia {
+			m.ResponseType = "*goa.ErrorResponse"
+		}
+	}
+	return m
+}
+
+// harnessTmpl generates the test harness of a resource.
+// template input: *HarnessData
+var harnessTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}{{ $harness := printf "%sHarness" .Resource }}
+// {{ $harness }} serves the {{ .Resource }} controller actions on an httptest server. The actions
+// are implemented by a mock controller, set its action functions to define the responses.
+type {{ $harness }} struct {
+	// Server is the test server running the service.
+	Server *httptest.Server
+	// Service is the service the controller is mounted on.
+	Service *goa.Service
+	// Controller is the mock controller that implements the actions.
+	Controller *mocks.{{ .Resource }}Controller
+}
+
+// New{{ $harness }} mounts a mock {{ .Resource }} controller on a new service and starts a test
+// server that serves it. Call Close to stop the server.
+func New{{ $harness }}() *{{ $harness }} {
+	service := goa.New({{ printf "%q" .API }})
+	service.WithLogger(goa.NewLogger(log.New(ioutil.Discard, "", 0)))
+	ctrl := mocks.New{{ .Resource }}Controller(service)
+	{{ .AppPkg }}.Mount{{ .Resource }}Controller(service, ctrl)
+	return &{{ $harness }}{
+		Server:     httptest.NewServer(service.Mux),
+		Service:    service,
+		Controller: ctrl,
+	}
+}
+
+// Close stops the test server.
+func (h *{{ $harness }}) Close() {
+	h.Server.Close()
+}
+{{ range $test := .Methods }}
+// {{ $test.HarnessName }} sends a {{ $test.RouteVerb }} request to the {{ $test.ActionName }} action and checks that the response
+// status is {{ $test.Status }}. It returns the response{{ if $test.ResponseType }} and its decoded body{{ end }}.
+func (h *{{ $harness }}) {{ $test.HarnessName }}(t goatest.TInterface{{/*
+*/}}{{ range $param := $test.Params }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
+*/}}{{ range $param := $test.QueryParams }}, {{ $param.Name }} {{ $param.Pointer }}{{ $param.Type }}{{ end }}{{/*
+*/}}{{ range $header := $test.Headers }}, {{ $header.Name }} {{ $header.Pointer }}{{ $header.Type }}{{ end }}{{/*
+*/}}{{ if $test.Payload }}, {{ $test.Payload.Name }} {{ $test.Payload.Pointer }}{{ $test.Payload.Type }}{{ end }}){{/*
+*/}} (*http.Response{{ if $test.ResponseType }}, {{ $test.ResponseType }}{{ end }}) {
+{{- $query := $test.Escape "query" }}{{ $u := $test.Escape "u" }}{{ $body := $test.Escape "body" }}{{/*
+*/}}{{ $b := $test.Escape "b" }}{{ $err := $test.Escape "err" }}{{ $req := $test.Escape "req" }}{{/*
+*/}}{{ $resp := $test.Escape "resp" }}{{ $mt := $test.Escape "mt" }}
+{{ if $test.QueryParams }}	{{ $query }} := url.Values{}
+{{ range $param := $test.QueryParams }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
+{{ template "convertParam" $param }}
+		{{ $query }}[{{ printf "%q" $param.Label }}] = sliceVal
+	}
+{{ end }}{{ end }}	{{ $u }} := &url.URL{
+		Path: fmt.Sprintf({{ printf "%q" $test.FullPath }}{{ range $param := $test.Params }}, {{ $param.Name }}{{ end }}),
+{{ if $test.QueryParams }}		RawQuery: {{ $query }}.Encode(),
+{{ end }}	}
+	var {{ $body }} io.Reader
+{{ if $test.Payload }}	{{ $b }}, {{ $err }} := json.Marshal({{ $test.Payload.Name }})
+	if {{ $err }} != nil {
+		t.Fatalf("failed to encode payload: %s", {{ $err }})
+	}
+	{{ $body }} = bytes.NewReader({{ $b }})
+{{ end }}	{{ $req }}, {{ $err }} := http.NewRequest("{{ $test.RouteVerb }}", h.Server.URL+{{ $u }}.String(), {{ $body }})
+	if {{ $err }} != nil {
+		t.Fatalf("invalid request: %s", {{ $err }})
+	}
+{{ if $test.Payload }}	{{ $req }}.Header.Set("Content-Type", "application/json")
+{{ end }}{{ range $header := $test.Headers }}{{ if $header.Pointer }}	if {{ $header.Name }} != nil {{ end }}{
+{{ template "convertParam" $header }}
+		{{ $req }}.Header[{{ printf "%q" $header.Label }}] = sliceVal
+	}
+{{ end }}	{{ $resp }}, {{ $err }} := http.DefaultClient.Do({{ $req }})
+	if {{ $err }} != nil {
+		t.Fatalf("request failed: %s", {{ $err }})
+	}
+	if {{ $resp }}.StatusCode != {{ $test.Status }} {
+		t.Errorf("invalid response status code: got %d, expected {{ $test.Status }}", {{ $resp }}.StatusCode)
+	}
+{{ if $test.ResponseType }}	var {{ $mt }} {{ $test.ResponseType }}
+	if {{ $resp }}.StatusCode == {{ $test.Status }} {
+		defer {{ $resp }}.Body.Close()
+		if {{ $err }} := json.NewDecoder({{ $resp }}.Body).Decode(&{{ $mt }}); {{ $err }} != nil {
+			t.Fatalf("failed to decode response body: %s", {{ $err }})
+		}
+{{ if and $test.ReturnType.Validatable (not $test.ReturnsErrorMedia) }}		if {{ $err }} := {{ $mt }}.Validate(); {{ $err }} != nil {
+			t.Errorf("invalid response media type: %s", {{ $err }})
+		}
+{{ end }}	}
+	return {{ $resp }}, {{ $mt }}
+{{ else }}	return {{ $resp }}
+{{ end }}}
+{{ end }}`
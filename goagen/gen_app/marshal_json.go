@@ -0,0 +1,316 @@
+package genapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// marshalJSONMetadata is the name of the metadata that enables the generation of the reflection-free
+// JSON encoding methods. It applies to all the media types when set on the API.
+const marshalJSONMetadata = "goagen:encoding:marshal-json"
+
+// marshalJSONTypes returns the names of the media types for which the MarshalJSON and AppendJSON
+// methods must be generated: the media types that define the marshal-json metadata (all the media
+// types if the API defines it) and the media types they contain so that the generated code does not
+// need to fall back to reflection to encode them.
+func marshalJSONTypes(api *design.APIDefinition) map[string]bool {
+	_, all := api.Metadata[marshalJSONMetadata]
+	types := make(map[string]bool)
+	var walk func(att *design.AttributeDefinition)
+	walk = func(att *design.AttributeDefinition) {
+		switch actual := att.Type.(type) {
+		case *design.MediaTypeDefinition:
+			if types[actual.TypeName] {
+				return
+			}
+			types[actual.TypeName] = true
+			walk(actual.AttributeDefinition)
+		case design.Object:
+			for _, catt := range actual {
+				walk(catt)
+			}
+		case *design.Array:
+			walk(actual.ElemType)
+		case *design.Hash:
+			walk(actual.KeyType)
+			walk(actual.ElemType)
+		}
+	}
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if _, ok := mt.Metadata[marshalJSONMetadata]; (ok || all) && !mt.IsError() {
+			walk(&design.AttributeDefinition{Type: mt})
+		}
+		return nil
+	})
+	return types
+}
+
+// parallelJSONMetadata is the name of the metadata that sets the minimum number of elements from
+// which the AppendJSON methods of collections encode the elements concurrently.
+const parallelJSONMetadata = "goagen:encoding:parallel-threshold"
+
+// parallelJSONThresholds returns the parallel encoding thresholds of the collection media types
+// listed in types indexed by media type name. The threshold is read from the metadata of the
+// collection, of its element media type or of the API in this order.
+func parallelJSONThresholds(api *design.APIDefinition, types map[string]bool) (map[string]int, error) {
+	thresholds := make(map[string]int)
+	err := api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if !types[mt.TypeName] || !mt.IsArray() {
+			return nil
+		}
+		vals, ok := mt.Metadata[parallelJSONMetadata]
+		if !ok {
+			if elem, isMT := mt.ToArray().ElemType.Type.(*design.MediaTypeDefinition); isMT {
+				vals, ok = elem.Metadata[parallelJSONMetadata]
+			}
+		}
+		if !ok {
+			vals, ok = api.Metadata[parallelJSONMetadata]
+		}
+		if !ok || len(vals) == 0 {
+			return nil
+		}
+		threshold, err := strconv.Atoi(vals[0])
+		if err != nil || threshold <= 0 {
+			return fmt.Errorf(`invalid %q metadata value %q, must be a positive integer`, parallelJSONMetadata, vals[0])
+		}
+		thresholds[mt.TypeName] = threshold
+		return nil
+	})
+	return thresholds, err
+}
+
+// jsonEncoder produces the body of the AppendJSON method of a media type. The generated code
+// appends the JSON encoding of the media type to the slice b, it produces the same output as
+// encoding/json. Only the omitempty option of JSON struct tags overridden with the "struct:tag:json"
+// metadata is taken into account.
+type jsonEncoder struct {
+	buf bytes.Buffer
+	// fallible is true if the generated code may fail and thus uses the err variable.
+	fallible bool
+}
+
+// appendJSONCode returns the code that appends the JSON encoding of the media type value held in
+// the variable mt to b and whether the code uses the err variable. threshold is the number of
+// elements from which the elements of a collection are encoded concurrently, 0 to always encode
+// them sequentially.
+func appendJSONCode(mt *design.MediaTypeDefinition, threshold int) (string, bool) {
+	e := &jsonEncoder{}
+	if mt.IsArray() && threshold > 0 {
+		e.call(1, "goa.AppendJSONArray(b, len(mt), %d, func(b []byte, i int) ([]byte, error) {\n%sreturn mt[i].AppendJSON(b)\n%s})",
+			threshold, codegen.Tabs(2), codegen.Tabs(1))
+	} else if mt.IsArray() {
+		e.array(mt.ToArray().ElemType, "mt", 1)
+	} else {
+		e.object(mt.AttributeDefinition, "mt", 1)
+	}
+	return e.buf.String(), e.fallible
+}
+
+// value writes the code that encodes the value held in v. pointer is true if v is a pointer to a
+// primitive value, notNil is true if v was already checked against nil.
+func (e *jsonEncoder) value(att *design.AttributeDefinition, v string, pointer, notNil bool, depth int) {
+	if _, ok := att.Metadata["struct:field:type"]; ok {
+		e.fallback(v, depth)
+		return
+	}
+	ref := v
+	if pointer {
+		v = "*" + v
+	}
+	switch actual := att.Type.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			e.line(depth, "b = strconv.AppendBool(b, %s)", v)
+		case design.IntegerKind:
+			e.line(depth, "b = strconv.AppendInt(b, int64(%s), 10)", v)
+		case design.NumberKind:
+			e.call(depth, "goa.AppendJSONFloat(b, %s)", v)
+		case design.StringKind:
+			e.line(depth, "b = goa.AppendJSONString(b, %s)", v)
+		case design.DateTimeKind:
+			e.call(depth, "goa.AppendJSONTime(b, %s)", v)
+		case design.UUIDKind:
+			e.line(depth, "b = goa.AppendJSONString(b, %s.String())", ref)
+		default:
+			e.fallback(v, depth)
+		}
+	case *design.MediaTypeDefinition:
+		e.call(depth, "%s.AppendJSON(b)", v)
+	case design.Object:
+		e.nilCheck(v, notNil, depth, func(depth int) { e.object(att, v, depth) })
+	case *design.Array:
+		e.nilCheck(v, notNil, depth, func(depth int) { e.array(actual.ElemType, v, depth) })
+	case *design.Hash:
+		if key, ok := actual.KeyType.Type.(design.Primitive); !ok || key.Kind() != design.StringKind {
+			e.fallback(v, depth)
+			return
+		}
+		e.nilCheck(v, notNil, depth, func(depth int) { e.hash(actual, v, depth) })
+	default:
+		e.fallback(v, depth)
+	}
+}
+
+// object writes the code that encodes the fields of the non-nil struct held in v. The fields are
+// written in the struct order with a leading comma, the first comma is replaced with the opening
+// brace once all the fields have been written.
+func (e *jsonEncoder) object(def *design.AttributeDefinition, v string, depth int) {
+	obj := def.Type.ToObject()
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	start := fmt.Sprintf("start%d", depth)
+	e.line(depth, "%s := len(b)", start)
+	for _, n := range names {
+		att := obj[n]
+		name, omit, ok := jsonField(def, att, n)
+		if !ok {
+			continue
+		}
+		field := v + "." + codegen.GoifyAtt(att, n, true)
+		pointer := def.IsPrimitivePointer(n)
+		key, _ := json.Marshal(name)
+		nilable := pointer || att.Type.IsObject() || def.IsInterface(n)
+		switch {
+		case !omit:
+		case nilable:
+			e.line(depth, "if %s != nil {", field)
+		case att.Type.IsArray() || att.Type.IsHash():
+			e.line(depth, "if len(%s) > 0 {", field)
+		case att.Type.Kind() == design.BooleanKind:
+			e.line(depth, "if %s {", field)
+		case att.Type.Kind() == design.StringKind:
+			e.line(depth, `if %s != "" {`, field)
+		case att.Type.Kind() == design.IntegerKind || att.Type.Kind() == design.NumberKind:
+			e.line(depth, "if %s != 0 {", field)
+		default:
+			// encoding/json never omits struct values.
+			omit = false
+		}
+		d := depth
+		if omit {
+			d++
+		}
+		e.line(d, "b = append(b, %q...)", ","+string(key)+":")
+		if pointer && !omit {
+			e.nilCheck(field, false, d, func(depth int) { e.value(att, field, true, true, depth) })
+		} else {
+			e.value(att, field, pointer, omit, d)
+		}
+		if omit {
+			e.line(depth, "}")
+		}
+	}
+	e.line(depth, "if len(b) == %s {", start)
+	e.line(depth+1, `b = append(b, "{}"...)`)
+	e.line(depth, "} else {")
+	e.line(depth+1, "b[%s] = '{'", start)
+	e.line(depth+1, "b = append(b, '}')")
+	e.line(depth, "}")
+}
+
+// array writes the code that encodes the elements of the non-nil slice held in v.
+func (e *jsonEncoder) array(elem *design.AttributeDefinition, v string, depth int) {
+	i, el := fmt.Sprintf("i%d", depth), fmt.Sprintf("e%d", depth)
+	e.line(depth, "b = append(b, '[')")
+	e.line(depth, "for %s, %s := range %s {", i, el, v)
+	e.line(depth+1, "if %s > 0 {", i)
+	e.line(depth+2, "b = append(b, ',')")
+	e.line(depth+1, "}")
+	e.value(elem, el, false, false, depth+1)
+	e.line(depth, "}")
+	e.line(depth, "b = append(b, ']')")
+}
+
+// hash writes the code that encodes the non-nil map held in v. encoding/json sorts the map keys.
+func (e *jsonEncoder) hash(h *design.Hash, v string, depth int) {
+	keys, i, k := fmt.Sprintf("keys%d", depth), fmt.Sprintf("i%d", depth), fmt.Sprintf("k%d", depth)
+	e.line(depth, "%s := make([]string, 0, len(%s))", keys, v)
+	e.line(depth, "for %s := range %s {", k, v)
+	e.line(depth+1, "%s = append(%s, %s)", keys, keys, k)
+	e.line(depth, "}")
+	e.line(depth, "sort.Strings(%s)", keys)
+	e.line(depth, "b = append(b, '{')")
+	e.line(depth, "for %s, %s := range %s {", i, k, keys)
+	e.line(depth+1, "if %s > 0 {", i)
+	e.line(depth+2, "b = append(b, ',')")
+	e.line(depth+1, "}")
+	e.line(depth+1, "b = goa.AppendJSONString(b, %s)", k)
+	e.line(depth+1, "b = append(b, ':')")
+	e.value(h.ElemType, fmt.Sprintf("%s[%s]", v, k), false, false, depth+1)
+	e.line(depth, "}")
+	e.line(depth, "b = append(b, '}')")
+}
+
+// nilCheck writes the code that encodes the value held in v as null if it is nil and calls encode
+// to write the code that encodes it otherwise. The nil check is omitted if notNil is true.
+func (e *jsonEncoder) nilCheck(v string, notNil bool, depth int, encode func(depth int)) {
+	if notNil {
+		encode(depth)
+		return
+	}
+	e.line(depth, "if %s == nil {", v)
+	e.line(depth+1, `b = append(b, "null"...)`)
+	e.line(depth, "} else {")
+	encode(depth + 1)
+	e.line(depth, "}")
+}
+
+// fallback writes the code that encodes the value held in v with encoding/json.
+func (e *jsonEncoder) fallback(v string, depth int) {
+	e.call(depth, "goa.AppendJSONValue(b, %s)", v)
+}
+
+// call writes the code that calls a function that appends to b and may return an error.
+func (e *jsonEncoder) call(depth int, format string, a ...interface{}) {
+	e.fallible = true
+	e.line(depth, "if b, err = %s; err != nil {", fmt.Sprintf(format, a...))
+	e.line(depth+1, "return b, err")
+	e.line(depth, "}")
+}
+
+// line writes a line of code indented with the given depth.
+func (e *jsonEncoder) line(depth int, format string, a ...interface{}) {
+	e.buf.WriteString(codegen.Tabs(depth))
+	fmt.Fprintf(&e.buf, format, a...)
+	e.buf.WriteByte('\n')
+}
+
+// jsonField returns the JSON name of the struct field generated for the attribute with the given
+// name of def and whether the field is omitted when empty. ok is false if the field is not encoded.
+func jsonField(def, att *design.AttributeDefinition, name string) (jsonName string, omit, ok bool) {
+	if tag, ok := att.Metadata["struct:tag:json"]; ok {
+		parts := strings.Split(strings.Join(tag, ","), ",")
+		if parts[0] == "-" && len(parts) == 1 {
+			return "", false, false
+		}
+		jsonName = parts[0]
+		if jsonName == "" {
+			jsonName = codegen.GoifyAtt(att, name, true)
+		}
+		for _, p := range parts[1:] {
+			if p == "omitempty" {
+				omit = true
+			}
+		}
+		return jsonName, omit, true
+	}
+	for k := range att.Metadata {
+		if strings.HasPrefix(k, "struct:tag:") {
+			// Other tags are overridden, the field has no JSON tag.
+			return codegen.GoifyAtt(att, name, true), false, true
+		}
+	}
+	return name, !def.IsRequired(name) && !def.HasDefaultValue(name), true
+}
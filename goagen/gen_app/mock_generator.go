@@ -0,0 +1,135 @@
+package genapp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+func makeMockDir(g *Generator) (outDir string, err error) {
+	outDir = filepath.Join(g.OutDir, "mocks")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+	return
+}
+
+// MockControllerData contains the information required to generate a mock controller.
+type MockControllerData struct {
+	Resource    string        // Controller name, e.g. "Bottle"
+	AppPkg      string        // Name of the generated application package, e.g. "app"
+	FileServers bool          // Whether the controller serves files
+	Actions     []*MockAction // Controller actions
+}
+
+// MockAction contains the information required to generate a mock controller action.
+type MockAction struct {
+	Name    string // Action method name, e.g. "Show"
+	Context string // Action context type name, e.g. "ShowBottleContext"
+}
+
+// generateMocks generates a mock implementation of each resource controller interface in the
+// "mocks" package.
+func (g *Generator) generateMocks() error {
+	if len(g.API.Resources) == 0 {
+		return nil
+	}
+	mockTmpl := template.Must(template.New("mock").Parse(mockTmpl))
+	outDir, err := makeMockDir(g)
+	if err != nil {
+		return err
+	}
+	appPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("sync"),
+		codegen.SimpleImport(appPkg),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+
+	return g.API.IterateResources(func(res *design.ResourceDefinition) (err error) {
+		data := &MockControllerData{
+			Resource:    codegen.Goify(res.Name, true),
+			AppPkg:      g.Target,
+			FileServers: len(res.FileServers) > 0,
+		}
+		res.IterateActions(func(a *design.ActionDefinition) error {
+			data.Actions = append(data.Actions, &MockAction{
+				Name:    codegen.Goify(a.Name, true),
+				Context: fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(res.Name, true)),
+			})
+			return nil
+		})
+		if len(data.Actions) == 0 && !data.FileServers {
+			return nil
+		}
+
+		filename := filepath.Join(outDir, codegen.SnakeCase(res.Name)+"_mock.go")
+		var file *codegen.SourceFile
+		file, err = codegen.SourceFileFor(filename)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			file.Close()
+			if err == nil {
+				err = file.FormatCode()
+			}
+		}()
+		title := fmt.Sprintf("%s: %s Mocks", g.API.Context(), res.Name)
+		if err = file.WriteHeader(title, "mocks", imports); err != nil {
+			return err
+		}
+		g.genfiles = append(g.genfiles, filename)
+		err = mockTmpl.Execute(file, data)
+		return
+	})
+}
+
+// mockTmpl generates a mock implementation of a controller interface.
+// template input: *MockControllerData
+const mockTmpl = `{{ $ctrl := printf "%sController" .Resource }}{{ $pkg := .AppPkg }}
+// {{ $ctrl }} is a mock implementation of the {{ $pkg }}.{{ $ctrl }} interface.
+// Set the action function fields to configure the behavior of the mock, actions whose function
+// field is nil return nil. All calls are recorded and can be inspected via the Calls fields.
+type {{ $ctrl }} struct {
+	*goa.Controller
+{{ range .Actions }}
+	// {{ .Name }}Func is called by {{ .Name }} if not nil.
+	{{ .Name }}Func func(*{{ $pkg }}.{{ .Context }}) error
+	// {{ .Name }}Calls records the contexts of all calls made to {{ .Name }}.
+	{{ .Name }}Calls []*{{ $pkg }}.{{ .Context }}
+{{ end }}
+	mu sync.Mutex
+}
+
+// New{{ $ctrl }} creates a mock {{ .Resource }} controller.
+func New{{ $ctrl }}(service *goa.Service) *{{ $ctrl }} {
+	return &{{ $ctrl }}{Controller: service.NewController("{{ $ctrl }}")}
+}
+
+// Make sure the mock implements the controller interface.
+var _ {{ $pkg }}.{{ $ctrl }} = (*{{ $ctrl }})(nil)
+{{ range .Actions }}
+// {{ .Name }} records the call and runs {{ .Name }}Func if set.
+func (m *{{ $ctrl }}) {{ .Name }}(ctx *{{ $pkg }}.{{ .Context }}) error {
+	m.mu.Lock()
+	m.{{ .Name }}Calls = append(m.{{ .Name }}Calls, ctx)
+	f := m.{{ .Name }}Func
+	m.mu.Unlock()
+	if f == nil {
+		return nil
+	}
+	return f(ctx)
+}
+{{ end }}`
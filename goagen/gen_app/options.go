@@ -32,3 +32,10 @@ func NoTest(noTest bool) Option {
 		g.NoTest = noTest
 	}
 }
+
+//MaxValidationErrors Maximum number of errors reported by the generated validation code, 1 for fail-fast validations
+func MaxValidationErrors(max int) Option {
+	return func(g *Generator) {
+		g.MaxValidationErrors = max
+	}
+}
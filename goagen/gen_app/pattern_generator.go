@@ -0,0 +1,65 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generatePatterns generates the package-level variables holding the compiled regular expressions
+// used by the pattern validations so that the generated code compiles each of them once at
+// initialization. The file is only generated if the design defines pattern validations.
+func (g *Generator) generatePatterns() error {
+	filename, err := GeneratePatterns(g.API, g.OutDir, g.Target)
+	if filename != "" {
+		g.genfiles = append(g.genfiles, filename)
+	}
+	return err
+}
+
+// GeneratePatterns writes the patterns.go file of the package pkg in dir. The file declares the
+// package-level variables named by codegen.PatternVar that the validation code generated for the
+// types of the API relies on. Generators that produce validation code in packages other than the
+// app package, e.g. with NewMediaTypesWriter or NewUserTypesWriter, must call it for these packages
+// too. GeneratePatterns returns the path to the generated file, if any, or the empty string if the
+// design does not define pattern validations.
+func GeneratePatterns(api *design.APIDefinition, dir, pkg string) (_ string, err error) {
+	patterns := codegen.Patterns(api)
+	if len(patterns) == 0 {
+		return "", nil
+	}
+	filename := filepath.Join(dir, "patterns.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Validation Patterns", api.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("regexp"),
+	}
+	if err = file.WriteHeader(title, pkg, imports); err != nil {
+		return filename, err
+	}
+	fn := template.FuncMap{"patternVar": codegen.PatternVar}
+	err = template.Must(template.New("patterns").Funcs(fn).Parse(patternsT)).Execute(file, patterns)
+	return filename, err
+}
+
+// patternsT generates the compiled validation regular expressions.
+// template input: []string
+const patternsT = `
+// The regular expressions used by the validations, compiled once.
+var (
+{{ range . }}	{{ patternVar . }} = regexp.MustCompile({{ printf "%q" . }})
+{{ end }})
+`
@@ -0,0 +1,249 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// PayloadGeneratorData contains the information required to generate the random value generators
+// of a payload type.
+type PayloadGeneratorData struct {
+	Name        string // Name of the public type, e.g. "CreateBottlePayload"
+	Ref         string // Reference to the type from the test package, e.g. "*app.CreateBottlePayload"
+	Schema      string // Name of the variable holding the type schema, e.g. "createBottlePayloadSchema"
+	SchemaCode  string // Schema literal
+	Validatable bool   // Whether the type has a Validate method
+}
+
+// generatePayloadGenerators generates functions that produce random instances of the user types and
+// action payloads. The values satisfy the design validations or violate a single validation rule so
+// that they can be used in property-based tests of the service implementation. The generators live
+// in the test package and rely on the goatest package to produce the values.
+func (g *Generator) generatePayloadGenerators() (err error) {
+	data := g.payloadGenerators()
+	if len(data) == 0 {
+		return nil
+	}
+	appPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(g.OutDir, "test", "generators.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Payload Generators", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("math/rand"),
+		codegen.SimpleImport(appPkg),
+		codegen.SimpleImport("github.com/goadesign/goa/goatest"),
+	}
+	if err = file.WriteHeader(title, "test", imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	tmpl := template.Must(template.New("payloadGenerator").Parse(payloadGeneratorT))
+	for _, d := range data {
+		if err = tmpl.Execute(file, d); err != nil {
+			return err
+		}
+	}
+	_, err = file.Write([]byte(decodeGeneratedT))
+	return err
+}
+
+// payloadGenerators returns the data needed to generate the random value generators of the user
+// types and of the action payloads that are not user types.
+func (g *Generator) payloadGenerators() []*PayloadGeneratorData {
+	var (
+		data []*PayloadGeneratorData
+		seen = make(map[string]bool)
+	)
+	add := func(t *design.UserTypeDefinition) {
+		if seen[t.TypeName] {
+			return
+		}
+		seen[t.TypeName] = true
+		if !t.IsObject() && !t.IsArray() || !testable(t.AttributeDefinition, nil) {
+			return
+		}
+		name := codegen.Goify(t.TypeName, true)
+		ref := g.Target + "." + name
+		if t.IsObject() {
+			ref = "*" + ref
+		}
+		data = append(data, &PayloadGeneratorData{
+			Name:        name,
+			Ref:         ref,
+			Schema:      codegen.Goify(t.TypeName, false) + "Schema",
+			SchemaCode:  schemaCode(t.AttributeDefinition, []string{t.TypeName}, 0),
+			Validatable: g.validator.Code(t.AttributeDefinition, false, false, false, "ut", "type", 1, false) != "",
+		})
+	}
+	g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		add(t)
+		return nil
+	})
+	g.API.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			if action.Payload != nil && !action.PayloadMultipart {
+				add(action.Payload)
+			}
+			return nil
+		})
+	})
+	return data
+}
+
+// schemaCode returns the Go code of the goatest.Schema literal describing att. seen lists the user
+// types being described, recursive references to them produce nil schemas.
+func schemaCode(att *design.AttributeDefinition, seen []string, depth int) string {
+	switch actual := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		return typeSchemaCode(actual.TypeName, actual.AttributeDefinition, seen, depth)
+	case *design.MediaTypeDefinition:
+		return typeSchemaCode(actual.TypeName, actual.AttributeDefinition, seen, depth)
+	}
+	var fields []string
+	field := func(name, code string) {
+		fields = append(fields, fmt.Sprintf("%s%s: %s,", codegen.Tabs(depth+1), name, code))
+	}
+	field("Type", strconv.Quote(schemaType(att.Type.Kind())))
+	if val := att.Validation; val != nil {
+		if len(val.Values) > 0 {
+			values := make([]string, len(val.Values))
+			for i, v := range val.Values {
+				values[i] = fmt.Sprintf("%#v", v)
+			}
+			field("Enum", fmt.Sprintf("[]interface{}{%s}", strings.Join(values, ", ")))
+		}
+		if val.Format != "" {
+			field("Format", strconv.Quote(val.Format))
+		}
+		if val.Pattern != "" {
+			field("Pattern", strconv.Quote(val.Pattern))
+		}
+		if val.Minimum != nil {
+			field("Minimum", fmt.Sprintf("goatest.Float64(%s)", strconv.FormatFloat(*val.Minimum, 'g', -1, 64)))
+		}
+		if val.Maximum != nil {
+			field("Maximum", fmt.Sprintf("goatest.Float64(%s)", strconv.FormatFloat(*val.Maximum, 'g', -1, 64)))
+		}
+		if val.MinLength != nil {
+			field("MinLength", fmt.Sprintf("goatest.Int(%d)", *val.MinLength))
+		}
+		if val.MaxLength != nil {
+			field("MaxLength", fmt.Sprintf("goatest.Int(%d)", *val.MaxLength))
+		}
+	}
+	switch actual := att.Type.(type) {
+	case design.Object:
+		if req := required(att); len(req) > 0 {
+			names := make([]string, len(req))
+			for i, n := range req {
+				names[i] = strconv.Quote(n)
+			}
+			field("Required", fmt.Sprintf("[]string{%s}", strings.Join(names, ", ")))
+		}
+		atts := make([]string, 0, len(actual))
+		for _, n := range sortedKeys(actual) {
+			atts = append(atts, fmt.Sprintf("%s%q: %s,", codegen.Tabs(depth+2), n, schemaCode(actual[n], seen, depth+2)))
+		}
+		field("Attributes", fmt.Sprintf("map[string]*goatest.Schema{\n%s\n%s}", strings.Join(atts, "\n"), codegen.Tabs(depth+1)))
+	case *design.Array:
+		field("Elem", schemaCode(actual.ElemType, seen, depth+1))
+	case *design.Hash:
+		field("Key", schemaCode(actual.KeyType, seen, depth+1))
+		field("Elem", schemaCode(actual.ElemType, seen, depth+1))
+	}
+	return fmt.Sprintf("&goatest.Schema{\n%s\n%s}", strings.Join(fields, "\n"), codegen.Tabs(depth))
+}
+
+// typeSchemaCode returns the Go code of the schema literal describing the user type with the given
+// name and attribute or "nil" if the type is already being described.
+func typeSchemaCode(name string, att *design.AttributeDefinition, seen []string, depth int) string {
+	for _, s := range seen {
+		if s == name {
+			return "nil"
+		}
+	}
+	return schemaCode(att, append(seen, name), depth)
+}
+
+// schemaType returns the goatest.Schema type of the given kind.
+func schemaType(kind design.Kind) string {
+	switch kind {
+	case design.BooleanKind:
+		return "boolean"
+	case design.IntegerKind:
+		return "integer"
+	case design.NumberKind:
+		return "number"
+	case design.StringKind:
+		return "string"
+	case design.DateTimeKind:
+		return "datetime"
+	case design.UUIDKind:
+		return "uuid"
+	case design.ArrayKind:
+		return "array"
+	case design.HashKind:
+		return "hash"
+	case design.ObjectKind, design.UserTypeKind, design.MediaTypeKind:
+		return "object"
+	}
+	return "any"
+}
+
+// payloadGeneratorT generates the random value generators of a type.
+// template input: *PayloadGeneratorData
+const payloadGeneratorT = `
+// {{ .Schema }} describes {{ .Name }} and its validations.
+var {{ .Schema }} = {{ .SchemaCode }}
+
+// Generate{{ .Name }} returns a random {{ .Name }} that satisfies the design validations.
+func Generate{{ .Name }}(r *rand.Rand) {{ .Ref }} {
+	var v {{ .Ref }}
+	decodeGenerated(goatest.Generate(r, {{ .Schema }}), &v)
+	return v
+}
+{{ if .Validatable }}
+// GenerateInvalid{{ .Name }} returns a random {{ .Name }} that violates a single design validation
+// and the description of the violated rule.
+func GenerateInvalid{{ .Name }}(r *rand.Rand) ({{ .Ref }}, string) {
+	invalid, rule := goatest.GenerateInvalid(r, {{ .Schema }})
+	var v {{ .Ref }}
+	decodeGenerated(invalid, &v)
+	return v, rule
+}
+{{ end }}`
+
+// decodeGeneratedT generates the function shared by the payload generators.
+const decodeGeneratedT = `
+// decodeGenerated decodes the JSON encoding of the generated value v into target.
+func decodeGenerated(v, target interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(b, target); err != nil {
+		panic(err)
+	}
+}
+`
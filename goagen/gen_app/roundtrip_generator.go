@@ -0,0 +1,150 @@
+package genapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// RoundTripTestData contains the information required to generate the round-trip test and the
+// benchmark of a user type or media type view.
+type RoundTripTestData struct {
+	Name      string // Test name suffix, e.g. "BottleTiny"
+	TypeName  string // Name of the type the example is decoded into, e.g. "bottlePayload"
+	Publicize bool   // Whether the decoded value must be publicized before being encoded
+	JSON      string // JSON example of the type
+}
+
+// generateRoundTripTests generates tests that decode an example of each user type and media type
+// view, encode the result back and check that the resulting JSON is equivalent to the example.
+// User type examples go through the same private type and Publicize transform as request payloads.
+func (g *Generator) generateRoundTripTests() (err error) {
+	if g.API.NoExamples {
+		return nil
+	}
+	tests := g.typeExamples()
+	if len(tests) == 0 {
+		return nil
+	}
+
+	filename := filepath.Join(g.OutDir, "round_trip_test.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application Types Round-Trip Tests", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("reflect"),
+		codegen.SimpleImport("testing"),
+	}
+	if err = file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	tmpl := template.Must(template.New("roundTrip").Parse(roundTripTestT))
+	for _, data := range tests {
+		if err = tmpl.Execute(file, data); err != nil {
+			return err
+		}
+	}
+	_, err = file.Write([]byte(roundTripHelperT))
+	return err
+}
+
+// typeExamples returns the examples of the user types and media type views whose JSON encoding
+// can be decoded into the generated types.
+func (g *Generator) typeExamples() []*RoundTripTestData {
+	var tests []*RoundTripTestData
+	g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		if !t.IsObject() || !testable(t.AttributeDefinition, nil) {
+			return nil
+		}
+		if example, ok := roundTripExample(t.AttributeDefinition, g.API.RandomGenerator()); ok {
+			tests = append(tests, &RoundTripTestData{
+				Name:      codegen.GoTypeName(t, t.AllRequired(), 0, false),
+				TypeName:  codegen.GoTypeName(t, t.AllRequired(), 0, true),
+				Publicize: true,
+				JSON:      example,
+			})
+		}
+		return nil
+	})
+	g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.IsError() || !mt.Type.IsObject() && !mt.Type.IsArray() {
+			return nil
+		}
+		return mt.IterateViews(func(view *design.ViewDefinition) error {
+			p, _, err := mt.Project(view.Name)
+			if err != nil || !testable(p.AttributeDefinition, nil) {
+				return nil
+			}
+			if example, ok := roundTripExample(p.AttributeDefinition, g.API.RandomGenerator()); ok {
+				name := codegen.GoTypeName(p, p.AllRequired(), 0, false)
+				tests = append(tests, &RoundTripTestData{Name: name, TypeName: name, JSON: example})
+			}
+			return nil
+		})
+	})
+	return tests
+}
+
+// roundTripExample returns the JSON encoding of an example of att.
+func roundTripExample(att *design.AttributeDefinition, r *design.RandomGenerator) (string, bool) {
+	example, ok := validExample(att, r)
+	if !ok {
+		return "", false
+	}
+	b, err := json.Marshal(example)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// roundTripTestT generates the round-trip test of a type.
+// template input: *RoundTripTestData
+const roundTripTestT = `
+// Test{{ .Name }}RoundTrip checks that encoding a decoded {{ .Name }} produces the original JSON.
+func Test{{ .Name }}RoundTrip(t *testing.T) {
+	const example = {{ printf "%q" .JSON }}
+	var v {{ .TypeName }}
+	if err := json.Unmarshal([]byte(example), &v); err != nil {
+		t.Fatalf("failed to decode %s: %s", example, err)
+	}
+	assertRoundTrip(t, example, {{ if .Publicize }}v.Publicize(){{ else }}v{{ end }})
+}
+`
+
+// roundTripHelperT generates the function shared by the round-trip tests.
+const roundTripHelperT = `
+// assertRoundTrip encodes v and checks that the result is equivalent to the JSON example it was
+// decoded from.
+func assertRoundTrip(t *testing.T, example string, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode: %s", err)
+	}
+	var expected, actual interface{}
+	if err := json.Unmarshal([]byte(example), &expected); err != nil {
+		t.Fatalf("invalid example %s: %s", example, err)
+	}
+	if err := json.Unmarshal(b, &actual); err != nil {
+		t.Fatalf("failed to decode %s: %s", b, err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("round-trip mismatch:\nexpected: %s\nactual:   %s", example, b)
+	}
+}
+`
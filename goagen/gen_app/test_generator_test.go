@@ -183,9 +183,39 @@ var _ = Describe("Generate", func() {
 			}
 		})
 
+		It("generates the media type round-trip tests", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "round_trip_test.go")))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "round_trip_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(content)).Should(ContainSubstring("func TestIntContainerRoundTrip(t *testing.T) {"))
+			Ω(string(content)).Should(ContainSubstring("assertRoundTrip(t, example, v)"))
+		})
+
+		It("generates the media type benchmarks", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "benchmark_test.go")))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "benchmark_test.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(content)).Should(ContainSubstring("func BenchmarkIntContainerEncode(b *testing.B) {"))
+			Ω(string(content)).Should(ContainSubstring("enc := goa.NewJSONEncoder(ioutil.Discard)"))
+		})
+
+		It("generates the payload generators", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(ContainElement(filepath.Join(outDir, "app", "test", "generators.go")))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "generators.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(string(content)).Should(ContainSubstring("func GenerateCustomName(r *rand.Rand) app.CustomName {"))
+			Ω(string(content)).ShouldNot(ContainSubstring("GenerateInvalidCustomName"))
+		})
+
 		It("does not call Validate on the resulting media type when it does not exist", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(15))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -194,7 +224,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates the ActionRouteResponse test methods ", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(15))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -0,0 +1,592 @@
+package genapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// ValidationTestData contains the information required to generate the validation test of a user
+// type.
+type ValidationTestData struct {
+	TypeName string                // Name of the public type, e.g. "CreateBottlePayload"
+	Cases    []*ValidationTestCase // Test cases, the first case is always valid
+}
+
+// ValidationTestCase is a single case of a generated validation test.
+type ValidationTestCase struct {
+	Name  string // Case name, e.g. "name: too short"
+	JSON  string // JSON representation of the type instance
+	Valid bool   // Whether Validate should succeed
+}
+
+// formatExamples lists a valid value for each string format, they replace the generated examples
+// which are not guaranteed to pass the format validations.
+var formatExamples = map[string]string{
+	"date":      "2006-01-02",
+	"date-time": "2006-01-02T15:04:05Z",
+	"uuid":      "5e4bb2ca-8f1f-4a5c-9d4b-2a0b1c3f4e5d",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.168.0.1",
+	"ipv6":      "::1",
+	"ip":        "127.0.0.1",
+	"uri":       "http://example.com",
+	"mac":       "01:23:45:67:89:ab",
+	"cidr":      "192.168.0.0/24",
+	"regexp":    "^a+$",
+	"rfc1123":   "Mon, 02 Jan 2006 15:04:05 MST",
+}
+
+// maxViolationLength is the maximum length of the values generated to violate a length validation.
+const maxViolationLength = 256
+
+// generateValidationTests generates table-driven tests for the Validate methods of the user types.
+// Each test decodes a valid example of the type and variations of it that each violate a single
+// validation rule.
+func (g *Generator) generateValidationTests() (err error) {
+	if g.API.NoExamples {
+		return nil
+	}
+	var tests []*ValidationTestData
+	g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		if data := g.validationTest(t); data != nil {
+			tests = append(tests, data)
+		}
+		return nil
+	})
+	if len(tests) == 0 {
+		return nil
+	}
+
+	filename := filepath.Join(g.OutDir, "user_types_test.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Application User Types Validation Tests", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("testing"),
+	}
+	if err = file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	tmpl := template.Must(template.New("validation").Parse(validationTestT))
+	for _, data := range tests {
+		if err = tmpl.Execute(file, data); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// validationTest computes the test cases for the given user type. It returns nil if the type has no
+// Validate method or if no valid example could be generated for it.
+func (g *Generator) validationTest(t *design.UserTypeDefinition) *ValidationTestData {
+	if !t.IsObject() || !testable(t.AttributeDefinition, nil) {
+		return nil
+	}
+	if g.validator.Code(t.AttributeDefinition, false, false, false, "ut", "type", 1, false) == "" {
+		return nil
+	}
+	example, ok := validExample(t.AttributeDefinition, g.API.RandomGenerator())
+	if !ok || !conforms(t.AttributeDefinition, example) {
+		return nil
+	}
+	valid, err := json.Marshal(example)
+	if err != nil {
+		return nil
+	}
+	data := &ValidationTestData{
+		TypeName: codegen.GoTypeName(t, t.AllRequired(), 0, false),
+		Cases:    []*ValidationTestCase{{Name: "valid", JSON: string(valid), Valid: true}},
+	}
+	var root interface{} = example
+	violations(t.AttributeDefinition, "", example, func(v interface{}) { root = v }, nil,
+		func(name string, restore func()) {
+			if b, err := json.Marshal(root); err == nil && string(b) != string(valid) {
+				data.Cases = append(data.Cases, &ValidationTestCase{Name: name, JSON: string(b)})
+			}
+			restore()
+		})
+	return data
+}
+
+// testable returns true if the instances of the given attribute can be decoded from JSON into the
+// generated type as is.
+func testable(att *design.AttributeDefinition, seen map[string]bool) bool {
+	if _, ok := att.Metadata["struct:field:type"]; ok {
+		return false
+	}
+	if _, ok := att.Metadata["struct:tag:json"]; ok {
+		return false
+	}
+	switch actual := att.Type.(type) {
+	case design.Primitive:
+		return actual.Kind() != design.FileKind
+	case *design.Array:
+		return testable(actual.ElemType, seen)
+	case *design.Hash:
+		if k := actual.KeyType.Type.Kind(); k != design.StringKind && k != design.IntegerKind {
+			// JSON object keys only decode into string and integer map keys.
+			return false
+		}
+		return testable(actual.KeyType, seen) && testable(actual.ElemType, seen)
+	case design.Object:
+		for _, n := range sortedKeys(actual) {
+			if !testable(actual[n], seen) {
+				return false
+			}
+		}
+		return true
+	case *design.UserTypeDefinition:
+		return testableType(actual.TypeName, actual.AttributeDefinition, seen)
+	case *design.MediaTypeDefinition:
+		return testableType(actual.Identifier, actual.AttributeDefinition, seen)
+	}
+	return false
+}
+
+// testableType calls testable on the attribute of a user type unless the type was already visited.
+func testableType(name string, att *design.AttributeDefinition, seen map[string]bool) bool {
+	if seen[name] {
+		return true
+	}
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	seen[name] = true
+	return testable(att, seen)
+}
+
+// validExample generates an example for att and normalizes it so that it has the same shape as
+// the result of decoding JSON. Numbers are decoded as json.Number to preserve large integers.
+func validExample(att *design.AttributeDefinition, r *design.RandomGenerator) (interface{}, bool) {
+	b, err := json.Marshal(jsonable(att.GenerateExample(r, nil)))
+	if err != nil {
+		return nil, false
+	}
+	var example interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&example); err != nil {
+		return nil, false
+	}
+	return normalize(att, example, 0), example != nil
+}
+
+// normalize replaces the values of the date time and UUID attributes and of the string attributes
+// that define a format with a value known to be valid.
+func normalize(att *design.AttributeDefinition, v interface{}, depth int) interface{} {
+	if v == nil || depth > 10 {
+		return v
+	}
+	switch att.Type.Kind() {
+	case design.DateTimeKind:
+		return formatExamples["date-time"]
+	case design.UUIDKind:
+		return formatExamples["uuid"]
+	}
+	if att.Validation != nil && att.Validation.Format != "" && att.Type.Kind() == design.StringKind {
+		if val, ok := formatExamples[att.Validation.Format]; ok {
+			return val
+		}
+	}
+	if ds, ok := att.Type.(design.DataStructure); ok {
+		return normalize(ds.Definition(), v, depth+1)
+	}
+	switch {
+	case att.Type.IsObject():
+		if m, ok := v.(map[string]interface{}); ok {
+			for n, child := range att.Type.ToObject() {
+				if e, ok := m[n]; ok {
+					m[n] = normalize(child, e, depth+1)
+				}
+			}
+		}
+	case att.Type.IsArray():
+		if a, ok := v.([]interface{}); ok {
+			for i, e := range a {
+				a[i] = normalize(att.Type.ToArray().ElemType, e, depth+1)
+			}
+		}
+	case att.Type.IsHash():
+		if m, ok := v.(map[string]interface{}); ok {
+			for k, e := range m {
+				m[k] = normalize(att.Type.ToHash().ElemType, e, depth+1)
+			}
+		}
+	}
+	return v
+}
+
+// conforms returns true if v satisfies the validations of att that can be checked at generation
+// time.
+func conforms(att *design.AttributeDefinition, v interface{}) bool {
+	return conformsDepth(att, v, 0)
+}
+
+func conformsDepth(att *design.AttributeDefinition, v interface{}, depth int) bool {
+	if v == nil || depth > 10 {
+		return true
+	}
+	if val := att.Validation; val != nil {
+		if len(val.Values) > 0 && !inEnum(val.Values, v) {
+			return false
+		}
+		if s, ok := v.(string); ok && val.Pattern != "" {
+			if matched, err := regexp.MatchString(val.Pattern, s); err != nil || !matched {
+				return false
+			}
+		}
+		if f, ok := toFloat(v); ok {
+			if val.Minimum != nil && f < *val.Minimum || val.Maximum != nil && f > *val.Maximum {
+				return false
+			}
+		}
+		if l, ok := length(v); ok {
+			if val.MinLength != nil && l < *val.MinLength || val.MaxLength != nil && l > *val.MaxLength {
+				return false
+			}
+		}
+	}
+	if ds, ok := att.Type.(design.DataStructure); ok {
+		return conformsDepth(ds.Definition(), v, depth+1)
+	}
+	switch {
+	case att.Type.IsObject():
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, n := range required(att) {
+			if _, ok := m[n]; !ok {
+				return false
+			}
+		}
+		for n, child := range att.Type.ToObject() {
+			if !conformsDepth(child, m[n], depth+1) {
+				return false
+			}
+		}
+	case att.Type.IsArray():
+		a, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, e := range a {
+			if !conformsDepth(att.Type.ToArray().ElemType, e, depth+1) {
+				return false
+			}
+		}
+	case att.Type.IsHash():
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, e := range m {
+			if !conformsDepth(att.Type.ToHash().ElemType, e, depth+1) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// violations calls add once for each single validation rule that can be violated by modifying v.
+// set replaces v in its parent, add must call restore after having recorded the modified value.
+func violations(att *design.AttributeDefinition, path string, v interface{}, set func(interface{}), seen []string, add func(string, func())) {
+	violate := func(rule string, value interface{}) {
+		set(value)
+		add(strings.TrimPrefix(path+": "+rule, ": "), func() { set(v) })
+	}
+	kind := att.Type.Kind()
+	if val := att.Validation; val != nil {
+		if len(val.Values) > 0 {
+			if invalid, ok := notInEnum(kind, val.Values); ok {
+				violate("not in enum", invalid)
+			}
+		}
+		if val.Format != "" && kind == design.StringKind {
+			violate("invalid format", "(")
+		}
+		if val.Pattern != "" && kind == design.StringKind {
+			for _, s := range []string{"", "!", "(", " "} {
+				if matched, err := regexp.MatchString(val.Pattern, s); err == nil && !matched {
+					violate("does not match pattern", s)
+					break
+				}
+			}
+		}
+		if kind == design.IntegerKind || kind == design.NumberKind {
+			if val.Minimum != nil {
+				below := *val.Minimum - 1
+				if kind == design.IntegerKind {
+					below = math.Ceil(*val.Minimum) - 1
+				}
+				if math.Abs(below) < 1e15 {
+					violate("below minimum", below)
+				}
+			}
+			if val.Maximum != nil {
+				above := *val.Maximum + 1
+				if kind == design.IntegerKind {
+					above = math.Floor(*val.Maximum) + 1
+				}
+				if math.Abs(above) < 1e15 {
+					violate("above maximum", above)
+				}
+			}
+		}
+		if val.MinLength != nil && *val.MinLength > 0 && *val.MinLength <= maxViolationLength {
+			if short, ok := withLength(kind, v, *val.MinLength-1); ok {
+				violate("too short", short)
+			}
+		}
+		if val.MaxLength != nil && *val.MaxLength < maxViolationLength {
+			if long, ok := withLength(kind, v, *val.MaxLength+1); ok {
+				violate("too long", long)
+			}
+		}
+	}
+	switch actual := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		// Media types define their own validations.
+		return
+	case *design.UserTypeDefinition:
+		if len(seen) > 3 {
+			return
+		}
+		for _, s := range seen {
+			if s == actual.TypeName {
+				return
+			}
+		}
+		violations(actual.AttributeDefinition, path, v, set, append(seen, actual.TypeName), add)
+		return
+	}
+	switch {
+	case att.Type.IsObject():
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj := att.Type.ToObject()
+		for _, n := range required(att) {
+			child, ok := obj[n]
+			e, present := m[n]
+			if !ok || !present || child.Type.IsPrimitive() && child.Type.Kind() != design.StringKind {
+				// Missing required primitive fields are zero values in the public types.
+				continue
+			}
+			delete(m, n)
+			add(strings.TrimPrefix(path+"."+n, ".")+": missing", func() { m[n] = e })
+		}
+		for _, n := range sortedKeys(obj) {
+			e, ok := m[n]
+			if !ok || e == nil {
+				continue
+			}
+			name := n
+			violations(obj[n], strings.TrimPrefix(path+"."+n, "."), e, func(nv interface{}) { m[name] = nv }, seen, add)
+		}
+	case att.Type.IsArray():
+		a, ok := v.([]interface{})
+		if !ok || len(a) == 0 || a[0] == nil {
+			return
+		}
+		violations(att.Type.ToArray().ElemType, path+"[0]", a[0], func(nv interface{}) { a[0] = nv }, seen, add)
+	}
+}
+
+// required returns the names of the required attributes of the object att.
+func required(att *design.AttributeDefinition) []string {
+	if att.Validation == nil {
+		return nil
+	}
+	return att.Validation.Required
+}
+
+// notInEnum returns a value of the given kind that is not one of values.
+func notInEnum(kind design.Kind, values []interface{}) (interface{}, bool) {
+	switch kind {
+	case design.StringKind:
+		candidate := "x"
+		for inEnum(values, candidate) {
+			candidate += "x"
+		}
+		return candidate, true
+	case design.IntegerKind, design.NumberKind:
+		max := math.Inf(-1)
+		for _, v := range values {
+			f, ok := toFloat(v)
+			if !ok {
+				return nil, false
+			}
+			max = math.Max(max, f)
+		}
+		if math.Abs(max) >= 1e15 {
+			return nil, false
+		}
+		return math.Floor(max) + 1, true
+	}
+	return nil, false
+}
+
+// inEnum returns true if v is equal to one of values once both are encoded in JSON.
+func inEnum(values []interface{}, v interface{}) bool {
+	for _, e := range values {
+		if f, ok := toFloat(e); ok {
+			if g, ok := toFloat(v); ok && f == g {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(jsonable(e), v) {
+			return true
+		}
+	}
+	return false
+}
+
+// withLength returns a string or an array derived from v with the given length.
+func withLength(kind design.Kind, v interface{}, l int) (interface{}, bool) {
+	switch kind {
+	case design.StringKind:
+		return strings.Repeat("a", l), true
+	case design.ArrayKind:
+		a, ok := v.([]interface{})
+		if !ok || l > 0 && len(a) == 0 {
+			return nil, false
+		}
+		res := make([]interface{}, l)
+		for i := range res {
+			res[i] = a[0]
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// length returns the length of v as computed by the generated validation code.
+func length(v interface{}) (int, bool) {
+	switch actual := v.(type) {
+	case string:
+		return len([]rune(actual)), true
+	case []interface{}:
+		return len(actual), true
+	case map[string]interface{}:
+		return len(actual), true
+	}
+	return 0, false
+}
+
+// toFloat converts the numeric values used in designs and examples to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// jsonable converts the hashes with non string keys produced by the example generator so that the
+// result may be encoded in JSON.
+func jsonable(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[fmt.Sprintf("%v", k)] = jsonable(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = jsonable(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = jsonable(e)
+		}
+		return res
+	}
+	return v
+}
+
+// sortedKeys returns the names of the object attributes sorted alphabetically.
+func sortedKeys(o design.Object) []string {
+	keys := make([]string, 0, len(o))
+	for n := range o {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validationTestT generates the table-driven test of a user type Validate method.
+// template input: *ValidationTestData
+const validationTestT = `
+// Test{{ .TypeName }}Validate checks that Validate accepts a valid {{ .TypeName }} and rejects
+// instances that violate a single validation rule.
+func Test{{ .TypeName }}Validate(t *testing.T) {
+	cases := []struct {
+		Name  string
+		JSON  string
+		Valid bool
+	}{
+{{- range .Cases }}
+		{ {{ printf "%q" .Name }}, {{ printf "%q" .JSON }}, {{ .Valid }} },
+{{- end }}
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var ut {{ .TypeName }}
+			if err := json.Unmarshal([]byte(c.JSON), &ut); err != nil {
+				t.Fatalf("failed to decode %s: %s", c.JSON, err)
+			}
+			err := ut.Validate()
+			if c.Valid && err != nil {
+				t.Errorf("unexpected validation error: %s", err)
+			}
+			if !c.Valid && err == nil {
+				t.Errorf("expected a validation error for %s", c.JSON)
+			}
+		})
+	}
+}
+`
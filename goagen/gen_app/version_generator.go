@@ -0,0 +1,80 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/template"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateVersion generates the constants that record the version and the hash of the design the
+// package was generated from and the middleware that reports them in the responses so that running
+// services can tell exactly which contract they implement.
+func (g *Generator) generateVersion() (err error) {
+	filename := filepath.Join(g.OutDir, "version.go")
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	title := fmt.Sprintf("%s: Design Version", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	if err = file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	data := map[string]string{
+		"Version": g.API.Version,
+		"Hash":    codegen.DesignHash,
+		"Stamp":   DesignStamp(g.API.Version, codegen.DesignHash),
+	}
+	return template.Must(template.New("version").Parse(versionT)).Execute(file, data)
+}
+
+// DesignStamp returns the value of the header that reports the design version and hash. It uses the
+// semantic versioning build metadata syntax, e.g. "1.0+6b0f1c...", and omits the parts that are
+// empty.
+func DesignStamp(version, hash string) string {
+	if hash == "" {
+		return version
+	}
+	if version == "" {
+		return hash
+	}
+	return version + "+" + hash
+}
+
+// versionT generates the design version constants and middleware.
+// template input: map[string]string
+const versionT = `
+const (
+	// DesignVersion is the version of the API design the package was generated from.
+	DesignVersion = {{ printf "%q" .Version }}
+	// DesignHash is the hash of the design package sources the package was generated from.
+	DesignHash = {{ printf "%q" .Hash }}
+	// DesignVersionHeader is the name of the response header set by DesignVersionMiddleware.
+	DesignVersionHeader = "X-Design-Version"
+)
+
+// DesignVersionMiddleware returns a middleware that sets the DesignVersionHeader response header to
+// the design version and hash, e.g. "1.0+6b0f1c...".
+func DesignVersionMiddleware() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Set(DesignVersionHeader, {{ printf "%q" .Stamp }})
+			return h(ctx, rw, req)
+		}
+	}
+}
+`
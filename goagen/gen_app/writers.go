@@ -46,6 +46,11 @@ type (
 		SecurityTmpl *template.Template
 	}
 
+	// WebhooksWriter generate code for the webhooks described with the Webhook DSL.
+	WebhooksWriter struct {
+		*codegen.SourceFile
+	}
+
 	// ResourcesWriter generate code for a goa application resources.
 	// Resources are data structures initialized by the application handlers and passed to controller
 	// actions.
@@ -60,6 +65,12 @@ type (
 		*codegen.SourceFile
 		MediaTypeTmpl *template.Template
 		Validator     *codegen.Validator
+		// MarshalJSON lists the names of the media types that get reflection-free JSON
+		// encoding methods.
+		MarshalJSON map[string]bool
+		// ParallelJSON contains the number of elements from which the elements of the
+		// collection media types listed in MarshalJSON are encoded concurrently.
+		ParallelJSON map[string]int
 	}
 
 	// UserTypesWriter generate code for a goa application user types.
@@ -85,13 +96,14 @@ type (
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		Subscription string // Name of the event streamed by the action if any
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
 	ControllerTemplateData struct {
 		API            *design.APIDefinition          // API definition
 		Resource       string                         // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context" and "Unmarshal"
+		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context", "Unmarshal", "Timeout", "CSRF", "Audit" and "AuditIdentifiers"
 		FileServers    []*design.FileServerDefinition // File servers
 		Encoders       []*EncoderTemplateData         // Encoder data
 		Decoders       []*EncoderTemplateData         // Decoder data
@@ -107,6 +119,7 @@ type (
 		Type              *design.MediaTypeDefinition // Type of resource media type
 		CanonicalTemplate string                      // CanonicalFormat represents the resource canonical path in the form of a fmt.Sprintf format.
 		CanonicalParams   []string                    // CanonicalParams is the list of parameter names that appear in the resource canonical path in order.
+		HAL               bool                        // HAL is true if the API uses HAL links.
 	}
 
 	// EncoderTemplateData contains the data needed to render the registration code for a single
@@ -123,6 +136,48 @@ type (
 		// Default is true if this encoder/decoder should be set as the default.
 		Default bool
 	}
+
+	// SecurityResponseTemplateData contains the data needed to render the constructor of the
+	// errors sent using a response defined by a security scheme.
+	SecurityResponseTemplateData struct {
+		// Name is the name of the response, e.g. "Unauthorized".
+		Name string
+		// Status is the response HTTP status code.
+		Status int
+		// ContentType is the response Content-Type header value.
+		ContentType string
+		// TypeRef is the Go type reference of the response body, empty if there is none.
+		TypeRef string
+	}
+
+	// MediaTypeProjectionData contains the data needed to render the method that projects the
+	// default view of a media type onto one of its other views.
+	MediaTypeProjectionData struct {
+		// Method is the name of the projection method, e.g. "ProjectTiny".
+		Method string
+		// View is the name of the view the method projects onto.
+		View string
+		// Source is the Go type reference of the default view type, e.g. "*Bottle".
+		Source string
+		// Target is the Go type reference of the projected view type, e.g. "*BottleTiny".
+		Target string
+		// TargetName is the Go type name of the projected view type, e.g. "BottleTiny".
+		TargetName string
+		// Collection is true if the media type is a collection, in which case the method
+		// projects each element.
+		Collection bool
+		// Fields lists the struct fields rendered by the view.
+		Fields []*ProjectedField
+	}
+
+	// ProjectedField describes how a projection method computes a field of the projected type.
+	ProjectedField struct {
+		// Name is the name of the struct field.
+		Name string
+		// Method is the name of the projection method called on the field value, empty if
+		// the value is copied as is.
+		Method string
+	}
 )
 
 // IsPathParam returns true if the given parameter name corresponds to a path parameter for all
@@ -219,6 +274,11 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
 	}
+	if data.Subscription != "" {
+		if err := w.ExecuteTemplate("subscribe", ctxSubscribeT, nil, data); err != nil {
+			return err
+		}
+	}
 	if data.Payload != nil {
 		found := false
 		for _, t := range design.Design.Types {
@@ -333,15 +393,18 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 			}
 		}
 		fn := template.FuncMap{
-			"newCoerceData":  newCoerceData,
-			"finalizeCode":   w.Finalizer.Code,
-			"validationCode": w.Validator.Code,
+			"newCoerceData":       newCoerceData,
+			"finalizeCode":        w.Finalizer.Code,
+			"validationCode":      w.Validator.Code,
+			"arrayValidationCode": arrayValidationCode,
+			"maxLength":           maxLength,
+			"isUserType":          isUserType,
 		}
 		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
 			return err
 		}
 	}
-	return nil
+	return w.ExecuteTemplate("controllers", controllersT, nil, data)
 }
 
 // NewSecurityWriter returns a security functionality code writer.
@@ -356,7 +419,27 @@ func NewSecurityWriter(filename string) (*SecurityWriter, error) {
 
 // Execute adds the different security schemes and middleware supporting functions.
 func (w *SecurityWriter) Execute(schemes []*design.SecuritySchemeDefinition) error {
-	return w.ExecuteTemplate("security_schemes", securitySchemesT, nil, schemes)
+	fn := template.FuncMap{
+		"securityResponses":    securityResponses,
+		"hasSecurityResponses": hasSecurityResponses,
+		"securityFailures":     securityFailures,
+		"isCustomFailureBody":  isCustomFailureBody,
+	}
+	return w.ExecuteTemplate("security_schemes", securitySchemesT, fn, schemes)
+}
+
+// NewWebhooksWriter returns a webhooks code writer.
+func NewWebhooksWriter(filename string) (*WebhooksWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhooksWriter{SourceFile: file}, nil
+}
+
+// Execute writes the Webhooks struct and the methods that send the requests of each webhook.
+func (w *WebhooksWriter) Execute(webhooks []*design.WebhookDefinition) error {
+	return w.ExecuteTemplate("webhooks", webhooksT, nil, webhooks)
 }
 
 // NewResourcesWriter returns a contexts code writer.
@@ -398,7 +481,19 @@ func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 		if err != nil {
 			return err
 		}
-		return w.ExecuteTemplate("mediatype", mediaTypeT, fn, p)
+		if err := w.ExecuteTemplate("mediatype", mediaTypeT, fn, p); err != nil {
+			return err
+		}
+		if !w.MarshalJSON[mt.TypeName] {
+			return nil
+		}
+		code, fallible := appendJSONCode(p, w.ParallelJSON[mt.TypeName])
+		data := map[string]interface{}{
+			"TypeRef":  codegen.GoTypeRef(p, p.AllRequired(), 0, false),
+			"Code":     code,
+			"Fallible": fallible,
+		}
+		return w.ExecuteTemplate("mediatypemarshaljson", mediaTypeMarshalJSONT, nil, data)
 	})
 	if err != nil {
 		return err
@@ -408,7 +503,138 @@ func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 			return err
 		}
 	}
-	return nil
+	return mt.IterateViews(func(view *design.ViewDefinition) error {
+		if view.Name == design.DefaultView {
+			return nil
+		}
+		data := mediaTypeProjection(mt, view.Name, make(map[string]bool))
+		if data == nil {
+			return nil
+		}
+		return w.ExecuteTemplate("mediatypeprojection", mediaTypeProjectionT, nil, data)
+	})
+}
+
+// projectionMethod returns the name of the method that projects the default view of a media type
+// onto the given view.
+func projectionMethod(view string) string {
+	return "Project" + codegen.Goify(view, true)
+}
+
+// mediaTypeProjection computes the data used to render the method that builds the given view of a
+// media type out of its default view. The method only copies the fields rendered by the view and
+// recursively projects the nested media types (including collections) instead of copying their
+// default view. mediaTypeProjection returns nil if the view cannot be computed from the default
+// view, for example because it renders attributes the default view omits. seen records the
+// projections being computed to handle recursive media types.
+func mediaTypeProjection(mt *design.MediaTypeDefinition, view string, seen map[string]bool) *MediaTypeProjectionData {
+	if view == design.DefaultView {
+		return nil
+	}
+	key := mt.TypeName + "#" + view
+	if ok, done := seen[key]; done {
+		if !ok {
+			return nil
+		}
+		// Recursive projection, the method is being computed by the caller.
+		return &MediaTypeProjectionData{Method: projectionMethod(view)}
+	}
+	seen[key] = true
+	data := projectMediaType(mt, view, seen)
+	seen[key] = data != nil
+	return data
+}
+
+// projectMediaType implements mediaTypeProjection.
+func projectMediaType(mt *design.MediaTypeDefinition, view string, seen map[string]bool) *MediaTypeProjectionData {
+	if _, ok := mt.Views[design.DefaultView]; !ok {
+		return nil
+	}
+	src, _, err := mt.Project(design.DefaultView)
+	if err != nil {
+		return nil
+	}
+	target, _, err := mt.Project(view)
+	if err != nil {
+		return nil
+	}
+	data := &MediaTypeProjectionData{
+		Method:     projectionMethod(view),
+		View:       view,
+		Source:     codegen.GoTypeRef(src, src.AllRequired(), 0, false),
+		Target:     codegen.GoTypeRef(target, target.AllRequired(), 0, false),
+		TargetName: codegen.GoTypeName(target, target.AllRequired(), 0, false),
+	}
+	if mt.IsArray() {
+		elem, ok := mt.ToArray().ElemType.Type.(*design.MediaTypeDefinition)
+		if !ok || mediaTypeProjection(elem, view, seen) == nil {
+			return nil
+		}
+		data.Collection = true
+		return data
+	}
+	srcObj := src.Type.ToObject()
+	targetObj := target.Type.ToObject()
+	mtObj := mt.Type.ToObject()
+	names := make([]string, 0, len(targetObj))
+	for n := range targetObj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		att := targetObj[n]
+		if _, ok := srcObj[n]; !ok {
+			return nil
+		}
+		field := &ProjectedField{Name: codegen.GoifyAtt(att, n, true)}
+		if field.Name == data.Method {
+			return nil
+		}
+		if projectedFieldType(target.AttributeDefinition, n) != projectedFieldType(src.AttributeDefinition, n) {
+			mtAtt, ok := mtObj[n]
+			if !ok {
+				return nil
+			}
+			nested, ok := mtAtt.Type.(*design.MediaTypeDefinition)
+			if !ok || fieldView(mt, design.DefaultView, n) != design.DefaultView {
+				return nil
+			}
+			nv := fieldView(mt, view, n)
+			if mediaTypeProjection(nested, nv, seen) == nil {
+				return nil
+			}
+			field.Method = projectionMethod(nv)
+		}
+		data.Fields = append(data.Fields, field)
+	}
+	return data
+}
+
+// projectedFieldType returns the Go type of the struct field generated for the attribute with the
+// given name of the given object attribute.
+func projectedFieldType(def *design.AttributeDefinition, name string) string {
+	field := def.Type.ToObject()[name]
+	typedef := codegen.GoTypeDef(field, 0, false, false)
+	if field.Type.IsObject() || def.IsPrimitivePointer(name) {
+		typedef = "*" + typedef
+	}
+	return typedef
+}
+
+// fieldView returns the name of the view used to render the media type attribute with the given
+// name in the given view of its parent media type.
+func fieldView(mt *design.MediaTypeDefinition, view, name string) string {
+	var nv string
+	if att, ok := mt.Views[view].Type.ToObject()[name]; ok {
+		nv = att.View
+	}
+	if nv == "" {
+		nv = mt.Type.ToObject()[name].View
+	}
+	if nv == "" {
+		nv = design.DefaultView
+	}
+	return nv
 }
 
 // NewUserTypesWriter returns a contexts code writer.
@@ -434,6 +660,37 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 	return w.ExecuteTemplate("types", userTypeT, fn, t)
 }
 
+// maxLength returns the maximum length of the array attribute att, -1 if it has none.
+func maxLength(att *design.AttributeDefinition) int {
+	if att.Validation == nil || att.Validation.MaxLength == nil {
+		return -1
+	}
+	return *att.Validation.MaxLength
+}
+
+// arrayValidationCode produces the code validating the array attribute att itself. The elements
+// and the maximum length are validated while the array is decoded so they are left out.
+func arrayValidationCode(att *design.AttributeDefinition, target, context string, depth int) string {
+	if att.Validation == nil {
+		return ""
+	}
+	val := *att.Validation
+	val.MaxLength = nil
+	a := *att
+	a.Validation = &val
+	return codegen.ValidationChecker(&a, false, false, false, target, context, depth, false)
+}
+
+// isUserType returns true if the type of att is a user type or a media type, that is if the
+// generated code validates its values with their Validate method.
+func isUserType(att *design.AttributeDefinition) bool {
+	switch att.Type.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		return true
+	}
+	return false
+}
+
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
 func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
 	return map[string]interface{}{
@@ -451,6 +708,78 @@ func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
 }
 
+// securityResponses returns the data needed to render the responses defined by the security
+// scheme sorted by name.
+func securityResponses(scheme *design.SecuritySchemeDefinition) ([]*SecurityResponseTemplateData, error) {
+	names := make([]string, 0, len(scheme.Responses))
+	for n := range scheme.Responses {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	data := make([]*SecurityResponseTemplateData, len(names))
+	for i, n := range names {
+		resp := scheme.Responses[n]
+		d := &SecurityResponseTemplateData{Name: resp.Name, Status: resp.Status, ContentType: resp.MediaType}
+		mt, ok := resp.Type.(*design.MediaTypeDefinition)
+		if !ok && resp.Type != nil {
+			d.TypeRef = codegen.GoTypeRef(resp.Type, nil, 0, false)
+		} else if mt == nil {
+			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
+		}
+		if mt != nil {
+			view := resp.ViewName
+			if view == "" {
+				view = "default"
+			}
+			projected, _, err := mt.Project(view)
+			if err != nil {
+				return nil, err
+			}
+			d.TypeRef = codegen.GoTypeRef(projected, projected.AllRequired(), 0, false)
+			if mt.ContentType != "" {
+				d.ContentType = mt.ContentType
+			}
+		}
+		data[i] = d
+	}
+	return data, nil
+}
+
+// securityFailures returns the data needed to render the responses sent when the auth middleware
+// of the security scheme rejects a request. It keeps the first response by name for each status.
+func securityFailures(scheme *design.SecuritySchemeDefinition) ([]*SecurityResponseTemplateData, error) {
+	resps, err := securityResponses(scheme)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[int]bool)
+	var failures []*SecurityResponseTemplateData
+	for _, r := range resps {
+		if seen[r.Status] {
+			continue
+		}
+		seen[r.Status] = true
+		failures = append(failures, r)
+	}
+	return failures, nil
+}
+
+// isCustomFailureBody returns true if the body of the response cannot be built from the auth
+// middleware error directly, i.e. if it is neither an error nor a string.
+func isCustomFailureBody(r *SecurityResponseTemplateData) bool {
+	return r.TypeRef != "" && r.TypeRef != "error" && r.TypeRef != "string"
+}
+
+// hasSecurityResponses returns true if any of the security schemes defines responses.
+func hasSecurityResponses(schemes []*design.SecuritySchemeDefinition) bool {
+	for _, s := range schemes {
+		if len(s.Responses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
@@ -640,6 +969,37 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.
 	return err{{ else }}
 	return nil{{ end }}
 }
+{{ if .Response.MediaType }}
+// {{ goify .Response.Name true }}Stream sends a HTTP response with status code {{ .Response.Status }} whose body is copied from r
+// without loading it in memory.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}Stream(r io.Reader) error {
+	if ctx.ResponseData.Header().Get("Content-Type") == "" {
+		ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
+	}
+	ctx.ResponseData.WriteHeader({{ .Response.Status }})
+	_, err := io.Copy(ctx.ResponseData, r)
+	return err
+}
+{{ if eq .Response.Status 200 }}
+// {{ goify .Response.Name true }}Content sends the content read from content with http.ServeContent which handles range
+// and conditional requests and sends files with sendfile when possible. modtime is the last
+// modification time of the content, it is ignored if zero.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}Content(modtime time.Time, content io.ReadSeeker) error {
+	if ctx.ResponseData.Header().Get("Content-Type") == "" {
+		ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
+	}
+	http.ServeContent(ctx.ResponseData, ctx.RequestData.Request, "", modtime, content)
+	return nil
+}
+{{ end }}{{ end }}`
+
+	// ctxSubscribeT generates the code for the context subscription method.
+	// template input: *ContextTemplateData
+	ctxSubscribeT = `// Subscribe returns a channel that receives the values published to b for the {{ printf "%q" .Subscription }}
+// event. The subscription is canceled and the channel closed once the request completes.
+func (ctx *{{ .Name }}) Subscribe(b *goa.Broadcaster) <-chan interface{} {
+	return b.SubscribeContext(ctx, {{ printf "%q" .Subscription }})
+}
 `
 
 	// payloadT generates the payload type definition GoGenerator
@@ -723,6 +1083,7 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 		// Build the context
 		rctx, err := New{{ .Context }}(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 {{ if .Payload }}		// Build the payload
@@ -731,9 +1092,23 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 {{ if not .PayloadOptional }}		} else {
 			return goa.MissingPayloadError()
 {{ end }}		}
-{{ end }}		return ctrl.{{ .Name }}(rctx)
-	}
-{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ end }}{{ range .AuditIdentifiers }}{{ if .Payload }}		if rctx.Payload != nil {
+			goa.AuditIdentifier(ctx, {{ printf "%q" .Name }}, rctx.Payload.{{ .Field }})
+		}
+{{ else }}		goa.AuditIdentifier(ctx, {{ printf "%q" .Name }}, rctx.{{ .Field }})
+{{ end }}{{ end }}{{ if .PayloadPool }}		err = ctrl.{{ .Name }}(rctx)
+		if rctx.Payload != nil {
+			// Release the payload now that the action has returned
+			*rctx.Payload = {{ gotypename .Payload nil 2 false }}{}
+			{{ .PayloadPool }}.Put(rctx.Payload)
+		}
+		return err
+{{ else }}		return ctrl.{{ .Name }}(rctx)
+{{ end }}	}
+{{ with .Timeout }}	h = goa.TimeoutHandler(h, {{ . }})
+{{ end }}{{ if .CSRF }}	h = goa.CSRFHandler(h)
+{{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
+{{ end }}{{ if .Audit }}	h = goa.AuditHandler(h)
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
@@ -744,6 +1119,28 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 {{ end }}	service.Mux.Handle("GET", "{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" .RequestPath) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
 {{ end }}}
+`
+
+	// controllersT generates the struct that groups all the controllers of the API and the
+	// function that mounts them.
+	// template input: []*ControllerTemplateData
+	controllersT = `// Controllers groups the implementations of all the API controllers. Use NewControllers as the
+// provider when wiring the service with a dependency injection framework such as Wire or fx.
+type Controllers struct {
+{{ range . }}	{{ .Resource }} {{ .Resource }}Controller
+{{ end }}}
+
+// NewControllers returns the Controllers struct initialized with the given controllers.
+func NewControllers({{ range $i, $d := . }}{{ if $i }}, {{ end }}{{ goify $d.Resource false }} {{ $d.Resource }}Controller{{ end }}) *Controllers {
+	return &Controllers{
+{{ range . }}		{{ .Resource }}: {{ goify .Resource false }},
+{{ end }}	}
+}
+
+// MountAll mounts all the controllers on the given service.
+func MountAll(service *goa.Service, ctrls *Controllers) {
+{{ range . }}	Mount{{ .Resource }}Controller(service, ctrls.{{ .Resource }})
+{{ end }}}
 `
 
 	// handleCORST generates the code that checks whether a CORS request is authorized
@@ -780,7 +1177,14 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 
 	// unmarshalT generates the code for an action payload unmarshal function.
 	// template input: *ControllerTemplateData
-	unmarshalT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `{{ range .Actions }}{{ if .Payload }}
+	unmarshalT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `{{ range .Actions }}{{ if .Payload }}{{ if .PayloadPool }}
+// {{ .PayloadPool }} and {{ .RawPayloadPool }} recycle the payload structs of the
+// {{ .DesignName }} action. The payload is released once the action returns.
+var (
+	{{ .PayloadPool }} = sync.Pool{New: func() interface{} { return new({{ gotypename .Payload nil 1 false }}) }}
+	{{ .RawPayloadPool }} = sync.Pool{New: func() interface{} { return new({{ gotypename .Payload nil 1 true }}) }}
+)
+{{ end }}
 // {{ .Unmarshal }} unmarshals the request body into the context request data Payload field.
 func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) error {
 	{{ if .PayloadMultipart}}var err error
@@ -791,20 +1195,77 @@ func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Reque
 {{ template "Coerce" (newCoerceData $name $att true (printf "payload.%s" (goifyatt $att $name true)) 1) }}{{ end }}{{/*
 */}}	if err != nil {
 		return err
-	}{{ else if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	}{{ else if .PayloadPool }}payload := {{ .RawPayloadPool }}.Get().(*{{ gotypename .Payload nil 1 true }})
 	if err := service.DecodeRequest(req, payload); err != nil {
+		*payload = {{ gotypename .Payload nil 1 true }}{}
+		{{ .RawPayloadPool }}.Put(payload)
 		return err
 	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
-	payload.Finalize(){{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
-	if err := service.DecodeRequest(req, &payload); err != nil {
+	payload.Finalize(){{ end }}{{ else if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	if err := service.DecodeRequest(req, payload); err != nil {
+		return err
+	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ else if .Payload.IsArray }}{{ $elem := .Payload.ToArray.ElemType }}{{ $max := maxLength .Payload.AttributeDefinition }}{{/*
+*/}}{{ $elemValidation := validationCode $elem true false false "elem" "raw[*]" 3 false }}{{/*
+*/}}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}var payload {{ gotypename .Payload nil 1 false }}{{ if $validation }}
+	var streamed bool{{ end }}
+	err := service.DecodeRequestArray(req, &payload, func(decode func(interface{}) error) error {
+		{{ if $validation }}streamed = true
+		{{ end }}{{ if ge $max 0 }}if !service.SkipValidation && len(payload) == {{ $max }} {
+			return goa.InvalidLengthError(` + "`" + `raw` + "`" + `, payload, len(payload)+1, {{ $max }}, false)
+		}
+		{{ end }}var elem {{ gotyperef $elem.Type $elem.AllRequired 2 false }}
+		if err := decode(&elem); err != nil {
+			return err
+		}{{ if $elemValidation }}
+		if !service.SkipValidation {
+			{{ if isUserType $elem }}if elem != nil {
+				if err := elem.Validate(); err != nil {
+					return err
+				}
+			}{{ else }}var err error
+{{ $elemValidation }}
+			if err != nil {
+				return err
+			}{{ end }}
+		}{{ end }}
+		payload = append(payload, elem)
+		return nil
+	})
+	if err != nil {
 		return err
-	}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}
-	if err := payload.Validate(); err != nil {
-		// Initialize payload with private data structure so it can be logged
-		goa.ContextRequest(ctx).Payload = payload
+	}{{ if $validation }}{{ $arrayValidation := arrayValidationCode .Payload.AttributeDefinition "payload" "raw" 3 }}
+	if !service.SkipValidation {
+		// The elements of streamed arrays are validated as they are decoded.
+		{{ if $arrayValidation }}if streamed {
+{{ $arrayValidation }}
+		} else {
+			err = payload.Validate()
+		}{{ else }}if !streamed {
+			err = payload.Validate()
+		}{{ end }}
+		if err != nil {
+			// Initialize payload with private data structure so it can be logged
+			goa.ContextRequest(ctx).Payload = payload
+			return err
+		}
+	}{{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
+	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
-	}{{ end }}
-	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}
+	}{{ end }}{{ if not .Payload.IsArray }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 true }}{{ if $validation }}
+	if !service.SkipValidation {
+		if err := payload.Validate(); err != nil {
+			// Initialize payload with private data structure so it can be logged
+			goa.ContextRequest(ctx).Payload = payload
+			return err
+		}
+	}{{ end }}{{ end }}{{ if .PayloadPool }}
+	pub := {{ .PayloadPool }}.Get().({{ gotyperef .Payload nil 1 false }})
+	{{ recursivePublicizer .Payload.AttributeDefinition "payload" "pub" 1 }}
+	*payload = {{ gotypename .Payload nil 1 true }}{}
+	{{ .RawPayloadPool }}.Put(payload)
+	goa.ContextRequest(ctx).Payload = pub{{ else }}
+	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}{{ end }}
 	return nil
 }
 {{ end }}
@@ -818,7 +1279,12 @@ func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} i
 {{ end }}{{ if .CanonicalParams }}	return fmt.Sprintf("{{ .CanonicalTemplate }}", param{{ join .CanonicalParams ", param" }})
 {{ else }}	return "{{ .CanonicalTemplate }}"
 {{ end }}}
-{{ end }}`
+{{ if .HAL }}
+// {{ .Name }}HALLink returns the HAL link object of the resource.
+func {{ .Name }}HALLink({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} interface{}{{ end }}) *HALLink {
+	return &HALLink{Href: {{ .Name }}Href({{ join .CanonicalParams ", " }})}
+}
+{{ end }}{{ end }}`
 
 	// mediaTypeT generates the code for a media type.
 	// template input: MediaTypeTemplateData
@@ -833,6 +1299,42 @@ func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 	return
 }
 {{ end }}
+`
+
+	// mediaTypeProjectionT generates the method that projects a media type onto one of its views.
+	// template input: *MediaTypeProjectionData
+	mediaTypeProjectionT = `// {{ .Method }} returns the {{ printf "%q" .View }} view of the media type. It only copies the
+// attributes rendered by the view.
+func (mt {{ .Source }}) {{ .Method }}() {{ .Target }} {
+	if mt == nil {
+		return nil
+	}
+{{ if .Collection }}	res := make({{ .Target }}, len(mt))
+	for i, e := range mt {
+		res[i] = e.{{ .Method }}()
+	}
+{{ else }}	res := &{{ .TargetName }}{}
+{{ range .Fields }}	res.{{ .Name }} = mt.{{ .Name }}{{ if .Method }}.{{ .Method }}(){{ end }}
+{{ end }}{{ end }}	return res
+}
+`
+
+	// mediaTypeMarshalJSONT generates the reflection-free JSON encoding methods of a media type.
+	// template input: map[string]interface{}
+	mediaTypeMarshalJSONT = `// MarshalJSON returns the JSON encoding of the media type, see AppendJSON.
+func (mt {{ .TypeRef }}) MarshalJSON() ([]byte, error) {
+	return mt.AppendJSON(nil)
+}
+
+// AppendJSON appends the JSON encoding of the media type to b. The encoding is the same as the one
+// produced by encoding/json but does not rely on reflection.
+func (mt {{ .TypeRef }}) AppendJSON(b []byte) ([]byte, error) {
+	if mt == nil {
+		return append(b, "null"...), nil
+	}
+{{ if .Fallible }}	var err error
+{{ end }}{{ .Code }}	return b, nil
+}
 `
 
 	// mediaTypeLinkT generates the code for a media type link.
@@ -878,12 +1380,48 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 
 	// securitySchemesT generates the code for the security module.
 	// template input: []*design.SecuritySchemeDefinition
+	// template input: []*design.WebhookDefinition
+	webhooksT = `// Webhooks sends the webhook requests to the URLs registered by the API consumers. The requests
+// are signed and the failed deliveries retried by Sender.
+type Webhooks struct {
+	Sender *goa.WebhookSender
+}
+
+// NewWebhooks returns the webhooks that send their requests with sender.
+func NewWebhooks(sender *goa.WebhookSender) *Webhooks {
+	return &Webhooks{Sender: sender}
+}
+{{ range . }}
+// {{ goify .Name true }} sends the {{ printf "%q" .Name }} webhook request to url.{{ if .Description }}
+{{ comment .Description }}{{ end }}
+func (w *Webhooks) {{ goify .Name true }}(ctx context.Context, url string, payload {{ gotyperef .Payload nil 0 false }}) error {
+	return w.Sender.Send(ctx, url, {{ printf "%q" .Name }}, payload)
+}
+{{ end }}`
+
 	securitySchemesT = `
 type (
 	// Private type used to store auth handler info in request context
 	authMiddlewareKey string
-)
+{{ if hasSecurityResponses . }}
+	// securityResponse is the error returned by auth functions to send a response defined by a
+	// security scheme.
+	securityResponse struct {
+		status      int
+		contentType string
+		body        interface{}
+	}
+{{ end }})
+{{ if hasSecurityResponses . }}
+// Error returns the response status text.
+func (r *securityResponse) Error() string { return http.StatusText(r.status) }
 
+// ResponseStatus returns the response status code.
+func (r *securityResponse) ResponseStatus() int { return r.status }
+
+// Token returns an empty string, security responses are not logged as errors.
+func (r *securityResponse) Token() string { return "" }
+{{ end }}
 {{ range . }}
 {{ $funcName := printf "Use%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} mounts the {{ .SchemeName }} auth middleware onto the service.
 func {{ $funcName }}(service *goa.Service, middleware goa.Middleware) {
@@ -913,13 +1451,78 @@ func {{ $funcName }}() *goa.{{ .Context }} {
 {{ range $k, $v := . }}			{{ printf "%q" $k }}: {{ printf "%q" $v }},
 {{ end }}{{/*
 */}}		},{{ end }}
+{{ else if eq .Context "SessionSecurity" }}{{/*
+*/}}		Name: {{ printf "%q" .Name }},{{ if .TTL }}
+		TTL:  time.Duration({{ .TTL.Nanoseconds }}),{{ end }}
 {{ end }}{{/*
 */}}	}
 {{ if .Description }} def.Description = {{ printf "%q" .Description }}
 {{ end }}	return &def
 }
+{{ if eq .Context "JWTSecurity" }}
+{{ $funcName := printf "New%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} creates a middleware that extracts the JWT from the requests and authenticates it
+// using auth. Requests with no token are rejected with a 401 response.
+func {{ $funcName }}(auth goa.JWTAuthFunc) goa.Middleware {
+	return goa.NewJWTMiddleware(New{{ goify .SchemeName true }}Security(), auth)
+}
+{{ else if eq .Context "OAuth2Security" }}
+{{ $funcName := printf "New%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} creates a middleware that extracts the OAuth2 access token from the requests and
+// validates it using auth. Requests with no token are rejected with a 401 response.
+func {{ $funcName }}(auth goa.OAuth2AuthFunc) goa.Middleware {
+	return goa.NewOAuth2Middleware(New{{ goify .SchemeName true }}Security(), auth)
+}
+{{ else if eq .Context "APIKeySecurity" }}
+{{ $funcName := printf "New%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} creates a middleware that extracts the API key from the requests and validates it
+// using auth. Requests with no key are rejected with a 401 response.
+func {{ $funcName }}(auth goa.APIKeyAuthFunc) goa.Middleware {
+	return goa.NewAPIKeyMiddleware(New{{ goify .SchemeName true }}Security(), auth)
+}
+{{ else if eq .Context "BasicAuthSecurity" }}
+{{ $funcName := printf "New%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} creates a middleware that reads the basic auth credentials from the requests and
+// validates them using auth. Requests with no credentials are rejected with a 401 response.
+func {{ $funcName }}(auth goa.BasicAuthFunc) goa.Middleware {
+	return goa.NewBasicAuthMiddleware(New{{ goify .SchemeName true }}Security(), auth)
+}
+{{ else if eq .Context "MutualTLSSecurity" }}
+{{ $funcName := printf "New%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} creates a middleware that authorizes the requests using the verified TLS client
+// certificate and auth. Requests with no verified client certificate are rejected with a 401 response.
+func {{ $funcName }}(auth goa.MutualTLSAuthFunc) goa.Middleware {
+	return goa.NewMutualTLSMiddleware(New{{ goify .SchemeName true }}Security(), auth)
+}
+{{ else if eq .Context "SessionSecurity" }}
+{{ $funcName := printf "New%sMiddleware" (goify .SchemeName true) }}// {{ $funcName }} creates a middleware that decodes the session from the request cookie using store
+// and authorizes it using auth. Requests with no valid session are rejected with a 401 response.
+func {{ $funcName }}(store goa.SessionStore, auth goa.SessionAuthFunc) goa.Middleware {
+	return goa.NewSessionMiddleware(New{{ goify .SchemeName true }}Security(), store, auth)
+}
+{{ end }}{{ $schemeName := .SchemeName }}{{ range securityResponses . }}
+{{ $funcName := printf "New%s%s" (goify $schemeName true) (goify .Name true) }}// {{ $funcName }} creates an error sent using the {{ .Name }} response of the {{ $schemeName }} security
+// scheme. Return it from the auth function to customize the response.
+func {{ $funcName }}({{ if .TypeRef }}body {{ .TypeRef }}{{ end }}) error {
+	return &securityResponse{status: {{ .Status }}, contentType: {{ printf "%q" .ContentType }}{{ if .TypeRef }}, body: body{{ end }}}
+}
+{{ end }}{{ range securityFailures . }}{{ if isCustomFailureBody . }}
+{{ $varName := printf "%s%sBody" (goify $schemeName true) (goify .Name true) }}// {{ $varName }} builds the body of the {{ .Name }} response of the {{ $schemeName }} security scheme
+// sent when the auth middleware rejects a request, e.g. because it has no credentials. The
+// middleware error is sent as is if nil.
+var {{ $varName }} func(err error) {{ .TypeRef }}
+{{ end }}{{ end }}
+{{ end }}// Authorizer is the interface implemented by the user to authenticate the requests for all the
+// security schemes of the API. Mount an implementation with UseAuthorizer.
+type Authorizer interface {
+{{ range . }}	// {{ goify .SchemeName true }}Auth authenticates the requests using the {{ printf "%q" .SchemeName }} security scheme.
+	{{ goify .SchemeName true }}Auth(ctx context.Context, {{ if eq .Context "BasicAuthSecurity" }}user, pass string{{ else if eq .Context "MutualTLSSecurity" }}cert *x509.Certificate{{ else if eq .Context "APIKeySecurity" }}key string{{ else if eq .Context "SessionSecurity" }}session *goa.Session{{ else }}token string{{ end }}, scheme *goa.{{ .Context }}) (context.Context, error)
+{{ if eq .Context "SessionSecurity" }}	// {{ goify .SchemeName true }}Store returns the store used to decode the {{ printf "%q" .SchemeName }} session cookies.
+	{{ goify .SchemeName true }}Store() goa.SessionStore
+{{ end }}{{ end }}}
+
+// UseAuthorizer mounts the auth middleware of all the API security schemes onto the service, the
+// middleware delegate to the corresponding Authorizer methods.
+func UseAuthorizer(service *goa.Service, a Authorizer) {
+{{ range . }}	Use{{ goify .SchemeName true }}Middleware(service, New{{ goify .SchemeName true }}Middleware({{ if eq .Context "SessionSecurity" }}a.{{ goify .SchemeName true }}Store(), {{ end }}a.{{ goify .SchemeName true }}Auth))
+{{ end }}}
 
-{{ end }}// handleSecurity creates a handler that runs the auth middleware for the security scheme.
+// handleSecurity creates a handler that runs the auth middleware for the security scheme.
 func handleSecurity(schemeName string, h goa.Handler, scopes ...string) goa.Handler {
 	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		scheme := ctx.Value(authMiddlewareKey(schemeName))
@@ -928,8 +1531,47 @@ func handleSecurity(schemeName string, h goa.Handler, scopes ...string) goa.Hand
 			return goa.NoAuthMiddleware(schemeName)
 		}
 		ctx = goa.WithRequiredScopes(ctx, scopes)
-		return am(h)(ctx, rw, req)
-	}
+{{ if hasSecurityResponses . }}		called := false
+		err := am(func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called = true
+			return h(ctx, rw, req)
+		})(ctx, rw, req)
+		r, ok := err.(*securityResponse)
+		if !ok {
+			// Only the auth middleware failures use the scheme responses, not the action errors.
+			serr, isService := err.(goa.ServiceError)
+			if called || !isService {
+				return err
+			}
+			if r = securityFailure(schemeName, serr); r == nil {
+				return err
+			}
+		}
+		if r.body == nil {
+			rw.WriteHeader(r.status)
+			return nil
+		}
+		rw.Header().Set("Content-Type", r.contentType)
+		return goa.ContextResponse(ctx).Service.Send(ctx, r.status, r.body)
+{{ else }}		return am(h)(ctx, rw, req)
+{{ end }}	}
 }
-`
+{{ if hasSecurityResponses . }}
+// securityFailure returns the response defined by the security scheme for the status of the error
+// returned by its auth middleware, nil if the scheme does not define one.
+func securityFailure(schemeName string, err goa.ServiceError) *securityResponse {
+	switch schemeName {
+{{ range . }}{{ if .Responses }}{{ $schemeName := .SchemeName }}	case {{ printf "%q" .SchemeName }}:
+		switch err.ResponseStatus() {
+{{ range securityFailures . }}		case {{ .Status }}:
+{{ if isCustomFailureBody . }}{{ $varName := printf "%s%sBody" (goify $schemeName true) (goify .Name true) }}			if {{ $varName }} == nil {
+				return nil
+			}
+			return &securityResponse{status: {{ .Status }}, contentType: {{ printf "%q" .ContentType }}, body: {{ $varName }}(err)}
+{{ else }}			return &securityResponse{status: {{ .Status }}, contentType: {{ printf "%q" .ContentType }}{{ if eq .TypeRef "error" }}, body: err{{ else if eq .TypeRef "string" }}, body: err.Error(){{ end }}}
+{{ end }}{{ end }}		}
+{{ end }}{{ end }}	}
+	return nil
+}
+{{ end }}`
 )
@@ -3,6 +3,7 @@ package genapp_test
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/design/apidsl"
@@ -54,6 +55,7 @@ var _ = Describe("ContextsWriter", func() {
 			var payload *design.UserTypeDefinition
 			var responses map[string]*design.ResponseDefinition
 			var routes []*design.RouteDefinition
+			var subscription string
 
 			var data *genapp.ContextTemplateData
 
@@ -63,6 +65,7 @@ var _ = Describe("ContextsWriter", func() {
 				payload = nil
 				responses = nil
 				routes = nil
+				subscription = ""
 				data = nil
 			})
 
@@ -78,6 +81,7 @@ var _ = Describe("ContextsWriter", func() {
 					Routes:       routes,
 					API:          design.Design,
 					DefaultPkg:   "",
+					Subscription: subscription,
 				}
 			})
 
@@ -91,6 +95,22 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(written).ShouldNot(BeEmpty())
 					Ω(written).Should(ContainSubstring(emptyContext))
 					Ω(written).Should(ContainSubstring(emptyContextFactory))
+					Ω(written).ShouldNot(ContainSubstring("Subscribe"))
+				})
+			})
+
+			Context("with a subscription", func() {
+				BeforeEach(func() {
+					subscription = "bottle.updated"
+				})
+
+				It("writes the subscribe method", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("func (ctx *ListBottleContext) Subscribe(b *goa.Broadcaster) <-chan interface{} {\n\treturn b.SubscribeContext(ctx, \"bottle.updated\")\n}"))
 				})
 			})
 
@@ -180,6 +200,36 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with responses with an unknown media type", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:      "OK",
+							Status:    200,
+							MediaType: "application/octet-stream",
+						},
+						"Forbidden": {
+							Name:      "Forbidden",
+							Status:    403,
+							MediaType: "text/plain",
+						},
+					}
+				})
+
+				It("writes response helpers that stream the body", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(okStreamResponse))
+					Ω(written).Should(ContainSubstring(okContentResponse))
+					Ω(written).Should(ContainSubstring("func (ctx *ListBottleContext) ForbiddenStream(r io.Reader) error {"))
+					Ω(written).ShouldNot(ContainSubstring("ForbiddenContent"))
+				})
+			})
+
 			Context("with an integer param", func() {
 				var (
 					intParam   *design.AttributeDefinition
@@ -995,6 +1045,9 @@ var _ = Describe("ControllersWriter", func() {
 
 		Context("with data", func() {
 			var multipart bool
+			var timeout string
+			var csrf bool
+			var audit []map[string]interface{}
 			var actions, verbs, paths, contexts, unmarshals []string
 			var payloads []*design.UserTypeDefinition
 			var encoders, decoders []*genapp.EncoderTemplateData
@@ -1004,6 +1057,9 @@ var _ = Describe("ControllersWriter", func() {
 
 			BeforeEach(func() {
 				multipart = false
+				timeout = ""
+				csrf = false
+				audit = nil
 				actions = nil
 				verbs = nil
 				paths = nil
@@ -1044,6 +1100,10 @@ var _ = Describe("ControllersWriter", func() {
 						"Unmarshal":        unmarshal,
 						"Payload":          payload,
 						"PayloadMultipart": multipart,
+						"Timeout":          timeout,
+						"CSRF":             csrf,
+						"Audit":            audit != nil,
+						"AuditIdentifiers": audit,
 					}
 				}
 				if len(as) > 0 {
@@ -1085,6 +1145,65 @@ var _ = Describe("ControllersWriter", func() {
 					Ω(written).ShouldNot(BeEmpty())
 					Ω(written).Should(ContainSubstring(simpleController))
 					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(simpleControllers))
+				})
+			})
+
+			Context("with an action with a timeout", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					timeout = "5 * time.Second"
+				})
+
+				It("wraps the handler with the timeout", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\th = goa.TimeoutHandler(h, 5 * time.Second)\n"))
+				})
+			})
+
+			Context("with an action protected against CSRF", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					csrf = true
+				})
+
+				It("wraps the handler with the CSRF check", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\th = goa.CSRFHandler(h)\n"))
+				})
+			})
+
+			Context("with an audited action", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					audit = []map[string]interface{}{{"Name": "accountID", "Field": "AccountID", "Payload": false}}
+				})
+
+				It("wraps the handler with the audit handler", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\t\tgoa.AuditIdentifier(ctx, \"accountID\", rctx.AccountID)\n"))
+					Ω(written).Should(ContainSubstring("\th = goa.AuditHandler(h)\n"))
 				})
 			})
 
@@ -1192,6 +1311,40 @@ var _ = Describe("ControllersWriter", func() {
 				})
 			})
 
+			Context("with actions that take an array payload", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					minLength, minItems, maxLength := 2, 1, 1000
+					verbs = []string{"POST"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: &design.Array{
+									ElemType: &design.AttributeDefinition{
+										Type:       design.String,
+										Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+									},
+								},
+								Validation: &dslengine.ValidationDefinition{MinLength: &minItems, MaxLength: &maxLength},
+							},
+						},
+					}
+				})
+
+				It("writes the payload unmarshal function decoding the elements one at a time", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadArrayUnmarshal))
+				})
+			})
+
 			Context("with multiple controllers", func() {
 				BeforeEach(func() {
 					actions = []string{"list", "show"}
@@ -1351,6 +1504,7 @@ var _ = Describe("HrefWriter", func() {
 		Context("with data", func() {
 			var canoTemplate string
 			var canoParams []string
+			var hal bool
 			var mediaType *design.MediaTypeDefinition
 
 			var data *genapp.ResourceData
@@ -1359,6 +1513,7 @@ var _ = Describe("HrefWriter", func() {
 				mediaType = nil
 				canoTemplate = ""
 				canoParams = nil
+				hal = false
 				data = nil
 			})
 
@@ -1370,6 +1525,7 @@ var _ = Describe("HrefWriter", func() {
 					Type:              mediaType,
 					CanonicalTemplate: canoTemplate,
 					CanonicalParams:   canoParams,
+					HAL:               hal,
 				}
 			})
 
@@ -1431,6 +1587,23 @@ var _ = Describe("HrefWriter", func() {
 						written := string(b)
 						Ω(written).ShouldNot(BeEmpty())
 						Ω(written).Should(ContainSubstring(simpleResourceHref))
+						Ω(written).ShouldNot(ContainSubstring("HALLink"))
+					})
+
+					Context("using HAL links", func() {
+						BeforeEach(func() {
+							hal = true
+						})
+
+						It("writes the HAL link method", func() {
+							err := writer.Execute(data)
+							Ω(err).ShouldNot(HaveOccurred())
+							b, err := ioutil.ReadFile(filename)
+							Ω(err).ShouldNot(HaveOccurred())
+							written := string(b)
+							Ω(written).Should(ContainSubstring(simpleResourceHref))
+							Ω(written).Should(ContainSubstring(simpleResourceHALLink))
+						})
 					})
 				})
 
@@ -1567,6 +1740,261 @@ var _ = Describe("UserTypesWriter", func() {
 	})
 })
 
+var _ = Describe("SecurityWriter", func() {
+	var writer *genapp.SecurityWriter
+	var workspace *codegen.Workspace
+	var filename string
+	var schemes []*design.SecuritySchemeDefinition
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("security")
+		Ω(err).ShouldNot(HaveOccurred())
+		src, err := pkg.CreateSourceFile("test.go")
+		Ω(err).ShouldNot(HaveOccurred())
+		defer src.Close()
+		filename = src.Abs()
+		schemes = nil
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewSecurityWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a JWT security scheme", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.JWTSecurityKind,
+				SchemeName: "jwt",
+				Type:       "apiKey",
+				In:         "header",
+				Name:       "Authorization",
+			}}
+		})
+
+		It("writes the auth middleware constructor", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("func UseJWTMiddleware(service *goa.Service, middleware goa.Middleware) {"))
+			Ω(written).Should(ContainSubstring("func NewJWTMiddleware(auth goa.JWTAuthFunc) goa.Middleware {\n\treturn goa.NewJWTMiddleware(NewJWTSecurity(), auth)\n}"))
+		})
+	})
+
+	Context("with an OAuth2 security scheme", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.OAuth2SecurityKind,
+				SchemeName: "oauth2",
+				Type:       "oauth2",
+				Flow:       "application",
+				TokenURL:   "https://example.com/token",
+			}}
+		})
+
+		It("writes the auth middleware constructor", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("func NewOauth2Middleware(auth goa.OAuth2AuthFunc) goa.Middleware {\n\treturn goa.NewOAuth2Middleware(NewOauth2Security(), auth)\n}"))
+		})
+	})
+
+	Context("with an API key security scheme", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.APIKeySecurityKind,
+				SchemeName: "api_key",
+				Type:       "apiKey",
+				In:         "query",
+				Name:       "key",
+			}}
+		})
+
+		It("writes the auth middleware constructor", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("In:   goa.LocQuery,"))
+			Ω(written).Should(ContainSubstring("func NewAPIKeyMiddleware(auth goa.APIKeyAuthFunc) goa.Middleware {\n\treturn goa.NewAPIKeyMiddleware(NewAPIKeySecurity(), auth)\n}"))
+		})
+	})
+
+	Context("with a basic auth security scheme", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.BasicAuthSecurityKind,
+				SchemeName: "basic",
+				Type:       "basic",
+			}}
+		})
+
+		It("writes the auth middleware constructor", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("func NewBasicMiddleware(auth goa.BasicAuthFunc) goa.Middleware {\n\treturn goa.NewBasicAuthMiddleware(NewBasicSecurity(), auth)\n}"))
+		})
+	})
+
+	Context("with a mutual TLS security scheme", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.MutualTLSSecurityKind,
+				SchemeName: "mtls",
+				Type:       "mutualTLS",
+			}}
+		})
+
+		It("writes the auth middleware constructor", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("func NewMtlsSecurity() *goa.MutualTLSSecurity {"))
+			Ω(written).Should(ContainSubstring("func NewMtlsMiddleware(auth goa.MutualTLSAuthFunc) goa.Middleware {\n\treturn goa.NewMutualTLSMiddleware(NewMtlsSecurity(), auth)\n}"))
+		})
+	})
+
+	Context("with a session security scheme", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.SessionSecurityKind,
+				SchemeName: "session",
+				Type:       "session",
+				In:         "cookie",
+				Name:       "sid",
+				TTL:        time.Hour,
+			}}
+		})
+
+		It("writes the auth middleware constructor", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("Name: \"sid\","))
+			Ω(written).Should(ContainSubstring("TTL:  time.Duration(3600000000000),"))
+			Ω(written).Should(ContainSubstring("func NewSessionMiddleware(store goa.SessionStore, auth goa.SessionAuthFunc) goa.Middleware {\n\treturn goa.NewSessionMiddleware(NewSessionSecurity(), store, auth)\n}"))
+			Ω(written).Should(ContainSubstring("\tSessionStore() goa.SessionStore\n"))
+			Ω(written).Should(ContainSubstring("\tUseSessionMiddleware(service, NewSessionMiddleware(a.SessionStore(), a.SessionAuth))\n"))
+		})
+	})
+
+	Context("with a security scheme defining responses", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{{
+				Kind:       design.JWTSecurityKind,
+				SchemeName: "jwt",
+				Type:       "apiKey",
+				In:         "header",
+				Name:       "Authorization",
+				Responses: map[string]*design.ResponseDefinition{
+					"Unauthorized": {Name: "Unauthorized", Status: 401, MediaType: "text/plain", Type: design.String},
+				},
+			}}
+		})
+
+		It("writes the response constructors", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("func NewJWTUnauthorized(body string) error {\n\treturn &securityResponse{status: 401, contentType: \"text/plain\", body: body}\n}"))
+			Ω(written).Should(ContainSubstring("r, ok := err.(*securityResponse)"))
+		})
+
+		It("sends the responses when the auth middleware rejects a request", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("if r = securityFailure(schemeName, serr); r == nil {"))
+			Ω(written).Should(ContainSubstring(`	case "jwt":
+		switch err.ResponseStatus() {
+		case 401:
+			return &securityResponse{status: 401, contentType: "text/plain", body: err.Error()}
+		}`))
+		})
+
+		Context("with a media type body", func() {
+			BeforeEach(func() {
+				mt := &design.MediaTypeDefinition{
+					UserTypeDefinition: &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{"reason": &design.AttributeDefinition{Type: design.String}},
+						},
+						TypeName: "AuthError",
+					},
+					Identifier: "application/vnd.auth-error+json",
+					Views: map[string]*design.ViewDefinition{
+						"default": {
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"reason": &design.AttributeDefinition{Type: design.String}},
+							},
+							Name: "default",
+						},
+					},
+				}
+				schemes[0].Responses["Unauthorized"] = &design.ResponseDefinition{Name: "Unauthorized", Status: 401, MediaType: mt.Identifier, Type: mt}
+			})
+
+			It("lets the user build the body from the middleware error", func() {
+				err := writer.Execute(schemes)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := ioutil.ReadFile(filename)
+				Ω(err).ShouldNot(HaveOccurred())
+				written := string(b)
+				Ω(written).Should(ContainSubstring("var JWTUnauthorizedBody func(err error) *AuthError"))
+				Ω(written).Should(ContainSubstring(`			if JWTUnauthorizedBody == nil {
+				return nil
+			}
+			return &securityResponse{status: 401, contentType: "application/vnd.auth-error+json", body: JWTUnauthorizedBody(err)}`))
+			})
+		})
+	})
+
+	Context("with multiple security schemes", func() {
+		BeforeEach(func() {
+			schemes = []*design.SecuritySchemeDefinition{
+				{Kind: design.JWTSecurityKind, SchemeName: "jwt", Type: "apiKey", In: "header", Name: "Authorization"},
+				{Kind: design.BasicAuthSecurityKind, SchemeName: "basic", Type: "basic"},
+			}
+		})
+
+		It("writes the authorizer interface", func() {
+			err := writer.Execute(schemes)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring("\tJWTAuth(ctx context.Context, token string, scheme *goa.JWTSecurity) (context.Context, error)\n"))
+			Ω(written).Should(ContainSubstring("\tBasicAuth(ctx context.Context, user, pass string, scheme *goa.BasicAuthSecurity) (context.Context, error)\n"))
+			Ω(written).Should(ContainSubstring("func UseAuthorizer(service *goa.Service, a Authorizer) {\n\tUseJWTMiddleware(service, NewJWTMiddleware(a.JWTAuth))\n\tUseBasicMiddleware(service, NewBasicMiddleware(a.BasicAuth))\n}"))
+		})
+	})
+})
+
 const (
 	emptyContext = `
 type ListBottleContext struct {
@@ -2307,10 +2735,12 @@ func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}
-	if err := payload.Validate(); err != nil {
-		// Initialize payload with private data structure so it can be logged
-		goa.ContextRequest(ctx).Payload = payload
-		return err
+	if !service.SkipValidation {
+		if err := payload.Validate(); err != nil {
+			// Initialize payload with private data structure so it can be logged
+			goa.ContextRequest(ctx).Payload = payload
+			return err
+		}
 	}
 	goa.ContextRequest(ctx).Payload = payload.Publicize()
 	return nil
@@ -2332,14 +2762,66 @@ func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *
 	if err != nil {
 		return err
 	}
-	if err := payload.Validate(); err != nil {
-		// Initialize payload with private data structure so it can be logged
-		goa.ContextRequest(ctx).Payload = payload
-		return err
+	if !service.SkipValidation {
+		if err := payload.Validate(); err != nil {
+			// Initialize payload with private data structure so it can be logged
+			goa.ContextRequest(ctx).Payload = payload
+			return err
+		}
 	}
 	goa.ContextRequest(ctx).Payload = payload.Publicize()
 	return nil
 }
+`
+
+	payloadArrayUnmarshal = `
+func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	var payload ListBottlePayload
+	var streamed bool
+	err := service.DecodeRequestArray(req, &payload, func(decode func(interface{}) error) error {
+		streamed = true
+		if !service.SkipValidation && len(payload) == 1000 {
+			return goa.InvalidLengthError(` + "`" + `raw` + "`" + `, payload, len(payload)+1, 1000, false)
+		}
+		var elem string
+		if err := decode(&elem); err != nil {
+			return err
+		}
+		if !service.SkipValidation {
+			var err error
+				if utf8.RuneCountInString(elem) < 2 {
+				err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `raw[*]` + "`" + `, elem, utf8.RuneCountInString(elem), 2, true))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		payload = append(payload, elem)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !service.SkipValidation {
+		// The elements of streamed arrays are validated as they are decoded.
+		if streamed {
+			if payload != nil {
+				if len(payload) < 1 {
+					err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `raw` + "`" + `, payload, len(payload), 1, true))
+				}
+			}
+		} else {
+			err = payload.Validate()
+		}
+		if err != nil {
+			// Initialize payload with private data structure so it can be logged
+			goa.ContextRequest(ctx).Payload = payload
+			return err
+		}
+	}
+	goa.ContextRequest(ctx).Payload = payload
+	return nil
+}
 `
 
 	payloadNoValidationsObjUnmarshal = `
@@ -2468,6 +2950,7 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		// Build the context
 		rctx, err := NewListBottleContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		return ctrl.List(rctx)
@@ -2489,6 +2972,7 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		// Build the context
 		rctx, err := NewListBottleContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		return ctrl.List(rctx)
@@ -2496,6 +2980,25 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
 	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
 }
+`
+
+	simpleControllers = `// Controllers groups the implementations of all the API controllers. Use NewControllers as the
+// provider when wiring the service with a dependency injection framework such as Wire or fx.
+type Controllers struct {
+	Bottles BottlesController
+}
+
+// NewControllers returns the Controllers struct initialized with the given controllers.
+func NewControllers(bottles BottlesController) *Controllers {
+	return &Controllers{
+		Bottles: bottles,
+	}
+}
+
+// MountAll mounts all the controllers on the given service.
+func MountAll(service *goa.Service, ctrls *Controllers) {
+	MountBottlesController(service, ctrls.Bottles)
+}
 `
 
 	multiController = `// BottlesController is the controller interface for the Bottles actions.
@@ -2518,6 +3021,7 @@ type BottlesController interface {
 		// Build the context
 		rctx, err := NewListBottleContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		return ctrl.List(rctx)
@@ -2533,6 +3037,7 @@ type BottlesController interface {
 		// Build the context
 		rctx, err := NewShowBottleContext(ctx, req, service)
 		if err != nil {
+			goa.ValidationFailures.Add(1)
 			return err
 		}
 		return ctrl.Show(rctx)
@@ -2546,6 +3051,10 @@ type BottlesController interface {
 	paramid := strings.TrimLeftFunc(fmt.Sprintf("%v", id), func(r rune) bool { return r == '/' })
 	return fmt.Sprintf("/bottles/%v", paramid)
 }
+`
+	simpleResourceHALLink = `func BottleHALLink(id interface{}) *HALLink {
+	return &HALLink{Href: BottleHref(id)}
+}
 `
 	noParamHref = `func BottleHref() string {
 	return "/bottles"
@@ -2609,3 +3118,20 @@ type ComplexPayload struct {
 }
 `
 )
+
+const okStreamResponse = `func (ctx *ListBottleContext) OKStream(r io.Reader) error {
+	if ctx.ResponseData.Header().Get("Content-Type") == "" {
+		ctx.ResponseData.Header().Set("Content-Type", "application/octet-stream")
+	}
+	ctx.ResponseData.WriteHeader(200)
+	_, err := io.Copy(ctx.ResponseData, r)
+	return err
+}`
+
+const okContentResponse = `func (ctx *ListBottleContext) OKContent(modtime time.Time, content io.ReadSeeker) error {
+	if ctx.ResponseData.Header().Get("Content-Type") == "" {
+		ctx.ResponseData.Header().Set("Content-Type", "application/octet-stream")
+	}
+	http.ServeContent(ctx.ResponseData, ctx.RequestData.Request, "", modtime, content)
+	return nil
+}`
@@ -0,0 +1,15 @@
+/*
+Package genavro generates Avro schemas for the API user types and action payloads so that the
+payloads can be published to message buses such as Kafka and registered in a schema registry.
+
+Each object user type gives a record schema written to "avro/<type>.avsc". Each action that has an
+object payload gives a record schema named after the action payload Go type, for example
+"CreateBottlePayload", written to "avro/<resource>_<action>.avsc". The schema files are self
+contained: the named types they use are defined inline the first time they appear.
+
+goa types map to Avro types as follows: Boolean to boolean, Integer to long, Number to double,
+String to string, DateTime to long with the timestamp-millis logical type, UUID to string with the
+uuid logical type, File and Any to bytes, arrays to arrays, hashes to maps and objects to records.
+Attributes that are not required map to unions with null that default to null.
+*/
+package genavro
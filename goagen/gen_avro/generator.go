@@ -0,0 +1,245 @@
+package genavro
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of an Avro Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the Avro schemas generator.
+type Generator struct {
+	API       *design.APIDefinition // The API definition
+	OutDir    string                // Path to output directory
+	Namespace string                // Avro namespace of the generated schemas
+	genfiles  []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, ns string
+	set := flag.NewFlagSet("avro", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&ns, "namespace", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Namespace: ns, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Avro schema files.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Namespace == "" {
+		g.Namespace = strings.ToLower(avroName(codegen.SnakeCase(g.API.Name)))
+	}
+
+	outDir := filepath.Join(g.OutDir, "avro")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	err = g.API.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if !ut.IsObject() {
+			return nil
+		}
+		return g.write(filepath.Join(outDir, codegen.SnakeCase(ut.TypeName)+".avsc"), ut)
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload == nil || !a.Payload.IsObject() {
+				return nil
+			}
+			name := codegen.SnakeCase(r.Name) + "_" + codegen.SnakeCase(a.Name) + ".avsc"
+			return g.write(filepath.Join(outDir, name), a.Payload)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// Schema returns the Avro record schema describing the given object user type. The schema defines
+// all the named types it uses.
+func (g *Generator) Schema(ut *design.UserTypeDefinition) map[string]interface{} {
+	b := &builder{defined: make(map[string]bool)}
+	s := b.record(codegen.Goify(ut.TypeName, true), ut.AttributeDefinition).(map[string]interface{})
+	s["namespace"] = g.Namespace
+	return s
+}
+
+// write writes the Avro schema of the given user type to path.
+func (g *Generator) write(path string, ut *design.UserTypeDefinition) error {
+	b, err := json.MarshalIndent(g.Schema(ut), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, path)
+	return nil
+}
+
+// builder builds the Avro schema of a type, it keeps track of the named types already defined so
+// that they are only defined once.
+type builder struct {
+	defined map[string]bool
+}
+
+// typ returns the Avro schema of the type of the given attribute. name is the name given to the
+// record built from the attribute if it is an anonymous object.
+func (b *builder) typ(att *design.AttributeDefinition, name string) interface{} {
+	switch actual := att.Type.(type) {
+	case design.Primitive:
+		switch actual.Kind() {
+		case design.BooleanKind:
+			return "boolean"
+		case design.IntegerKind:
+			return "long"
+		case design.NumberKind:
+			return "double"
+		case design.StringKind:
+			return "string"
+		case design.DateTimeKind:
+			return map[string]interface{}{"type": "long", "logicalType": "timestamp-millis"}
+		case design.UUIDKind:
+			return map[string]interface{}{"type": "string", "logicalType": "uuid"}
+		default:
+			// Any and File
+			return "bytes"
+		}
+	case *design.Array:
+		return map[string]interface{}{"type": "array", "items": b.typ(actual.ElemType, name+"Item")}
+	case *design.Hash:
+		// Avro map keys are always strings.
+		return map[string]interface{}{"type": "map", "values": b.typ(actual.ElemType, name+"Value")}
+	case design.Object:
+		return b.record(name, att)
+	case *design.MediaTypeDefinition:
+		return b.record(codegen.Goify(actual.TypeName, true), actual.AttributeDefinition)
+	case *design.UserTypeDefinition:
+		if !actual.IsObject() {
+			return b.typ(actual.AttributeDefinition, name)
+		}
+		return b.record(codegen.Goify(actual.TypeName, true), actual.AttributeDefinition)
+	}
+	return "bytes"
+}
+
+// record returns the Avro record schema with the given name built from the object attribute att.
+// It returns the name of the record if it is already defined.
+func (b *builder) record(name string, att *design.AttributeDefinition) interface{} {
+	if b.defined[name] {
+		return name
+	}
+	b.defined[name] = true
+	obj := att.Type.ToObject()
+	fields := make([]interface{}, 0, len(obj))
+	for _, n := range sortedNames(obj) {
+		fatt := obj[n]
+		t := b.typ(fatt, name+codegen.Goify(n, true))
+		f := map[string]interface{}{"name": avroName(n)}
+		if fatt.Description != "" {
+			f["doc"] = fatt.Description
+		}
+		if att.IsRequired(n) {
+			f["type"] = t
+			if _, ok := t.(string); ok && fatt.DefaultValue != nil && fatt.Type.IsPrimitive() {
+				f["default"] = fatt.DefaultValue
+			}
+		} else {
+			f["type"] = []interface{}{"null", t}
+			f["default"] = nil
+		}
+		fields = append(fields, f)
+	}
+	r := map[string]interface{}{
+		"type":   "record",
+		"name":   name,
+		"fields": fields,
+	}
+	if att.Description != "" {
+		r["doc"] = att.Description
+	}
+	return r
+}
+
+// sortedNames returns the names of the object attributes in alphabetical order.
+func sortedNames(obj design.Object) []string {
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// avroName returns a valid Avro name built from name: the characters that are not letters, digits
+// or underscores are replaced with underscores.
+func avroName(name string) string {
+	res := []byte(name)
+	for i, c := range res {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			res[i] = '_'
+		}
+	}
+	if len(res) == 0 || res[0] >= '0' && res[0] <= '9' {
+		res = append([]byte{'_'}, res...)
+	}
+	return string(res)
+}
@@ -0,0 +1,141 @@
+package genavro_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_avro"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("avrotest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genavro.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with user types and payloads", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			origin := apidsl.Type("Origin", func() {
+				apidsl.Attribute("country", design.String)
+			})
+			apidsl.Type("Bottle", func() {
+				apidsl.Description("A bottle of wine")
+				apidsl.Attribute("name", design.String, "Name of bottle")
+				apidsl.Attribute("vintage", design.Integer, func() {
+					apidsl.Default(2000)
+				})
+				apidsl.Attribute("origin", origin)
+				apidsl.Attribute("tags", apidsl.ArrayOf(design.String))
+				apidsl.Required("name", "vintage")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("created_at", design.DateTime)
+						apidsl.Required("created_at")
+					})
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the type and payload schemas", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(4))
+
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "avro", "bottle.avsc"))
+			Ω(err).ShouldNot(HaveOccurred())
+			var schema map[string]interface{}
+			Ω(json.Unmarshal(content, &schema)).ShouldNot(HaveOccurred())
+			Ω(schema["type"]).Should(Equal("record"))
+			Ω(schema["name"]).Should(Equal("Bottle"))
+			Ω(schema["namespace"]).Should(Equal("test_api"))
+			Ω(schema["doc"]).Should(Equal("A bottle of wine"))
+			fields := schema["fields"].([]interface{})
+			Ω(fields).Should(HaveLen(4))
+			Ω(fields[0]).Should(Equal(map[string]interface{}{"name": "name", "type": "string", "doc": "Name of bottle"}))
+			Ω(fields[1]).Should(Equal(map[string]interface{}{
+				"name":    "origin",
+				"type":    []interface{}{"null", map[string]interface{}{"type": "record", "name": "Origin", "fields": []interface{}{map[string]interface{}{"name": "country", "type": []interface{}{"null", "string"}, "default": nil}}}},
+				"default": nil,
+			}))
+			Ω(fields[2]).Should(Equal(map[string]interface{}{"name": "tags", "type": []interface{}{"null", map[string]interface{}{"type": "array", "items": "string"}}, "default": nil}))
+			Ω(fields[3]).Should(Equal(map[string]interface{}{"name": "vintage", "type": "long", "default": 2000.0}))
+
+			content, err = ioutil.ReadFile(filepath.Join(testPkg.Abs(), "avro", "bottle_create.avsc"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(json.Unmarshal(content, &schema)).ShouldNot(HaveOccurred())
+			Ω(schema["name"]).Should(Equal("CreateBottlePayload"))
+			Ω(schema["fields"]).Should(Equal([]interface{}{
+				map[string]interface{}{"name": "created_at", "type": map[string]interface{}{"type": "long", "logicalType": "timestamp-millis"}},
+			}))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genavro.Generator
+
+	var args = struct {
+		api       *design.APIDefinition
+		outDir    string
+		namespace string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:    "out_dir",
+		namespace: "com.example",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genavro.NewGenerator(
+				genavro.API(args.api),
+				genavro.OutDir(args.outDir),
+				genavro.Namespace(args.namespace),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Namespace).Should(Equal(args.namespace))
+		})
+	})
+})
@@ -0,0 +1,25 @@
+/*
+Package genbackstage generates the Backstage catalog entity descriptor of a goa API so that the
+services built from the design register automatically in developer portals that use the Backstage
+software catalog, see https://backstage.io/docs/features/software-catalog/descriptor-format.
+
+The generator produces the file catalog-info.yaml which describes an entity of kind API whose
+definition is the OpenAPI specification generated by the openapi command. The definition is
+embedded using a $text substitution that refers to the specification relative to the descriptor,
+"./openapi/openapi.yaml" by default.
+
+The entity ownership and the other catalog specific properties are given with API metadata:
+
+	var _ = API("cellar", func() {
+		Title("The virtual wine cellar")
+		Metadata("backstage:owner", "group:wine-team") // Required
+		Metadata("backstage:lifecycle", "experimental") // Defaults to "production"
+		Metadata("backstage:system", "cellar")
+		Metadata("backstage:tags", "wine", "rest")
+		Metadata("backstage:name", "cellar-api")        // Defaults to the kebab case API name
+	})
+
+The entity title and description are the API title and description, the API documentation URL is
+listed in the entity links.
+*/
+package genbackstage
@@ -0,0 +1,13 @@
+package genbackstage_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenBackstage(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenBackstage Suite")
+}
@@ -0,0 +1,208 @@
+package genbackstage
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// DefaultDefinition is the path to the API specification used when none is given, it is the path
+// to the specification generated by the openapi command relative to the output directory.
+const DefaultDefinition = "./openapi/openapi.yaml"
+
+//NewGenerator returns an initialized instance of a Backstage Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+type (
+	// Generator is the Backstage catalog entity descriptor generator.
+	Generator struct {
+		API        *design.APIDefinition // The API definition
+		OutDir     string                // Path to output directory
+		Definition string                // Path to the API specification relative to OutDir
+		genfiles   []string              // Generated files
+	}
+
+	// Entity is the Backstage catalog entity descriptor of an API.
+	Entity struct {
+		APIVersion string          `yaml:"apiVersion"`
+		Kind       string          `yaml:"kind"`
+		Metadata   *EntityMetadata `yaml:"metadata"`
+		Spec       *EntitySpec     `yaml:"spec"`
+	}
+
+	// EntityMetadata contains the entity metadata.
+	EntityMetadata struct {
+		Name        string        `yaml:"name"`
+		Title       string        `yaml:"title,omitempty"`
+		Description string        `yaml:"description,omitempty"`
+		Tags        []string      `yaml:"tags,omitempty"`
+		Links       []*EntityLink `yaml:"links,omitempty"`
+	}
+
+	// EntityLink is an external hyperlink related to the entity.
+	EntityLink struct {
+		URL   string `yaml:"url"`
+		Title string `yaml:"title,omitempty"`
+	}
+
+	// EntitySpec is the specification of an entity of kind API.
+	EntitySpec struct {
+		Type       string            `yaml:"type"`
+		Lifecycle  string            `yaml:"lifecycle"`
+		Owner      string            `yaml:"owner"`
+		System     string            `yaml:"system,omitempty"`
+		Definition map[string]string `yaml:"definition"`
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, definition string
+	set := flag.NewFlagSet("backstage", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&definition, "definition", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Definition: definition, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the catalog-info.yaml file.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	e, err := NewEntity(g.API, g.Definition)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := yaml.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return nil, err
+	}
+	file := filepath.Join(g.OutDir, "catalog-info.yaml")
+	if err = ioutil.WriteFile(file, raw, 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, file)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// NewEntity returns the Backstage catalog entity describing the given API. definition is the path
+// to the API specification relative to the entity descriptor. It defaults to the specification
+// generated by the openapi command, DefaultDefinition unless the "goagen:out:openapi" API metadata
+// is set.
+func NewEntity(api *design.APIDefinition, definition string) (*Entity, error) {
+	owner := metadata(api, "backstage:owner")
+	if owner == "" {
+		return nil, fmt.Errorf(`missing entity owner, use Metadata("backstage:owner", ...) in the API design`)
+	}
+	lifecycle := metadata(api, "backstage:lifecycle")
+	if lifecycle == "" {
+		lifecycle = "production"
+	}
+	name := metadata(api, "backstage:name")
+	if name == "" {
+		name = entityName(api.Name)
+	}
+	if definition == "" {
+		definition = DefaultDefinition
+		if dir := codegen.OutputDir(api, "openapi", ""); dir != "" {
+			definition = "./" + path.Join(filepath.ToSlash(dir), "openapi.yaml")
+		}
+	}
+	var links []*EntityLink
+	if api.Docs != nil && api.Docs.URL != "" {
+		title := api.Docs.Description
+		if title == "" {
+			title = "Documentation"
+		}
+		links = append(links, &EntityLink{URL: api.Docs.URL, Title: title})
+	}
+	return &Entity{
+		APIVersion: "backstage.io/v1alpha1",
+		Kind:       "API",
+		Metadata: &EntityMetadata{
+			Name:        name,
+			Title:       api.Title,
+			Description: api.Description,
+			Tags:        api.Metadata["backstage:tags"],
+			Links:       links,
+		},
+		Spec: &EntitySpec{
+			Type:       "openapi",
+			Lifecycle:  lifecycle,
+			Owner:      owner,
+			System:     metadata(api, "backstage:system"),
+			Definition: map[string]string{"$text": definition},
+		},
+	}, nil
+}
+
+// metadata returns the first value of the API metadata with the given key, an empty string if
+// there is none.
+func metadata(api *design.APIDefinition, key string) string {
+	if vals := api.Metadata[key]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// entityName returns a valid Backstage entity name built from name: the name is lower cased and
+// the sequences of characters other than letters and digits are replaced with dashes.
+func entityName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(codegen.SnakeCase(name)), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	res := strings.Join(words, "-")
+	if len(res) > 63 {
+		res = strings.TrimRight(res[:63], "-")
+	}
+	return res
+}
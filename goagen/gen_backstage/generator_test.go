@@ -0,0 +1,177 @@
+package genbackstage_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_backstage"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("backstagetest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genbackstage.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an API owner", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+				apidsl.Description("The dummy API")
+				apidsl.Docs(func() {
+					apidsl.URL("https://example.com/docs")
+				})
+				apidsl.Metadata("backstage:owner", "group:dummies")
+				apidsl.Metadata("backstage:system", "dummy")
+				apidsl.Metadata("backstage:tags", "rest", "dummy")
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the catalog entity descriptor", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(Equal([]string{filepath.Join(testPkg.Abs(), "catalog-info.yaml")}))
+			content, err := ioutil.ReadFile(files[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			var entity map[string]interface{}
+			Ω(yaml.Unmarshal(content, &entity)).ShouldNot(HaveOccurred())
+			Ω(entity["apiVersion"]).Should(Equal("backstage.io/v1alpha1"))
+			Ω(entity["kind"]).Should(Equal("API"))
+			Ω(entity["metadata"]).Should(Equal(map[interface{}]interface{}{
+				"name":        "test-api",
+				"title":       "dummy API",
+				"description": "The dummy API",
+				"tags":        []interface{}{"rest", "dummy"},
+				"links":       []interface{}{map[interface{}]interface{}{"url": "https://example.com/docs", "title": "Documentation"}},
+			}))
+			Ω(entity["spec"]).Should(Equal(map[interface{}]interface{}{
+				"type":       "openapi",
+				"lifecycle":  "production",
+				"owner":      "group:dummies",
+				"system":     "dummy",
+				"definition": map[interface{}]interface{}{"$text": "./openapi/openapi.yaml"},
+			}))
+		})
+	})
+
+	Context("with no API owner", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("returns an error", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(files).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("NewEntity", func() {
+	var api *design.APIDefinition
+	var definition string
+	var entity *genbackstage.Entity
+	var err error
+
+	BeforeEach(func() {
+		api = &design.APIDefinition{
+			Name: "Wine Cellar",
+			Metadata: dslengine.MetadataDefinition{
+				"backstage:owner":     {"user:jane"},
+				"backstage:lifecycle": {"experimental"},
+			},
+		}
+		definition = ""
+	})
+
+	JustBeforeEach(func() {
+		entity, err = genbackstage.NewEntity(api, definition)
+	})
+
+	It("uses the API metadata and defaults", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(entity.Metadata.Name).Should(Equal("wine-cellar"))
+		Ω(entity.Spec.Owner).Should(Equal("user:jane"))
+		Ω(entity.Spec.Lifecycle).Should(Equal("experimental"))
+		Ω(entity.Spec.Definition).Should(Equal(map[string]string{"$text": genbackstage.DefaultDefinition}))
+	})
+
+	Context("with an explicit name and definition", func() {
+		BeforeEach(func() {
+			api.Metadata["backstage:name"] = []string{"cellar"}
+			definition = "./swagger/swagger.yaml"
+		})
+
+		It("uses them", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(entity.Metadata.Name).Should(Equal("cellar"))
+			Ω(entity.Spec.Definition).Should(Equal(map[string]string{"$text": "./swagger/swagger.yaml"}))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genbackstage.Generator
+
+	var args = struct {
+		api        *design.APIDefinition
+		outDir     string
+		definition string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:     "out_dir",
+		definition: "./swagger/swagger.yaml",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genbackstage.NewGenerator(
+				genbackstage.API(args.api),
+				genbackstage.OutDir(args.outDir),
+				genbackstage.Definition(args.definition),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Definition).Should(Equal(args.definition))
+		})
+	})
+})
@@ -59,6 +59,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 	hasBasicAuthSigners := false
 	hasAPIKeySigners := false
 	hasTokenSigners := false
+	hasSessionSigners := false
 	for _, s := range g.API.SecuritySchemes {
 		if signerType(s) != "" {
 			hasSigners = true
@@ -69,6 +70,8 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 				hasAPIKeySigners = true
 			case "jwt", "oauth2":
 				hasTokenSigners = true
+			case "session":
+				hasSessionSigners = true
 			}
 		}
 	}
@@ -81,6 +84,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		HasBasicAuthSigners bool
 		HasAPIKeySigners    bool
 		HasTokenSigners     bool
+		HasSessionSigners   bool
 	}{
 		API:                 g.API,
 		Version:             version,
@@ -89,6 +93,7 @@ func (g *Generator) generateMain(mainFile string, clientPkg, cliPkg string, func
 		HasBasicAuthSigners: hasBasicAuthSigners,
 		HasAPIKeySigners:    hasAPIKeySigners,
 		HasTokenSigners:     hasTokenSigners,
+		HasSessionSigners:   hasSessionSigners,
 	}
 	err = file.ExecuteTemplate("main", mainTmpl, funcs, data)
 	return
@@ -118,6 +123,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	funcs["formatExample"] = formatExample
 	funcs["shouldAddExample"] = shouldAddExample
 	funcs["kebabCase"] = codegen.KebabCase
+	funcs["payloadFlags"] = payloadFlags
 
 	commandTypesTmpl := template.Must(template.New("commandTypes").Funcs(funcs).Parse(commandTypesTmpl))
 	commandsTmpl := template.Must(template.New("commands").Funcs(funcs).Parse(commandsTmpl))
@@ -138,6 +144,7 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/spf13/cobra"),
+		codegen.SimpleImport("github.com/spf13/pflag"),
 		codegen.SimpleImport(clientPkg),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
@@ -258,6 +265,50 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	return
 }
 
+// generateConfig generates the code that reads the default values of the global flags from the
+// environment and from a configuration file and the command that generates the shell completion
+// scripts.
+func (g *Generator) generateConfig(configFile string) (err error) {
+	var file *codegen.SourceFile
+	file, err = codegen.SourceFileFor(configFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("path/filepath"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("github.com/spf13/cobra"),
+		codegen.SimpleImport("github.com/spf13/pflag"),
+	}
+	title := fmt.Sprintf("%s: CLI Configuration", g.API.Context())
+	if err = file.WriteHeader(title, "cli", imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, configFile)
+	data := map[string]string{
+		"EnvPrefix": envPrefix(g.API),
+	}
+	return file.ExecuteTemplate("config", configT, nil, data)
+}
+
+// envPrefix returns the prefix of the environment variables read by the generated CLI, e.g.
+// "CELLAR" for the "cellar" API.
+func envPrefix(api *design.APIDefinition) string {
+	return strings.ToUpper(codegen.SnakeCase(codegen.Goify(api.Name, true)))
+}
+
 // defaultRouteParams returns the parameters needed to build the first route of the given action.
 func defaultRouteParams(a *design.ActionDefinition) *design.AttributeDefinition {
 	r := a.Routes[0]
@@ -272,6 +323,69 @@ func defaultRouteParams(a *design.ActionDefinition) *design.AttributeDefinition
 	return &design.AttributeDefinition{Type: o, NonZeroAttributes: nz}
 }
 
+// payloadFlag describes a CLI flag used to set a payload attribute.
+type payloadFlag struct {
+	Name         string // Flag name, same as the attribute name
+	Field        string // Name of the command struct field holding the flag value
+	PayloadField string // Name of the payload struct field
+	Type         string // Go type of the flag value
+	FlagType     string // Suffix of the pflag method used to register the flag, e.g. "String"
+	Zero         string // Go code for the flag zero value
+	Pointer      bool   // Whether the payload field is a pointer
+	Description  string // Flag description
+}
+
+// payloadFlags returns the flags used to set the primitive attributes of the action object
+// payload. Attributes whose names collide with the other flags of the command are skipped.
+func payloadFlags(a *design.ActionDefinition) []*payloadFlag {
+	if a.Payload == nil || !a.Payload.Type.IsObject() || a.PayloadMultipart {
+		return nil
+	}
+	taken := map[string]bool{"payload": true, "content": true, "pp": true}
+	for _, att := range []*design.AttributeDefinition{defaultRouteParams(a), a.QueryParams, a.Headers} {
+		if att == nil {
+			continue
+		}
+		for n := range att.Type.ToObject() {
+			taken[n] = true
+		}
+	}
+	var flags []*payloadFlag
+	o := a.Payload.Type.ToObject()
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if taken[n] {
+			continue
+		}
+		att := o[n]
+		flag := &payloadFlag{
+			Name:         n,
+			Field:        "Payload" + codegen.Goify(n, true),
+			PayloadField: codegen.GoifyAtt(att, n, true),
+			Pointer:      a.Payload.IsPrimitivePointer(n),
+			Description:  att.Description,
+		}
+		switch att.Type.Kind() {
+		case design.StringKind:
+			flag.Type, flag.FlagType, flag.Zero = "string", "String", `""`
+		case design.IntegerKind:
+			flag.Type, flag.FlagType, flag.Zero = "int", "Int", "0"
+		case design.NumberKind:
+			flag.Type, flag.FlagType, flag.Zero = "float64", "Float64", "0"
+		case design.BooleanKind:
+			flag.Type, flag.FlagType, flag.Zero = "bool", "Bool", "false"
+		default:
+			continue
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
 // produces a fmt template to render the first route of action.
 func defaultRouteTemplate(a *design.ActionDefinition) string {
 	return design.WildcardRegex.ReplaceAllLiteralString(a.Routes[0].FullPath(), "/%v")
@@ -532,6 +646,8 @@ func signerSignature(sec *design.SecuritySchemeDefinition) string {
 		return "source goaclient.TokenSource"
 	case "oauth2":
 		return "source goaclient.TokenSource"
+	case "session":
+		return "session string"
 	default:
 		return ""
 	}
@@ -549,6 +665,8 @@ func signerArgs(sec *design.SecuritySchemeDefinition) string {
 		return "source"
 	case "oauth2":
 		return "source"
+	case "session":
+		return "session"
 	default:
 		return ""
 	}
@@ -639,7 +757,15 @@ func main() {
 {{ end }}{{ if .HasTokenSigners }} var token, typ string
 	app.PersistentFlags().StringVar(&token, "token", "", "Token used for authentication")
 	app.PersistentFlags().StringVar(&typ, "token-type", "Bearer", "Token type used for authentication")
-{{ end }}
+{{ end }}{{ if .HasSessionSigners }} var session string
+	app.PersistentFlags().StringVar(&session, "session", "", "Session cookie value used for authentication")
+{{ end }}{{ end }}
+	// Read the flags default values from the environment and the configuration file
+	if err := cli.ApplyConfig(app); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+{{ if .HasSigners }}
 	// Parse flags and setup signers
 	app.ParseFlags(os.Args)
 {{ if .HasTokenSigners }}	source := &goaclient.StaticTokenSource{
@@ -668,7 +794,7 @@ func main() {
 func newHTTPClient() *http.Client {
 	// TBD: Change as needed (e.g. to use a different transport to control redirection policy or
 	// disable cert validation or...)
-	return http.DefaultClient
+	return goaclient.NewHTTPClient()
 }
 
 {{ range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}
@@ -691,6 +817,10 @@ func new{{ goify $security.SchemeName true }}Signer({{ signerSignature $security
 {{ else if eq .Type "oauth2" }}	return &goaclient.OAuth2Signer{
 		TokenSource: source,
 	}
+{{ else if eq .Type "session" }}	return &goaclient.CookieSigner{
+		Name: "{{ $security.Name }}",
+		Value: session,
+	}
 {{ end }}
 }
 {{ end }}{{ end }}
@@ -700,7 +830,10 @@ const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Name (titl
 	{{ $cmdName }} struct {
 {{ if .Payload }}		Payload string
 		ContentType string
-{{ end }}{{ $params := defaultRouteParams . }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
+{{ range payloadFlags . }}{{ if .Description }}		{{ multiComment .Description }}
+{{ end }}		{{ .Field }} {{ .Type }}
+{{ end }}{{ if payloadFlags . }}		flags *pflag.FlagSet
+{{ end }}{{ end }}{{ $params := defaultRouteParams . }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false }}
 {{ end }}{{ end }}{{ $params := .QueryParams }}{{ if $params }}{{ range $name, $att := $params.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type false}}
@@ -797,7 +930,9 @@ const registerTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Action.Name (t
 func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *{{ .Package }}.Client) {
 {{ if .Action.Payload }}	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request body encoded in JSON")
 	cc.Flags().StringVar(&cmd.ContentType, "content", "", "Request content type override, e.g. 'application/x-www-form-urlencoded'")
-{{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
+{{ range payloadFlags .Action }}	cc.Flags().{{ .FlagType }}Var(&cmd.{{ .Field }}, "{{ .Name }}", {{ .Zero }}, ` + "`" + `{{ escapeBackticks .Description }}` + "`" + `)
+{{ end }}{{ if payloadFlags .Action }}	cmd.flags = cc.Flags()
+{{ end }}{{ end }}{{ $pparams := defaultRouteParams .Action }}{{ if $pparams }}{{ range $pname, $pparam := $pparams.Type.ToObject }}{{ $tmp := goify $pname false }}{{/*
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type false }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
 */}}{{ if $pparam.DefaultValue }}{{ defaultVal $pparam }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
@@ -827,7 +962,11 @@ func (cmd *{{ $cmdName }}) Run(c *{{ .Package }}.Client, args []string) error {
 {{ if eq .Action.Payload.Type.Kind 4 }}	payload = cmd.Payload
 {{ else }}			return fmt.Errorf("failed to deserialize payload: %s", err)
 {{ end }}		}
-	}
+	}{{ $pflags := payloadFlags .Action }}{{ if $pflags }} else {
+{{ range $pflags }}		if cmd.flags.Changed("{{ .Name }}") {
+			payload.{{ .PayloadField }} = {{ if .Pointer }}&{{ end }}cmd.{{ .Field }}
+		}
+{{ end }}	}{{ end }}
 {{ end }}	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
 	ctx := goa.WithLogger(context.Background(), logger){{ $specialTypeResult := handleSpecialTypes .Action.QueryParams .Action.Headers }}{{ $specialTypeResult.Output }}
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{/*
@@ -879,7 +1018,8 @@ Payload example:
 	}
 	dlc.Flags().StringVar(&dl.OutFile, "out", "", "Output file")
 	app.AddCommand(dlc)
-{{ end }}}
+{{ end }}	app.AddCommand(newCompletionCommand(app))
+}
 
 func intFlagVal(name string, parsed int) *int {
 	if hasFlag(name) {
@@ -1033,3 +1173,126 @@ func boolArray(ins []string) ([]bool, error) {
 	}
 	return vals, nil
 }`
+
+const configT = `
+// EnvPrefix is the prefix of the environment variables that set the default values of the global
+// flags, see ApplyConfig.
+const EnvPrefix = "{{ .EnvPrefix }}"
+
+// ApplyConfig sets the default values of the global flags of app from the environment and from the
+// configuration file. The environment variable of a flag is named after the flag, e.g.
+// {{ .EnvPrefix }}_HOST for --host and {{ .EnvPrefix }}_TOKEN_TYPE for --token-type. The configuration file is a JSON
+// object whose keys are flag names, e.g. {"host": "api.example.com", "timeout": "30s"}. Its path is
+// the value of the {{ .EnvPrefix }}_CONFIG environment variable if set, ConfigFile otherwise.
+// The flags given on the command line take precedence over the environment which takes precedence
+// over the configuration file.
+func ApplyConfig(app *cobra.Command) error {
+	values := make(map[string]string)
+	path := os.Getenv(EnvPrefix + "_CONFIG")
+	if path == "" {
+		path = ConfigFile(app)
+	}
+	if b, err := ioutil.ReadFile(path); err == nil {
+		var config map[string]interface{}
+		if err := json.Unmarshal(b, &config); err != nil {
+			return fmt.Errorf("invalid configuration file %s: %s", path, err)
+		}
+		for k, v := range config {
+			values[k] = fmt.Sprint(v)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	var err error
+	app.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		env := EnvPrefix + "_" + strings.ToUpper(strings.Replace(f.Name, "-", "_", -1))
+		if v, ok := os.LookupEnv(env); ok {
+			values[f.Name] = v
+		}
+		if v, ok := values[f.Name]; ok && err == nil {
+			if serr := f.Value.Set(v); serr != nil {
+				err = fmt.Errorf("invalid default value %q for flag --%s: %s", v, f.Name, serr)
+			}
+		}
+	})
+	return err
+}
+
+// ConfigFile returns the default path to the configuration file read by ApplyConfig:
+// $XDG_CONFIG_HOME/<tool>/config.json, $XDG_CONFIG_HOME defaulting to $HOME/.config.
+func ConfigFile(app *cobra.Command) string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, app.Name(), "config.json")
+}
+
+// newCompletionCommand returns the command that writes the shell completion script of app.
+func newCompletionCommand(app *cobra.Command) *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion [bash|zsh|fish]",
+		Short: "Generate the shell completion script",
+		Long: ` + "`" + `Generate the shell completion script, for example:
+
+  source <(` + "`" + ` + app.Name() + ` + "`" + ` completion bash)
+  ` + "`" + ` + app.Name() + ` + "`" + ` completion fish > ~/.config/fish/completions/` + "`" + ` + app.Name() + ` + "`" + `.fish` + "`" + `,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("usage: %s completion [bash|zsh|fish]", app.Name())
+			}
+			switch args[0] {
+			case "bash":
+				return app.GenBashCompletion(os.Stdout)
+			case "zsh":
+				return app.GenZshCompletion(os.Stdout)
+			case "fish":
+				return genFishCompletion(app, os.Stdout)
+			}
+			return fmt.Errorf("unsupported shell %q, must be bash, zsh or fish", args[0])
+		},
+	}
+}
+
+// genFishCompletion writes the fish completion script of app to w.
+func genFishCompletion(app *cobra.Command, w io.Writer) error {
+	var buf bytes.Buffer
+	name := app.Name()
+	quote := func(s string) string {
+		return "'" + strings.Replace(strings.Replace(s, "\\", "\\\\", -1), "'", "\\'", -1) + "'"
+	}
+	flags := func(cond string, fs *pflag.FlagSet) {
+		fs.VisitAll(func(f *pflag.Flag) {
+			if f.Hidden {
+				return
+			}
+			fmt.Fprintf(&buf, "complete -c %s", name)
+			if cond != "" {
+				fmt.Fprintf(&buf, " -n %s", quote(cond))
+			}
+			fmt.Fprintf(&buf, " -l %s", f.Name)
+			if f.Shorthand != "" {
+				fmt.Fprintf(&buf, " -s %s", f.Shorthand)
+			}
+			fmt.Fprintf(&buf, " -d %s\n", quote(f.Usage))
+		})
+	}
+	fmt.Fprintf(&buf, "# fish completion for %s\n", name)
+	flags("", app.PersistentFlags())
+	var walk func(cmd *cobra.Command, cond string)
+	walk = func(cmd *cobra.Command, cond string) {
+		for _, sub := range cmd.Commands() {
+			if sub.Hidden {
+				continue
+			}
+			fmt.Fprintf(&buf, "complete -c %s -f -n %s -a %s -d %s\n", name, quote(cond), sub.Name(), quote(sub.Short))
+			subCond := "__fish_seen_subcommand_from " + sub.Name()
+			flags(subCond, sub.LocalFlags())
+			walk(sub, subCond)
+		}
+	}
+	walk(app, "__fish_use_subcommand")
+	_, err := buf.WriteTo(w)
+	return err
+}
+`
@@ -54,7 +54,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates a dummy app", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(9))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
 			content := string(c)
 			Ω(err).ShouldNot(HaveOccurred())
@@ -63,6 +63,22 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 
+		It("generates the configuration and completion support", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "config.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring(`const EnvPrefix = "TESTAPI"`))
+			Ω(content).Should(ContainSubstring("func ApplyConfig(app *cobra.Command) error {"))
+			Ω(content).Should(ContainSubstring("func genFishCompletion(app *cobra.Command, w io.Writer) error {"))
+			c, err = ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(c)).Should(ContainSubstring("if err := cli.ApplyConfig(app); err != nil {"))
+			c, err = ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(c)).Should(ContainSubstring("app.AddCommand(newCompletionCommand(app))"))
+		})
+
 		Context("generated commands.go", func() {
 			var commandHeader string
 
@@ -144,6 +160,59 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with an action with an object payload", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Consumes:    design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"create": {
+								Name: "create",
+								Payload: &design.UserTypeDefinition{
+									TypeName: "CreateFooPayload",
+									AttributeDefinition: &design.AttributeDefinition{
+										Type: design.Object{
+											"name":    &design.AttributeDefinition{Type: design.String, Description: "Foo name"},
+											"vintage": &design.AttributeDefinition{Type: design.Integer},
+										},
+										Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+									},
+								},
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "POST",
+										Path: "/foos",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			createAct := fooRes.Actions["create"]
+			createAct.Parent = fooRes
+			createAct.Routes[0].Parent = createAct
+		})
+
+		It("generates typed flags for the payload attributes", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
+			content := string(c)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`cc.Flags().StringVar(&cmd.PayloadName, "name", "", ` + "`Foo name`" + `)`))
+			Ω(content).Should(ContainSubstring(`cc.Flags().IntVar(&cmd.PayloadVintage, "vintage", 0, ` + "``" + `)`))
+			Ω(content).Should(ContainSubstring(`payload.Name = cmd.PayloadName`))
+			Ω(content).Should(ContainSubstring(`payload.Vintage = &cmd.PayloadVintage`))
+		})
+	})
+
 	Context("with a resource name with underscores characters", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -361,7 +430,7 @@ var _ = Describe("Generate", func() {
 
 			It("properly escapes the multi-line string used in the short description", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(9))
+				Ω(files).Should(HaveLen(10))
 				c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
 				content := string(c)
 				Ω(err).ShouldNot(HaveOccurred())
@@ -379,7 +448,7 @@ var _ = Describe("Generate", func() {
 
 			It("properly escapes the multi-line string used in the short description", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(9))
+				Ω(files).Should(HaveLen(10))
 				c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
 				content := string(c)
 				Ω(err).ShouldNot(HaveOccurred())
@@ -426,7 +495,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates direct access to Command field when resolving path", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "cli", "commands.go"))
 			content := string(c)
 			Ω(err).ShouldNot(HaveOccurred())
@@ -483,7 +552,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates registers the signer flags from main", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
 			content := string(c)
 			Ω(err).ShouldNot(HaveOccurred())
@@ -11,6 +11,12 @@ The generated code includes a client package with:
     * Structs for the action media types and corresponding decoder functions
 
 The generated code also includes a CLI tool with commands for each action and sub-commands for
-each resource.
+each resource. The "completion" command of the tool writes its bash, zsh or fish completion script.
+The tool reads the default values of its global flags (host, timeout, credentials etc.) from
+environment variables prefixed with the upper snake case API name, e.g. CELLAR_HOST, and from the
+JSON configuration file $XDG_CONFIG_HOME/<tool>/config.json, see the ApplyConfig function of the
+generated cli package. The flags given on the command line take precedence. The main.go of the tool is
+only generated once, existing tools must call cli.ApplyConfig before parsing the flags to read the
+configuration.
 */
 package genclient
@@ -63,6 +63,7 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&regen, "regen", false, "")
 	set.String("design", "", "")
 	set.Bool("force", false, "")
+	set.Bool("debug-listener", false, "")
 	set.Bool("notest", false, "")
 	set.Parse(os.Args[1:])
 
@@ -105,20 +106,25 @@ func (g *Generator) Generate() (_ []string, err error) {
 	g.ToolDirName = firstNonEmpty(g.ToolDirName, "tool")
 	g.Tool = firstNonEmpty(g.Tool, defaultToolName(g.API))
 
+	// The client package and tool may be generated in other directories, see codegen.OutputDir.
+	clientDir := codegen.OutputDir(g.API, "client", g.Target)
+	g.Target = filepath.Base(clientDir)
+	toolsDir := codegen.OutputDir(g.API, "tool", g.ToolDirName)
+
 	codegen.Reserved[g.Target] = true
 
 	// Setup output directories as needed
 	var pkgDir, toolDir, cliDir string
 	{
 		if !g.NoTool {
-			toolDir = filepath.Join(g.OutDir, g.ToolDirName, g.Tool)
+			toolDir = filepath.Join(g.OutDir, toolsDir, g.Tool)
 			if _, err = os.Stat(toolDir); err != nil {
 				if err = os.MkdirAll(toolDir, 0755); err != nil {
 					return
 				}
 			}
 
-			cliDir = filepath.Join(g.OutDir, g.ToolDirName, "cli")
+			cliDir = filepath.Join(g.OutDir, toolsDir, "cli")
 			if err = os.RemoveAll(cliDir); err != nil {
 				return
 			}
@@ -127,7 +133,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 			}
 		}
 
-		pkgDir = filepath.Join(g.OutDir, g.Target)
+		pkgDir = filepath.Join(g.OutDir, clientDir)
 		if err = os.RemoveAll(pkgDir); err != nil {
 			return
 		}
@@ -192,6 +198,11 @@ func (g *Generator) Generate() (_ []string, err error) {
 		if err = g.generateCommands(filepath.Join(cliDir, "commands.go"), clientPkg, funcs); err != nil {
 			return
 		}
+
+		// Generate tool/cli/config.go
+		if err = g.generateConfig(filepath.Join(cliDir, "config.go")); err != nil {
+			return
+		}
 	}
 
 	// Generate client/client.go
@@ -281,13 +292,18 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 
 	// Generate
 	data := struct {
-		API      *design.APIDefinition
-		Encoders []*genapp.EncoderTemplateData
-		Decoders []*genapp.EncoderTemplateData
+		API        *design.APIDefinition
+		DesignHash string
+		Encoders   []*genapp.EncoderTemplateData
+		Decoders   []*genapp.EncoderTemplateData
 	}{
-		API:      g.API,
-		Encoders: encoders,
-		Decoders: decoders,
+		API:        g.API,
+		DesignHash: codegen.DesignHash,
+		Encoders:   encoders,
+		Decoders:   decoders,
+	}
+	if err = codegen.Dump("client/client", data); err != nil {
+		return
 	}
 	err = clientTmpl.Execute(file, data)
 	return
@@ -303,8 +319,16 @@ func (g *Generator) generateClientResources(pkgDir, clientPkg string, funcs temp
 	if err := g.generateUserTypes(pkgDir); err != nil {
 		return err
 	}
+	if err := g.generateMediaTypes(pkgDir, funcs); err != nil {
+		return err
+	}
 
-	return g.generateMediaTypes(pkgDir, funcs)
+	// The validation code of the user and media types uses the compiled patterns.
+	patternsFile, err := genapp.GeneratePatterns(g.API, pkgDir, g.Target)
+	if patternsFile != "" {
+		g.genfiles = append(g.genfiles, patternsFile)
+	}
+	return err
 }
 
 func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDefinition, funcs template.FuncMap) (err error) {
@@ -346,6 +370,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
 	title := fmt.Sprintf("%s: %s Resource Client", g.API.Context(), res.Name)
@@ -500,6 +525,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Payload            *design.UserTypeDefinition
 		PayloadMultipart   bool
 		HasPayload         bool
+		HasSensitive       bool
 		HasMultiContent    bool
 		DefaultContentType string
 		Params             string
@@ -516,6 +542,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Payload:            action.Payload,
 		PayloadMultipart:   action.PayloadMultipart,
 		HasPayload:         action.Payload != nil,
+		HasSensitive:       action.Payload != nil && design.HasSensitive(action.Payload),
 		HasMultiContent:    len(design.Design.Consumes) > 1,
 		DefaultContentType: design.Design.Consumes[0].MIMETypes[0],
 		Params:             strings.Join(params, ", "),
@@ -525,6 +552,10 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		QueryParams:        queryParams,
 		Headers:            headers,
 	}
+	name := codegen.Goify(action.Name, true) + codegen.Goify(action.Parent.Name, true)
+	if err := codegen.Dump("client/actions/"+name, data); err != nil {
+		return err
+	}
 	if action.WebSocket() {
 		return clientsWSTmpl.Execute(file, data)
 	}
@@ -846,6 +877,8 @@ func signerType(scheme *design.SecuritySchemeDefinition) string {
 		return "goaclient.APIKeySigner"
 	case design.BasicAuthSecurityKind:
 		return "goaclient.BasicSigner"
+	case design.SessionSecurityKind:
+		return "goaclient.CookieSigner"
 	}
 	return ""
 }
@@ -974,7 +1007,8 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	if err != nil {
 		return nil, err
 	}
-	return c.Client.Do(ctx, req)
+{{ if .HasSensitive }}	ctx = goaclient.WithDumpPayload(ctx, payload)
+{{ end }}	return c.Client.Do(ctx, req)
 }
 `
 
@@ -1149,7 +1183,17 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 }
 `
 
-	clientTmpl = `// Client is the {{ .API.Name }} service client.
+	clientTmpl = `const (
+	// DesignVersion is the version of the API design the client was generated from.
+	DesignVersion = {{ printf "%q" .API.Version }}
+	// DesignHash is the hash of the design package sources the client was generated from.
+	DesignHash = {{ printf "%q" .DesignHash }}
+	// DesignVersionHeader is the name of the response header that reports the version and the hash of
+	// the design implemented by the service, e.g. "1.0+6b0f1c...".
+	DesignVersionHeader = "X-Design-Version"
+)
+
+// Client is the {{ .API.Name }} service client.
 type Client struct {
 	*goaclient.Client{{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}
 	{{ goify $security.SchemeName true }}Signer goaclient.Signer{{ end }}{{ end }}
@@ -1157,7 +1201,8 @@ type Client struct {
 	Decoder *goa.HTTPDecoder
 }
 
-// New instantiates the client.
+// New instantiates the client. If c is nil the client uses the HTTP transport shared by all the
+// service clients of the process, see goaclient.SharedTransport.
 func New(c goaclient.Doer) *Client {
 	client := &Client{
 		Client: goaclient.New(c),
@@ -1181,6 +1226,12 @@ func New(c goaclient.Doer) *Client {
 {{ end }}	return client
 }
 
+// NewInMemory instantiates a client that sends requests directly to the given service instance
+// without going through the network. It is intended for testing code that depends on the service.
+func NewInMemory(service *goa.Service) *Client {
+	return New(goaclient.HandlerDoer(service.Mux))
+}
+
 {{range $security := .API.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}{{/*
 */}}{{ $name := printf "%sSigner" (goify $security.SchemeName true) }}{{/*
 */}}// Set{{ $name }} sets the request signer for the {{ $security.SchemeName }} security scheme.
@@ -166,7 +166,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates header initialization code that compiles", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			content := string(c)
@@ -212,7 +212,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates path initialization code that uses all defined URL params in proper format", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			content := string(c)
@@ -268,7 +268,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates param initialization code that uses the param name given in the design", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			content := string(c)
@@ -295,7 +295,7 @@ var _ = Describe("Generate", func() {
 
 			It("should not return an error", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(5)) // 9, minus 4 entries for tool paths
+				Ω(files).Should(HaveLen(5)) // 10, minus 5 entries for tool paths
 			})
 		})
 	})
@@ -339,7 +339,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates param initialization code that uses the param name given in the design", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			content := string(c)
@@ -372,7 +372,7 @@ var _ = Describe("Generate", func() {
 
 			It("should not return an error", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(5)) // 9, minus 4 entries for tool paths
+				Ω(files).Should(HaveLen(5)) // 10, minus 5 entries for tool paths
 			})
 		})
 	})
@@ -412,7 +412,7 @@ var _ = Describe("Generate", func() {
 
 		It("generates Path function with unique names", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(content).Should(ContainSubstring("func ShowFooPath("))
@@ -435,7 +435,7 @@ var _ = Describe("Generate", func() {
 
 			It("generates a Download function", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(9))
+				Ω(files).Should(HaveLen(10))
 				content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 				Ω(err).ShouldNot(HaveOccurred())
 				Ω(content).Should(ContainSubstring("func (c *Client) DownloadSwaggerJSON("))
@@ -494,22 +494,55 @@ var _ = Describe("Generate", func() {
 
 		It("generates the correct client Fields", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(content).Should(ContainSubstring("JWT1Signer goaclient.Signer"))
 			Ω(content).Should(ContainSubstring("func (c *Client) SetJWT1Signer(signer goaclient.Signer) {\n	c.JWT1Signer = signer\n}"))
 		})
 
+		It("generates the in-memory client constructor", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("func NewInMemory(service *goa.Service) *Client {\n	return New(goaclient.HandlerDoer(service.Mux))\n}"))
+		})
+
+		It("generates the design version constants", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("DesignVersion = \"\""))
+			Ω(content).Should(ContainSubstring("DesignVersionHeader = \"X-Design-Version\""))
+		})
+
 		It("generates the Signer.Sign call from Action", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(content).Should(ContainSubstring(`		if err := c.JWT1Signer.Sign(req); err != nil {
 			return nil, err
 		}`))
 		})
+
+		Context("using a session scheme", func() {
+			BeforeEach(func() {
+				scheme := design.Design.SecuritySchemes[0]
+				scheme.Kind = design.SessionSecurityKind
+				scheme.Type = "session"
+				scheme.Name = "sid"
+			})
+
+			It("generates a tool that signs the requests with the session cookie", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "tool", "testapi-cli", "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`app.PersistentFlags().StringVar(&session, "session", "", "Session cookie value used for authentication")`))
+				Ω(string(content)).Should(ContainSubstring("Signer := newJWT1Signer(session)"))
+				Ω(string(content)).Should(ContainSubstring("return &goaclient.CookieSigner{\n\t\tName:  \"sid\",\n\t\tValue: session,\n\t}"))
+			})
+		})
 	})
 
 	Context("with an action with a user type payload", func() {
@@ -559,11 +592,34 @@ var _ = Describe("Generate", func() {
 
 		It("generates the user type imports", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(9))
+			Ω(files).Should(HaveLen(10))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "user_types.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(content).Should(ContainSubstring("uuid \"github.com/goadesign/goa/uuid\""))
 		})
+
+		Context("with a pattern validation", func() {
+			BeforeEach(func() {
+				o := design.Design.Types["TestType"].Type.ToObject()
+				o["name"] = &design.AttributeDefinition{
+					Type:       design.String,
+					Validation: &dslengine.ValidationDefinition{Pattern: "^[a-z]+$"},
+				}
+			})
+
+			It("declares the compiled pattern used by the validation in the client package", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(11))
+				patternVar := codegen.PatternVar("^[a-z]+$")
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "user_types.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("if ok := " + patternVar + ".MatchString(*ut.Name); !ok {"))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "client", "patterns.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("package client"))
+				Ω(string(content)).Should(ContainSubstring(patternVar + ` = regexp.MustCompile("^[a-z]+$")`))
+			})
+		})
 	})
 })
 
@@ -2,5 +2,7 @@
 Package gencontroller generates the controller code for a given design resource.
 This generator is intended for use when resources are added to the design
 after the initial bootstrapping.
+The --merge flag regenerates the existing controllers and re-applies the changes made to them
+since they were last generated, see the genmain package.
 */
 package gencontroller
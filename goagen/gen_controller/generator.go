@@ -33,16 +33,18 @@ type Generator struct {
 	AppPkg    string                // Name of generated "app" package
 	Force     bool                  // Whether to override existing files
 	Regen     bool                  // Whether to regenerate scaffolding in place, retaining controller impls
+	Merge     bool                  // Whether to merge the changes made to the existing files with the regenerated scaffolding
 	Pkg       string                // Name of the generated package
 	Resource  string                // Name of the generated file
 	genfiles  []string              // Generated files
+	conflicts []string              // Merged files that contain conflicts
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
 		outDir, designPkg, appPkg, ver, res, pkg string
-		force, regen                             bool
+		force, regen, merge                      bool
 	)
 
 	set := flag.NewFlagSet("controller", flag.PanicOnError)
@@ -54,6 +56,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
+	set.BoolVar(&merge, "merge", false, "")
 	set.Bool("notest", false, "")
 	set.Parse(os.Args[1:])
 
@@ -61,7 +64,7 @@ func Generate() (files []string, err error) {
 		return nil, err
 	}
 
-	g := &Generator{OutDir: outDir, DesignPkg: designPkg, AppPkg: appPkg, Force: force, Regen: regen, API: design.Design, Pkg: pkg, Resource: res}
+	g := &Generator{OutDir: outDir, DesignPkg: designPkg, AppPkg: appPkg, Force: force, Regen: regen, Merge: merge, API: design.Design, Pkg: pkg, Resource: res}
 
 	return g.Generate()
 }
@@ -75,13 +78,14 @@ func (g *Generator) Generate() (_ []string, err error) {
 	go utils.Catch(nil, func() { g.Cleanup() })
 
 	defer func() {
-		if err != nil {
+		if err != nil && len(g.conflicts) == 0 {
 			g.Cleanup()
 		}
 	}()
 
-	if g.AppPkg == "" {
-		g.AppPkg = "app"
+	if g.AppPkg == "" || g.AppPkg == "app" {
+		// Default to the directory the app package is generated in, see codegen.OutputDir.
+		g.AppPkg = filepath.ToSlash(codegen.OutputDir(g.API, "app", "app"))
 	}
 	g.smartenPkg()
 	elems := strings.Split(g.AppPkg, "/")
@@ -94,7 +98,11 @@ func (g *Generator) Generate() (_ []string, err error) {
 			err      error
 		)
 		if g.Resource == "" || g.Resource == r.Name {
-			filename, err = genmain.GenerateController(g.Force, g.Regen, g.AppPkg, g.OutDir, g.Pkg, r.Name, r)
+			filename, err = genmain.GenerateController(g.Force, g.Regen, g.Merge, g.AppPkg, g.OutDir, g.Pkg, r.Name, r)
+		}
+		if cerr, ok := err.(*codegen.MergeConflictError); ok {
+			g.conflicts = append(g.conflicts, cerr.Filename)
+			err = nil
 		}
 
 		if err != nil {
@@ -107,6 +115,9 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err != nil {
 		return nil, err
 	}
+	if len(g.conflicts) > 0 {
+		return nil, fmt.Errorf("merge conflicts in %s, resolve the conflict markers", strings.Join(g.conflicts, ", "))
+	}
 
 	return g.genfiles, err
 }
@@ -47,6 +47,13 @@ func Regen(regen bool) Option {
 	}
 }
 
+//Merge Whether to merge the changes made to the existing controllers with the regenerated scaffolding
+func Merge(merge bool) Option {
+	return func(g *Generator) {
+		g.Merge = merge
+	}
+}
+
 //Pkg sets the name of generated package
 func Pkg(name string) Option {
 	return func(g *Generator) {
@@ -0,0 +1,570 @@
+package gendiff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	// Change describes a change that breaks the existing clients of the API.
+	Change struct {
+		// Endpoint is the HTTP method and path of the affected endpoint, e.g.
+		// "GET /bottles/{id}".
+		Endpoint string
+		// Location is the part of the endpoint affected by the change if any, e.g.
+		// `query parameter "limit"` or "request body.rating".
+		Location string
+		// Message describes the change.
+		Message string
+	}
+
+	// spec is a Swagger 2.0 or OpenAPI 3.x document indexed by endpoint.
+	spec struct {
+		doc       map[string]interface{}
+		endpoints map[string]*endpoint
+	}
+
+	// endpoint holds the parts of an operation relevant to the clients.
+	endpoint struct {
+		name         string
+		params       map[string]map[string]interface{}
+		required     map[string]bool
+		body         map[string]interface{}
+		bodyRequired bool
+		responses    map[string]map[string]interface{}
+		secured      bool
+	}
+
+	// comparison holds the state of the comparison of two specifications.
+	comparison struct {
+		base, current *spec
+		endpoint      string
+		changes       []*Change
+		seen          map[string]bool // Schema references being compared, guards recursion
+	}
+)
+
+// methods lists the operation keys of a path item.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// templateRegex matches the path template variables.
+var templateRegex = regexp.MustCompile(`\{[^}]*\}`)
+
+// String returns a human readable description of the change.
+func (c *Change) String() string {
+	if c.Location == "" {
+		return fmt.Sprintf("%s: %s", c.Endpoint, c.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", c.Endpoint, c.Location, c.Message)
+}
+
+// Load decodes a Swagger 2.0 or OpenAPI 3.x document written in JSON or YAML.
+func Load(doc []byte) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(doc, &raw); err != nil {
+		return nil, fmt.Errorf("invalid specification: %s", err)
+	}
+	s, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid specification: not an object")
+	}
+	_, isSwagger := s["swagger"]
+	_, isOpenAPI := s["openapi"]
+	if !isSwagger && !isOpenAPI {
+		return nil, fmt.Errorf("invalid specification: missing openapi or swagger version")
+	}
+	return s, nil
+}
+
+// Compare returns the changes made between the base and current specifications that break the
+// clients of the base API: removed endpoints and responses, new required parameters, request body
+// attributes and security requirements, changed types and narrowed validations of the request
+// parameters and bodies, removed response attributes and widened response values. The
+// specifications may be Swagger 2.0 or OpenAPI 3.x documents as returned by Load. The endpoints are
+// matched by HTTP method and path regardless of the names of the path parameters. The changes are
+// sorted by endpoint.
+func Compare(base, current map[string]interface{}) []*Change {
+	c := &comparison{base: newSpec(base), current: newSpec(current)}
+	for _, key := range sortedKeys(c.base.endpoints) {
+		b := c.base.endpoints[key]
+		cur, ok := c.current.endpoints[key]
+		if !ok {
+			c.changes = append(c.changes, &Change{Endpoint: b.name, Message: "endpoint removed"})
+			continue
+		}
+		c.endpoint = cur.name
+		c.seen = make(map[string]bool)
+		c.compareEndpoint(b, cur)
+	}
+	sort.SliceStable(c.changes, func(i, j int) bool { return c.changes[i].Endpoint < c.changes[j].Endpoint })
+	return c.changes
+}
+
+// newSpec indexes the endpoints of the given document.
+func newSpec(doc map[string]interface{}) *spec {
+	s := &spec{doc: doc, endpoints: make(map[string]*endpoint)}
+	_, isSwagger := doc["swagger"]
+	prefix := ""
+	if isSwagger {
+		prefix = strings.TrimSuffix(str(doc, "basePath"), "/")
+	}
+	globalSec := list(doc["security"])
+	paths := object(doc, "paths")
+	for _, path := range sortedKeys(paths) {
+		item := toObject(paths[path])
+		full := prefix + path
+		if full == "" {
+			full = "/"
+		}
+		for _, method := range methods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			e := &endpoint{
+				name:      strings.ToUpper(method) + " " + full,
+				params:    make(map[string]map[string]interface{}),
+				required:  make(map[string]bool),
+				responses: make(map[string]map[string]interface{}),
+			}
+			sec := globalSec
+			if v, ok := op["security"]; ok {
+				sec = list(v)
+			}
+			for _, req := range sec {
+				if len(toObject(req)) > 0 {
+					e.secured = true
+					break
+				}
+			}
+			// Operation parameters override the path item parameters.
+			for _, raw := range append(list(op["parameters"]), list(item["parameters"])...) {
+				p := s.resolve(toObject(raw))
+				in, name := str(p, "in"), str(p, "name")
+				if in == "body" {
+					if e.body == nil {
+						e.body, e.bodyRequired = toObject(p["schema"]), p["required"] == true
+					}
+					continue
+				}
+				key := fmt.Sprintf("%s parameter %q", in, name)
+				if _, ok := e.params[key]; ok {
+					continue
+				}
+				e.params[key] = paramSchema(p)
+				e.required[key] = p["required"] == true
+			}
+			if rb := s.resolve(toObject(op["requestBody"])); rb != nil {
+				e.body, e.bodyRequired = content(rb), rb["required"] == true
+			}
+			responses := object(op, "responses")
+			for code, raw := range responses {
+				r := s.resolve(toObject(raw))
+				schema := toObject(r["schema"])
+				if _, ok := r["content"]; ok {
+					schema = content(r)
+				}
+				e.responses[code] = schema
+			}
+			s.endpoints[strings.ToUpper(method)+" "+templateRegex.ReplaceAllString(full, "{}")] = e
+		}
+	}
+	return s
+}
+
+// compareEndpoint records the breaking changes between two versions of an endpoint.
+func (c *comparison) compareEndpoint(b, cur *endpoint) {
+	if !b.secured && cur.secured {
+		c.add("", "authentication is now required")
+	}
+	for _, key := range sortedKeys(cur.params) {
+		bp, ok := b.params[key]
+		if strings.HasPrefix(key, "path ") {
+			// Path parameters are matched by the endpoint key, they may be renamed.
+			if ok {
+				c.compareSchema(key, bp, cur.params[key], true)
+			}
+			continue
+		}
+		if cur.required[key] {
+			if !ok {
+				c.add(key, "new required parameter")
+				continue
+			}
+			if !b.required[key] {
+				c.add(key, "parameter is now required")
+			}
+		}
+		if ok {
+			c.compareSchema(key, bp, cur.params[key], true)
+		}
+	}
+	if cur.body != nil {
+		if cur.bodyRequired && (b.body == nil || !b.bodyRequired) {
+			c.add("request body", "request body is now required")
+		}
+		if b.body != nil {
+			c.compareSchema("request body", b.body, cur.body, true)
+		}
+	}
+	for _, code := range sortedKeys(b.responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		r, ok := cur.responses[code]
+		if !ok {
+			c.add("response "+code, "response removed")
+			continue
+		}
+		if bs := b.responses[code]; bs != nil {
+			if r == nil {
+				c.add("response "+code, "response body removed")
+				continue
+			}
+			c.compareSchema("response "+code+" body", bs, r, false)
+		}
+	}
+}
+
+// compareSchema records the breaking changes between two versions of the schema located at loc.
+// input is true if the schema describes values sent by the clients (parameters and request
+// bodies) and false if it describes values received by the clients (response bodies). The
+// validations of the input values must not be narrowed while the output values must not be
+// widened.
+func (c *comparison) compareSchema(loc string, b, cur map[string]interface{}, input bool) {
+	bref, cref := str(b, "$ref"), str(cur, "$ref")
+	if bref != "" && cref != "" {
+		key := fmt.Sprintf("%t %s %s", input, bref, cref)
+		if c.seen[key] {
+			return
+		}
+		c.seen[key] = true
+		defer delete(c.seen, key)
+	}
+	b, cur = c.base.flatten(b), c.current.flatten(cur)
+	if b == nil || cur == nil {
+		return
+	}
+
+	bt, bnull := schemaType(b)
+	ct, cnull := schemaType(cur)
+	if bt != "" && ct != "" && bt != ct {
+		widening := input && bt == "integer" && ct == "number" || !input && bt == "number" && ct == "integer"
+		if !widening {
+			c.add(loc, fmt.Sprintf("type changed from %s to %s", bt, ct))
+			return
+		}
+	}
+	if bf, cf := str(b, "format"), str(cur, "format"); bf != cf && (input && cf != "" || !input && bf != "") {
+		c.add(loc, fmt.Sprintf("format changed from %q to %q", bf, cf))
+	}
+
+	if input {
+		if bnull && !cnull {
+			c.add(loc, "null is no longer accepted")
+		}
+		if _, ok := cur["enum"]; ok {
+			if _, ok := b["enum"]; !ok {
+				c.add(loc, fmt.Sprintf("values are now restricted to %s", values(list(cur["enum"]))))
+			} else if removed := missing(list(b["enum"]), list(cur["enum"])); len(removed) > 0 {
+				c.add(loc, fmt.Sprintf("values %s are no longer accepted", values(removed)))
+			}
+		}
+		for _, k := range []string{"maximum", "maxLength", "maxItems", "maxProperties"} {
+			if cv, ok := number(cur[k]); ok {
+				if bv, ok := number(b[k]); !ok || cv < bv {
+					c.add(loc, fmt.Sprintf("%s lowered to %v", k, cur[k]))
+				}
+			}
+		}
+		for _, k := range []string{"minimum", "minLength", "minItems", "minProperties"} {
+			if cv, ok := number(cur[k]); ok {
+				if bv, ok := number(b[k]); !ok || cv > bv {
+					c.add(loc, fmt.Sprintf("%s raised to %v", k, cur[k]))
+				}
+			}
+		}
+		if p := str(cur, "pattern"); p != "" && p != str(b, "pattern") {
+			c.add(loc, fmt.Sprintf("pattern changed to %q", p))
+		}
+		breq := make(map[string]bool)
+		for _, n := range strs(b["required"]) {
+			breq[n] = true
+		}
+		for _, n := range strs(cur["required"]) {
+			if !breq[n] {
+				c.add(child(loc, n), "new required attribute")
+			}
+		}
+	} else {
+		if !bnull && cnull {
+			c.add(loc, "value may now be null")
+		}
+		if _, ok := b["enum"]; ok {
+			if added := missing(list(cur["enum"]), list(b["enum"])); len(added) > 0 {
+				c.add(loc, fmt.Sprintf("new values %s may be returned", values(added)))
+			}
+			if _, ok := cur["enum"]; !ok {
+				c.add(loc, "values are no longer restricted to an enum")
+			}
+		}
+		creq := make(map[string]bool)
+		for _, n := range strs(cur["required"]) {
+			creq[n] = true
+		}
+		cprops := object(cur, "properties")
+		for _, n := range strs(b["required"]) {
+			if _, ok := cprops[n]; ok && !creq[n] {
+				c.add(child(loc, n), "attribute is no longer required")
+			}
+		}
+		for _, n := range sortedKeys(object(b, "properties")) {
+			if _, ok := cprops[n]; !ok {
+				c.add(child(loc, n), "attribute removed")
+			}
+		}
+	}
+
+	bprops, cprops := object(b, "properties"), object(cur, "properties")
+	for _, n := range sortedKeys(bprops) {
+		if cp, ok := cprops[n]; ok {
+			c.compareSchema(child(loc, n), toObject(bprops[n]), toObject(cp), input)
+		}
+	}
+	if bi, ci := toObject(b["items"]), toObject(cur["items"]); bi != nil && ci != nil {
+		c.compareSchema(loc+"[]", bi, ci, input)
+	}
+	if ba, ca := toObject(b["additionalProperties"]), toObject(cur["additionalProperties"]); ba != nil && ca != nil {
+		c.compareSchema(loc+"{}", ba, ca, input)
+	}
+}
+
+// add records a breaking change of the current endpoint.
+func (c *comparison) add(loc, msg string) {
+	c.changes = append(c.changes, &Change{Endpoint: c.endpoint, Location: loc, Message: msg})
+}
+
+// resolve returns the object referred to by the local JSON reference of the given object if any,
+// the object itself otherwise.
+func (s *spec) resolve(o map[string]interface{}) map[string]interface{} {
+	for i := 0; i < 32; i++ {
+		ref := str(o, "$ref")
+		if !strings.HasPrefix(ref, "#/") {
+			return o
+		}
+		var cur interface{} = s.doc
+		for _, tok := range strings.Split(ref[2:], "/") {
+			tok = strings.Replace(strings.Replace(tok, "~1", "/", -1), "~0", "~", -1)
+			cur = toObject(cur)[tok]
+		}
+		o = toObject(cur)
+	}
+	return o
+}
+
+// flatten resolves the schema references and merges the properties and required attributes of
+// the allOf members into the schema.
+func (s *spec) flatten(schema map[string]interface{}) map[string]interface{} {
+	schema = s.resolve(schema)
+	allOf := list(schema["allOf"])
+	if len(allOf) == 0 {
+		return schema
+	}
+	res := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		if k != "allOf" {
+			res[k] = v
+		}
+	}
+	props := make(map[string]interface{})
+	for k, v := range object(schema, "properties") {
+		props[k] = v
+	}
+	required := list(schema["required"])
+	for _, m := range allOf {
+		ms := s.flatten(toObject(m))
+		for k, v := range object(ms, "properties") {
+			props[k] = v
+		}
+		required = append(required, list(ms["required"])...)
+		if _, ok := res["type"]; !ok && ms["type"] != nil {
+			res["type"] = ms["type"]
+		}
+	}
+	res["properties"] = props
+	res["required"] = required
+	return res
+}
+
+// schemaType returns the type of the schema and whether it accepts null. It supports the OpenAPI
+// 3.1 type lists, the OpenAPI 3.0 nullable keyword and the x-nullable Swagger extension.
+func schemaType(s map[string]interface{}) (string, bool) {
+	nullable := s["nullable"] == true || s["x-nullable"] == true
+	if t, ok := s["type"].(string); ok {
+		return t, nullable || t == "null"
+	}
+	var typ string
+	for _, t := range strs(s["type"]) {
+		if t == "null" {
+			nullable = true
+		} else if typ == "" {
+			typ = t
+		}
+	}
+	return typ, nullable
+}
+
+// paramSchema returns the schema of a parameter. Swagger 2.0 parameters inline the schema
+// properties while OpenAPI 3 parameters use a schema field.
+func paramSchema(p map[string]interface{}) map[string]interface{} {
+	if s := toObject(p["schema"]); s != nil {
+		return s
+	}
+	return p
+}
+
+// content returns the schema of the JSON content of an OpenAPI 3 request body or response, nil
+// if there is no content.
+func content(o map[string]interface{}) map[string]interface{} {
+	c := object(o, "content")
+	if len(c) == 0 {
+		return nil
+	}
+	keys := sortedKeys(c)
+	pick := keys[0]
+	for _, k := range keys {
+		if k == "application/json" {
+			pick = k
+			break
+		}
+		if strings.Contains(k, "json") && !strings.Contains(pick, "json") {
+			pick = k
+		}
+	}
+	s := toObject(object(c, pick)["schema"])
+	if s == nil {
+		s = map[string]interface{}{}
+	}
+	return s
+}
+
+// child returns the location of the attribute n of the object located at loc.
+func child(loc, n string) string {
+	return loc + "." + n
+}
+
+// missing returns the values of a that are not in b.
+func missing(a, b []interface{}) []interface{} {
+	var res []interface{}
+	for _, v := range a {
+		found := false
+		for _, o := range b {
+			if fmt.Sprint(v) == fmt.Sprint(o) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// values renders the given enum values.
+func values(vals []interface{}) string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		if s, ok := v.(string); ok {
+			res[i] = fmt.Sprintf("%q", s)
+		} else {
+			res[i] = fmt.Sprint(v)
+		}
+	}
+	return strings.Join(res, ", ")
+}
+
+// number returns the float value of a JSON or YAML number.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// normalize converts the maps produced by the YAML decoder into maps keyed by strings.
+func normalize(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			res[fmt.Sprintf("%v", k)] = normalize(val)
+		}
+		return res
+	case []interface{}:
+		for i, val := range actual {
+			actual[i] = normalize(val)
+		}
+	}
+	return v
+}
+
+func toObject(v interface{}) map[string]interface{} {
+	o, _ := v.(map[string]interface{})
+	return o
+}
+
+func object(o map[string]interface{}, key string) map[string]interface{} {
+	return toObject(o[key])
+}
+
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func str(o map[string]interface{}, key string) string {
+	s, _ := o[key].(string)
+	return s
+}
+
+func strs(v interface{}) []string {
+	var res []string
+	for _, e := range list(v) {
+		if s, ok := e.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch actual := m.(type) {
+	case map[string]interface{}:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]*endpoint:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]interface{}:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,192 @@
+package gendiff_test
+
+import (
+	"github.com/goadesign/goa/goagen/gen_diff"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compare", func() {
+	const baseSpec = `
+swagger: "2.0"
+basePath: /api
+paths:
+  /bottles:
+    get:
+      parameters:
+      - {in: query, name: limit, type: integer, maximum: 100}
+      responses:
+        "200":
+          description: OK
+          schema:
+            type: array
+            items: {$ref: "#/definitions/Bottle"}
+    post:
+      parameters:
+      - in: body
+        name: payload
+        required: true
+        schema:
+          type: object
+          properties:
+            name: {type: string}
+            color: {type: string, enum: [red, white]}
+          required: [name]
+      responses:
+        "201": {description: Created}
+  /bottles/{id}:
+    get:
+      parameters:
+      - {in: path, name: id, type: integer, required: true}
+      responses:
+        "200":
+          description: OK
+          schema: {$ref: "#/definitions/Bottle"}
+    delete:
+      parameters:
+      - {in: path, name: id, type: integer, required: true}
+      responses:
+        "204": {description: No Content}
+definitions:
+  Bottle:
+    type: object
+    properties:
+      id: {type: integer}
+      name: {type: string}
+      vintage: {type: integer}
+    required: [id, name]
+`
+
+	var base, current map[string]interface{}
+	var changes []string
+
+	BeforeEach(func() {
+		var err error
+		base, err = gendiff.Load([]byte(baseSpec))
+		Ω(err).ShouldNot(HaveOccurred())
+		current, err = gendiff.Load([]byte(baseSpec))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		changes = nil
+		for _, c := range gendiff.Compare(base, current) {
+			changes = append(changes, c.String())
+		}
+	})
+
+	path := func(p string) map[string]interface{} {
+		return current["paths"].(map[string]interface{})[p].(map[string]interface{})
+	}
+
+	op := func(p, method string) map[string]interface{} {
+		return path(p)[method].(map[string]interface{})
+	}
+
+	Context("with identical specifications", func() {
+		It("reports no change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("with a removed endpoint", func() {
+		BeforeEach(func() {
+			delete(path("/bottles/{id}"), "delete")
+		})
+
+		It("reports the endpoint", func() {
+			Ω(changes).Should(ConsistOf("DELETE /api/bottles/{id}: endpoint removed"))
+		})
+	})
+
+	Context("with a renamed path parameter", func() {
+		BeforeEach(func() {
+			paths := current["paths"].(map[string]interface{})
+			paths["/bottles/{bottleID}"] = paths["/bottles/{id}"]
+			delete(paths, "/bottles/{id}")
+		})
+
+		It("reports no change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("with a new required query parameter and a lower maximum", func() {
+		BeforeEach(func() {
+			get := op("/bottles", "get")
+			get["parameters"] = []interface{}{
+				map[string]interface{}{"in": "query", "name": "limit", "type": "integer", "maximum": 50},
+				map[string]interface{}{"in": "query", "name": "account", "type": "string", "required": true},
+			}
+		})
+
+		It("reports the parameter changes", func() {
+			Ω(changes).Should(ConsistOf(
+				`GET /api/bottles: query parameter "account": new required parameter`,
+				`GET /api/bottles: query parameter "limit": maximum lowered to 50`,
+			))
+		})
+	})
+
+	Context("with a narrowed request body", func() {
+		BeforeEach(func() {
+			body := op("/bottles", "post")["parameters"].([]interface{})[0].(map[string]interface{})
+			schema := body["schema"].(map[string]interface{})
+			props := schema["properties"].(map[string]interface{})
+			props["color"] = map[string]interface{}{"type": "string", "enum": []interface{}{"red"}}
+			props["name"] = map[string]interface{}{"type": "integer"}
+			schema["required"] = []interface{}{"name", "color"}
+		})
+
+		It("reports the request body changes", func() {
+			Ω(changes).Should(ConsistOf(
+				`POST /api/bottles: request body.color: new required attribute`,
+				`POST /api/bottles: request body.color: values "white" are no longer accepted`,
+				`POST /api/bottles: request body.name: type changed from string to integer`,
+			))
+		})
+	})
+
+	Context("with a changed response type", func() {
+		BeforeEach(func() {
+			bottle := current["definitions"].(map[string]interface{})["Bottle"].(map[string]interface{})
+			bottle["properties"] = map[string]interface{}{
+				"id":   map[string]interface{}{"type": "integer"},
+				"name": map[string]interface{}{"type": "string"},
+			}
+			bottle["required"] = []interface{}{"id"}
+		})
+
+		It("reports the changes of each response", func() {
+			Ω(changes).Should(ConsistOf(
+				`GET /api/bottles: response 200 body[].name: attribute is no longer required`,
+				`GET /api/bottles: response 200 body[].vintage: attribute removed`,
+				`GET /api/bottles/{id}: response 200 body.name: attribute is no longer required`,
+				`GET /api/bottles/{id}: response 200 body.vintage: attribute removed`,
+			))
+		})
+	})
+
+	Context("with a new security requirement", func() {
+		BeforeEach(func() {
+			op("/bottles", "post")["security"] = []interface{}{map[string]interface{}{"basic": []interface{}{}}}
+		})
+
+		It("reports the requirement", func() {
+			Ω(changes).Should(ConsistOf("POST /api/bottles: authentication is now required"))
+		})
+	})
+})
+
+var _ = Describe("Load", func() {
+	It("rejects documents that are not specifications", func() {
+		_, err := gendiff.Load([]byte(`{"foo": "bar"}`))
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("loads OpenAPI 3 JSON documents", func() {
+		spec, err := gendiff.Load([]byte(`{"openapi": "3.1.0", "paths": {}}`))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(spec).Should(HaveKeyWithValue("openapi", "3.1.0"))
+	})
+})
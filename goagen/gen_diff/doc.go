@@ -0,0 +1,25 @@
+/*
+Package gendiff detects the changes made to a goa API design that break the existing clients.
+
+The generator compares the design with a previously generated specification, by default the
+swagger/swagger.json file produced by "goagen swagger" in the output directory. The --base flag
+sets the path to another Swagger 2.0 or OpenAPI 3.x specification written in JSON or YAML, for
+example the specification generated from the design of the last release:
+
+	git show v1.2.0:swagger/swagger.json > /tmp/v1.2.0.json
+	goagen diff -d github.com/goadesign/goa-cellar/design --base /tmp/v1.2.0.json
+
+The endpoints are matched by HTTP method and path. The generator reports:
+
+  - the removed endpoints and success responses,
+  - the new required parameters, request bodies and request body attributes,
+  - the new security requirements,
+  - the changed types and formats,
+  - the narrowed validations of the parameters and request bodies (enum values removed, lower
+    maximums, higher minimums, new patterns),
+  - the removed or no longer required response attributes and the new response enum values.
+
+The command fails and lists the breaking changes if there are any so that it can be used to guard
+continuous integration pipelines.
+*/
+package gendiff
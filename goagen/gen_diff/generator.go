@@ -0,0 +1,125 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a breaking change detector
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the breaking change detector. It compares the API design with a previously
+// generated specification and fails if the design breaks the clients of that specification.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Base     string                // Path to the specification the design is compared with
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, base string
+	set := flag.NewFlagSet("diff", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&base, "base", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Base: base, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate compares the design with the base specification. It returns an error listing the
+// breaking changes if there are any. The base specification defaults to the Swagger
+// specification generated in the output directory by "goagen swagger".
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	base := g.Base
+	if base == "" {
+		base = filepath.Join(g.OutDir, codegen.OutputDir(g.API, "swagger", "swagger"), "swagger.json")
+	}
+	raw, err := ioutil.ReadFile(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base specification: %s", err)
+	}
+	baseSpec, err := Load(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", base, err)
+	}
+
+	// Compare documents of the same kind so that the changes between Swagger 2.0 and OpenAPI 3
+	// are not reported.
+	var current map[string]interface{}
+	if _, ok := baseSpec["openapi"]; ok {
+		if current, err = genopenapi.New(g.API); err != nil {
+			return nil, err
+		}
+	} else {
+		s, err := genswagger.New(g.API)
+		if err != nil {
+			return nil, err
+		}
+		js, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(js, &current); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := Compare(baseSpec, current)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = "  - " + c.String()
+	}
+	return nil, fmt.Errorf("%d breaking change(s) since %s:\n%s", len(changes), base, strings.Join(lines, "\n"))
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
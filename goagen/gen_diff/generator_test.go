@@ -0,0 +1,148 @@
+package gendiff_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	const baseSpec = `{
+  "swagger": "2.0",
+  "paths": {
+    "/bottles/{id}": {
+      "get": {
+        "parameters": [{"in": "path", "name": "id", "type": "integer", "required": true}],
+        "responses": {"200": {"description": "OK"}}
+      },
+      "delete": {
+        "parameters": [{"in": "path", "name": "id", "type": "integer", "required": true}],
+        "responses": {"204": {"description": "No Content"}}
+      }
+    }
+  }
+}`
+
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("difftest")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(os.MkdirAll(filepath.Join(testPkg.Abs(), "swagger"), 0755)).Should(Succeed())
+		base := filepath.Join(testPkg.Abs(), "swagger", "swagger.json")
+		Ω(ioutil.WriteFile(base, []byte(baseSpec), 0644)).Should(Succeed())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		_, genErr = gendiff.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a compatible design", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/bottles/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.OK)
+				})
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/bottles/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET("/bottles"))
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("succeeds", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a removed action", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", nil)
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/bottles/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.OK)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("reports the breaking change", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring("1 breaking change(s)"))
+			Ω(genErr.Error()).Should(ContainSubstring("DELETE /bottles/{id}: endpoint removed"))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *gendiff.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+		base   string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+		base:   "v1.json",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = gendiff.NewGenerator(
+				gendiff.API(args.api),
+				gendiff.OutDir(args.outDir),
+				gendiff.Base(args.base),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Base).Should(Equal(args.base))
+		})
+	})
+})
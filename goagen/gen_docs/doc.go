@@ -0,0 +1,18 @@
+/*
+Package gendocs generates a static HTML documentation site for a goa API.
+
+The generator writes the site to the "docs" directory: an overview page (index.html) that
+describes the API and lists its resources, one page per resource named after the resource (for
+example bottle.html) and a page listing the user types and media types (types.html). All the pages
+share a navigation bar that links to the other pages.
+
+The resource pages document each action with its routes, parameters, headers, request body and
+responses. The attributes of the request bodies and of the types are rendered as tables and the
+types link to their definition. Each action also lists the curl commands produced by the snippets
+generator that can be copied to try the API.
+
+The pages are self-contained: the style sheet is inlined and they load no script or external
+resource so that the site can be published as is, for example by serving the "docs" directory
+with GitHub Pages as part of the release process.
+*/
+package gendocs
@@ -0,0 +1,375 @@
+package gendocs
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_snippets"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Docs Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the HTML documentation site generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Scheme   string                // Scheme used in the example invocation URLs
+	Host     string                // Host used in the example invocation URLs
+	genfiles []string              // Generated files
+}
+
+type (
+	// Link is a navigation link to a page of the site.
+	Link struct {
+		Title string // Link text
+		File  string // Name of the linked page file
+	}
+
+	// Resource describes the documentation of a resource.
+	Resource struct {
+		Name        string    // Resource name
+		Description string    // Resource description
+		File        string    // Name of the resource page file
+		Actions     []*Action // Resource actions
+	}
+
+	// Action describes the documentation of an action.
+	Action struct {
+		Name        string        // Action name
+		Description string        // Action description
+		Routes      []string      // Action routes, e.g. "GET /bottles/:id"
+		Params      []*Attribute  // Path and query string parameters
+		Headers     []*Attribute  // Request headers
+		Payload     template.HTML // Request body type if any
+		PayloadAttr []*Attribute  // Request body attributes if the type is not a documented type
+		Responses   []*Response   // Action responses sorted by status
+		Commands    []string      // Example invocations, one per route
+		Env         []string      // Environment variables read by the example invocations
+	}
+
+	// Response describes an action response.
+	Response struct {
+		Status      int           // HTTP status code
+		Name        string        // Response name
+		Description string        // Response description
+		Body        template.HTML // Response body type if any
+	}
+
+	// Type describes the documentation of a user type or media type.
+	Type struct {
+		Name        string       // Type name
+		Anchor      string       // Type anchor in the types page
+		Identifier  string       // Media type identifier, empty for user types
+		Description string       // Type description
+		Views       []string     // Media type view names
+		Attributes  []*Attribute // Type attributes
+	}
+
+	// Attribute describes an attribute of an object.
+	Attribute struct {
+		Name        string        // Attribute name, nested attributes use dotted names
+		Type        template.HTML // Attribute type
+		Required    bool          // Whether the attribute is required
+		Description string        // Attribute description
+		Default     string        // Attribute default value if any
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, scheme, host string
+	set := flag.NewFlagSet("docs", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the documentation site files.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	outDir := filepath.Join(g.OutDir, "docs")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	snippets := gensnippets.NewGenerator(
+		gensnippets.API(g.API),
+		gensnippets.Scheme(g.Scheme),
+		gensnippets.Host(g.Host),
+	)
+	var resources []*Resource
+	nav := []*Link{{Title: "Overview", File: "index.html"}}
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		res := g.resource(r, snippets.Snippets(r))
+		resources = append(resources, res)
+		nav = append(nav, &Link{Title: res.Name, File: res.File})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	types := g.types()
+	if len(types) > 0 {
+		nav = append(nav, &Link{Title: "Types", File: "types.html"})
+	}
+
+	t := template.Must(template.New("docs").Funcs(template.FuncMap{"join": strings.Join}).Parse(layoutT))
+	template.Must(t.New("index").Parse(indexT))
+	template.Must(t.New("resource").Parse(resourceT))
+	template.Must(t.New("types").Parse(typesT))
+
+	page := func(name, file, title string, data map[string]interface{}) error {
+		data["API"] = g.API
+		data["Nav"] = nav
+		data["File"] = file
+		data["Title"] = title
+		return g.write(filepath.Join(outDir, file), t.Lookup(name), data)
+	}
+	title := g.API.Title
+	if title == "" {
+		title = g.API.Name
+	}
+	if err = page("index", "index.html", title, map[string]interface{}{"Resources": resources}); err != nil {
+		return nil, err
+	}
+	for _, res := range resources {
+		if err = page("resource", res.File, res.Name+" - "+title, map[string]interface{}{"Resource": res}); err != nil {
+			return nil, err
+		}
+	}
+	if len(types) > 0 {
+		if err = page("types", "types.html", "Types - "+title, map[string]interface{}{"Types": types}); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// write renders the given template to the file at path.
+func (g *Generator) write(path string, t *template.Template, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.genfiles = append(g.genfiles, path)
+	return t.Execute(f, data)
+}
+
+// resource computes the documentation of the given resource. snippets contains the example
+// invocations of the resource actions.
+func (g *Generator) resource(r *design.ResourceDefinition, snippets *gensnippets.Resource) *Resource {
+	commands := make(map[string]*gensnippets.Action, len(snippets.Actions))
+	for _, a := range snippets.Actions {
+		commands[a.Name] = a
+	}
+	res := &Resource{Name: r.Name, Description: r.Description, File: codegen.SnakeCase(r.Name) + ".html"}
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		act := &Action{Name: a.Name, Description: a.Description}
+		for _, route := range a.Routes {
+			act.Routes = append(act.Routes, route.Verb+" "+route.FullPath())
+		}
+		if p := a.AllParams(); p != nil {
+			act.Params = g.attributes(p, "")
+		}
+		for _, h := range []*design.AttributeDefinition{r.Headers, a.Headers} {
+			if h != nil {
+				act.Headers = append(act.Headers, g.attributes(h, "")...)
+			}
+		}
+		if a.Payload != nil {
+			act.Payload = g.typeRef(a.Payload)
+			if !g.documented(a.Payload) && a.Payload.IsObject() {
+				act.PayloadAttr = g.attributes(a.Payload.AttributeDefinition, "")
+			}
+		}
+		a.IterateResponses(func(resp *design.ResponseDefinition) error {
+			act.Responses = append(act.Responses, g.response(resp))
+			return nil
+		})
+		sort.SliceStable(act.Responses, func(i, j int) bool {
+			return act.Responses[i].Status < act.Responses[j].Status
+		})
+		if s, ok := commands[a.Name]; ok {
+			act.Commands, act.Env = s.Commands, s.Env
+		}
+		res.Actions = append(res.Actions, act)
+		return nil
+	})
+	return res
+}
+
+// response computes the documentation of the given response.
+func (g *Generator) response(r *design.ResponseDefinition) *Response {
+	resp := &Response{Status: r.Status, Name: r.Name, Description: r.Description}
+	if mt := g.API.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+		resp.Body = g.typeRef(mt)
+	} else if r.Type != nil {
+		resp.Body = g.typeRef(r.Type)
+	} else if r.MediaType != "" {
+		resp.Body = template.HTML(template.HTMLEscapeString(r.MediaType))
+	}
+	return resp
+}
+
+// types computes the documentation of the API user types and media types sorted by name.
+func (g *Generator) types() []*Type {
+	var types []*Type
+	g.API.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		types = append(types, &Type{
+			Name:        ut.TypeName,
+			Anchor:      anchor(ut.TypeName),
+			Description: ut.Description,
+			Attributes:  g.attributes(ut.AttributeDefinition, ""),
+		})
+		return nil
+	})
+	g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		t := &Type{
+			Name:        mt.TypeName,
+			Anchor:      anchor(mt.TypeName),
+			Identifier:  mt.Identifier,
+			Description: mt.Description,
+			Attributes:  g.attributes(mt.AttributeDefinition, ""),
+		}
+		for n := range mt.Views {
+			t.Views = append(t.Views, n)
+		}
+		sort.Strings(t.Views)
+		types = append(types, t)
+		return nil
+	})
+	sort.SliceStable(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// attributes returns the attributes of the given object attribute in alphabetical order. The
+// attributes of anonymous nested objects follow their parent and use dotted names prefixed with
+// prefix.
+func (g *Generator) attributes(att *design.AttributeDefinition, prefix string) []*Attribute {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return nil
+	}
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var attrs []*Attribute
+	for _, n := range names {
+		child := obj[n]
+		a := &Attribute{
+			Name:        prefix + n,
+			Type:        g.typeRef(child.Type),
+			Required:    att.IsRequired(n),
+			Description: child.Description,
+		}
+		if child.DefaultValue != nil {
+			a.Default = fmt.Sprintf("%v", child.DefaultValue)
+		}
+		attrs = append(attrs, a)
+		if _, ok := child.Type.(design.Object); ok {
+			attrs = append(attrs, g.attributes(child, prefix+n+".")...)
+		}
+	}
+	return attrs
+}
+
+// typeRef returns the HTML describing the given type. The documented types link to their
+// definition in the types page.
+func (g *Generator) typeRef(t design.DataType) template.HTML {
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		if g.documented(actual.UserTypeDefinition) {
+			return link(actual.TypeName)
+		}
+		return g.typeRef(actual.Type)
+	case *design.UserTypeDefinition:
+		if g.documented(actual) {
+			return link(actual.TypeName)
+		}
+		return g.typeRef(actual.Type)
+	case *design.Array:
+		return "array of " + g.typeRef(actual.ElemType.Type)
+	case *design.Hash:
+		return "hash of " + g.typeRef(actual.KeyType.Type) + " to " + g.typeRef(actual.ElemType.Type)
+	}
+	return template.HTML(template.HTMLEscapeString(t.Name()))
+}
+
+// documented returns true if the given type is listed in the types page.
+func (g *Generator) documented(ut *design.UserTypeDefinition) bool {
+	if g.API.Types[ut.TypeName] == ut {
+		return true
+	}
+	for _, mt := range g.API.MediaTypes {
+		if mt.UserTypeDefinition == ut {
+			return true
+		}
+	}
+	return false
+}
+
+// link returns the HTML link to the definition of the type with the given name.
+func link(name string) template.HTML {
+	n := template.HTMLEscapeString(name)
+	return template.HTML(`<a href="types.html#` + anchor(name) + `">` + n + `</a>`)
+}
+
+// anchor returns the anchor of the definition of the type with the given name.
+func anchor(name string) string {
+	return "type-" + codegen.KebabCase(codegen.Goify(name, true))
+}
@@ -0,0 +1,152 @@
+package gendocs_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_docs"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("docstest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gendocs.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with resources and media types", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy <API>")
+				apidsl.Host("example.com")
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Description("A bottle of wine")
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer, "ID of bottle")
+					apidsl.Attribute("rating", func() {
+						apidsl.Attribute("score", design.Number)
+					})
+					apidsl.Required("id")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Description("Show a bottle")
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.OK, bottle)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String, func() {
+							apidsl.Default("merlot")
+						})
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the site pages", func() {
+			Ω(genErr).Should(BeNil())
+			dir := filepath.Join(testPkg.Abs(), "docs")
+			Ω(files).Should(Equal([]string{
+				dir,
+				filepath.Join(dir, "index.html"),
+				filepath.Join(dir, "bottle.html"),
+				filepath.Join(dir, "types.html"),
+			}))
+
+			content, err := ioutil.ReadFile(filepath.Join(dir, "index.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("<title>dummy &lt;API&gt;</title>"))
+			Ω(string(content)).Should(ContainSubstring(`<li><a href="bottle.html">bottle</a></li>`))
+
+			content, err = ioutil.ReadFile(filepath.Join(dir, "bottle.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`<p class="route">GET /bottles/:id</p>`))
+			Ω(string(content)).Should(ContainSubstring(`<tr><td>200</td><td>OK</td><td><a href="types.html#type-bottle">Bottle</a></td><td>OK</td></tr>`))
+			Ω(string(content)).Should(ContainSubstring(`<tr><td><code>name</code> <span class="required" title="required">*</span></td><td>string</td><td></td><td><code>merlot</code></td></tr>`))
+			Ω(string(content)).Should(ContainSubstring(`<pre><code>curl -X POST &#34;http://example.com/bottles&#34;`))
+
+			content, err = ioutil.ReadFile(filepath.Join(dir, "types.html"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`<section id="type-bottle">`))
+			Ω(string(content)).Should(ContainSubstring("<p>Media type <code>application/vnd.bottle</code>, views: default</p>"))
+			Ω(string(content)).Should(ContainSubstring(`<tr><td><code>rating.score</code></td><td>number</td><td></td><td></td></tr>`))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *gendocs.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+		scheme string
+		host   string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+		scheme: "https",
+		host:   "example.com",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = gendocs.NewGenerator(
+				gendocs.API(args.api),
+				gendocs.OutDir(args.outDir),
+				gendocs.Scheme(args.scheme),
+				gendocs.Host(args.host),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Scheme).Should(Equal(args.scheme))
+			Ω(generator.Host).Should(Equal(args.host))
+		})
+	})
+})
@@ -0,0 +1,123 @@
+package gendocs
+
+// layoutT defines the header and footer shared by all the pages. The pages are self-contained:
+// the style sheet is inlined and there are no scripts.
+const layoutT = `{{ define "header" }}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+body { margin: 0; font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292e; line-height: 1.5; }
+nav { position: fixed; top: 0; bottom: 0; left: 0; width: 14rem; padding: 1rem; overflow-y: auto; background: #f6f8fa; border-right: 1px solid #e1e4e8; }
+nav h1 { font-size: 1.1rem; margin-top: 0; }
+nav ul { list-style: none; padding: 0; }
+nav li { margin: .3rem 0; }
+nav a.current { font-weight: bold; }
+main { margin-left: 16rem; padding: 1rem 2rem; max-width: 60rem; }
+a { color: #0366d6; text-decoration: none; }
+a:hover { text-decoration: underline; }
+code, pre { font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: .9em; }
+pre { background: #f6f8fa; padding: .8rem; overflow-x: auto; border-radius: 4px; }
+table { border-collapse: collapse; margin: .5rem 0 1rem; }
+th, td { border: 1px solid #e1e4e8; padding: .3rem .6rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.route { font-family: SFMono-Regular, Consolas, Menlo, monospace; }
+.required { color: #cb2431; }
+section.action { border-top: 1px solid #e1e4e8; margin-top: 2rem; }
+</style>
+</head>
+<body>
+<nav>
+<h1><a href="index.html">{{ if .API.Title }}{{ .API.Title }}{{ else }}{{ .API.Name }}{{ end }}</a></h1>
+<ul>
+{{- $file := .File }}
+{{- range .Nav }}
+<li><a href="{{ .File }}"{{ if eq .File $file }} class="current"{{ end }}>{{ .Title }}</a></li>
+{{- end }}
+</ul>
+</nav>
+<main>
+{{ end }}{{ define "footer" }}</main>
+</body>
+</html>
+{{ end }}{{ define "attributes" }}<table>
+<tr><th>Name</th><th>Type</th><th>Description</th><th>Default</th></tr>
+{{- range . }}
+<tr><td><code>{{ .Name }}</code>{{ if .Required }} <span class="required" title="required">*</span>{{ end }}</td><td>{{ .Type }}</td><td>{{ .Description }}</td><td>{{ if .Default }}<code>{{ .Default }}</code>{{ end }}</td></tr>
+{{- end }}
+</table>
+{{ end }}`
+
+// indexT renders the overview page.
+const indexT = `{{ template "header" . }}<h1>{{ .Title }}</h1>
+{{ if .API.Description }}<p>{{ .API.Description }}</p>
+{{ end }}<table>
+{{- if .API.Version }}
+<tr><th>Version</th><td>{{ .API.Version }}</td></tr>
+{{- end }}
+{{- if .API.Host }}
+<tr><th>Host</th><td><code>{{ .API.Host }}</code></td></tr>
+{{- end }}
+{{- if .API.BasePath }}
+<tr><th>Base path</th><td><code>{{ .API.BasePath }}</code></td></tr>
+{{- end }}
+{{- if .API.Schemes }}
+<tr><th>Schemes</th><td>{{ join .API.Schemes ", " }}</td></tr>
+{{- end }}
+{{- with .API.Contact }}
+<tr><th>Contact</th><td>{{ .Name }}{{ if .Email }} &lt;<a href="mailto:{{ .Email }}">{{ .Email }}</a>&gt;{{ end }}{{ if .URL }} <a href="{{ .URL }}">{{ .URL }}</a>{{ end }}</td></tr>
+{{- end }}
+{{- with .API.License }}
+<tr><th>License</th><td>{{ if .URL }}<a href="{{ .URL }}">{{ .Name }}</a>{{ else }}{{ .Name }}{{ end }}</td></tr>
+{{- end }}
+{{- with .API.Docs }}
+<tr><th>Documentation</th><td><a href="{{ .URL }}">{{ if .Description }}{{ .Description }}{{ else }}{{ .URL }}{{ end }}</a></td></tr>
+{{- end }}
+</table>
+<h2>Resources</h2>
+<ul>
+{{- range .Resources }}
+<li><a href="{{ .File }}">{{ .Name }}</a>{{ if .Description }}: {{ .Description }}{{ end }}</li>
+{{- end }}
+</ul>
+{{ template "footer" . }}`
+
+// resourceT renders the page of a resource.
+const resourceT = `{{ template "header" . }}{{ with .Resource }}<h1>{{ .Name }}</h1>
+{{ if .Description }}<p>{{ .Description }}</p>
+{{ end }}
+{{- range .Actions }}
+<section class="action" id="{{ .Name }}">
+<h2><a href="#{{ .Name }}">{{ .Name }}</a></h2>
+{{ range .Routes }}<p class="route">{{ . }}</p>
+{{ end }}{{ if .Description }}<p>{{ .Description }}</p>
+{{ end }}{{ if .Params }}<h3>Parameters</h3>
+{{ template "attributes" .Params }}{{ end }}{{ if .Headers }}<h3>Headers</h3>
+{{ template "attributes" .Headers }}{{ end }}{{ if .Payload }}<h3>Request body</h3>
+<p>{{ .Payload }}</p>
+{{ if .PayloadAttr }}{{ template "attributes" .PayloadAttr }}{{ end }}{{ end }}{{ if .Responses }}<h3>Responses</h3>
+<table>
+<tr><th>Status</th><th>Name</th><th>Body</th><th>Description</th></tr>
+{{- range .Responses }}
+<tr><td>{{ .Status }}</td><td>{{ .Name }}</td><td>{{ .Body }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{ end }}{{ if .Commands }}<h3>Try it</h3>
+{{ if .Env }}<p>The command reads the credentials from the {{ join .Env ", " }} environment variable{{ if gt (len .Env) 1 }}s{{ end }}.</p>
+{{ end }}{{ range .Commands }}<pre><code>{{ . }}</code></pre>
+{{ end }}{{ end }}</section>
+{{- end }}
+{{ end }}{{ template "footer" . }}`
+
+// typesT renders the page listing the user types and media types.
+const typesT = `{{ template "header" . }}<h1>Types</h1>
+{{- range .Types }}
+<section id="{{ .Anchor }}">
+<h2><a href="#{{ .Anchor }}">{{ .Name }}</a></h2>
+{{ if .Identifier }}<p>Media type <code>{{ .Identifier }}</code>{{ if .Views }}, views: {{ join .Views ", " }}{{ end }}</p>
+{{ end }}{{ if .Description }}<p>{{ .Description }}</p>
+{{ end }}{{ if .Attributes }}{{ template "attributes" .Attributes }}{{ end }}</section>
+{{- end }}
+{{ template "footer" . }}`
@@ -0,0 +1,20 @@
+/*
+Package genexplain describes an action of a goa API design as the code generators see it. This
+helps understanding the result of composing definitions with Reference, traits, resource and API
+level headers, parameters and responses.
+
+The generator does not write any file, it prints the description of the action given as argument:
+
+	goagen explain bottle.show -d github.com/goadesign/goa-cellar/design
+
+The description lists:
+
+  - the routes of the action, including the resource and API base paths, and its schemes,
+  - the name of the generated context,
+  - the path and query parameters, the headers and the payload attributes with their final
+    validations and default values and the names and types of the generated struct fields,
+  - the responses with their status, media type and the context methods that send them, one per
+    media type view,
+  - the security scheme and scopes.
+*/
+package genexplain
@@ -0,0 +1,263 @@
+package genexplain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// Explain returns the description of the action identified by name, the name of the resource and
+// the name of the action separated with a dot, e.g. "bottle.show". The description lists the
+// routes, the parameters, the headers, the payload attributes and the responses of the action as
+// they are once the DSL has run, that is with the attributes and validations inherited from the
+// referenced types and from the parent resource and API. It also shows the names of the types,
+// fields and methods generated for them.
+func Explain(api *design.APIDefinition, name string) (string, error) {
+	elems := strings.Split(name, ".")
+	if len(elems) != 2 || elems[0] == "" || elems[1] == "" {
+		return "", fmt.Errorf("invalid action %q, must be of the form RESOURCE.ACTION, e.g. bottle.show", name)
+	}
+	r, ok := api.Resources[elems[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown resource %q, available resources are: %s", elems[0], strings.Join(resourceNames(api), ", "))
+	}
+	a, ok := r.Actions[elems[1]]
+	if !ok {
+		return "", fmt.Errorf("unknown action %q of resource %q, available actions are: %s", elems[1], r.Name, strings.Join(actionNames(r), ", "))
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s.%s", r.Name, a.Name)
+	if a.Description != "" {
+		fmt.Fprintf(&b, ": %s", a.Description)
+	}
+	b.WriteString("\n")
+
+	b.WriteString("  Routes:\n")
+	for _, route := range a.Routes {
+		fmt.Fprintf(&b, "    %s %s\n", route.Verb, route.FullPath())
+	}
+	if schemes := a.EffectiveSchemes(); len(schemes) > 0 {
+		fmt.Fprintf(&b, "  Schemes: %s\n", strings.Join(schemes, ", "))
+	}
+	fmt.Fprintf(&b, "  Context: %s\n", codegen.Goify(a.Name, true)+codegen.Goify(r.Name, true)+"Context")
+
+	if params := a.AllParams(); params != nil && len(params.Type.ToObject()) > 0 {
+		path := make(map[string]bool)
+		if pp := a.PathParams(); pp != nil {
+			for n := range pp.Type.ToObject() {
+				path[n] = true
+			}
+		}
+		b.WriteString("  Params:\n")
+		writeObject(&b, params, 4, func(n string) string {
+			if path[n] {
+				return "path"
+			}
+			return "query"
+		})
+	}
+
+	headers := &design.AttributeDefinition{Type: design.Object{}}
+	if r.Headers != nil {
+		headers.Merge(r.Headers)
+		headers.Validation = r.Headers.Validation
+	}
+	if a.Headers != nil {
+		headers.Merge(a.Headers)
+		headers.Validation = a.Headers.Validation
+	}
+	if len(headers.Type.ToObject()) > 0 {
+		b.WriteString("  Headers:\n")
+		writeObject(&b, headers, 4, nil)
+	}
+
+	if a.Payload != nil {
+		fmt.Fprintf(&b, "  Payload: %s", codegen.GoTypeRef(a.Payload, nil, 0, false))
+		if a.PayloadOptional {
+			b.WriteString(" (optional)")
+		}
+		b.WriteString("\n")
+		if a.Payload.IsObject() {
+			writeObject(&b, a.Payload.AttributeDefinition, 4, nil)
+		}
+	}
+
+	if len(a.Responses) > 0 {
+		b.WriteString("  Responses:\n")
+		a.IterateResponses(func(resp *design.ResponseDefinition) error {
+			writeResponse(&b, api, resp)
+			return nil
+		})
+	}
+
+	if a.Security != nil && a.Security.Scheme != nil {
+		fmt.Fprintf(&b, "  Security: %s", a.Security.Scheme.SchemeName)
+		if len(a.Security.Scopes) > 0 {
+			fmt.Fprintf(&b, " (scopes: %s)", strings.Join(a.Security.Scopes, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String(), nil
+}
+
+// writeObject writes one line per attribute of the object att indented with the given number of
+// spaces. The attributes of the inline objects are written below their parent. location returns the
+// location of the attribute (e.g. "path" or "query"), it may be nil.
+func writeObject(b *bytes.Buffer, att *design.AttributeDefinition, indent int, location func(string) string) {
+	obj := att.Type.ToObject()
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		child := obj[n]
+		fmt.Fprintf(b, "%s%s %s", strings.Repeat(" ", indent), n, typeName(child.Type))
+		if location != nil {
+			fmt.Fprintf(b, " (%s)", location(n))
+		}
+		if details := validations(att, n, child); len(details) > 0 {
+			fmt.Fprintf(b, " %s", strings.Join(details, ", "))
+		}
+		field := codegen.GoifyAtt(child, n, true)
+		ref := codegen.GoTypeRef(child.Type, child.AllRequired(), 0, false)
+		if _, ok := child.Type.(design.Object); ok {
+			ref = "struct"
+		}
+		if att.IsPrimitivePointer(n) {
+			ref = "*" + ref
+		}
+		fmt.Fprintf(b, " -> %s %s\n", field, ref)
+		if _, ok := child.Type.(design.Object); ok {
+			writeObject(b, child, indent+2, nil)
+		}
+	}
+}
+
+// writeResponse writes the status, the media type and the context methods of the response.
+func writeResponse(b *bytes.Buffer, api *design.APIDefinition, resp *design.ResponseDefinition) {
+	fmt.Fprintf(b, "    %s %d", resp.Name, resp.Status)
+	if resp.MediaType != "" {
+		fmt.Fprintf(b, " %s", resp.MediaType)
+	}
+	mt, _ := resp.Type.(*design.MediaTypeDefinition)
+	if mt == nil && resp.Type == nil {
+		mt = api.MediaTypeWithIdentifier(resp.MediaType)
+	}
+	switch {
+	case mt != nil && !mt.IsError():
+		b.WriteString("\n")
+		views := []string{resp.ViewName}
+		if resp.ViewName == "" {
+			views = make([]string, 0, len(mt.Views))
+			for v := range mt.Views {
+				views = append(views, v)
+			}
+			sort.Strings(views)
+		}
+		for _, view := range views {
+			method := codegen.Goify(resp.Name, true)
+			if view != "default" {
+				method = codegen.Goify(resp.Name+strings.Title(view), true)
+			}
+			typ := "?"
+			if projected, _, err := mt.Project(view); err == nil {
+				typ = codegen.GoTypeRef(projected, projected.AllRequired(), 0, false)
+			}
+			fmt.Fprintf(b, "      view %s -> ctx.%s(r %s)\n", view, method, typ)
+		}
+	case resp.Type != nil:
+		fmt.Fprintf(b, " -> ctx.%s(r %s)\n", codegen.Goify(resp.Name, true), codegen.GoTypeRef(resp.Type, nil, 0, false))
+	case resp.MediaType != "":
+		fmt.Fprintf(b, " -> ctx.%s(resp []byte)\n", codegen.Goify(resp.Name, true))
+	default:
+		fmt.Fprintf(b, " -> ctx.%s()\n", codegen.Goify(resp.Name, true))
+	}
+}
+
+// validations returns the descriptions of the validations and of the default value of the
+// attribute att of the object parent.
+func validations(parent *design.AttributeDefinition, name string, att *design.AttributeDefinition) []string {
+	var details []string
+	if parent.IsRequired(name) {
+		details = append(details, "required")
+	}
+	if v := att.Validation; v != nil {
+		if len(v.Values) > 0 {
+			details = append(details, "enum="+toJSON(v.Values))
+		}
+		if v.Format != "" {
+			details = append(details, "format="+v.Format)
+		}
+		if v.Pattern != "" {
+			details = append(details, "pattern="+toJSON(v.Pattern))
+		}
+		if v.Minimum != nil {
+			details = append(details, fmt.Sprintf("minimum=%v", *v.Minimum))
+		}
+		if v.Maximum != nil {
+			details = append(details, fmt.Sprintf("maximum=%v", *v.Maximum))
+		}
+		if v.MinLength != nil {
+			details = append(details, fmt.Sprintf("minLength=%d", *v.MinLength))
+		}
+		if v.MaxLength != nil {
+			details = append(details, fmt.Sprintf("maxLength=%d", *v.MaxLength))
+		}
+	}
+	if att.DefaultValue != nil {
+		details = append(details, "default="+toJSON(att.DefaultValue))
+	}
+	return details
+}
+
+// typeName returns the name of the data type as it appears in the design, e.g. "array<string>".
+func typeName(t design.DataType) string {
+	switch actual := t.(type) {
+	case *design.Array:
+		return "array<" + typeName(actual.ElemType.Type) + ">"
+	case *design.Hash:
+		return "hash<" + typeName(actual.KeyType.Type) + ", " + typeName(actual.ElemType.Type) + ">"
+	case *design.UserTypeDefinition:
+		return actual.TypeName
+	case *design.MediaTypeDefinition:
+		return actual.TypeName
+	}
+	return t.Name()
+}
+
+// toJSON returns the JSON representation of v.
+func toJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// resourceNames returns the sorted names of the API resources.
+func resourceNames(api *design.APIDefinition) []string {
+	names := make([]string, 0, len(api.Resources))
+	for n := range api.Resources {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// actionNames returns the sorted names of the resource actions.
+func actionNames(r *design.ResourceDefinition) []string {
+	names := make([]string, 0, len(r.Actions))
+	for n := range r.Actions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,121 @@
+package genexplain_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_explain"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Explain", func() {
+	var action string
+	var desc string
+	var explainErr error
+
+	BeforeEach(func() {
+		action = "bottle.create"
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.BasePath("/api")
+		})
+		bottlePayload := apidsl.Type("BottlePayload", func() {
+			apidsl.Attribute("name", design.String, func() {
+				apidsl.MinLength(2)
+			})
+			apidsl.Attribute("vintage", design.Integer, func() {
+				apidsl.Minimum(1900)
+			})
+		})
+		bottle := apidsl.MediaType("application/vnd.bottle", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("name", design.String)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("name")
+			})
+			apidsl.View("tiny", func() {
+				apidsl.Attribute("name")
+			})
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Headers(func() {
+				apidsl.Header("X-Account", design.String, func() {
+					apidsl.Pattern("^[a-z]+$")
+				})
+			})
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST("/:cellarID"))
+				apidsl.Params(func() {
+					apidsl.Param("cellarID", design.Integer)
+					apidsl.Param("sort", design.String, func() {
+						apidsl.Enum("asc", "desc")
+						apidsl.Default("asc")
+					})
+				})
+				apidsl.Payload(func() {
+					apidsl.Reference(bottlePayload)
+					apidsl.Attribute("name")
+					apidsl.Attribute("vintage")
+					apidsl.Required("name")
+				})
+				apidsl.Response(design.Created, bottle)
+				apidsl.Response(design.NotFound)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		desc, explainErr = genexplain.Explain(design.Design, action)
+	})
+
+	It("describes the routes and the context", func() {
+		Ω(explainErr).ShouldNot(HaveOccurred())
+		Ω(desc).Should(HavePrefix("bottle.create\n  Routes:\n    POST /api/bottles/:cellarID\n"))
+		Ω(desc).Should(ContainSubstring("  Context: CreateBottleContext\n"))
+	})
+
+	It("describes the params and headers", func() {
+		Ω(desc).Should(ContainSubstring("    cellarID integer (path) -> CellarID int\n"))
+		Ω(desc).Should(ContainSubstring(`    sort string (query) enum=["asc","desc"], default="asc" -> Sort string` + "\n"))
+		Ω(desc).Should(ContainSubstring(`    X-Account string pattern="^[a-z]+$" -> XAccount *string` + "\n"))
+	})
+
+	It("describes the payload with the referenced validations", func() {
+		Ω(desc).Should(ContainSubstring("  Payload: *CreateBottlePayload\n"))
+		Ω(desc).Should(ContainSubstring("    name string required, minLength=2 -> Name string\n"))
+		Ω(desc).Should(ContainSubstring("    vintage integer minimum=1900 -> Vintage *int\n"))
+	})
+
+	It("describes the responses", func() {
+		Ω(desc).Should(ContainSubstring("    Created 201 application/vnd.bottle\n"))
+		Ω(desc).Should(ContainSubstring("      view default -> ctx.Created(r *Bottle)\n"))
+		Ω(desc).Should(ContainSubstring("      view tiny -> ctx.CreatedTiny(r *BottleTiny)\n"))
+		Ω(desc).Should(ContainSubstring("    NotFound 404 -> ctx.NotFound()\n"))
+	})
+
+	Context("with an unknown action", func() {
+		BeforeEach(func() {
+			action = "bottle.show"
+		})
+
+		It("lists the available actions", func() {
+			Ω(explainErr).Should(HaveOccurred())
+			Ω(explainErr.Error()).Should(Equal(`unknown action "show" of resource "bottle", available actions are: create`))
+		})
+	})
+
+	Context("with an invalid name", func() {
+		BeforeEach(func() {
+			action = "bottle"
+		})
+
+		It("fails", func() {
+			Ω(explainErr).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package genexplain_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenExplain(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenExplain Suite")
+}
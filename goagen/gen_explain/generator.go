@@ -0,0 +1,65 @@
+package genexplain
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+//NewGenerator returns an initialized instance of an action explainer
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the action explainer. It describes an action of the design as the code generators
+// see it.
+type Generator struct {
+	API    *design.APIDefinition // The API definition
+	Action string                // Name of the action to explain, e.g. "bottle.show"
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var ver, action string
+	set := flag.NewFlagSet("explain", flag.PanicOnError)
+	set.String("out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&action, "action", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{API: design.Design, Action: action}
+
+	return g.Generate()
+}
+
+// Generate does not write any file, it returns the lines of the description of the action so that
+// goagen prints them in place of the list of generated files.
+func (g *Generator) Generate() ([]string, error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	desc, err := Explain(g.API, g.Action)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(desc, "\n"), "\n"), nil
+}
+
+// Cleanup is a no-op, the explainer does not generate files.
+func (g *Generator) Cleanup() {}
@@ -0,0 +1,61 @@
+package genexplain_test
+
+import (
+	"os"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_explain"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var action string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		action = "bottle.delete"
+		dslengine.Reset()
+		apidsl.API("test api", func() {})
+		apidsl.Resource("bottle", func() {
+			apidsl.Action("delete", func() {
+				apidsl.Routing(apidsl.DELETE("/bottles"))
+				apidsl.Response(design.NoContent)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		os.Args = []string{"goagen", "--out=.", "--design=foo", "--version=" + version.String(), "--action=" + action}
+		files, genErr = genexplain.Generate()
+	})
+
+	It("returns the lines of the description", func() {
+		Ω(genErr).ShouldNot(HaveOccurred())
+		Ω(files).Should(Equal([]string{
+			"bottle.delete",
+			"  Routes:",
+			"    DELETE /bottles",
+			"  Context: DeleteBottleContext",
+			"  Responses:",
+			"    NoContent 204 -> ctx.NoContent()",
+		}))
+	})
+
+	Context("with an unknown resource", func() {
+		BeforeEach(func() {
+			action = "account.delete"
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring(`unknown resource "account", available resources are: bottle`))
+		})
+	})
+})
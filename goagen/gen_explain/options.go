@@ -0,0 +1,20 @@
+package genexplain
+
+import "github.com/goadesign/goa/design"
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//Action Name of the action to explain, e.g. "bottle.show"
+func Action(action string) Option {
+	return func(g *Generator) {
+		g.Action = action
+	}
+}
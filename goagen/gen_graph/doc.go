@@ -0,0 +1,16 @@
+/*
+Package gengraph generates a diagram of the API design so that large designs can be reviewed
+visually.
+
+The diagram groups the actions by resource and links each action to its payload type and to the
+media types of its responses, the links are labeled with the response names. The user types and
+media types are linked to the types they use, the links are labeled with the path of the attribute
+that uses the type, e.g. "origin" or "bottles[]".
+
+The diagram is written to graph/design.dot in the Graphviz format by default or to
+graph/design.mmd in the Mermaid format when the --format flag is "mermaid":
+
+	goagen graph -d github.com/goadesign/goa-cellar/design
+	dot -Tsvg graph/design.dot > design.svg
+*/
+package gengraph
@@ -0,0 +1,13 @@
+package gengraph_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenGraph(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenGraph Suite")
+}
@@ -0,0 +1,101 @@
+package gengraph
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Graph Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design graph generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Format   string                // Format of the diagram, "dot" (default) or "mermaid"
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, format string
+	set := flag.NewFlagSet("graph", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&format, "format", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Format: format, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the diagram file.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	var filename, content string
+	switch g.Format {
+	case "", "dot":
+		filename, content = "design.dot", NewGraph(g.API).Dot(g.API.Name)
+	case "mermaid":
+		filename, content = "design.mmd", NewGraph(g.API).Mermaid()
+	default:
+		return nil, fmt.Errorf(`invalid graph format %q, must be "dot" or "mermaid"`, g.Format)
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	outDir := filepath.Join(g.OutDir, "graph")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	path := filepath.Join(outDir, filename)
+	if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, path)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,127 @@
+package gengraph_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_graph"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+	var format string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("graphtest")
+		Ω(err).ShouldNot(HaveOccurred())
+		format = ""
+
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Title("dummy API")
+		})
+		origin := apidsl.Type("Origin", func() {
+			apidsl.Attribute("country", design.String)
+		})
+		account := apidsl.MediaType("application/vnd.account", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("name", design.String)
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("name")
+			})
+		})
+		bottle := apidsl.MediaType("application/vnd.bottle", func() {
+			apidsl.Attributes(func() {
+				apidsl.Attribute("name", design.String)
+				apidsl.Attribute("origin", origin)
+				apidsl.Attribute("accounts", apidsl.ArrayOf(account))
+			})
+			apidsl.View("default", func() {
+				apidsl.Attribute("name")
+			})
+		})
+		apidsl.Resource("bottle", func() {
+			apidsl.BasePath("/bottles")
+			apidsl.Action("create", func() {
+				apidsl.Routing(apidsl.POST(""))
+				apidsl.Payload(func() {
+					apidsl.Attribute("origin", origin)
+				})
+				apidsl.Response(design.Created, bottle)
+			})
+		})
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String(), "--format=" + format}
+		files, genErr = gengraph.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	It("generates the dot diagram", func() {
+		Ω(genErr).Should(BeNil())
+		Ω(files).Should(HaveLen(2))
+
+		content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "graph", "design.dot"))
+		Ω(err).ShouldNot(HaveOccurred())
+		dot := string(content)
+		Ω(dot).Should(HavePrefix(`digraph "test api" {`))
+		Ω(dot).Should(ContainSubstring(`label="bottle";`))
+		Ω(dot).Should(ContainSubstring(`"action:bottle.create" [label="create\nPOST /bottles", shape=ellipse];`))
+		Ω(dot).Should(ContainSubstring(`"action:bottle.create" -> "type:CreateBottlePayload" [label="payload"];`))
+		Ω(dot).Should(ContainSubstring(`"action:bottle.create" -> "media:application/vnd.bottle" [label="Created"];`))
+		Ω(dot).Should(ContainSubstring(`"type:CreateBottlePayload" -> "type:Origin" [label="origin"];`))
+		Ω(dot).Should(ContainSubstring(`"media:application/vnd.bottle" -> "media:application/vnd.account" [label="accounts[]"];`))
+		Ω(dot).ShouldNot(ContainSubstring(`vnd.goa.error`))
+	})
+
+	Context("with the mermaid format", func() {
+		BeforeEach(func() {
+			format = "mermaid"
+		})
+
+		It("generates the mermaid diagram", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "graph", "design.mmd"))
+			Ω(err).ShouldNot(HaveOccurred())
+			mmd := string(content)
+			Ω(mmd).Should(HavePrefix("flowchart LR\n"))
+			Ω(mmd).Should(ContainSubstring(`subgraph r1 ["bottle"]`))
+			Ω(mmd).Should(ContainSubstring(`n1(["create<br/>POST /bottles"])`))
+			Ω(mmd).Should(ContainSubstring(`n1 -->|"payload"| n2`))
+		})
+	})
+
+	Context("with an invalid format", func() {
+		BeforeEach(func() {
+			format = "svg"
+		})
+
+		It("fails", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,271 @@
+package gengraph
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// Node kinds.
+const (
+	// ActionNode is the kind of the nodes representing actions.
+	ActionNode = "action"
+	// TypeNode is the kind of the nodes representing user types, including action payloads.
+	TypeNode = "type"
+	// MediaTypeNode is the kind of the nodes representing media types.
+	MediaTypeNode = "media type"
+)
+
+type (
+	// Graph describes the resources, actions and types of an API design and their dependencies.
+	Graph struct {
+		// Resources lists the names of the resources in alphabetical order.
+		Resources []string
+		// Nodes lists the nodes in the order they were added.
+		Nodes []*Node
+		// Edges lists the edges in the order they were added.
+		Edges []*Edge
+
+		index map[string]*Node
+	}
+
+	// Node is an action, a user type or a media type.
+	Node struct {
+		// ID identifies the node in the graph, e.g. "action:bottle.show" or "type:Bottle".
+		ID string
+		// Kind is one of ActionNode, TypeNode or MediaTypeNode.
+		Kind string
+		// Label is the text displayed in the node.
+		Label string
+		// Resource is the name of the resource of the action nodes.
+		Resource string
+	}
+
+	// Edge links an action to its payload and response types or a type to the types it uses.
+	Edge struct {
+		// From is the ID of the origin node.
+		From string
+		// To is the ID of the destination node.
+		To string
+		// Label is the response name or the attribute path.
+		Label string
+	}
+)
+
+// NewGraph builds the graph of the given API design.
+func NewGraph(api *design.APIDefinition) *Graph {
+	g := &Graph{index: make(map[string]*Node)}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		g.Resources = append(g.Resources, r.Name)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			id := "action:" + r.Name + "." + a.Name
+			label := a.Name
+			for _, route := range a.Routes {
+				label += "\n" + route.Verb + " " + route.FullPath()
+			}
+			g.add(&Node{ID: id, Kind: ActionNode, Label: label, Resource: r.Name})
+			if a.Payload != nil {
+				g.link(id, g.typeNode(a.Payload), "payload")
+			}
+			a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				if mt := api.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
+					g.link(id, g.typeNode(mt), resp.Name)
+				} else if resp.Type != nil {
+					for _, d := range dependencies(&design.AttributeDefinition{Type: resp.Type}, "") {
+						g.link(id, g.typeNode(d.typ), resp.Name)
+					}
+				}
+				return nil
+			})
+			return nil
+		})
+	})
+	api.IterateUserTypes(func(u *design.UserTypeDefinition) error {
+		g.typeNode(u)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if !mt.IsError() {
+			g.typeNode(mt)
+		}
+		return nil
+	})
+	return g
+}
+
+// Dot renders the graph in the Graphviz dot format.
+func (g *Graph) Dot(name string) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph %s {\n", dotString(name))
+	b.WriteString("\trankdir=LR;\n\tnode [fontname=\"Helvetica\"];\n\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	for i, r := range g.Resources {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%s;\n", i, dotString(r))
+		for _, n := range g.Nodes {
+			if n.Kind == ActionNode && n.Resource == r {
+				fmt.Fprintf(&b, "\t\t%s [label=%s, shape=ellipse];\n", dotString(n.ID), dotString(n.Label))
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case TypeNode:
+			fmt.Fprintf(&b, "\t%s [label=%s, shape=box];\n", dotString(n.ID), dotString(n.Label))
+		case MediaTypeNode:
+			fmt.Fprintf(&b, "\t%s [label=%s, shape=box, style=rounded];\n", dotString(n.ID), dotString(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", dotString(e.From), dotString(e.To), dotString(e.Label))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i+1)
+	}
+	var b bytes.Buffer
+	b.WriteString("flowchart LR\n")
+	for i, r := range g.Resources {
+		fmt.Fprintf(&b, "\tsubgraph r%d [%s]\n", i+1, mermaidString(r))
+		for _, n := range g.Nodes {
+			if n.Kind == ActionNode && n.Resource == r {
+				fmt.Fprintf(&b, "\t\t%s([%s])\n", ids[n.ID], mermaidString(n.Label))
+			}
+		}
+		b.WriteString("\tend\n")
+	}
+	for _, n := range g.Nodes {
+		switch n.Kind {
+		case TypeNode:
+			fmt.Fprintf(&b, "\t%s[%s]\n", ids[n.ID], mermaidString(n.Label))
+		case MediaTypeNode:
+			fmt.Fprintf(&b, "\t%s(%s)\n", ids[n.ID], mermaidString(n.Label))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -->|%s| %s\n", ids[e.From], mermaidString(e.Label), ids[e.To])
+	}
+	return b.String()
+}
+
+// add adds n to the graph unless there already is a node with the same ID.
+func (g *Graph) add(n *Node) bool {
+	if g.index[n.ID] != nil {
+		return false
+	}
+	g.index[n.ID] = n
+	g.Nodes = append(g.Nodes, n)
+	return true
+}
+
+// link adds an edge to the graph unless there already is an identical one.
+func (g *Graph) link(from, to, label string) {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to && e.Label == label {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Label: label})
+}
+
+// typeNode adds the node of the given user type or media type and of the types it depends on if
+// not already done and returns its ID.
+func (g *Graph) typeNode(t design.DataType) string {
+	var (
+		n   *Node
+		att *design.AttributeDefinition
+	)
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		n = &Node{
+			ID:    "media:" + design.CanonicalIdentifier(actual.Identifier),
+			Kind:  MediaTypeNode,
+			Label: codegen.Goify(actual.TypeName, true) + "\n" + actual.Identifier,
+		}
+		att = actual.AttributeDefinition
+	case *design.UserTypeDefinition:
+		n = &Node{
+			ID:    "type:" + actual.TypeName,
+			Kind:  TypeNode,
+			Label: codegen.Goify(actual.TypeName, true),
+		}
+		att = actual.AttributeDefinition
+	default:
+		panic(fmt.Sprintf("unexpected data type %s", t.Name())) // bug
+	}
+	if g.add(n) {
+		for _, d := range dependencies(att, "") {
+			g.link(n.ID, g.typeNode(d.typ), d.path)
+		}
+	}
+	return n.ID
+}
+
+// dependency is a user type or media type used by an attribute.
+type dependency struct {
+	path string
+	typ  design.DataType
+}
+
+// dependencies returns the user types and media types used by att and the path of the attributes
+// that use them relative to att. The types used by the dependencies are not returned.
+func dependencies(att *design.AttributeDefinition, path string) []*dependency {
+	if att == nil {
+		return nil
+	}
+	switch t := att.Type.(type) {
+	case *design.UserTypeDefinition:
+		if path == "" {
+			// att is the definition of a user type, describe its attributes
+			return dependencies(&design.AttributeDefinition{Type: t.Type}, path)
+		}
+		return []*dependency{{path: path, typ: t}}
+	case *design.MediaTypeDefinition:
+		if path == "" {
+			return dependencies(&design.AttributeDefinition{Type: t.Type}, path)
+		}
+		return []*dependency{{path: path, typ: t}}
+	case design.Object:
+		names := make([]string, 0, len(t))
+		for n := range t {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		var deps []*dependency
+		for _, n := range names {
+			p := n
+			if path != "" {
+				p = path + "." + n
+			}
+			deps = append(deps, dependencies(t[n], p)...)
+		}
+		return deps
+	case *design.Array:
+		return dependencies(t.ElemType, path+"[]")
+	case *design.Hash:
+		return append(dependencies(t.KeyType, path+"{key}"), dependencies(t.ElemType, path+"{}")...)
+	}
+	return nil
+}
+
+// dotString returns s as a Graphviz quoted string.
+func dotString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return `"` + strings.Replace(s, "\n", `\n`, -1) + `"`
+}
+
+// mermaidString returns s as a Mermaid quoted label.
+func mermaidString(s string) string {
+	s = strings.Replace(s, `"`, "#quot;", -1)
+	return `"` + strings.Replace(s, "\n", "<br/>", -1) + `"`
+}
@@ -0,0 +1,27 @@
+package gengraph
+
+import "github.com/goadesign/goa/design"
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+//Format Format of the diagram, "dot" or "mermaid"
+func Format(format string) Option {
+	return func(g *Generator) {
+		g.Format = format
+	}
+}
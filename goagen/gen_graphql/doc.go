@@ -0,0 +1,13 @@
+/*
+Package gengraphql generates a GraphQL schema and the corresponding resolvers for the API.
+Actions that use the GET method are exposed as queries, all other actions as mutations. The action
+path and query string parameters map to the field arguments and the action payload maps to the
+"payload" argument whose type is a GraphQL input type derived from the payload type. The field
+type is derived from the media type of the first successful response of the action.
+
+The generated resolvers run the actions using the service mux so that the payloads go through the
+same decoding and validation as the HTTP requests. The --noresolvers flag skips the generation of
+the resolvers so that the schema can be exported on its own, for example to be stitched by a
+GraphQL gateway.
+*/
+package gengraphql
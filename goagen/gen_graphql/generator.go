@@ -0,0 +1,474 @@
+package gengraphql
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a GraphQL Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the GraphQL schema and resolvers code generator.
+type Generator struct {
+	API         *design.APIDefinition // The API definition
+	OutDir      string                // Path to output directory
+	NoResolvers bool                  // Whether to only generate the schema
+	genfiles    []string              // Generated files
+}
+
+type (
+	// Operation describes an action exposed as a GraphQL query or mutation field.
+	Operation struct {
+		Resource    string   // Resource name
+		Action      string   // Action name
+		Description string   // Action description
+		Name        string   // Go name of the resolver method, e.g. "ShowBottle"
+		Field       string   // GraphQL field name, e.g. "showBottle"
+		Args        []*Field // Field arguments
+		Type        string   // GraphQL type of the field
+		Verb        string   // HTTP method of the action route
+		Path        string   // Path of the action route
+		Params      []string // Names of the path parameters
+		Query       []string // Names of the query string parameters
+		Mutation    bool     // Whether the operation is a mutation
+	}
+
+	// Type describes a GraphQL object or input type.
+	Type struct {
+		Name        string   // GraphQL type name
+		Description string   // Type description
+		Input       bool     // Whether the type is an input type
+		Fields      []*Field // Type fields
+	}
+
+	// Field describes a GraphQL type field or field argument.
+	Field struct {
+		Name        string // Field name
+		Description string // Field description
+		Type        string // GraphQL type of the field
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, ver string
+		noResolvers bool
+	)
+	set := flag.NewFlagSet("graphql", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.BoolVar(&noResolvers, "noresolvers", false, "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, NoResolvers: noResolvers, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the GraphQL schema and unless NoResolvers is set the resolvers.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	outDir := filepath.Join(g.OutDir, "graphql")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	b := &schemaBuilder{types: make(map[string]*Type)}
+	ops := b.operations(g.API)
+	if err = g.generateSchema(outDir, b, ops); err != nil {
+		return
+	}
+	if !g.NoResolvers {
+		if err = g.generateResolvers(outDir, ops); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// generateSchema writes the GraphQL schema definition file.
+func (g *Generator) generateSchema(outDir string, b *schemaBuilder, ops []*Operation) error {
+	var queries, mutations []*Operation
+	for _, op := range ops {
+		if op.Mutation {
+			mutations = append(mutations, op)
+		} else {
+			queries = append(queries, op)
+		}
+	}
+	names := make([]string, len(b.types))
+	i := 0
+	for n := range b.types {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	types := make([]*Type, len(names))
+	for i, n := range names {
+		types[i] = b.types[n]
+	}
+
+	filename := filepath.Join(outDir, "schema.graphql")
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.genfiles = append(g.genfiles, filename)
+	tmpl := template.Must(template.New("schema").Parse(schemaT))
+	data := map[string]interface{}{
+		"API":       g.API,
+		"Types":     types,
+		"Queries":   queries,
+		"Mutations": mutations,
+	}
+	return tmpl.Execute(f, data)
+}
+
+// generateResolvers writes the Go resolvers file.
+func (g *Generator) generateResolvers(outDir string, ops []*Operation) error {
+	filename := filepath.Join(outDir, "resolvers.go")
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	title := fmt.Sprintf("%s: GraphQL Resolvers", g.API.Context())
+	if err := file.WriteHeader(title, "graphql", imports); err != nil {
+		return err
+	}
+	if err := file.ExecuteTemplate("resolvers", resolversT, nil, ops); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+// schemaBuilder computes the GraphQL types and operations from the API design.
+type schemaBuilder struct {
+	types map[string]*Type
+}
+
+// operations returns the GraphQL operations of all the API actions and records the types they use.
+func (b *schemaBuilder) operations(api *design.APIDefinition) []*Operation {
+	var ops []*Operation
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 {
+				return nil
+			}
+			name := codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true)
+			op := &Operation{
+				Resource:    r.Name,
+				Action:      a.Name,
+				Description: a.Description,
+				Name:        name,
+				Field:       codegen.Goify(a.Name, false) + codegen.Goify(r.Name, true),
+				Verb:        a.Routes[0].Verb,
+				Path:        a.Routes[0].FullPath(),
+				Mutation:    a.Routes[0].Verb != "GET",
+				Type:        "Boolean",
+			}
+			params := a.AllParams()
+			for _, p := range a.Routes[0].Params() {
+				att := params.Type.ToObject()[p]
+				if att == nil {
+					continue
+				}
+				op.Params = append(op.Params, p)
+				op.Args = append(op.Args, &Field{
+					Name:        p,
+					Description: att.Description,
+					Type:        b.typeRef(att, name+codegen.Goify(p, true), true) + "!",
+				})
+			}
+			if a.QueryParams != nil {
+				obj := a.QueryParams.Type.ToObject()
+				for _, n := range sortedKeys(obj) {
+					att := obj[n]
+					typ := b.typeRef(att, name+codegen.Goify(n, true), true)
+					if a.QueryParams.IsRequired(n) {
+						typ += "!"
+					}
+					op.Query = append(op.Query, n)
+					op.Args = append(op.Args, &Field{Name: n, Description: att.Description, Type: typ})
+				}
+			}
+			if a.Payload != nil {
+				typ := b.typeRef(a.Payload.AttributeDefinition, codegen.Goify(a.Payload.TypeName, true), true)
+				if !a.PayloadOptional {
+					typ += "!"
+				}
+				op.Args = append(op.Args, &Field{Name: "payload", Description: a.Payload.Description, Type: typ})
+			}
+			if att := successResult(api, a); att != nil {
+				op.Type = b.typeRef(att, name+"Result", false)
+			}
+			ops = append(ops, op)
+			return nil
+		})
+	})
+	return ops
+}
+
+// successResult returns the attribute describing the body of the first successful response of the
+// action, nil if there is none.
+func successResult(api *design.APIDefinition, a *design.ActionDefinition) *design.AttributeDefinition {
+	names := make([]string, len(a.Responses))
+	i := 0
+	for n := range a.Responses {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		r := a.Responses[n]
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		if r.Type != nil {
+			return &design.AttributeDefinition{Type: r.Type}
+		}
+		if mt := api.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+			return &design.AttributeDefinition{Type: mt}
+		}
+	}
+	return nil
+}
+
+// typeRef returns the GraphQL reference to the type of att, defining the object types it uses as
+// needed. name is the name given to the type if att is an inline object. input specifies whether
+// the reference is used as argument in which case the object types are input types.
+func (b *schemaBuilder) typeRef(att *design.AttributeDefinition, name string, input bool) string {
+	switch t := att.Type.(type) {
+	case design.Primitive:
+		switch t.Kind() {
+		case design.BooleanKind:
+			return "Boolean"
+		case design.IntegerKind:
+			return "Int"
+		case design.NumberKind:
+			return "Float"
+		case design.UUIDKind:
+			return "ID"
+		default:
+			return "String"
+		}
+	case *design.Array:
+		return "[" + b.typeRef(t.ElemType, name+"Elem", input) + "]"
+	case *design.Hash:
+		// GraphQL has no map type, hashes are serialized to JSON strings.
+		return "String"
+	case design.Object:
+		return b.object(t, att, name, input)
+	case *design.UserTypeDefinition:
+		return b.typeRef(t.AttributeDefinition, codegen.Goify(t.TypeName, true), input)
+	case *design.MediaTypeDefinition:
+		return b.typeRef(t.AttributeDefinition, codegen.Goify(t.TypeName, true), input)
+	}
+	return "String"
+}
+
+// object defines the GraphQL type corresponding to the given object and returns its name.
+func (b *schemaBuilder) object(o design.Object, att *design.AttributeDefinition, name string, input bool) string {
+	if input {
+		name += "Input"
+	}
+	if _, ok := b.types[name]; ok {
+		return name
+	}
+	typ := &Type{Name: name, Description: att.Description, Input: input}
+	b.types[name] = typ
+	for _, n := range sortedKeys(o) {
+		fatt := o[n]
+		ref := b.typeRef(fatt, strings.TrimSuffix(name, "Input")+codegen.Goify(n, true), input)
+		if input && att.IsRequired(n) {
+			// Output fields are nullable as the media type views may omit required attributes.
+			ref += "!"
+		}
+		typ.Fields = append(typ.Fields, &Field{Name: fieldName(n), Description: fatt.Description, Type: ref})
+	}
+	return name
+}
+
+// validName matches valid GraphQL names.
+var validName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// fieldName returns the GraphQL field name for the given attribute name.
+func fieldName(n string) string {
+	if validName.MatchString(n) {
+		return n
+	}
+	return codegen.Goify(n, false)
+}
+
+// sortedKeys returns the names of the object attributes in alphabetical order.
+func sortedKeys(o design.Object) []string {
+	keys := make([]string, len(o))
+	i := 0
+	for n := range o {
+		keys[i] = n
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// schemaT generates the GraphQL schema definition.
+// template input: map[string]interface{}
+const schemaT = `# {{ .API.Context }}: GraphQL Schema
+#
+# Code generated by goagen, DO NOT EDIT.
+{{ range .Types }}
+{{ if .Description }}"""{{ .Description }}"""
+{{ end }}{{ if .Input }}input{{ else }}type{{ end }} {{ .Name }} {
+{{ range .Fields }}{{ if .Description }}	"""{{ .Description }}"""
+{{ end }}	{{ .Name }}: {{ .Type }}
+{{ end }}}
+{{ end }}
+type Query {
+{{ range .Queries }}{{ if .Description }}	"""{{ .Description }}"""
+{{ end }}	{{ .Field }}{{ if .Args }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Name }}: {{ $a.Type }}{{ end }}){{ end }}: {{ .Type }}
+{{ else }}	_empty: Boolean
+{{ end }}}
+{{ if .Mutations }}
+type Mutation {
+{{ range .Mutations }}{{ if .Description }}	"""{{ .Description }}"""
+{{ end }}	{{ .Field }}{{ if .Args }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Name }}: {{ $a.Type }}{{ end }}){{ end }}: {{ .Type }}
+{{ end }}}
+{{ end }}`
+
+// resolversT generates the GraphQL resolvers.
+// template input: []*Operation
+const resolversT = `// Resolver resolves the GraphQL queries and mutations by running the corresponding actions. The
+// service must have the corresponding controllers mounted.
+type Resolver struct {
+	service *goa.Service
+}
+
+// NewResolver returns a resolver that runs the actions using the given service.
+func NewResolver(service *goa.Service) *Resolver {
+	return &Resolver{service: service}
+}
+{{ range . }}
+// {{ .Name }} resolves the {{ printf "%q" .Field }} {{ if .Mutation }}mutation{{ else }}query{{ end }} by running the {{ .Resource }} {{ .Action }} action.
+func (r *Resolver) {{ .Name }}(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return r.resolve(ctx, {{ printf "%q" .Verb }}, {{ printf "%q" .Path }}, {{ printf "%#v" .Params }}, {{ printf "%#v" .Query }}, args)
+}
+{{ end }}
+// resolve runs the action mounted on the service mux under the given method and path. The path
+// and query string parameters are read from args and the "payload" argument is used as request
+// body. The decoded response body is returned, true if the response has no body.
+func (r *Resolver) resolve(ctx context.Context, verb, path string, params, query []string, args map[string]interface{}) (interface{}, error) {
+	for _, p := range params {
+		v := url.PathEscape(fmt.Sprintf("%v", args[p]))
+		path = strings.Replace(path, ":"+p, v, 1)
+		path = strings.Replace(path, "*"+p, v, 1)
+	}
+	values := make(url.Values)
+	for _, q := range query {
+		v, ok := args[q]
+		if !ok || v == nil {
+			continue
+		}
+		if vs, ok := v.([]interface{}); ok {
+			for _, e := range vs {
+				values.Add(q, fmt.Sprintf("%v", e))
+			}
+			continue
+		}
+		values.Set(q, fmt.Sprintf("%v", v))
+	}
+	if len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+	var body bytes.Buffer
+	if p, ok := args["payload"]; ok && p != nil {
+		if err := json.NewEncoder(&body).Encode(p); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(verb, path, &body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	rw := httptest.NewRecorder()
+	r.service.Mux.ServeHTTP(rw, req)
+	if rw.Code >= 400 {
+		var e goa.ErrorResponse
+		if err := json.Unmarshal(rw.Body.Bytes(), &e); err == nil && e.Code != "" {
+			return nil, &e
+		}
+		return nil, goa.NewErrorClass("graphql", rw.Code)(rw.Body.String())
+	}
+	if rw.Body.Len() == 0 {
+		return true, nil
+	}
+	var res interface{}
+	if err := json.Unmarshal(rw.Body.Bytes(), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+`
@@ -0,0 +1,149 @@
+package gengraphql_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_graphql"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("graphqltest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gengraphql.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a query and a mutation", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("name", design.String)
+					apidsl.Required("id")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Description("Retrieve a bottle")
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.OK, bottle)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the schema", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "graphql", "schema.graphql"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("type Bottle {\n\tid: Int\n\tname: String\n}"))
+			Ω(string(content)).Should(ContainSubstring("input CreateBottlePayloadInput {\n\tname: String!\n}"))
+			Ω(string(content)).Should(ContainSubstring("type Query {\n\t\"\"\"Retrieve a bottle\"\"\"\n\tshowBottle(id: Int!): Bottle\n}"))
+			Ω(string(content)).Should(ContainSubstring("type Mutation {\n\tcreateBottle(payload: CreateBottlePayloadInput!): Boolean\n}"))
+		})
+
+		It("generates the resolvers", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "graphql", "resolvers.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("func (r *Resolver) ShowBottle(ctx context.Context, args map[string]interface{}) (interface{}, error) {"))
+			Ω(string(content)).Should(ContainSubstring(`r.resolve(ctx, "GET", "/bottles/:id", []string{"id"}, []string(nil), args)`))
+			Ω(string(content)).Should(ContainSubstring(`r.resolve(ctx, "POST", "/bottles", []string(nil), []string(nil), args)`))
+		})
+
+		Context("with --noresolvers", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--noresolvers")
+			})
+
+			It("only generates the schema", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(2))
+				_, err := os.Stat(filepath.Join(testPkg.Abs(), "graphql", "schema.graphql"))
+				Ω(err).ShouldNot(HaveOccurred())
+				_, err = os.Stat(filepath.Join(testPkg.Abs(), "graphql", "resolvers.go"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+			})
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *gengraphql.Generator
+
+	var args = struct {
+		api         *design.APIDefinition
+		outDir      string
+		noResolvers bool
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:      "out_dir",
+		noResolvers: true,
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = gengraphql.NewGenerator(
+				gengraphql.API(args.api),
+				gengraphql.OutDir(args.outDir),
+				gengraphql.NoResolvers(args.noResolvers),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.NoResolvers).Should(Equal(args.noResolvers))
+		})
+	})
+})
@@ -0,0 +1,9 @@
+/*
+Package genjobs generates the code that runs API actions as asynchronous jobs.
+Actions marked with the Async DSL get an Enqueue function that encodes the action payload and
+pushes it to a job queue. The generated worker consumes the queue and runs the corresponding
+action for each job, the outcome is reported as a Result which holds either the response body or
+the goa error produced by the action. The queue implementation is pluggable via the Queue
+interface.
+*/
+package genjobs
@@ -0,0 +1,13 @@
+package genjobs_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenJobs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenJobs Suite")
+}
@@ -0,0 +1,235 @@
+package genjobs
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Jobs Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the asynchronous jobs code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// JobData describes an action run as an asynchronous job.
+type JobData struct {
+	Resource string // Resource name
+	Action   string // Action name
+	Name     string // Go name of the job, e.g. "ImportBottle"
+	Verb     string // HTTP method of the action route
+	Path     string // Path of the action route
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("jobs", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the job enqueue functions and worker.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	outDir := filepath.Join(g.OutDir, "jobs")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	filename := filepath.Join(outDir, "jobs.go")
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, filename)
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	title := fmt.Sprintf("%s: Asynchronous Jobs", g.API.Context())
+	if err = file.WriteHeader(title, "jobs", imports); err != nil {
+		return
+	}
+	data := map[string]interface{}{
+		"Jobs":        g.jobs(),
+		"ContentType": g.contentType(),
+	}
+	if err = file.ExecuteTemplate("jobs", jobsT, nil, data); err != nil {
+		return
+	}
+	if err = file.FormatCode(); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// jobs returns the data of all the async actions.
+func (g *Generator) jobs() []*JobData {
+	var jobs []*JobData
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if !a.Async || len(a.Routes) == 0 {
+				return nil
+			}
+			jobs = append(jobs, &JobData{
+				Resource: r.Name,
+				Action:   a.Name,
+				Name:     codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true),
+				Verb:     a.Routes[0].Verb,
+				Path:     a.Routes[0].FullPath(),
+			})
+			return nil
+		})
+	})
+	return jobs
+}
+
+// contentType returns the content type used to encode the job payloads.
+func (g *Generator) contentType() string {
+	for _, enc := range g.API.Consumes {
+		if len(enc.MIMETypes) > 0 {
+			return enc.MIMETypes[0]
+		}
+	}
+	return "application/json"
+}
+
+const jobsT = `// Job is a unit of work stored in the queue.
+type Job struct {
+	// Name identifies the action run by the job, e.g. "ImportBottle".
+	Name string
+	// Payload is the encoded action payload.
+	Payload []byte
+}
+
+// Result is the outcome of a job.
+type Result struct {
+	// Job is the job that produced the result.
+	Job *Job
+	// Status is the HTTP status code of the action response.
+	Status int
+	// Body is the response body if the job succeeded.
+	Body []byte
+	// Err is the error returned by the action if the job failed.
+	Err *goa.ErrorResponse
+}
+
+// Queue is the interface implemented by the job queues.
+type Queue interface {
+	// Enqueue adds the job to the queue.
+	Enqueue(job *Job) error
+	// Consume calls handler for each job in the queue until the queue is closed.
+	Consume(handler func(*Job)) error
+}
+
+// routes maps the job names to the corresponding action routes.
+var routes = map[string][2]string{
+{{ range .Jobs }}	{{ printf "%q" .Name }}: { {{ printf "%q" .Verb }}, {{ printf "%q" .Path }} },
+{{ end }}}
+{{ range .Jobs }}
+// Enqueue{{ .Name }} enqueues a job that runs the {{ .Resource }} {{ .Action }} action with the given payload.
+func Enqueue{{ .Name }}(service *goa.Service, queue Queue, payload interface{}) error {
+	var buf bytes.Buffer
+	if payload != nil {
+		if err := service.Encoder.Encode(payload, &buf, {{ printf "%q" $.ContentType }}); err != nil {
+			return err
+		}
+	}
+	return queue.Enqueue(&Job{Name: {{ printf "%q" .Name }}, Payload: buf.Bytes()})
+}
+{{ end }}
+// Work consumes the jobs in the queue and runs the corresponding actions using the service. The
+// service must have the corresponding controllers mounted. report is called with the result of each
+// job.
+func Work(service *goa.Service, queue Queue, report func(*Result)) error {
+	return queue.Consume(func(job *Job) {
+		res := run(service, job)
+		if report != nil {
+			report(res)
+		}
+	})
+}
+
+// run runs the action corresponding to the job.
+func run(service *goa.Service, job *Job) *Result {
+	route, ok := routes[job.Name]
+	if !ok {
+		return &Result{Job: job, Status: http.StatusNotFound, Err: goa.ErrNotFound("unknown job", "job", job.Name).(*goa.ErrorResponse)}
+	}
+	req, err := http.NewRequest(route[0], route[1], bytes.NewReader(job.Payload))
+	if err != nil {
+		return &Result{Job: job, Status: http.StatusInternalServerError, Err: goa.ErrInternal(err).(*goa.ErrorResponse)}
+	}
+	req.Header.Set("Content-Type", {{ printf "%q" .ContentType }})
+	rw := httptest.NewRecorder()
+	service.Mux.ServeHTTP(rw, req)
+	res := &Result{Job: job, Status: rw.Code}
+	if rw.Code < 400 {
+		res.Body = rw.Body.Bytes()
+		return res
+	}
+	if rw.Header().Get("Content-Type") == goa.ErrorMediaIdentifier {
+		var e goa.ErrorResponse
+		if err := service.Decoder.Decode(&e, rw.Body, "application/json"); err == nil {
+			res.Err = &e
+			return res
+		}
+	}
+	res.Err = goa.NewErrorClass("job_failed", rw.Code)(rw.Body.String()).(*goa.ErrorResponse)
+	return res
+}
+`
@@ -3,6 +3,11 @@ Package genjs provides a goa generator for a javascript client module.
 The module exposes functions for calling the API actions. It relies on the
 axios (https://github.com/mzabriskie/axios) javascript library to perform the actual HTTP requests.
 
+With the --fetch flag the generator instead produces a dependency-free ES module that uses the fetch
+API. The module exports a createClient function that returns an object exposing one function per
+action, the types of the API are described with JSDoc type definitions and the error responses are
+raised as ServiceError instances.
+
 The generator also produces an example controller and index HTML that shows how to use the module.
 The controller simply serves all the files under the "js" directory so that loading "/js" in a
 browser triggers the example code.
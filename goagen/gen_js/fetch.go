@@ -0,0 +1,359 @@
+package genjs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// fetchAction describes a function of the fetch client.
+	fetchAction struct {
+		Name        string        // Function name, e.g. "showBottle"
+		Description string        // Function description
+		Path        string        // Format of the request path
+		Verb        string        // HTTP method
+		Payload     string        // JSDoc type of the payload if any
+		Params      []*fetchParam // Query string parameters
+		Result      string        // JSDoc type of the successful response body if any
+		Errors      []string      // Error responses, e.g. "404 NotFound"
+	}
+
+	// fetchParam describes a query string parameter of a fetch client function.
+	fetchParam struct {
+		Name        string // Parameter name
+		Arg         string // Function argument name
+		Type        string // JSDoc type
+		Description string // Parameter description
+		Required    bool   // Whether the parameter is required
+	}
+
+	// fetchType describes a JSDoc type definition.
+	fetchType struct {
+		Name        string        // Type name
+		Description string        // Type description
+		Type        string        // JSDoc type, "Object" for objects
+		Properties  []*fetchParam // Object properties
+	}
+)
+
+func (g *Generator) generateFetchJS(jsFile string) (_ *design.ActionDefinition, err error) {
+	file, err := codegen.SourceFileFor(jsFile)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	g.genfiles = append(g.genfiles, jsFile)
+
+	var types []*fetchType
+	g.API.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		types = append(types, buildFetchType(ut.TypeName, ut.Description, ut.AttributeDefinition))
+		return nil
+	})
+	g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		types = append(types, buildFetchType(mt.TypeName, mt.Description, mt.AttributeDefinition))
+		return nil
+	})
+
+	byName := make(map[string][]*design.ActionDefinition)
+	g.API.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(action *design.ActionDefinition) error {
+			byName[action.Name] = append(byName[action.Name], action)
+			// Payloads defined inline in the action are not part of the API types.
+			if p := action.Payload; p != nil && g.API.Types[p.TypeName] == nil {
+				types = append(types, buildFetchType(p.TypeName, p.Description, p.AttributeDefinition))
+			}
+			return nil
+		})
+	})
+	keys := make([]string, 0, len(byName))
+	for n := range byName {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+
+	var (
+		exampleAction *design.ActionDefinition
+		actions       []*fetchAction
+	)
+	for _, n := range keys {
+		for _, a := range byName[n] {
+			if exampleAction == nil && a.Routes[0].Verb == "GET" {
+				exampleAction = a
+			}
+			actions = append(actions, g.buildFetchAction(a))
+		}
+	}
+
+	data := map[string]interface{}{
+		"API":     g.API,
+		"Host":    g.Host,
+		"Scheme":  g.Scheme,
+		"Timeout": int64(g.Timeout / time.Millisecond),
+		"Types":   types,
+		"Actions": actions,
+	}
+	if err = file.ExecuteTemplate("fetchModule", fetchModuleT, nil, data); err != nil {
+		return
+	}
+	return exampleAction, nil
+}
+
+// buildFetchAction computes the data needed to render the fetch client function of the action.
+func (g *Generator) buildFetchAction(a *design.ActionDefinition) *fetchAction {
+	name := fmt.Sprintf("%s%s", a.Name, strings.Title(a.Parent.Name))
+	desc := a.Description
+	if desc == "" {
+		desc = fmt.Sprintf("%s calls the %s action of the %s resource.", name, a.Name, a.Parent.Name)
+	}
+	fa := &fetchAction{
+		Name:        name,
+		Description: desc,
+		Path:        a.Routes[0].FullPath(),
+		Verb:        a.Routes[0].Verb,
+	}
+	if a.Payload != nil {
+		fa.Payload = jsType(a.Payload)
+	}
+	for _, n := range params(a) {
+		att := a.QueryParams.Type.ToObject()[n]
+		fa.Params = append(fa.Params, &fetchParam{
+			Name:        n,
+			Arg:         codegen.Goify(n, false),
+			Type:        jsType(att.Type),
+			Description: att.Description,
+			Required:    a.QueryParams.IsRequired(n),
+		})
+	}
+	var success *design.ResponseDefinition
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		switch {
+		case r.Status >= 400:
+			fa.Errors = append(fa.Errors, fmt.Sprintf("%d %s", r.Status, r.Name))
+		case r.Status >= 200 && r.Status < 300 && r.MediaType != "":
+			if success == nil || r.Status < success.Status {
+				success = r
+			}
+		}
+		return nil
+	})
+	if success != nil {
+		if mt := g.API.MediaTypeWithIdentifier(success.MediaType); mt != nil {
+			fa.Result = mt.TypeName
+		}
+	}
+	sort.Strings(fa.Errors)
+	return fa
+}
+
+// buildFetchType computes the JSDoc type definition of a user type or media type.
+func buildFetchType(name, desc string, att *design.AttributeDefinition) *fetchType {
+	ft := &fetchType{Name: name, Description: desc}
+	obj := att.Type.ToObject()
+	if obj == nil {
+		ft.Type = jsType(att.Type)
+		return ft
+	}
+	ft.Type = "Object"
+	keys := make([]string, 0, len(obj))
+	for n := range obj {
+		keys = append(keys, n)
+	}
+	sort.Strings(keys)
+	for _, n := range keys {
+		ft.Properties = append(ft.Properties, &fetchParam{
+			Name:        n,
+			Type:        jsType(obj[n].Type),
+			Description: obj[n].Description,
+			Required:    att.IsRequired(n),
+		})
+	}
+	return ft
+}
+
+// jsType returns the JSDoc type expression that corresponds to the given data type.
+func jsType(dt design.DataType) string {
+	switch t := dt.(type) {
+	case *design.MediaTypeDefinition:
+		return t.TypeName
+	case *design.UserTypeDefinition:
+		return t.TypeName
+	case design.Primitive:
+		switch t.Kind() {
+		case design.BooleanKind:
+			return "boolean"
+		case design.IntegerKind, design.NumberKind:
+			return "number"
+		case design.StringKind, design.DateTimeKind, design.UUIDKind:
+			return "string"
+		case design.FileKind:
+			return "Blob"
+		}
+	case *design.Array:
+		return fmt.Sprintf("Array<%s>", jsType(t.ElemType.Type))
+	case *design.Hash:
+		return fmt.Sprintf("Object<%s, %s>", jsType(t.KeyType.Type), jsType(t.ElemType.Type))
+	case design.Object:
+		keys := make([]string, 0, len(t))
+		for n := range t {
+			keys = append(keys, n)
+		}
+		sort.Strings(keys)
+		fields := make([]string, len(keys))
+		for i, n := range keys {
+			fields[i] = fmt.Sprintf("%q: %s", n, jsType(t[n].Type))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(fields, ", "))
+	}
+	return "*"
+}
+
+const fetchModuleT = `// This module exports functions that give access to the {{.API.Name}} API hosted at {{.API.Host}}.
+// It is a dependency-free ES module that uses the fetch API for making the actual HTTP requests.
+
+/**
+ * ErrorResponse is the body of the error responses produced by goa.
+ * @typedef {Object} ErrorResponse
+ * @property {string} [id] - Unique identifier for this particular occurrence of the problem.
+ * @property {string} [code] - Application-specific error code.
+ * @property {number} [status] - HTTP status code applicable to this problem.
+ * @property {string} [detail] - Human-readable explanation specific to this occurrence of the problem.
+ * @property {Object<string, *>} [meta] - Non-standard meta-information about the error.
+ */
+
+/**
+ * ServiceError is the error raised when the API responds with a 4xx or 5xx status.
+ */
+export class ServiceError extends Error {
+  /**
+   * @param {Response} response - The HTTP response.
+   * @param {ErrorResponse|string} body - The decoded response body.
+   */
+  constructor(response, body) {
+    super((body && body.detail) || response.statusText);
+    this.name = 'ServiceError';
+    /** @type {number} */
+    this.status = response.status;
+    /** @type {Response} */
+    this.response = response;
+    /** @type {ErrorResponse|string} */
+    this.body = body;
+    if (body && typeof body === 'object') {
+      this.id = body.id;
+      this.code = body.code;
+      this.detail = body.detail;
+      this.meta = body.meta;
+    }
+  }
+}
+{{range .Types}}
+/**
+{{if .Description}} * {{.Description}}
+{{end}} * @typedef {{"{"}}{{.Type}}{{"}"}} {{.Name}}
+{{range .Properties}} * @property {{"{"}}{{.Type}}{{"}"}} {{if .Required}}{{.Name}}{{else}}[{{.Name}}]{{end}}{{if .Description}} - {{.Description}}{{end}}
+{{end}} */
+{{end}}
+/**
+ * ClientOptions configures the client returned by createClient.
+ * @typedef {Object} ClientOptions
+ * @property {string} [scheme] - The URL scheme used to make requests, defaults to "{{.Scheme}}".
+ * @property {string} [host] - The API host, defaults to "{{.Host}}".
+ * @property {number} [timeout] - The request timeout in milliseconds, defaults to {{.Timeout}}.
+ * @property {Object<string, string>} [headers] - Headers added to all the requests.
+ * @property {function(string, RequestInit): Promise<Response>} [fetch] - The fetch implementation, defaults to the global fetch.
+ */
+
+/**
+ * createClient returns an object exposing one function per API action.
+ * @param {ClientOptions} [options] - The client options.
+ */
+export function createClient(options = {}) {
+  const scheme = options.scheme || '{{.Scheme}}';
+  const host = options.host || '{{.Host}}';
+  const timeout = options.timeout || {{.Timeout}};
+  const doFetch = options.fetch || ((url, init) => fetch(url, init));
+
+  // URL prefix for all API requests.
+  const urlPrefix = scheme + '://' + host;
+
+  async function request(method, path, data, params, init) {
+    const url = new URL(urlPrefix + path);
+    for (const name in params) {
+      const value = params[name];
+      if (value === undefined || value === null) {
+        continue;
+      }
+      for (const v of Array.isArray(value) ? value : [value]) {
+        url.searchParams.append(name, String(v));
+      }
+    }
+    const headers = Object.assign({ Accept: 'application/json' }, options.headers, init && init.headers);
+    const controller = new AbortController();
+    const req = Object.assign({ method: method, signal: controller.signal }, init, { headers: headers });
+    if (data !== undefined) {
+      if (!Object.keys(headers).some((h) => h.toLowerCase() === 'content-type')) {
+        headers['Content-Type'] = 'application/json';
+      }
+      req.body = JSON.stringify(data);
+    }
+    const timer = setTimeout(() => controller.abort(), timeout);
+    try {
+      const response = await doFetch(url.toString(), req);
+      const text = await response.text();
+      let body = text;
+      if (text && (response.headers.get('Content-Type') || '').indexOf('json') !== -1) {
+        body = JSON.parse(text);
+      }
+      if (!response.ok) {
+        throw new ServiceError(response, body);
+      }
+      return text ? body : undefined;
+    } finally {
+      clearTimeout(timer);
+    }
+  }
+
+  return {
+{{range $i, $a := .Actions}}{{if $i}}
+{{end}}    /**
+     * {{.Description}}
+     * @param {string} path - The request path, the format is "{{.Path}}".
+{{if .Payload}}     * @param {{"{"}}{{.Payload}}{{"}"}} data - The action payload (request body).
+{{end}}{{range .Params}}     * @param {{"{"}}{{.Type}}{{"}"}} {{if .Required}}{{.Arg}}{{else}}[{{.Arg}}]{{end}} - {{if .Description}}{{.Description}}{{else}}The "{{.Name}}" query string parameter.{{end}}
+{{end}}     * @param {RequestInit} [init] - Optional fetch options merged into the options built by the function.
+     * @returns {{"{"}}Promise<{{if .Result}}{{.Result}}{{else}}*{{end}}>{{"}"}} The decoded response body.
+     * @throws {ServiceError} If the response status is 4xx or 5xx{{if .Errors}} ({{join .Errors ", "}}){{end}}.
+     */
+    {{.Name}}(path{{if .Payload}}, data{{end}}{{range .Params}}, {{.Arg}}{{end}}, init) {
+      return request('{{.Verb}}', path, {{if .Payload}}data{{else}}undefined{{end}}, {{if .Params}}{ {{range $j, $p := .Params}}{{if $j}}, {{end}}{{printf "%q" $p.Name}}: {{$p.Arg}}{{end}} }{{else}}undefined{{end}}, init);
+    },
+{{end}}  };
+}
+`
+
+const exampleFetchT = `<!doctype html>
+<html>
+  <head>
+    <title>goa JavaScript client loader</title>
+  </head>
+  <body>
+    <h1>{{.API.Name}} Client Test</h1>
+    <div id="response"></div>
+    <script type="module">
+      import { createClient } from '/js/client.js';
+
+      createClient().{{.ExampleFunc}}
+        .then(function (body) {
+          document.getElementById('response').innerHTML = JSON.stringify(body);
+        })
+        .catch(function (err) {
+          document.getElementById('response').innerHTML = err.message;
+        });
+    </script>
+  </body>
+</html>
+`
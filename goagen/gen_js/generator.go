@@ -35,6 +35,7 @@ type Generator struct {
 	Scheme    string                // Scheme used by JavaScript client
 	Host      string                // Host addressed by JavaScript client
 	NoExample bool                  // Do not generate an HTML example file
+	Fetch     bool                  // Generate a dependency-free ES module using the fetch API
 	genfiles  []string              // Generated files
 }
 
@@ -45,6 +46,7 @@ func Generate() (files []string, err error) {
 		timeout      time.Duration
 		scheme, host string
 		noexample    bool
+		fetch        bool
 	)
 
 	set := flag.NewFlagSet("client", flag.PanicOnError)
@@ -55,6 +57,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&host, "host", "", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&noexample, "noexample", false, "")
+	set.BoolVar(&fetch, "fetch", false, "")
 	set.Parse(os.Args[1:])
 
 	// First check compatibility
@@ -63,7 +66,7 @@ func Generate() (files []string, err error) {
 	}
 
 	// Now proceed
-	g := &Generator{OutDir: outDir, Timeout: timeout, Scheme: scheme, Host: host, NoExample: noexample, API: design.Design}
+	g := &Generator{OutDir: outDir, Timeout: timeout, Scheme: scheme, Host: host, NoExample: noexample, Fetch: fetch, API: design.Design}
 
 	return g.Generate()
 }
@@ -108,14 +111,21 @@ func (g *Generator) Generate() (_ []string, err error) {
 	g.genfiles = append(g.genfiles, g.OutDir)
 
 	// Generate client.js
-	exampleAction, err := g.generateJS(filepath.Join(g.OutDir, "client.js"))
+	var exampleAction *design.ActionDefinition
+	if g.Fetch {
+		exampleAction, err = g.generateFetchJS(filepath.Join(g.OutDir, "client.js"))
+	} else {
+		exampleAction, err = g.generateJS(filepath.Join(g.OutDir, "client.js"))
+	}
 	if err != nil {
 		return
 	}
 
 	// Generate axios.html
-	if err = g.generateAxiosJS(); err != nil {
-		return
+	if !g.Fetch {
+		if err = g.generateAxiosJS(); err != nil {
+			return
+		}
 	}
 
 	if exampleAction != nil && !g.NoExample {
@@ -232,6 +242,9 @@ func (g *Generator) generateIndexHTML(htmlFile string, exampleAction *design.Act
 		"ExampleFunc": exampleFunc,
 	}
 
+	if g.Fetch {
+		return file.ExecuteTemplate("exampleHTML", exampleFetchT, nil, data)
+	}
 	return file.ExecuteTemplate("exampleHTML", exampleT, nil, data)
 }
 
@@ -98,6 +98,28 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(len(strings.Split(string(content), "\n"))).Should(BeNumerically(">=", 13))
 		})
+
+		Context("with the fetch flag", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--fetch")
+			})
+
+			It("generates a dependency-free ES module", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(4))
+				_, err := os.Stat(filepath.Join(outDir, "js", "axios.min.js"))
+				Ω(os.IsNotExist(err)).Should(BeTrue())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "js", "client.js"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("export function createClient(options = {}) {"))
+				Ω(string(content)).Should(ContainSubstring("export class ServiceError extends Error {"))
+				Ω(string(content)).Should(ContainSubstring(`     * @param {string} [query] - The "query" query string parameter.`))
+				Ω(string(content)).Should(ContainSubstring(`      return request('GET', path, undefined, { "query": query }, init);`))
+				html, err := ioutil.ReadFile(filepath.Join(outDir, "js", "index.html"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(html)).Should(ContainSubstring("import { createClient } from '/js/client.js';"))
+			})
+		})
 	})
 })
 
@@ -111,6 +133,7 @@ var _ = Describe("NewGenerator", func() {
 		scheme    string
 		host      string
 		noExample bool
+		fetch     bool
 	}{
 		api: &design.APIDefinition{
 			Name: "test api",
@@ -120,6 +143,7 @@ var _ = Describe("NewGenerator", func() {
 		scheme:    "http",
 		host:      "localhost",
 		noExample: true,
+		fetch:     true,
 	}
 
 	Context("with options all options set", func() {
@@ -132,6 +156,7 @@ var _ = Describe("NewGenerator", func() {
 				genjs.Scheme(args.scheme),
 				genjs.Host(args.host),
 				genjs.NoExample(args.noExample),
+				genjs.Fetch(args.fetch),
 			)
 		})
 
@@ -143,6 +168,7 @@ var _ = Describe("NewGenerator", func() {
 			Ω(generator.Scheme).Should(Equal(args.scheme))
 			Ω(generator.Host).Should(Equal(args.host))
 			Ω(generator.NoExample).Should(Equal(args.noExample))
+			Ω(generator.Fetch).Should(Equal(args.fetch))
 		})
 
 	})
@@ -47,3 +47,10 @@ func NoExample(noExample bool) Option {
 		g.NoExample = noExample
 	}
 }
+
+//Fetch Generate a dependency-free ES module client using the fetch API
+func Fetch(fetch bool) Option {
+	return func(g *Generator) {
+		g.Fetch = fetch
+	}
+}
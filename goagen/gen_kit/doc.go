@@ -0,0 +1,9 @@
+/*
+Package genkit generates adapters that expose the API actions as go-kit endpoints.
+Each action gets a function returning a go-kit compatible endpoint (a function with signature
+func(context.Context, interface{}) (interface{}, error)) together with a typed request struct and
+the request and response converters used by the go-kit HTTP transport. This makes it possible to
+wrap the actions with existing go-kit middleware stacks. The generated code does not import go-kit
+so that the dependency remains under the control of the user.
+*/
+package genkit
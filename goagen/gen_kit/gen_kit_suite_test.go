@@ -0,0 +1,13 @@
+package genkit_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenKit(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenKit Suite")
+}
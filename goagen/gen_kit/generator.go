@@ -0,0 +1,268 @@
+package genkit
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a go-kit Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the go-kit endpoint adapters code generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// EndpointData describes an action exposed as a go-kit endpoint.
+type EndpointData struct {
+	Resource string   // Resource name
+	Action   string   // Action name
+	Name     string   // Go name of the endpoint, e.g. "ShowBottle"
+	Verb     string   // HTTP method of the action route
+	Path     string   // Path of the action route
+	Params   []string // Names of the path parameters
+	Payload  bool     // Whether the action has a payload
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("kit", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the go-kit endpoint adapters.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	outDir := filepath.Join(g.OutDir, "kit")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	filename := filepath.Join(outDir, "endpoints.go")
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, filename)
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	title := fmt.Sprintf("%s: go-kit Endpoints", g.API.Context())
+	if err = file.WriteHeader(title, "kit", imports); err != nil {
+		return
+	}
+	data := map[string]interface{}{
+		"Endpoints":   g.endpoints(),
+		"ContentType": g.contentType(),
+	}
+	if err = file.ExecuteTemplate("endpoints", endpointsT, nil, data); err != nil {
+		return
+	}
+	if err = file.FormatCode(); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// endpoints returns the data of all the API actions.
+func (g *Generator) endpoints() []*EndpointData {
+	var endpoints []*EndpointData
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 || a.WebSocket() {
+				return nil
+			}
+			endpoints = append(endpoints, &EndpointData{
+				Resource: r.Name,
+				Action:   a.Name,
+				Name:     codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true),
+				Verb:     a.Routes[0].Verb,
+				Path:     a.Routes[0].FullPath(),
+				Params:   a.Routes[0].Params(),
+				Payload:  a.Payload != nil,
+			})
+			return nil
+		})
+	})
+	return endpoints
+}
+
+// contentType returns the content type used to encode the request payloads.
+func (g *Generator) contentType() string {
+	for _, enc := range g.API.Consumes {
+		if len(enc.MIMETypes) > 0 {
+			return enc.MIMETypes[0]
+		}
+	}
+	return "application/json"
+}
+
+// endpointsT generates the go-kit endpoints and converters.
+// template input: map[string]interface{}
+const endpointsT = `// Response is the response returned by the endpoints.
+type Response struct {
+	// Status is the HTTP status code of the action response.
+	Status int
+	// Header contains the action response headers.
+	Header http.Header
+	// Body is the action response body.
+	Body []byte
+}
+{{ range .Endpoints }}
+// {{ .Name }}Request is the request of the {{ .Name }} endpoint.
+type {{ .Name }}Request struct {
+{{ range .Params }}	// {{ goify . true }} is the value of the {{ printf "%q" . }} path parameter.
+	{{ goify . true }} string
+{{ end }}	// Query contains the query string parameters.
+	Query url.Values{{ if .Payload }}
+	// Payload is the action payload, it is encoded with the service encoder.
+	Payload interface{}{{ end }}
+}
+
+// Make{{ .Name }}Endpoint returns a go-kit endpoint that runs the {{ .Resource }} {{ .Action }} action.
+// The request must be a *{{ .Name }}Request and the response is a *Response.
+func Make{{ .Name }}Endpoint(service *goa.Service) func(context.Context, interface{}) (interface{}, error) {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(*{{ .Name }}Request)
+		if !ok {
+			return nil, goa.ErrBadRequest("invalid request type", "type", fmt.Sprintf("%T", request))
+		}
+		path := {{ printf "%q" .Path }}
+{{ range .Params }}		path = replaceParam(path, {{ printf "%q" . }}, req.{{ goify . true }})
+{{ end }}		var body []byte{{ if .Payload }}
+		if req.Payload != nil {
+			var buf bytes.Buffer
+			if err := service.Encoder.Encode(req.Payload, &buf, {{ printf "%q" $.ContentType }}); err != nil {
+				return nil, err
+			}
+			body = buf.Bytes()
+		}{{ end }}
+		return serve(ctx, service, {{ printf "%q" .Verb }}, path, req.Query, body)
+	}
+}
+
+// Decode{{ .Name }}Request is a go-kit HTTP transport request decoder that builds a
+// *{{ .Name }}Request from the incoming HTTP request. The payload is decoded using the service
+// decoder and params returns the value of the path parameters extracted by the router used by the
+// go-kit transport.
+func Decode{{ .Name }}Request(service *goa.Service, params func(*http.Request, string) string) func(context.Context, *http.Request) (interface{}, error) {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		req := &{{ .Name }}Request{
+{{ range .Params }}			{{ goify . true }}: params(r, {{ printf "%q" . }}),
+{{ end }}			Query: r.URL.Query(),
+		}{{ if .Payload }}
+		if r.ContentLength != 0 {
+			var payload interface{}
+			if err := service.Decoder.Decode(&payload, r.Body, r.Header.Get("Content-Type")); err != nil {
+				return nil, goa.ErrBadRequest(err)
+			}
+			req.Payload = payload
+		}{{ end }}
+		return req, nil
+	}
+}
+{{ end }}
+// EncodeResponse is a go-kit HTTP transport response encoder that writes the *Response returned by
+// the endpoints.
+func EncodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	resp, ok := response.(*Response)
+	if !ok {
+		return goa.ErrInternal("invalid response type", "type", fmt.Sprintf("%T", response))
+	}
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.Status)
+	_, err := w.Write(resp.Body)
+	return err
+}
+
+// replaceParam substitutes the value of the given path parameter in path.
+func replaceParam(path, name, value string) string {
+	value = url.PathEscape(value)
+	path = strings.Replace(path, ":"+name, value, 1)
+	return strings.Replace(path, "*"+name, value, 1)
+}
+
+// serve runs the action mounted on the service mux under the given method and path.
+func serve(ctx context.Context, service *goa.Service, verb, path string, query url.Values, body []byte) (*Response, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(verb, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if len(body) > 0 {
+		req.Header.Set("Content-Type", {{ printf "%q" .ContentType }})
+	}
+	rw := httptest.NewRecorder()
+	service.Mux.ServeHTTP(rw, req)
+	b, err := ioutil.ReadAll(rw.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Status: rw.Code, Header: rw.Header(), Body: b}, nil
+}
+`
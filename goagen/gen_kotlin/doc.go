@@ -0,0 +1,15 @@
+/*
+Package genkotlin generates a Kotlin client for the API targeting the JVM and Android.
+
+The client sources are written under the "kotlin" directory together with a Gradle build script.
+The API types, media types and action payloads are described by data classes serialized with
+kotlinx.serialization. The requests are described by a Retrofit service interface and made with
+OkHttp. The Client class exposes one suspending function per action, the path parameters, payload
+and query string parameters of the action map to the function arguments and the function returns
+the decoded body of the first successful response of the action.
+
+Each error response defined in the design maps to a subclass of the ServiceException sealed class,
+the client throws the exception corresponding to the response status when the API responds with a
+4xx or 5xx status.
+*/
+package genkotlin
@@ -0,0 +1,13 @@
+package genkotlin_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenKotlin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenKotlin Suite")
+}
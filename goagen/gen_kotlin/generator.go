@@ -0,0 +1,730 @@
+package genkotlin
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Kotlin Client Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the Kotlin client generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Package  string                // Name of the generated Kotlin package
+	Timeout  time.Duration         // Timeout used by the client when making requests
+	Scheme   string                // Scheme used by the client
+	Host     string                // Host addressed by the client
+	genfiles []string              // Generated files
+}
+
+type (
+	// Class describes a Kotlin data class generated for an object type.
+	Class struct {
+		Name        string   // Class name
+		Description string   // Class description
+		Fields      []*Field // Class properties, required properties first
+	}
+
+	// Field describes a data class property or a client function argument.
+	Field struct {
+		Name        string // Kotlin identifier
+		Key         string // Name of the attribute in the design
+		Description string // Field description
+		Type        string // Kotlin type, not nullable
+		Annotation  string // Retrofit annotation of the service function parameter
+		Required    bool   // Whether the field is required
+	}
+
+	// Method describes a client function generated for an action.
+	Method struct {
+		Name        string   // Function name, e.g. "showBottle"
+		Description string   // Function description
+		Annotation  string   // Retrofit annotation describing the HTTP method and path
+		Args        []*Field // Function arguments, required arguments first
+		Result      string   // Kotlin type of the result, "Unit" if there is none
+		Errors      []*Error // Exceptions thrown for the action error responses
+	}
+
+	// Error describes a Kotlin exception generated for a design error response.
+	Error struct {
+		Name     string // Exception class name
+		Response string // Name of the response in the design
+		Status   int    // HTTP status code
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, ver, pkg string
+		scheme, host     string
+		timeout          time.Duration
+	)
+	set := flag.NewFlagSet("kotlin", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&pkg, "package", "", "")
+	set.DurationVar(&timeout, "timeout", time.Duration(20)*time.Second, "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Package: pkg, Timeout: timeout, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Kotlin client sources and build script.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Package == "" {
+		g.Package = packageName(g.API.Name)
+	}
+	if g.Timeout == 0 {
+		g.Timeout = 20 * time.Second
+	}
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		g.Host = "localhost"
+	}
+
+	outDir := filepath.Join(g.OutDir, "kotlin")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	srcDir := filepath.Join(append([]string{outDir, "src", "main", "kotlin"}, strings.Split(g.Package, ".")...)...)
+	if err = os.MkdirAll(srcDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	b := &modelBuilder{classes: make(map[string]*Class)}
+	methods := b.methods(g.API)
+	names := make([]string, len(b.classes))
+	i := 0
+	for n := range b.classes {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	classes := make([]*Class, len(names))
+	for i, n := range names {
+		classes[i] = b.classes[n]
+	}
+	errs, byStatus := errorClasses(g.API)
+	version := g.API.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+
+	data := map[string]interface{}{
+		"API":            g.API,
+		"Project":        codegen.KebabCase(codegen.Goify(g.API.Name, true)),
+		"Package":        g.Package,
+		"Version":        version,
+		"BaseURL":        g.Scheme + "://" + g.Host,
+		"Timeout":        int64(g.Timeout / time.Millisecond),
+		"Classes":        classes,
+		"Methods":        methods,
+		"Errors":         errs,
+		"ErrorsByStatus": byStatus,
+	}
+	files := []struct{ path, tmpl string }{
+		{filepath.Join(outDir, "settings.gradle.kts"), settingsT},
+		{filepath.Join(outDir, "build.gradle.kts"), buildT},
+		{filepath.Join(srcDir, "Models.kt"), modelsT},
+		{filepath.Join(srcDir, "Errors.kt"), errorsT},
+		{filepath.Join(srcDir, "Client.kt"), clientT},
+	}
+	for _, f := range files {
+		if err = g.generateFile(f.path, f.tmpl, data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// generateFile renders the given template into the file with the given path.
+func (g *Generator) generateFile(path, tmpl string, data map[string]interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.genfiles = append(g.genfiles, path)
+	t := template.Must(template.New(filepath.Base(path)).Funcs(template.FuncMap{"str": str}).Parse(tmpl))
+	return t.Execute(f, data)
+}
+
+// modelBuilder computes the Kotlin data classes and client functions from the API design.
+type modelBuilder struct {
+	classes map[string]*Class
+}
+
+// methods returns the client functions of all the API actions and records the classes they use.
+func (b *modelBuilder) methods(api *design.APIDefinition) []*Method {
+	var methods []*Method
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 {
+				return nil
+			}
+			name := codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true)
+			m := &Method{
+				Name:        ktName(a.Name + "_" + r.Name),
+				Description: a.Description,
+				Result:      "Unit",
+			}
+			if m.Description == "" {
+				m.Description = fmt.Sprintf("%s calls the %s action of the %s resource.", m.Name, a.Name, r.Name)
+			}
+			var required, optional []*Field
+
+			// Path parameters
+			params := a.AllParams()
+			path := a.Routes[0].FullPath()
+			for _, match := range design.WildcardRegex.FindAllStringSubmatch(path, -1) {
+				var att *design.AttributeDefinition
+				if params != nil {
+					att = params.Type.ToObject()[match[1]]
+				}
+				if att == nil {
+					att = &design.AttributeDefinition{Type: design.String}
+				}
+				annotation := fmt.Sprintf("@Path(%s)", str(match[1]))
+				if strings.HasPrefix(match[0], "/*") {
+					annotation = fmt.Sprintf("@Path(value = %s, encoded = true)", str(match[1]))
+				}
+				required = append(required, &Field{
+					Name:        ktName(match[1]),
+					Key:         match[1],
+					Description: att.Description,
+					Type:        b.typeRef(att, name+codegen.Goify(match[1], true)),
+					Annotation:  annotation,
+					Required:    true,
+				})
+			}
+			path = design.WildcardRegex.ReplaceAllStringFunc(path, func(w string) string {
+				return "/{" + design.WildcardRegex.FindStringSubmatch(w)[1] + "}"
+			})
+
+			// Payload
+			if a.Payload != nil {
+				arg := &Field{
+					Name:        "payload",
+					Key:         "payload",
+					Description: a.Payload.Description,
+					Type:        b.typeRef(a.Payload.AttributeDefinition, codegen.Goify(a.Payload.TypeName, true)),
+					Annotation:  "@Body",
+					Required:    true,
+				}
+				if arg.Description == "" {
+					arg.Description = "The request body."
+				}
+				required = append(required, arg)
+			}
+
+			// Query string parameters
+			if a.QueryParams != nil {
+				obj := a.QueryParams.Type.ToObject()
+				for _, n := range sortedKeys(obj) {
+					att := obj[n]
+					arg := &Field{
+						Name:        ktName(n),
+						Key:         n,
+						Description: att.Description,
+						Type:        b.typeRef(att, name+codegen.Goify(n, true)),
+						Annotation:  fmt.Sprintf("@Query(%s)", str(n)),
+						Required:    a.QueryParams.IsRequired(n),
+					}
+					if arg.Required {
+						required = append(required, arg)
+					} else {
+						optional = append(optional, arg)
+					}
+				}
+			}
+			m.Args = append(required, optional...)
+			for _, arg := range m.Args {
+				if arg.Description == "" {
+					arg.Description = fmt.Sprintf("The %q parameter.", arg.Key)
+				}
+			}
+			m.Annotation = annotation(a.Routes[0].Verb, path, a.Payload != nil)
+
+			if att := successResult(api, a); att != nil {
+				m.Result = b.typeRef(att, name+"Result")
+			}
+			a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				if resp.Status >= 400 {
+					m.Errors = append(m.Errors, &Error{Name: errorName(resp.Name), Response: resp.Name, Status: resp.Status})
+				}
+				return nil
+			})
+			methods = append(methods, m)
+			return nil
+		})
+	})
+	return methods
+}
+
+// annotation returns the Retrofit annotation of a request with the given HTTP method and path.
+// Retrofit defines one annotation per common HTTP method, the other methods and the DELETE
+// requests with a body use the generic HTTP annotation.
+func annotation(verb, path string, hasBody bool) string {
+	switch verb {
+	case "GET", "POST", "PUT", "PATCH", "HEAD", "OPTIONS":
+		return fmt.Sprintf("@%s(%s)", verb, str(path))
+	case "DELETE":
+		if !hasBody {
+			return fmt.Sprintf("@DELETE(%s)", str(path))
+		}
+	}
+	return fmt.Sprintf("@HTTP(method = %s, path = %s, hasBody = %t)", str(verb), str(path), hasBody)
+}
+
+// successResult returns the attribute describing the body of the first successful response of the
+// action, nil if there is none.
+func successResult(api *design.APIDefinition, a *design.ActionDefinition) *design.AttributeDefinition {
+	names := make([]string, len(a.Responses))
+	i := 0
+	for n := range a.Responses {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		r := a.Responses[n]
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		if r.Type != nil {
+			return &design.AttributeDefinition{Type: r.Type}
+		}
+		if mt := api.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+			return &design.AttributeDefinition{Type: mt}
+		}
+	}
+	return nil
+}
+
+// typeRef returns the Kotlin type of att, defining the data classes it uses as needed. name is
+// the name given to the class if att is an inline object.
+func (b *modelBuilder) typeRef(att *design.AttributeDefinition, name string) string {
+	switch t := att.Type.(type) {
+	case design.Primitive:
+		switch t.Kind() {
+		case design.BooleanKind:
+			return "Boolean"
+		case design.IntegerKind:
+			return "Long"
+		case design.NumberKind:
+			return "Double"
+		case design.StringKind, design.DateTimeKind, design.UUIDKind:
+			return "String"
+		}
+		return "JsonElement"
+	case *design.Array:
+		return fmt.Sprintf("List<%s>", b.typeRef(t.ElemType, name+"Elem"))
+	case *design.Hash:
+		key := b.typeRef(t.KeyType, name+"Key")
+		return fmt.Sprintf("Map<%s, %s>", key, b.typeRef(t.ElemType, name+"Elem"))
+	case design.Object:
+		return b.class(t, att, name)
+	case *design.UserTypeDefinition:
+		return b.typeRef(t.AttributeDefinition, codegen.Goify(t.TypeName, true))
+	case *design.MediaTypeDefinition:
+		return b.typeRef(t.AttributeDefinition, codegen.Goify(t.TypeName, true))
+	}
+	return "JsonElement"
+}
+
+// class defines the Kotlin data class corresponding to the given object and returns its name.
+func (b *modelBuilder) class(o design.Object, att *design.AttributeDefinition, name string) string {
+	if _, ok := b.classes[name]; ok {
+		return name
+	}
+	c := &Class{Name: name, Description: att.Description}
+	if c.Description == "" {
+		c.Description = fmt.Sprintf("%s is a type of the API.", name)
+	}
+	b.classes[name] = c
+	var required, optional []*Field
+	for _, n := range sortedKeys(o) {
+		fatt := o[n]
+		f := &Field{
+			Name:        ktName(n),
+			Key:         n,
+			Description: fatt.Description,
+			Type:        b.typeRef(fatt, name+codegen.Goify(n, true)),
+			Required:    att.IsRequired(n),
+		}
+		if f.Description == "" {
+			f.Description = fmt.Sprintf("The %q attribute.", n)
+		}
+		if f.Required {
+			required = append(required, f)
+		} else {
+			optional = append(optional, f)
+		}
+	}
+	c.Fields = append(required, optional...)
+	return name
+}
+
+// errorClasses returns the exceptions generated for the error responses of the API actions and the
+// exceptions thrown for each status code sorted by status code.
+func errorClasses(api *design.APIDefinition) ([]*Error, []*Error) {
+	errs := make(map[string]*Error)
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			return a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				if resp.Status >= 400 {
+					n := errorName(resp.Name)
+					errs[n] = &Error{Name: n, Response: resp.Name, Status: resp.Status}
+				}
+				return nil
+			})
+		})
+	})
+	names := make([]string, len(errs))
+	i := 0
+	for n := range errs {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	res := make([]*Error, len(names))
+	statuses := make(map[int]*Error)
+	for i, n := range names {
+		res[i] = errs[n]
+		if _, ok := statuses[errs[n].Status]; !ok {
+			statuses[errs[n].Status] = errs[n]
+		}
+	}
+	byStatus := make([]*Error, 0, len(statuses))
+	for _, e := range statuses {
+		byStatus = append(byStatus, e)
+	}
+	sort.Slice(byStatus, func(i, j int) bool { return byStatus[i].Status < byStatus[j].Status })
+	return res, byStatus
+}
+
+// errorName returns the name of the exception thrown for the response with the given name.
+func errorName(response string) string {
+	n := codegen.Goify(response, true)
+	if strings.HasSuffix(n, "Exception") {
+		return n
+	}
+	return n + "Exception"
+}
+
+// keywords lists the Kotlin hard keywords.
+var keywords = map[string]bool{
+	"as": true, "break": true, "class": true, "continue": true, "do": true, "else": true,
+	"false": true, "for": true, "fun": true, "if": true, "in": true, "interface": true, "is": true,
+	"null": true, "object": true, "package": true, "return": true, "super": true, "this": true,
+	"throw": true, "true": true, "try": true, "typealias": true, "typeof": true, "val": true,
+	"var": true, "when": true, "while": true,
+}
+
+// ktName returns the lowerCamelCase Kotlin identifier for the given name. Keywords are escaped with
+// backticks.
+func ktName(name string) string {
+	runes := []rune(codegen.Goify(name, true))
+	var b []rune
+	for i, r := range runes {
+		if i == 0 {
+			b = append(b, unicode.ToLower(r))
+			continue
+		}
+		prev := runes[i-1]
+		wordStart := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		if !wordStart && unicode.IsUpper(prev) {
+			r = unicode.ToLower(r)
+		}
+		b = append(b, r)
+	}
+	res := string(b)
+	if keywords[res] {
+		return "`" + res + "`"
+	}
+	return res
+}
+
+// packageName returns the default Kotlin package name for the API with the given name.
+func packageName(name string) string {
+	return strings.ToLower(codegen.Goify(name, false))
+}
+
+// str returns the Kotlin string literal for s.
+func str(s string) string {
+	return strings.Replace(strconv.Quote(s), "$", `\$`, -1)
+}
+
+// sortedKeys returns the names of the object attributes in alphabetical order.
+func sortedKeys(o design.Object) []string {
+	keys := make([]string, len(o))
+	i := 0
+	for n := range o {
+		keys[i] = n
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// settingsT generates the Gradle settings script.
+// template input: map[string]interface{}
+const settingsT = `// {{ .API.Context }}: Kotlin Client Settings
+//
+// Code generated by goagen, DO NOT EDIT.
+
+rootProject.name = {{ str .Project }}
+`
+
+// buildT generates the Gradle build script.
+// template input: map[string]interface{}
+const buildT = `// {{ .API.Context }}: Kotlin Client Build
+//
+// Code generated by goagen, DO NOT EDIT.
+
+plugins {
+    kotlin("jvm") version "1.9.24"
+    kotlin("plugin.serialization") version "1.9.24"
+    ` + "`java-library`" + `
+}
+
+group = {{ str .Package }}
+version = {{ str .Version }}
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    api("com.squareup.okhttp3:okhttp:4.12.0")
+    api("com.squareup.retrofit2:retrofit:2.11.0")
+    api("org.jetbrains.kotlinx:kotlinx-serialization-json:1.6.3")
+    implementation("com.squareup.retrofit2:converter-kotlinx-serialization:2.11.0")
+}
+`
+
+// modelsT generates the data classes that describe the API types.
+// template input: map[string]interface{}
+const modelsT = `// {{ .API.Context }}: Kotlin Models
+//
+// Code generated by goagen, DO NOT EDIT.
+
+package {{ .Package }}
+
+import kotlinx.serialization.SerialName
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.json.Json
+import kotlinx.serialization.json.JsonElement
+
+/** json is the format used to encode the request bodies and decode the response bodies. */
+internal val json = Json { ignoreUnknownKeys = true }
+{{ range .Classes }}
+/**
+ * {{ .Description }}
+{{- if .Fields }}
+ *
+{{- range .Fields }}
+ * @property {{ .Name }} {{ .Description }}
+{{- end }}
+{{- end }}
+ */
+@Serializable
+{{- if .Fields }}
+data class {{ .Name }}(
+{{- range .Fields }}
+    @SerialName({{ str .Key }}) val {{ .Name }}: {{ .Type }}{{ if not .Required }}? = null{{ end }},
+{{- end }}
+)
+{{- else }}
+class {{ .Name }}
+{{- end }}
+{{ end }}`
+
+// errorsT generates the exceptions thrown by the client.
+// template input: map[string]interface{}
+const errorsT = `// {{ .API.Context }}: Kotlin Client Errors
+//
+// Code generated by goagen, DO NOT EDIT.
+
+package {{ .Package }}
+
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.json.JsonElement
+
+/** ErrorBody is the body of the error responses produced by goa. */
+@Serializable
+data class ErrorBody(
+    val id: String? = null,
+    val code: String? = null,
+    val status: Int? = null,
+    val detail: String? = null,
+    val meta: Map<String, JsonElement>? = null,
+)
+
+/**
+ * ServiceException is thrown when the API responds with a 4xx or 5xx status.
+ *
+ * @property status the response status code.
+ * @property body the response body if any.
+ * @property error the decoded response body if it is a goa error.
+ */
+sealed class ServiceException(
+    val status: Int,
+    val body: String?,
+    val error: ErrorBody? = decodeError(body),
+) : Exception(error?.detail ?: "HTTP status $status")
+{{ range .Errors }}
+/** {{ .Name }} is thrown when the API responds with the {{ .Response }} response. */
+class {{ .Name }}(body: String?) : ServiceException({{ .Status }}, body)
+{{ end }}
+/**
+ * UnexpectedStatusException is thrown when the API responds with a 4xx or 5xx status that does not
+ * correspond to an error response of the design.
+ */
+class UnexpectedStatusException(status: Int, body: String?) : ServiceException(status, body)
+
+/** errorFor returns the exception corresponding to the given response status. */
+internal fun errorFor(status: Int, body: String?): ServiceException = when (status) {
+{{- range .ErrorsByStatus }}
+    {{ .Status }} -> {{ .Name }}(body)
+{{- end }}
+    else -> UnexpectedStatusException(status, body)
+}
+
+/** decodeError decodes body if it is a goa error. */
+private fun decodeError(body: String?): ErrorBody? =
+    body?.let { runCatching { json.decodeFromString(ErrorBody.serializer(), it) }.getOrNull() }
+`
+
+// clientT generates the API client.
+// template input: map[string]interface{}
+const clientT = `// {{ .API.Context }}: Kotlin Client
+//
+// Code generated by goagen, DO NOT EDIT.
+
+package {{ .Package }}
+
+import java.util.concurrent.TimeUnit
+import kotlinx.serialization.json.JsonElement
+import okhttp3.MediaType.Companion.toMediaType
+import okhttp3.OkHttpClient
+import retrofit2.Response
+import retrofit2.Retrofit
+import retrofit2.converter.kotlinx.serialization.asConverterFactory
+import retrofit2.http.*
+
+/** Service describes the API requests. */
+internal interface Service {
+{{- range .Methods }}
+    {{ .Annotation }}
+    suspend fun {{ .Name }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Annotation }} {{ $a.Name }}: {{ $a.Type }}{{ if not $a.Required }}?{{ end }}{{ end }}): Response<{{ .Result }}>
+{{ end -}}
+}
+
+/**
+ * Client is the {{ .API.Name }} API client.
+ *
+ * @param baseUrl the URL of the API.
+ * @param timeoutMillis the duration before the requests time out in milliseconds.
+ * @param okHttpClient the client used to make the requests, it may be configured with
+ * interceptors e.g. to set the authorization headers.
+ */
+class Client(
+    baseUrl: String = {{ str .BaseURL }},
+    timeoutMillis: Long = {{ .Timeout }},
+    okHttpClient: OkHttpClient = OkHttpClient(),
+) {
+    private val service: Service = Retrofit.Builder()
+        .baseUrl(baseUrl.trimEnd('/') + "/")
+        .client(okHttpClient.newBuilder().callTimeout(timeoutMillis, TimeUnit.MILLISECONDS).build())
+        .addConverterFactory(json.asConverterFactory("application/json".toMediaType()))
+        .build()
+        .create(Service::class.java)
+{{ range .Methods }}
+    /**
+     * {{ .Description }}
+     *
+{{- range .Args }}
+     * @param {{ .Name }} {{ .Description }}
+{{- end }}
+{{- range .Errors }}
+     * @throws {{ .Name }} if the API responds with the {{ .Response }} response.
+{{- end }}
+     * @throws ServiceException if the response status is 4xx or 5xx.
+     */
+    suspend fun {{ .Name }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Name }}: {{ $a.Type }}{{ if not $a.Required }}? = null{{ end }}{{ end }}): {{ .Result }} =
+        service.{{ .Name }}({{ range $i, $a := .Args }}{{ if $i }}, {{ end }}{{ $a.Name }}{{ end }}).result()
+{{ end -}}
+}
+
+/** result returns the response body or throws the exception corresponding to the error status. */
+private fun <T : Any> Response<T>.result(): T {
+    if (!isSuccessful) {
+        throw errorFor(code(), errorBody()?.string())
+    }
+    return body() ?: throw IllegalStateException("${raw().request.method} ${raw().request.url}: empty response body")
+}
+`
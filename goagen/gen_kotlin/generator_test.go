@@ -0,0 +1,167 @@
+package genkotlin_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_kotlin"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+	var srcDir string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("kotlintest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+		srcDir = filepath.Join(testPkg.Abs(), "kotlin", "src", "main", "kotlin", "testapi")
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genkotlin.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a resource with two actions", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+				apidsl.Host("goa.design")
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("name", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.OK, bottle)
+					apidsl.Response(design.NotFound)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the project", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(6))
+			_, err := os.Stat(filepath.Join(testPkg.Abs(), "kotlin", "build.gradle.kts"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the models", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "Models.kt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("package testapi\n"))
+			Ω(string(content)).Should(ContainSubstring("data class Bottle(\n"))
+			Ω(string(content)).Should(ContainSubstring(`    @SerialName("id") val id: Long? = null,`))
+			Ω(string(content)).Should(ContainSubstring("data class CreateBottlePayload(\n"))
+			Ω(string(content)).Should(ContainSubstring(`    @SerialName("name") val name: String,`))
+		})
+
+		It("generates the client", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "Client.kt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`    baseUrl: String = "http://goa.design",`))
+			Ω(string(content)).Should(ContainSubstring(`    @GET("/bottles/{id}")`))
+			Ω(string(content)).Should(ContainSubstring(`    suspend fun showBottle(@Path("id") id: Long): Response<Bottle>`))
+			Ω(string(content)).Should(ContainSubstring("    suspend fun showBottle(id: Long): Bottle =\n"))
+			Ω(string(content)).Should(ContainSubstring(`    @POST("/bottles")`))
+			Ω(string(content)).Should(ContainSubstring("    suspend fun createBottle(payload: CreateBottlePayload): Unit =\n"))
+		})
+
+		It("generates the errors", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(srcDir, "Errors.kt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("class NotFoundException(body: String?) : ServiceException(404, body)\n"))
+			Ω(string(content)).Should(ContainSubstring("    404 -> NotFoundException(body)\n"))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genkotlin.Generator
+
+	var args = struct {
+		api     *design.APIDefinition
+		outDir  string
+		pkg     string
+		timeout time.Duration
+		scheme  string
+		host    string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:  "out_dir",
+		pkg:     "com.example.client",
+		timeout: time.Millisecond * 500,
+		scheme:  "https",
+		host:    "localhost",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genkotlin.NewGenerator(
+				genkotlin.API(args.api),
+				genkotlin.OutDir(args.outDir),
+				genkotlin.Package(args.pkg),
+				genkotlin.Timeout(args.timeout),
+				genkotlin.Scheme(args.scheme),
+				genkotlin.Host(args.host),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Package).Should(Equal(args.pkg))
+			Ω(generator.Timeout).Should(Equal(args.timeout))
+			Ω(generator.Scheme).Should(Equal(args.scheme))
+			Ω(generator.Host).Should(Equal(args.host))
+		})
+	})
+})
@@ -0,0 +1,36 @@
+/*
+Package genlint checks that a goa API design follows a set of conventions.
+
+The generator runs the lint rules against the design and writes the findings to lint/report.txt
+in the output directory, or to lint/report.json when the --format flag is "json". The command
+fails and lists the findings with the error severity if there are any so that it can be used to
+guard continuous integration pipelines:
+
+	goagen lint -d github.com/goadesign/goa-cellar/design --config goalint.yaml --format json
+
+The rules are:
+
+  - naming: resource, action and attribute names use the casing given by the "casing" option,
+    "snake" (default), "camel" or "kebab",
+  - description: the API, resources, actions, types, media types and attributes have a
+    description,
+  - errors: actions that validate their request define a BadRequest response and secured actions
+    define an Unauthorized response,
+  - pagination: GET actions returning a collection accept one of the parameters listed by the
+    "params" option, "page,limit,offset,cursor" by default.
+
+The --config flag sets the path to a JSON or YAML file that overrides the severity ("error",
+"warning" or "off") and the options of the rules:
+
+	rules:
+	  naming:
+	    options:
+	      casing: camel
+	  description:
+	    severity: off
+	  pagination:
+	    severity: error
+	    options:
+	      params: page,per_page
+*/
+package genlint
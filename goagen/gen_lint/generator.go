@@ -0,0 +1,143 @@
+package genlint
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a design linter
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design linter. It checks the API design against the configured conventions and
+// writes the findings to a report.
+type Generator struct {
+	API        *design.APIDefinition // The API definition
+	OutDir     string                // Path to output directory
+	ConfigPath string                // Path to the lint configuration file
+	Format     string                // Format of the report: "text" (default) or "json"
+	genfiles   []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, config, format string
+	set := flag.NewFlagSet("lint", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&config, "config", "", "")
+	set.StringVar(&format, "format", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, ConfigPath: config, Format: format, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate lints the design and writes the report to lint/report.txt or lint/report.json in the
+// output directory. It returns an error listing the findings if any has the error severity, the
+// report is kept in this case so that continuous integration pipelines may process it.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	var failed bool
+	defer func() {
+		if err != nil && !failed {
+			g.Cleanup()
+		}
+	}()
+
+	format := g.Format
+	if format == "" {
+		format = "text"
+	}
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("invalid report format %q, must be text or json", format)
+	}
+	var config *Config
+	if g.ConfigPath != "" {
+		if config, err = LoadConfig(g.ConfigPath); err != nil {
+			return nil, err
+		}
+	}
+
+	findings := Lint(g.API, config)
+	var errs []string
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			errs = append(errs, "  - "+f.String())
+		}
+	}
+
+	var report []byte
+	if format == "json" {
+		if findings == nil {
+			findings = []*Finding{}
+		}
+		if report, err = json.MarshalIndent(findings, "", "  "); err != nil {
+			return nil, err
+		}
+	} else {
+		lines := make([]string, len(findings))
+		for i, f := range findings {
+			lines[i] = f.String() + "\n"
+		}
+		report = []byte(strings.Join(lines, ""))
+	}
+
+	outDir := filepath.Join(g.OutDir, "lint")
+	os.RemoveAll(outDir)
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, outDir)
+	ext := "txt"
+	if format == "json" {
+		ext = "json"
+	}
+	reportFile := filepath.Join(outDir, "report."+ext)
+	if err = ioutil.WriteFile(reportFile, report, 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, reportFile)
+
+	if len(errs) > 0 {
+		failed = true
+		return nil, fmt.Errorf("%d lint error(s), see %s:\n%s", len(errs), reportFile, strings.Join(errs, "\n"))
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
@@ -0,0 +1,131 @@
+package genlint_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+	var format string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("linttest")
+		Ω(err).ShouldNot(HaveOccurred())
+		format = "json"
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Description("The test API")
+		})
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--format=" + format, "--version=" + version.String()}
+		files, genErr = genlint.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with warnings only", func() {
+		BeforeEach(func() {
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/bottles"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+		})
+
+		It("writes the JSON report", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(files).Should(HaveLen(2))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "lint", "report.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			var findings []*genlint.Finding
+			Ω(json.Unmarshal(content, &findings)).Should(Succeed())
+			Ω(findings).Should(HaveLen(2))
+			Ω(findings[0].Rule).Should(Equal("description"))
+			Ω(findings[0].Severity).Should(Equal(genlint.SeverityWarning))
+		})
+	})
+
+	Context("with errors", func() {
+		BeforeEach(func() {
+			format = "text"
+			apidsl.Resource("bottle", func() {
+				apidsl.Description("The bottles")
+				apidsl.Action("deleteBottle", func() {
+					apidsl.Description("Delete the bottles")
+					apidsl.Routing(apidsl.DELETE("/bottles"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+		})
+
+		It("fails and keeps the report", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring(`name "deleteBottle" is not snake case`))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "lint", "report.txt"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("[naming]"))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genlint.Generator
+
+	var args = struct {
+		api        *design.APIDefinition
+		outDir     string
+		configPath string
+		format     string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:     "out_dir",
+		configPath: "goalint.yaml",
+		format:     "json",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genlint.NewGenerator(
+				genlint.API(args.api),
+				genlint.OutDir(args.outDir),
+				genlint.ConfigPath(args.configPath),
+				genlint.Format(args.format),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.ConfigPath).Should(Equal(args.configPath))
+			Ω(generator.Format).Should(Equal(args.format))
+		})
+	})
+})
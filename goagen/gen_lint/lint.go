@@ -0,0 +1,431 @@
+package genlint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"gopkg.in/yaml.v2"
+)
+
+// Severity values of the findings and of the rule configurations.
+const (
+	// SeverityError causes the lint command to fail.
+	SeverityError = "error"
+	// SeverityWarning is reported but does not cause the lint command to fail.
+	SeverityWarning = "warning"
+	// SeverityOff disables a rule.
+	SeverityOff = "off"
+)
+
+type (
+	// Config is the lint configuration, it is read from a JSON or YAML file:
+	//
+	//	rules:
+	//	  naming:
+	//	    severity: error
+	//	    options:
+	//	      casing: camel
+	//	  description:
+	//	    severity: off
+	//
+	// The rules that are not listed use their default configuration.
+	Config struct {
+		// Rules maps rule names to their configuration.
+		Rules map[string]*RuleConfig `json:"rules" yaml:"rules"`
+	}
+
+	// RuleConfig configures a lint rule.
+	RuleConfig struct {
+		// Severity is one of "error", "warning" or "off".
+		Severity string `json:"severity" yaml:"severity"`
+		// Options are the rule specific settings.
+		Options map[string]string `json:"options,omitempty" yaml:"options,omitempty"`
+	}
+
+	// Finding is a design convention violation.
+	Finding struct {
+		// Rule is the name of the violated rule.
+		Rule string `json:"rule"`
+		// Severity is "error" or "warning".
+		Severity string `json:"severity"`
+		// Location describes the offending design definition.
+		Location string `json:"location"`
+		// Message describes the violation.
+		Message string `json:"message"`
+	}
+
+	// Rule checks a design convention.
+	Rule struct {
+		// Name is the name used in the configuration and in the findings.
+		Name string
+		// Description describes the convention enforced by the rule.
+		Description string
+		// Severity is the default severity of the rule findings.
+		Severity string
+		// Options are the default rule options.
+		Options map[string]string
+		// Check returns the locations and messages of the violations found in api.
+		Check func(api *design.APIDefinition, options map[string]string) []*Finding
+	}
+)
+
+// Rules lists the lint rules in the order they run.
+var Rules = []*Rule{
+	{
+		Name:        "naming",
+		Description: `resource, action and attribute names use the casing given by the "casing" option: "snake" (default), "camel" or "kebab"`,
+		Severity:    SeverityError,
+		Options:     map[string]string{"casing": "snake"},
+		Check:       checkNaming,
+	},
+	{
+		Name:        "description",
+		Description: "the API, resources, actions, types, media types and attributes have a description",
+		Severity:    SeverityWarning,
+		Check:       checkDescription,
+	},
+	{
+		Name:        "errors",
+		Description: "actions that validate their request define a BadRequest response and secured actions define an Unauthorized response",
+		Severity:    SeverityError,
+		Check:       checkErrors,
+	},
+	{
+		Name:        "pagination",
+		Description: `GET actions returning a collection accept one of the parameters listed by the "params" option (default "page,limit,offset,cursor")`,
+		Severity:    SeverityWarning,
+		Options:     map[string]string{"params": "page,limit,offset,cursor"},
+		Check:       checkPagination,
+	},
+}
+
+var casings = map[string]*regexp.Regexp{
+	"snake": regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+	"camel": regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"kebab": regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`),
+}
+
+// LoadConfig reads the lint configuration from the given JSON or YAML file.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid lint configuration %s: %s", path, err)
+	}
+	return &c, c.Validate()
+}
+
+// Validate checks that the configuration refers to existing rules and uses valid severities.
+func (c *Config) Validate() error {
+	for name, rc := range c.Rules {
+		r := findRule(name)
+		if r == nil {
+			return fmt.Errorf("unknown lint rule %q", name)
+		}
+		if rc == nil {
+			continue
+		}
+		switch rc.Severity {
+		case "", SeverityError, SeverityWarning, SeverityOff:
+		default:
+			return fmt.Errorf("rule %s: invalid severity %q, must be one of error, warning or off", name, rc.Severity)
+		}
+		if casing, ok := rc.Options["casing"]; ok && r.Name == "naming" {
+			if _, ok := casings[casing]; !ok {
+				return fmt.Errorf("rule naming: invalid casing %q, must be one of snake, camel or kebab", casing)
+			}
+		}
+	}
+	return nil
+}
+
+// Lint runs the rules enabled by c against api and returns the findings sorted by location. c may
+// be nil in which case all the rules run with their default configuration.
+func Lint(api *design.APIDefinition, c *Config) []*Finding {
+	var findings []*Finding
+	for _, r := range Rules {
+		severity := r.Severity
+		options := make(map[string]string, len(r.Options))
+		for k, v := range r.Options {
+			options[k] = v
+		}
+		if c != nil {
+			if rc := c.Rules[r.Name]; rc != nil {
+				if rc.Severity != "" {
+					severity = rc.Severity
+				}
+				for k, v := range rc.Options {
+					options[k] = v
+				}
+			}
+		}
+		if severity == SeverityOff {
+			continue
+		}
+		for _, f := range r.Check(api, options) {
+			f.Rule = r.Name
+			f.Severity = severity
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Location < findings[j].Location
+	})
+	return findings
+}
+
+// String returns the text representation of the finding.
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Severity, f.Location, f.Message, f.Rule)
+}
+
+// findRule returns the rule with the given name, nil if there is none.
+func findRule(name string) *Rule {
+	for _, r := range Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// checkNaming implements the "naming" rule.
+func checkNaming(api *design.APIDefinition, options map[string]string) []*Finding {
+	casing := options["casing"]
+	re, ok := casings[casing]
+	if !ok {
+		return nil
+	}
+	var findings []*Finding
+	check := func(loc, name string) {
+		if !re.MatchString(name) {
+			findings = append(findings, &Finding{
+				Location: loc,
+				Message:  fmt.Sprintf("name %q is not %s case", name, casing),
+			})
+		}
+	}
+	walkDesign(api, func(loc string, def interface{}) {
+		switch d := def.(type) {
+		case *design.ResourceDefinition:
+			check(loc, d.Name)
+		case *design.ActionDefinition:
+			check(loc, d.Name)
+		case *attribute:
+			check(loc, d.name)
+		}
+	})
+	return findings
+}
+
+// checkDescription implements the "description" rule.
+func checkDescription(api *design.APIDefinition, _ map[string]string) []*Finding {
+	var findings []*Finding
+	check := func(loc, desc string) {
+		if strings.TrimSpace(desc) == "" {
+			findings = append(findings, &Finding{Location: loc, Message: "missing description"})
+		}
+	}
+	check("API "+api.Name, api.Description)
+	walkDesign(api, func(loc string, def interface{}) {
+		switch d := def.(type) {
+		case *design.ResourceDefinition:
+			check(loc, d.Description)
+		case *design.ActionDefinition:
+			check(loc, d.Description)
+		case *design.UserTypeDefinition:
+			check(loc, d.Description)
+		case *design.MediaTypeDefinition:
+			check(loc, d.Description)
+		case *attribute:
+			check(loc, d.Description)
+		}
+	})
+	return findings
+}
+
+// checkErrors implements the "errors" rule.
+func checkErrors(api *design.APIDefinition, _ map[string]string) []*Finding {
+	var findings []*Finding
+	walkDesign(api, func(loc string, def interface{}) {
+		a, ok := def.(*design.ActionDefinition)
+		if !ok {
+			return
+		}
+		if validatesRequest(a) && !hasStatus(a, 400) {
+			findings = append(findings, &Finding{
+				Location: loc,
+				Message:  "the request may fail validation but no BadRequest response is defined",
+			})
+		}
+		if a.Security != nil && !hasStatus(a, 401) {
+			findings = append(findings, &Finding{
+				Location: loc,
+				Message:  "the action is secured but no Unauthorized response is defined",
+			})
+		}
+	})
+	return findings
+}
+
+// checkPagination implements the "pagination" rule.
+func checkPagination(api *design.APIDefinition, options map[string]string) []*Finding {
+	var names []string
+	for _, n := range strings.Split(options["params"], ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	var findings []*Finding
+	walkDesign(api, func(loc string, def interface{}) {
+		a, ok := def.(*design.ActionDefinition)
+		if !ok || !isGet(a) || !returnsCollection(api, a) {
+			return
+		}
+		params := a.AllParams()
+		for _, n := range names {
+			if params != nil {
+				if o := params.Type.ToObject(); o != nil && o[n] != nil {
+					return
+				}
+			}
+		}
+		findings = append(findings, &Finding{
+			Location: loc,
+			Message:  fmt.Sprintf("the action returns a collection but accepts none of the pagination parameters %s", strings.Join(names, ", ")),
+		})
+	})
+	return findings
+}
+
+// validatesRequest returns true if the request of a may fail validation: a has a payload, a
+// parameter that is not a string or a parameter with validations.
+func validatesRequest(a *design.ActionDefinition) bool {
+	if a.Payload != nil {
+		return true
+	}
+	params := a.AllParams()
+	if params == nil {
+		return false
+	}
+	o := params.Type.ToObject()
+	for _, p := range o {
+		if p.Type.Kind() != design.StringKind || p.Validation != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStatus returns true if a defines a response with the given status.
+func hasStatus(a *design.ActionDefinition, status int) bool {
+	for _, r := range a.Responses {
+		if r.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// isGet returns true if a has a GET route.
+func isGet(a *design.ActionDefinition) bool {
+	for _, r := range a.Routes {
+		if r.Verb == "GET" {
+			return true
+		}
+	}
+	return false
+}
+
+// returnsCollection returns true if a success response of a is an array.
+func returnsCollection(api *design.APIDefinition, a *design.ActionDefinition) bool {
+	for _, r := range a.Responses {
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		if r.Type != nil && r.Type.IsArray() {
+			return true
+		}
+		if mt := api.MediaTypeWithIdentifier(r.MediaType); mt != nil && mt.IsArray() {
+			return true
+		}
+	}
+	return false
+}
+
+// attribute is a named attribute visited by walkDesign.
+type attribute struct {
+	*design.AttributeDefinition
+	name string
+}
+
+// walkDesign calls visit with the resources, actions, user types, media types and attributes of
+// api and a description of their location. The attributes of the user types are visited with the
+// type definitions, the attributes whose type is a user type are not visited again.
+func walkDesign(api *design.APIDefinition, visit func(loc string, def interface{})) {
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		rloc := "resource " + r.Name
+		visit(rloc, r)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			aloc := rloc + " action " + a.Name
+			visit(aloc, a)
+			if a.Params != nil {
+				walkAttribute(aloc+" param", "", a.Params, visit)
+			}
+			if a.Payload != nil && api.Types[a.Payload.TypeName] == nil {
+				walkAttribute(aloc+" payload", "", a.Payload.AttributeDefinition, visit)
+			}
+			return nil
+		})
+	})
+	api.IterateUserTypes(func(u *design.UserTypeDefinition) error {
+		loc := "type " + u.TypeName
+		visit(loc, u)
+		walkAttribute(loc, "", u.AttributeDefinition, visit)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.Identifier == design.ErrorMediaIdentifier || mt.IsArray() {
+			return nil
+		}
+		loc := "media type " + mt.Identifier
+		visit(loc, mt)
+		walkAttribute(loc, "", mt.AttributeDefinition, visit)
+		return nil
+	})
+}
+
+// walkAttribute visits the attributes of the inline objects defined by att. path is the
+// attribute path of att relative to the definition located at loc.
+func walkAttribute(loc, path string, att *design.AttributeDefinition, visit func(loc string, def interface{})) {
+	if att == nil {
+		return
+	}
+	switch t := att.Type.(type) {
+	case design.Object:
+		names := make([]string, 0, len(t))
+		for n := range t {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			cpath := n
+			if path != "" {
+				cpath = path + "." + n
+			}
+			visit(loc+" "+cpath, &attribute{AttributeDefinition: t[n], name: n})
+			walkAttribute(loc, cpath, t[n], visit)
+		}
+	case *design.Array:
+		walkAttribute(loc, path+"[]", t.ElemType, visit)
+	case *design.Hash:
+		walkAttribute(loc, path+"{}", t.ElemType, visit)
+	}
+}
@@ -0,0 +1,191 @@
+package genlint_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	var config *genlint.Config
+	var findings []string
+
+	BeforeEach(func() {
+		config = &genlint.Config{Rules: map[string]*genlint.RuleConfig{
+			"description": {Severity: genlint.SeverityOff},
+		}}
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			apidsl.Description("The test API")
+		})
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		findings = nil
+		for _, f := range genlint.Lint(design.Design, config) {
+			findings = append(findings, f.String())
+		}
+	})
+
+	Context("with a design following the conventions", func() {
+		BeforeEach(func() {
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("vintage_year", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET("/bottles"))
+					apidsl.Params(func() {
+						apidsl.Param("limit", design.Integer)
+					})
+					apidsl.Response(design.OK, apidsl.CollectionOf(bottle))
+					apidsl.Response(design.BadRequest)
+				})
+			})
+		})
+
+		It("reports nothing", func() {
+			Ω(findings).Should(BeEmpty())
+		})
+	})
+
+	Context("with badly named definitions", func() {
+		BeforeEach(func() {
+			apidsl.Type("Bottle", func() {
+				apidsl.Attribute("vintageYear", design.Integer)
+				apidsl.Attribute("origin", func() {
+					apidsl.Attribute("CountryCode", design.String)
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("showBottle", func() {
+					apidsl.Routing(apidsl.GET("/bottles"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+		})
+
+		It("reports the names", func() {
+			Ω(findings).Should(ConsistOf(
+				`error: resource bottle action showBottle: name "showBottle" is not snake case [naming]`,
+				`error: type Bottle origin.CountryCode: name "CountryCode" is not snake case [naming]`,
+				`error: type Bottle vintageYear: name "vintageYear" is not snake case [naming]`,
+			))
+		})
+
+		Context("using the camel casing", func() {
+			BeforeEach(func() {
+				config.Rules["naming"] = &genlint.RuleConfig{Options: map[string]string{"casing": "camel"}}
+			})
+
+			It("reports the names that are not camel case", func() {
+				Ω(findings).Should(ConsistOf(
+					`error: type Bottle origin.CountryCode: name "CountryCode" is not camel case [naming]`,
+				))
+			})
+		})
+	})
+
+	Context("with missing descriptions", func() {
+		BeforeEach(func() {
+			delete(config.Rules, "description")
+			apidsl.Resource("bottle", func() {
+				apidsl.Description("The bottles")
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/bottles"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+		})
+
+		It("reports warnings", func() {
+			Ω(findings).Should(ConsistOf(
+				`warning: resource bottle action delete: missing description [description]`,
+			))
+		})
+	})
+
+	Context("with unmapped errors", func() {
+		BeforeEach(func() {
+			basic := apidsl.BasicAuthSecurity("basic")
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("create", func() {
+					apidsl.Security(basic)
+					apidsl.Routing(apidsl.POST("/bottles"))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+					})
+					apidsl.Response(design.Created)
+				})
+			})
+		})
+
+		It("reports the missing responses", func() {
+			Ω(findings).Should(ConsistOf(
+				`error: resource bottle action create: the request may fail validation but no BadRequest response is defined [errors]`,
+				`error: resource bottle action create: the action is secured but no Unauthorized response is defined [errors]`,
+			))
+		})
+	})
+
+	Context("with a collection that is not paginated", func() {
+		BeforeEach(func() {
+			config.Rules["pagination"] = &genlint.RuleConfig{
+				Severity: genlint.SeverityError,
+				Options:  map[string]string{"params": "page, per_page"},
+			}
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET("/bottles"))
+					apidsl.Params(func() {
+						apidsl.Param("limit", design.Integer)
+					})
+					apidsl.Response(design.OK, apidsl.CollectionOf(bottle))
+					apidsl.Response(design.BadRequest)
+				})
+			})
+		})
+
+		It("reports the action", func() {
+			Ω(findings).Should(ConsistOf(
+				`error: resource bottle action list: the action returns a collection but accepts none of the pagination parameters page, per_page [pagination]`,
+			))
+		})
+	})
+})
+
+var _ = Describe("Config", func() {
+	It("rejects unknown rules", func() {
+		c := &genlint.Config{Rules: map[string]*genlint.RuleConfig{"foo": {}}}
+		Ω(c.Validate()).Should(MatchError(`unknown lint rule "foo"`))
+	})
+
+	It("rejects invalid severities", func() {
+		c := &genlint.Config{Rules: map[string]*genlint.RuleConfig{"naming": {Severity: "fatal"}}}
+		Ω(c.Validate()).Should(HaveOccurred())
+	})
+
+	It("rejects invalid casings", func() {
+		c := &genlint.Config{Rules: map[string]*genlint.RuleConfig{"naming": {Options: map[string]string{"casing": "pascal"}}}}
+		Ω(c.Validate()).Should(HaveOccurred())
+	})
+})
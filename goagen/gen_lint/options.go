@@ -0,0 +1,34 @@
+package genlint
+
+import "github.com/goadesign/goa/design"
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+//ConfigPath Path to the lint configuration file
+func ConfigPath(configPath string) Option {
+	return func(g *Generator) {
+		g.ConfigPath = configPath
+	}
+}
+
+//Format Format of the lint report, "text" or "json"
+func Format(format string) Option {
+	return func(g *Generator) {
+		g.Format = format
+	}
+}
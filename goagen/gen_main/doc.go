@@ -5,5 +5,21 @@ bootstrap new applications.
 The generator creates a main.go file and one file per resource listed in the API metadata.
 If a file already exists it skips its creation unless the flag --force is provided on the command
 line in which case it overrides the content of existing files.
+With the flag --merge the generator regenerates the existing files and re-applies the changes made
+to them since they were last generated: the generated version of each file is recorded in the
+".goagen" directory next to it and a three-way merge combines the edited file, its recorded version
+and the new version. Conflicting changes are delimited with git style conflict markers and cause
+the command to fail. Files created without --merge are left untouched, use --merge --force once to
+start tracking them.
+With the flag --k8s the generator also creates Kubernetes manifests under the "k8s" directory: a
+Deployment whose liveness and readiness probes use the /healthz endpoint mounted by the generated
+main, a Service exposing the Deployment and a ConfigMap holding the values of the main command
+line flags.
+With the flag --docker the generator also creates a multi-stage Dockerfile that builds the main
+package and a docker-compose.yaml file that runs the resulting image with the service port exposed,
+so that "docker-compose up" builds and starts the service.
+With the flag --debug-listener the generated main accepts a --debug-addr flag that starts a separate listener
+serving the net/http/pprof endpoints and the expvar variables, including the goa decode, encode and
+validation failure counters. The listener is disabled unless --debug-addr is set.
 */
 package genmain
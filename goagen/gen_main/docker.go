@@ -0,0 +1,73 @@
+package genmain
+
+import (
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateDocker generates the Dockerfile that builds the service image and the docker-compose file
+// that runs it. Existing files are left untouched unless Force or Merge is true.
+func (g *Generator) generateDocker() error {
+	pkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return err
+	}
+	tls, mtls := tlsSchemes(g.API)
+	data := map[string]interface{}{
+		"Name":      k8sName(g.API.Name),
+		"Package":   pkg,
+		"Port":      getPort(g.API.Host),
+		"TLS":       tls || mtls,
+		"MutualTLS": mtls,
+	}
+	files := []struct{ name, tmpl string }{
+		{"Dockerfile", dockerfileT},
+		{"docker-compose.yaml", composeT},
+	}
+	for _, f := range files {
+		filename := filepath.Join(g.OutDir, f.name)
+		err := g.scaffold(filename, func() error { return g.writeManifest(filename, f.tmpl, data) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const dockerfileT = `# Build stage: compile a static binary of the service.
+FROM golang:1.10-alpine AS build
+RUN apk add --no-cache git
+WORKDIR /go/src/{{ .Package }}
+COPY . .
+RUN go get -d -v ./... && CGO_ENABLED=0 go build -o /go/bin/{{ .Name }} .
+
+# Run stage: copy the binary in a minimal image.
+FROM alpine:3.8
+RUN apk add --no-cache ca-certificates
+{{- if .TLS }}
+# The service reads its certificates from the working directory.
+{{- end }}
+WORKDIR /app
+COPY --from=build /go/bin/{{ .Name }} /usr/local/bin/{{ .Name }}
+EXPOSE {{ .Port }}
+ENTRYPOINT ["/usr/local/bin/{{ .Name }}"]
+`
+
+const composeT = `# Build and start the service with "docker-compose up --build".
+version: "3"
+services:
+  {{ .Name }}:
+    build: .
+    image: {{ .Name }}:latest
+    ports:
+      - "{{ .Port }}:{{ .Port }}"
+{{- if .TLS }}
+    volumes:
+{{- if .MutualTLS }}
+      - ./ca.pem:/app/ca.pem:ro
+{{- end }}
+      - ./cert.pem:/app/cert.pem:ro
+      - ./key.pem:/app/key.pem:ro
+{{- end }}
+`
@@ -33,20 +33,25 @@ func NewGenerator(options ...Option) *Generator {
 
 // Generator is the application code generator.
 type Generator struct {
-	API       *design.APIDefinition // The API definition
-	OutDir    string                // Path to output directory
-	DesignPkg string                // Path to design package, only used to mark generated files.
-	Target    string                // Name of generated "app" package
-	Force     bool                  // Whether to override existing files
-	Regen     bool                  // Whether to regenerate scaffolding in place, maintaining controller implementation
-	genfiles  []string              // Generated files
+	API           *design.APIDefinition // The API definition
+	OutDir        string                // Path to output directory
+	DesignPkg     string                // Path to design package, only used to mark generated files.
+	Target        string                // Name of generated "app" package
+	Force         bool                  // Whether to override existing files
+	Regen         bool                  // Whether to regenerate scaffolding in place, maintaining controller implementation
+	Merge         bool                  // Whether to merge the changes made to the existing files with the regenerated scaffolding
+	K8s           bool                  // Whether to generate Kubernetes manifests
+	Docker        bool                  // Whether to generate a Dockerfile and a docker-compose file
+	DebugListener bool                  // Whether to generate a debug listener serving the pprof and expvar endpoints
+	genfiles      []string              // Generated files
+	conflicts     []string              // Merged files that contain conflicts
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
-		outDir, toolDir, designPkg, target, ver string
-		force, notool, regen                    bool
+		outDir, toolDir, designPkg, target, ver                 string
+		force, notool, regen, merge, k8s, docker, debugListener bool
 	)
 
 	set := flag.NewFlagSet("main", flag.PanicOnError)
@@ -58,6 +63,10 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&notool, "notool", false, "")
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
+	set.BoolVar(&merge, "merge", false, "")
+	set.BoolVar(&k8s, "k8s", false, "")
+	set.BoolVar(&docker, "docker", false, "")
+	set.BoolVar(&debugListener, "debug-listener", false, "")
 	set.Bool("notest", false, "")
 	set.Parse(os.Args[1:])
 
@@ -66,7 +75,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, Regen: regen, API: design.Design}
+	g := &Generator{OutDir: outDir, DesignPkg: designPkg, Target: target, Force: force, Regen: regen, Merge: merge, K8s: k8s, Docker: docker, DebugListener: debugListener, API: design.Design}
 
 	return g.Generate()
 }
@@ -117,9 +126,27 @@ func extractControllerBody(filename string) (map[string]string, []*ast.ImportSpe
 }
 
 // GenerateController generates the controller corresponding to the given
-// resource and returns the generated filename.
-func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *design.ResourceDefinition) (filename string, err error) {
+// resource and returns the generated filename. If merge is true the changes
+// made to an existing controller file since it was last generated are merged
+// with the new scaffold, see codegen.MergeScaffold.
+func GenerateController(force, regen, merge bool, appPkg, outDir, pkg, name string, r *design.ResourceDefinition) (filename string, err error) {
 	filename = filepath.Join(outDir, codegen.SnakeCase(name)+".go")
+	if merge {
+		if force {
+			os.Remove(filename)
+		}
+		if err = os.MkdirAll(outDir, 0755); err != nil {
+			return "", err
+		}
+		var generated bool
+		generated, err = codegen.MergeScaffold(filename, func() error {
+			return writeController(filename, appPkg, outDir, pkg, r, nil, nil)
+		})
+		if !generated {
+			filename = ""
+		}
+		return
+	}
 	var (
 		actionImpls      map[string]string
 		extractedImports []*ast.ImportSpec
@@ -140,11 +167,20 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 	if err = os.MkdirAll(outDir, 0755); err != nil {
 		return "", err
 	}
+	if err = writeController(filename, appPkg, outDir, pkg, r, actionImpls, extractedImports); err != nil {
+		return "", err
+	}
+	return
+}
 
+// writeController writes the controller scaffold of the given resource to filename. actionImpls
+// and extractedImports are the action implementations and imports extracted from the previous
+// version of the file in regen mode.
+func writeController(filename, appPkg, outDir, pkg string, r *design.ResourceDefinition, actionImpls map[string]string, extractedImports []*ast.ImportSpec) (err error) {
 	var file *codegen.SourceFile
 	file, err = codegen.SourceFileFor(filename)
 	if err != nil {
-		return "", err
+		return err
 	}
 	defer func() {
 		file.Close()
@@ -161,7 +197,7 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 	} else {
 		imp, err = codegen.PackagePath(outDir)
 		if err != nil {
-			return "", err
+			return err
 		}
 		imp = path.Join(filepath.ToSlash(imp), appPkg)
 	}
@@ -187,10 +223,10 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 
 	funcs := funcMap(pkgName, actionImpls)
 	if err = file.WriteHeader("", pkg, imports); err != nil {
-		return "", err
+		return err
 	}
 	if err = file.ExecuteTemplate("controller", ctrlT, funcs, r); err != nil {
-		return "", err
+		return err
 	}
 	err = r.IterateActions(func(a *design.ActionDefinition) error {
 		if a.WebSocket() {
@@ -198,10 +234,7 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 		}
 		return file.ExecuteTemplate("action", actionT, funcs, a)
 	})
-	if err != nil {
-		return "", err
-	}
-	return
+	return err
 }
 
 // Generate produces the skeleton main.
@@ -213,7 +246,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 	go utils.Catch(nil, func() { g.Cleanup() })
 
 	defer func() {
-		if err != nil {
+		if err != nil && len(g.conflicts) == 0 {
 			g.Cleanup()
 		}
 	}()
@@ -222,25 +255,30 @@ func (g *Generator) Generate() (_ []string, err error) {
 		g.Target = "app"
 	}
 
+	// The app package may be generated in another directory, see codegen.OutputDir.
+	appDir := filepath.ToSlash(codegen.OutputDir(g.API, "app", g.Target))
+	g.Target = path.Base(appDir)
+
 	codegen.Reserved[g.Target] = true
 
-	mainFile := filepath.Join(g.OutDir, "main.go")
-	if g.Force {
-		os.Remove(mainFile)
+	// ensure that the output directory exists before creating a new main
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return nil, err
 	}
-	_, err = os.Stat(mainFile)
+	mainFile := filepath.Join(g.OutDir, "main.go")
+	err = g.scaffold(mainFile, func() error {
+		return g.createMainFile(mainFile, appDir, funcMap(g.Target, nil))
+	})
 	if err != nil {
-		// ensure that the output directory exists before creating a new main
-		if err = os.MkdirAll(g.OutDir, 0755); err != nil {
-			return nil, err
-		}
-		if err = g.createMainFile(mainFile, funcMap(g.Target, nil)); err != nil {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
-		filename, err := GenerateController(g.Force, g.Regen, g.Target, g.OutDir, "main", r.Name, r)
+		filename, err := GenerateController(g.Force, g.Regen, g.Merge, appDir, g.OutDir, "main", r.Name, r)
+		if cerr, ok := err.(*codegen.MergeConflictError); ok {
+			g.conflicts = append(g.conflicts, cerr.Filename)
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -252,9 +290,46 @@ func (g *Generator) Generate() (_ []string, err error) {
 		return
 	}
 
+	if g.K8s {
+		if err = g.generateK8s(); err != nil {
+			return
+		}
+	}
+
+	if g.Docker {
+		if err = g.generateDocker(); err != nil {
+			return
+		}
+	}
+
+	if len(g.conflicts) > 0 {
+		return nil, fmt.Errorf("merge conflicts in %s, resolve the conflict markers", strings.Join(g.conflicts, ", "))
+	}
+
 	return g.genfiles, nil
 }
 
+// scaffold generates the scaffold file filename with gen. Existing files are left untouched unless
+// Force is true. If Merge is true the changes made to an existing file since it was last generated
+// are merged with the new version and the files containing conflicts are recorded.
+func (g *Generator) scaffold(filename string, gen func() error) error {
+	if g.Force {
+		os.Remove(filename)
+	}
+	if g.Merge {
+		_, err := codegen.MergeScaffold(filename, gen)
+		if cerr, ok := err.(*codegen.MergeConflictError); ok {
+			g.conflicts = append(g.conflicts, cerr.Filename)
+			return nil
+		}
+		return err
+	}
+	if _, err := os.Stat(filename); err == nil {
+		return nil
+	}
+	return gen()
+}
+
 // Cleanup removes all the files generated by this generator during the last invokation of Generate.
 func (g *Generator) Cleanup() {
 	for _, f := range g.genfiles {
@@ -263,7 +338,7 @@ func (g *Generator) Cleanup() {
 	g.genfiles = nil
 }
 
-func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err error) {
+func (g *Generator) createMainFile(mainFile, appDir string, funcs template.FuncMap) (err error) {
 	var file *codegen.SourceFile
 	file, err = codegen.SourceFileFor(mainFile)
 	if err != nil {
@@ -276,19 +351,21 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err
 		}
 	}()
 	g.genfiles = append(g.genfiles, mainFile)
-	funcs["getPort"] = func(hostport string) string {
-		_, port, err := net.SplitHostPort(hostport)
-		if err != nil {
-			return "8080"
-		}
-		return port
-	}
+	funcs["getPort"] = getPort
 	outPkg, err := codegen.PackagePath(g.OutDir)
 	if err != nil {
 		return err
 	}
-	appPkg := path.Join(outPkg, "app")
+	appPkg := path.Join(outPkg, appDir)
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("crypto/tls"),
+		codegen.SimpleImport("crypto/x509"),
+		codegen.SimpleImport("expvar"),
+		codegen.SimpleImport("flag"),
+		codegen.SimpleImport("io/ioutil"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/pprof"),
+		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
@@ -298,31 +375,44 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) (err
 	if err = file.WriteHeader("", "main", imports); err != nil {
 		return err
 	}
-	tls := false
-	for _, scheme := range g.API.Schemes {
-		if scheme == "https" {
-			tls = true
-		}
-	}
+	tls, mtls := tlsSchemes(g.API)
 	data := map[string]interface{}{
-		"Name": g.API.Name,
-		"API":  g.API,
-		"TLS":  tls,
+		"Name":          g.API.Name,
+		"API":           g.API,
+		"TLS":           tls,
+		"MutualTLS":     mtls,
+		"K8s":           g.K8s,
+		"DebugListener": g.DebugListener,
+		"HealthPath":    healthPath,
+		"MountHealth":   g.K8s && !hasRoute(g.API, "GET", healthPath),
 	}
 	err = file.ExecuteTemplate("main", mainT, funcs, data)
 	return
 }
 
-// tempCount is the counter used to create unique temporary variable names.
-var tempCount int
+// getPort returns the port of the given host, "8080" if it does not specify one.
+func getPort(hostport string) string {
+	_, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return "8080"
+	}
+	return port
+}
 
-// tempvar generates a unique temp var name.
-func tempvar() string {
-	tempCount++
-	if tempCount == 1 {
-		return "c"
+// tlsSchemes returns whether the API is served over TLS and whether it requires client
+// certificates.
+func tlsSchemes(api *design.APIDefinition) (tls, mtls bool) {
+	for _, scheme := range api.Schemes {
+		if scheme == "https" {
+			tls = true
+		}
+	}
+	for _, scheme := range api.SecuritySchemes {
+		if scheme.Kind == design.MutualTLSSecurityKind {
+			mtls = true
+		}
 	}
-	return fmt.Sprintf("c%d", tempCount)
+	return
 }
 
 func okResp(a *design.ActionDefinition, appPkg string) map[string]interface{} {
@@ -379,7 +469,6 @@ func okResp(a *design.ActionDefinition, appPkg string) map[string]interface{} {
 // funcMap creates the funcMap used to render the controller code.
 func funcMap(appPkg string, actionImpls map[string]string) template.FuncMap {
 	return template.FuncMap{
-		"tempvar":   tempvar,
 		"okResp":    okResp,
 		"targetPkg": func() string { return appPkg },
 		"actionBody": func(name string) string {
@@ -450,7 +539,23 @@ func (c *{{ $ctrlName }}) {{ goify .Name true }}WSHandler(ctx *{{ targetPkg }}.{
 	}
 }`
 
-const mainT = `
+const mainT = `{{ define "debug" }}
+	// Start the debug listener on its own address so the profiling endpoints are not exposed with the API
+	if *debugAddr != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		mux.Handle("/debug/vars", expvar.Handler())
+		go func() {
+			if err := http.ListenAndServe(*debugAddr, mux); err != nil {
+				service.LogError("debug", "err", err)
+			}
+		}()
+	}
+{{ end }}
 func main() {
 	// Create service
 	service := goa.New({{ printf "%q" .Name }})
@@ -460,20 +565,59 @@ func main() {
 	service.Use(middleware.LogRequest(true))
 	service.Use(middleware.ErrorHandler(service, true))
 	service.Use(middleware.Recover())
-{{ $api := .API }}
-{{ range $name, $res := $api.Resources }}{{ $name := goify $res.Name true }} // Mount "{{$res.Name}}" controller
-	{{ $tmp := tempvar }}{{ $tmp }} := New{{ $name }}Controller(service)
-	{{ targetPkg }}.Mount{{ $name }}Controller(service, {{ $tmp }})
+{{ if .API.Resources }}	service.Use({{ targetPkg }}.DesignVersionMiddleware())
+
+	// Mount controllers
+	{{ targetPkg }}.MountAll(service, &{{ targetPkg }}.Controllers{
+{{ range $name, $res := .API.Resources }}{{ $name := goify $res.Name true }}		{{ $name }}: New{{ $name }}Controller(service),
+{{ end }}	})
 {{ end }}
+{{ if .MountHealth }}
+	// Mount health check endpoint used by the Kubernetes probes
+	service.Mux.Handle("GET", {{ printf "%q" .HealthPath }}, func(rw http.ResponseWriter, _ *http.Request, _ url.Values) {
+		rw.WriteHeader(http.StatusOK)
+	})
+{{ end }}{{ if .K8s }}
+	// Listen address, set by the Kubernetes manifests from the service ConfigMap
+	addr := flag.String("addr", ":{{ getPort .API.Host }}", "The address the service listens on")
+{{ end }}{{ if .DebugListener }}
+	// Debug listener address, the listener is disabled unless set
+	debugAddr := flag.String("debug-addr", "", "The address of the listener serving the pprof and expvar endpoints, disabled if empty")
+{{ end }}{{ if and (or .K8s .DebugListener) (not .MutualTLS) }}	flag.Parse()
+{{ if .DebugListener }}{{ template "debug" }}{{ end }}{{ end }}
+{{- if .MutualTLS }}
+	// Verify client certificates
+	var (
+		caCert = flag.String("ca-cert", "ca.pem", "Path to the PEM encoded certificates of the CAs used to verify client certificates")
+		cert   = flag.String("cert", "cert.pem", "Path to the PEM encoded server certificate")
+		key    = flag.String("key", "key.pem", "Path to the PEM encoded server private key")
+	)
+	flag.Parse()
+{{ if .DebugListener }}{{ template "debug" }}
+{{ end }}	pem, err := ioutil.ReadFile(*caCert)
+	if err != nil {
+		service.LogError("startup", "err", err)
+		return
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		service.LogError("startup", "err", "no certificate found in "+*caCert)
+		return
+	}
+	service.Server.TLSConfig = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
 
-{{ if .TLS }}
 	// Start service
-	if err := service.ListenAndServeTLS(":{{ getPort .API.Host }}", "cert.pem", "key.pem"); err != nil {
+	if err := service.ListenAndServeTLS({{ if .K8s }}*addr{{ else }}":{{ getPort .API.Host }}"{{ end }}, *cert, *key); err != nil {
+		service.LogError("startup", "err", err)
+	}
+{{ else if .TLS }}
+	// Start service
+	if err := service.ListenAndServeTLS({{ if .K8s }}*addr{{ else }}":{{ getPort .API.Host }}"{{ end }}, "cert.pem", "key.pem"); err != nil {
 		service.LogError("startup", "err", err)
 	}
 {{ else }}
 	// Start service
-	if err := service.ListenAndServe(":{{ getPort .API.Host }}"); err != nil {
+	if err := service.ListenAndServe({{ if .K8s }}*addr{{ else }}":{{ getPort .API.Host }}"{{ end }}); err != nil {
 		service.LogError("startup", "err", err)
 	}
 {{ end }}
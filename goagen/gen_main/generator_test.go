@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/gen_main"
 	"github.com/goadesign/goa/version"
 	. "github.com/onsi/ginkgo"
@@ -75,6 +76,98 @@ var _ = Describe("Generate", func() {
 			})
 
 		})
+
+		Context("with a mutual TLS security scheme", func() {
+			BeforeEach(func() {
+				design.Design.SecuritySchemes = []*design.SecuritySchemeDefinition{{
+					Kind:       design.MutualTLSSecurityKind,
+					SchemeName: "mtls",
+					Type:       "mutualTLS",
+				}}
+			})
+
+			It("generates an app verifying client certificates", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`caCert = flag.String("ca-cert", "ca.pem",`))
+				Ω(string(content)).Should(ContainSubstring("service.Server.TLSConfig = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}"))
+				Ω(string(content)).Should(ContainSubstring(`service.ListenAndServeTLS(":8080", *cert, *key)`))
+				_, err = gexec.Build(testgenPackagePath)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with Kubernetes manifests", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--k8s")
+			})
+
+			It("generates the manifests and mounts the health check endpoint", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(Equal([]string{
+					filepath.Join(outDir, "main.go"),
+					filepath.Join(outDir, "k8s", "configmap.yaml"),
+					filepath.Join(outDir, "k8s", "deployment.yaml"),
+					filepath.Join(outDir, "k8s", "service.yaml"),
+				}))
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`service.Mux.Handle("GET", "/healthz", func(rw http.ResponseWriter, _ *http.Request, _ url.Values) {`))
+				Ω(string(content)).Should(ContainSubstring(`addr := flag.String("addr", ":8080", "The address the service listens on")`))
+				Ω(string(content)).Should(ContainSubstring("service.ListenAndServe(*addr)"))
+				_, err = gexec.Build(testgenPackagePath)
+				Ω(err).ShouldNot(HaveOccurred())
+
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "k8s", "configmap.yaml"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("name: test-api-config"))
+				Ω(string(content)).Should(ContainSubstring(`ADDR: ":8080"`))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "k8s", "deployment.yaml"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("- --addr=$(ADDR)"))
+				Ω(string(content)).Should(ContainSubstring(k8sProbeCode))
+			})
+		})
+
+		Context("with a debug listener", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--debug-listener")
+			})
+
+			It("generates an app serving the pprof and expvar endpoints on the debug address", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`debugAddr := flag.String("debug-addr", "",`))
+				Ω(string(content)).Should(ContainSubstring(debugListenerCode))
+				Ω(string(content)).Should(ContainSubstring(listenAndServeCode))
+				_, err = gexec.Build(testgenPackagePath)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with Docker files", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--docker")
+			})
+
+			It("generates the Dockerfile and the docker-compose file", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(Equal([]string{
+					filepath.Join(outDir, "main.go"),
+					filepath.Join(outDir, "Dockerfile"),
+					filepath.Join(outDir, "docker-compose.yaml"),
+				}))
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "Dockerfile"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring("WORKDIR /go/src/" + testgenPackagePath))
+				Ω(string(content)).Should(ContainSubstring("EXPOSE 8080"))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "docker-compose.yaml"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`- "8080:8080"`))
+			})
+		})
 	})
 
 	Context("with resources", func() {
@@ -112,6 +205,24 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(MatchRegexp(`// FirstController_Alpha: start_implement\s*// Put your logic here\s*return nil\s*// FirstController_Alpha: end_implement`))
 		})
 
+		Context("with the app package generated in another directory", func() {
+			BeforeEach(func() {
+				design.Design.Metadata = dslengine.MetadataDefinition{"goagen:out:app": {"internal/rest"}}
+			})
+
+			It("imports the app package from that directory", func() {
+				Ω(genErr).Should(BeNil())
+				main, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(main)).Should(ContainSubstring(`"` + testgenPackagePath + `/internal/rest"`))
+				Ω(string(main)).Should(ContainSubstring("rest.MountAll(service, &rest.Controllers{"))
+				controller, err := ioutil.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(controller)).Should(ContainSubstring(`"` + testgenPackagePath + `/internal/rest"`))
+				Ω(string(controller)).Should(ContainSubstring("ctx *rest.AlphaFirstContext"))
+			})
+		})
+
 		Context("regenerated with a new resource", func() {
 			BeforeEach(func() {
 				// Perform a first generation
@@ -185,6 +296,53 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("regenerated in merge mode", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--merge")
+
+				// Perform a first generation
+				files, genErr = genmain.Generate()
+				Ω(genErr).ShouldNot(HaveOccurred())
+
+				// Edit the existing controller and main
+				for _, name := range []string{"first.go", "main.go"} {
+					existing, err := ioutil.ReadFile(filepath.Join(outDir, name))
+					Ω(err).ShouldNot(HaveOccurred())
+					existing = bytes.Replace(existing, []byte("// Put your logic here"), []byte("// I did it first"), 1)
+					existing = bytes.Replace(existing, []byte("func main() {"), []byte("// main is edited\nfunc main() {"), 1)
+					err = ioutil.WriteFile(filepath.Join(outDir, name), existing, 0644)
+					Ω(err).ShouldNot(HaveOccurred())
+				}
+
+				// Add an action to the existing resource
+				beta := &design.ActionDefinition{
+					Parent:      resource,
+					Name:        "beta",
+					Schemes:     []string{"http"},
+					Description: "Beta-like things",
+				}
+				resource.Actions[beta.Name] = beta
+			})
+
+			It("merges the changes with the new scaffolding", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(ConsistOf(filepath.Join(outDir, "main.go"), filepath.Join(outDir, "first.go")))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring("FirstController_Beta: start_implement"))
+				Ω(content).Should(MatchRegexp(`// FirstController_Alpha: start_implement\s*// I did it first`))
+
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(content).Should(ContainSubstring("// main is edited\nfunc main() {"))
+
+				base, err := ioutil.ReadFile(filepath.Join(outDir, ".goagen", "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(base).Should(ContainSubstring("FirstController_Beta: start_implement"))
+				Ω(base).ShouldNot(ContainSubstring("I did it first"))
+			})
+		})
 	})
 })
 
@@ -192,22 +350,28 @@ var _ = Describe("NewGenerator", func() {
 	var generator *genmain.Generator
 
 	var args = struct {
-		api       *design.APIDefinition
-		outDir    string
-		designPkg string
-		target    string
-		force     bool
-		regen     bool
-		noExample bool
+		api           *design.APIDefinition
+		outDir        string
+		designPkg     string
+		target        string
+		force         bool
+		regen         bool
+		k8s           bool
+		docker        bool
+		debugListener bool
+		noExample     bool
 	}{
 		api: &design.APIDefinition{
 			Name: "test api",
 		},
-		outDir:    "out_dir",
-		designPkg: "design",
-		target:    "app",
-		force:     false,
-		regen:     false,
+		outDir:        "out_dir",
+		designPkg:     "design",
+		target:        "app",
+		force:         false,
+		regen:         false,
+		k8s:           true,
+		docker:        true,
+		debugListener: true,
 	}
 
 	Context("with options all options set", func() {
@@ -220,6 +384,9 @@ var _ = Describe("NewGenerator", func() {
 				genmain.Target(args.target),
 				genmain.Force(args.force),
 				genmain.Regen(args.regen),
+				genmain.K8s(args.k8s),
+				genmain.Docker(args.docker),
+				genmain.DebugListener(args.debugListener),
 			)
 		})
 
@@ -231,6 +398,9 @@ var _ = Describe("NewGenerator", func() {
 			Ω(generator.Target).Should(Equal(args.target))
 			Ω(generator.Force).Should(Equal(args.force))
 			Ω(generator.Regen).Should(Equal(args.regen))
+			Ω(generator.K8s).Should(Equal(args.k8s))
+			Ω(generator.Docker).Should(Equal(args.docker))
+			Ω(generator.DebugListener).Should(Equal(args.debugListener))
 		})
 
 	})
@@ -242,8 +412,35 @@ const listenAndServeCode = `
 	}
 `
 
+const k8sProbeCode = `
+          livenessProbe:
+            httpGet:
+              path: /healthz
+              port: http
+`
+
 const listenAndServeTLSCode = `
 	if err := service.ListenAndServeTLS(":8080", "cert.pem", "key.pem"); err != nil {
 		service.LogError("startup", "err", err)
 	}
 `
+
+const debugListenerCode = `
+	flag.Parse()
+
+	// Start the debug listener on its own address so the profiling endpoints are not exposed with the API
+	if *debugAddr != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		mux.Handle("/debug/vars", expvar.Handler())
+		go func() {
+			if err := http.ListenAndServe(*debugAddr, mux); err != nil {
+				service.LogError("debug", "err", err)
+			}
+		}()
+	}
+`
@@ -0,0 +1,189 @@
+package genmain
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// healthPath is the path of the health check endpoint used by the Kubernetes probes.
+const healthPath = "/healthz"
+
+// generateK8s generates the Kubernetes manifests that deploy the service in the "k8s" directory.
+// Existing manifests are left untouched unless Force or Merge is true.
+func (g *Generator) generateK8s() error {
+	outDir := filepath.Join(g.OutDir, "k8s")
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	tls, mtls := tlsSchemes(g.API)
+	data := map[string]interface{}{
+		"Name":       k8sName(g.API.Name),
+		"Port":       getPort(g.API.Host),
+		"TLS":        tls || mtls,
+		"MutualTLS":  mtls,
+		"HealthPath": healthPath,
+	}
+	manifests := []struct{ name, tmpl string }{
+		{"configmap.yaml", configMapT},
+		{"deployment.yaml", deploymentT},
+		{"service.yaml", serviceT},
+	}
+	for _, m := range manifests {
+		filename := filepath.Join(outDir, m.name)
+		err := g.scaffold(filename, func() error { return g.writeManifest(filename, m.tmpl, data) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeManifest renders the given manifest template to filename.
+func (g *Generator) writeManifest(filename, tmpl string, data interface{}) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.genfiles = append(g.genfiles, filename)
+	return template.Must(template.New("manifest").Parse(tmpl)).Execute(f, data)
+}
+
+// hasRoute returns true if the API defines an action route with the given method and path.
+func hasRoute(api *design.APIDefinition, method, path string) bool {
+	found := false
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			for _, route := range a.Routes {
+				if route.Verb == method && route.FullPath() == path {
+					found = true
+				}
+			}
+			return nil
+		})
+	})
+	return found
+}
+
+// k8sName returns a valid Kubernetes resource name built from name: the name is lower cased and
+// the sequences of characters other than letters and digits are replaced with dashes.
+func k8sName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(codegen.SnakeCase(name)), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	res := strings.Join(words, "-")
+	if len(res) > 63 {
+		res = strings.TrimRight(res[:63], "-")
+	}
+	if res == "" {
+		res = "service"
+	}
+	return res
+}
+
+const configMapT = `# The values are given to the service as command line flags, see deployment.yaml.
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Name }}-config
+  labels:
+    app: {{ .Name }}
+data:
+  # Listen address, keep the port in sync with the deployment container port.
+  ADDR: ":{{ .Port }}"
+{{- if .MutualTLS }}
+  CA_CERT: /etc/{{ .Name }}/tls/ca.pem
+  CERT: /etc/{{ .Name }}/tls/cert.pem
+  KEY: /etc/{{ .Name }}/tls/key.pem
+{{- end }}
+`
+
+const deploymentT = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Name }}
+  labels:
+    app: {{ .Name }}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Name }}
+    spec:
+      containers:
+        - name: {{ .Name }}
+          image: {{ .Name }}:latest
+          args:
+            - --addr=$(ADDR)
+{{- if .MutualTLS }}
+            - --ca-cert=$(CA_CERT)
+            - --cert=$(CERT)
+            - --key=$(KEY)
+{{- end }}
+          envFrom:
+            - configMapRef:
+                name: {{ .Name }}-config
+          ports:
+            - name: http
+              containerPort: {{ .Port }}
+{{- if .MutualTLS }}
+          # The service requires client certificates, the probes only check that it accepts
+          # connections.
+          livenessProbe:
+            tcpSocket:
+              port: http
+          readinessProbe:
+            tcpSocket:
+              port: http
+          volumeMounts:
+            - name: tls
+              mountPath: /etc/{{ .Name }}/tls
+              readOnly: true
+      volumes:
+        - name: tls
+          secret:
+            secretName: {{ .Name }}-tls
+{{- else }}
+{{- if .TLS }}
+          # The service reads cert.pem and key.pem from its working directory.
+{{- end }}
+          livenessProbe:
+            httpGet:
+              path: {{ .HealthPath }}
+              port: http
+{{- if .TLS }}
+              scheme: HTTPS
+{{- end }}
+          readinessProbe:
+            httpGet:
+              path: {{ .HealthPath }}
+              port: http
+{{- if .TLS }}
+              scheme: HTTPS
+{{- end }}
+{{- end }}
+`
+
+const serviceT = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Name }}
+  labels:
+    app: {{ .Name }}
+spec:
+  selector:
+    app: {{ .Name }}
+  ports:
+    - name: {{ if .TLS }}https{{ else }}http{{ end }}
+      port: {{ if .TLS }}443{{ else }}80{{ end }}
+      targetPort: http
+`
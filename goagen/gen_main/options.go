@@ -46,3 +46,31 @@ func Regen(regen bool) Option {
 		g.Regen = regen
 	}
 }
+
+//Merge Whether to merge the changes made to the existing files with the regenerated scaffolding
+func Merge(merge bool) Option {
+	return func(g *Generator) {
+		g.Merge = merge
+	}
+}
+
+//K8s Whether to generate Kubernetes manifests
+func K8s(k8s bool) Option {
+	return func(g *Generator) {
+		g.K8s = k8s
+	}
+}
+
+//Docker Whether to generate a Dockerfile and a docker-compose file
+func Docker(docker bool) Option {
+	return func(g *Generator) {
+		g.Docker = docker
+	}
+}
+
+// DebugListener Whether to generate a debug listener serving the pprof and expvar endpoints
+func DebugListener(debugListener bool) Option {
+	return func(g *Generator) {
+		g.DebugListener = debugListener
+	}
+}
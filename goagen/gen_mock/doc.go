@@ -0,0 +1,19 @@
+/*
+Package genmock generates a standalone mock server for a goa API so that clients can be developed
+before the service is implemented.
+
+The generator writes the "mock/main.go" file of a program that serves all the routes of the API
+actions. Build and run the program with:
+
+	go run ./mock --addr :8080
+
+Each request receives a response defined in the design for the corresponding action: the first
+success response by default or the response whose status code is given in the X-Mock-Status
+request header. The response bodies are built from the design examples or generated randomly so
+that they satisfy the validations of the response types. Media type responses are rendered using
+the view defined in the design or the view given in the X-Mock-View request header. The server
+responds with 400 and a message listing the valid values if the headers do not match the design.
+
+The mock server does not decode nor validate the request parameters and payloads.
+*/
+package genmock
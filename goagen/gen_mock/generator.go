@@ -0,0 +1,372 @@
+package genmock
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Mock Server Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the mock server generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+type (
+	// Endpoint describes an action route served by the mock server.
+	Endpoint struct {
+		Method    string      // HTTP method
+		Path      string      // Route path, e.g. "/bottles/:id"
+		Action    string      // Action name
+		Resource  string      // Resource name
+		Responses []*Response // Action responses, the default response first
+	}
+
+	// Response describes a response returned by the mock server.
+	Response struct {
+		Status      int               // HTTP status code
+		ContentType string            // Response Content-Type header value if any
+		View        string            // Default view
+		Bodies      map[string]string // Response bodies indexed by view, nil if no body
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("mock", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the mock server source code.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	outDir := filepath.Join(g.OutDir, "mock")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	endpoints, err := g.Endpoints()
+	if err != nil {
+		return nil, err
+	}
+	if err = g.generateMain(filepath.Join(outDir, "main.go"), endpoints); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// Endpoints returns the endpoints served by the mock server, one per action route.
+func (g *Generator) Endpoints() ([]*Endpoint, error) {
+	var endpoints []*Endpoint
+	err := g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 {
+				return nil
+			}
+			var responses []*Response
+			err := a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				res, err := g.response(resp)
+				if err != nil {
+					return err
+				}
+				responses = append(responses, res)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			sort.SliceStable(responses, func(i, j int) bool {
+				si, sj := responses[i].Status, responses[j].Status
+				if success(si) != success(sj) {
+					return success(si)
+				}
+				return si < sj
+			})
+			for _, route := range a.Routes {
+				endpoints = append(endpoints, &Endpoint{
+					Method:    route.Verb,
+					Path:      route.FullPath(),
+					Action:    a.Name,
+					Resource:  r.Name,
+					Responses: responses,
+				})
+			}
+			return nil
+		})
+	})
+	return endpoints, err
+}
+
+// response computes the mock response for the given response definition. The bodies are built
+// from the design examples or randomly generated so that they satisfy the type validations.
+func (g *Generator) response(r *design.ResponseDefinition) (*Response, error) {
+	res := &Response{Status: r.Status, ContentType: r.MediaType, View: "default"}
+	gen := g.API.RandomGenerator()
+	if mt := g.API.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+		res.ContentType = mt.Identifier
+		if r.ViewName != "" {
+			res.View = r.ViewName
+		}
+		res.Bodies = make(map[string]string, len(mt.Views))
+		for n := range mt.Views {
+			p, _, err := mt.Project(n)
+			if err != nil {
+				return nil, err
+			}
+			body, err := json.Marshal(jsonable(p.GenerateExample(gen, nil)))
+			if err != nil {
+				return nil, err
+			}
+			res.Bodies[n] = string(body)
+		}
+		return res, nil
+	}
+	if r.Type != nil {
+		if res.ContentType == "" {
+			res.ContentType = "application/json"
+		}
+		body, err := json.Marshal(jsonable(r.Type.GenerateExample(gen, nil)))
+		if err != nil {
+			return nil, err
+		}
+		res.Bodies = map[string]string{res.View: string(body)}
+	}
+	return res, nil
+}
+
+// generateMain writes the mock server main file.
+func (g *Generator) generateMain(filename string, endpoints []*Endpoint) (err error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	g.genfiles = append(g.genfiles, filename)
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("flag"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("log"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("sort"),
+		codegen.SimpleImport("strconv"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("github.com/dimfeld/httptreemux"),
+	}
+	title := fmt.Sprintf("%s: Mock Server", g.API.Context())
+	if err = file.WriteHeader(title, "main", imports); err != nil {
+		return err
+	}
+	data := map[string]interface{}{"API": g.API, "Endpoints": endpoints}
+	return file.ExecuteTemplate("main", mainT, nil, data)
+}
+
+// success returns true if status is a 2xx status code.
+func success(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// jsonable converts the hashes with non string keys produced by the example generator so that the
+// result may be encoded in JSON.
+func jsonable(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[fmt.Sprintf("%v", k)] = jsonable(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = jsonable(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = jsonable(e)
+		}
+		return res
+	}
+	return v
+}
+
+const mainT = `{{ $api := .API }}
+// StatusHeader is the name of the request header that selects the status code of the response.
+// The mock server returns the first success response defined in the design by default.
+const StatusHeader = "X-Mock-Status"
+
+// ViewHeader is the name of the request header that selects the view used to render the response
+// media type. The mock server uses the view defined in the design by default.
+const ViewHeader = "X-Mock-View"
+
+type (
+	// endpoint is an action route served by the mock server.
+	endpoint struct {
+		method, path, action string
+		responses            []*response
+	}
+
+	// response is an action response, bodies are indexed by view.
+	response struct {
+		status      int
+		contentType string
+		view        string
+		bodies      map[string]string
+	}
+)
+
+// endpoints lists the mock server endpoints.
+var endpoints = []*endpoint{
+{{- range .Endpoints }}
+	{
+		method: {{ printf "%q" .Method }},
+		path:   {{ printf "%q" .Path }},
+		action: {{ printf "%q" (printf "%s %s" .Resource .Action) }},
+		responses: []*response{
+		{{- range .Responses }}
+			{
+				status:      {{ .Status }},
+				contentType: {{ printf "%q" .ContentType }},
+				view:        {{ printf "%q" .View }},
+				{{- if .Bodies }}
+				bodies: map[string]string{
+				{{- range $view, $body := .Bodies }}
+					{{ printf "%q" $view }}: {{ printf "%q" $body }},
+				{{- end }}
+				},
+				{{- end }}
+			},
+		{{- end }}
+		},
+	},
+{{- end }}
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "The address the mock server listens on")
+	flag.Parse()
+
+	mux := httptreemux.New()
+	for _, e := range endpoints {
+		mux.Handle(e.method, e.path, e.handle)
+	}
+	log.Printf("mock {{ $api.Name }} API listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// handle writes the response selected by the request StatusHeader and ViewHeader headers.
+func (e *endpoint) handle(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	resp := e.responses[0]
+	if s := r.Header.Get(StatusHeader); s != "" {
+		resp = nil
+		status, err := strconv.Atoi(s)
+		if err == nil {
+			for _, candidate := range e.responses {
+				if candidate.status == status {
+					resp = candidate
+					break
+				}
+			}
+		}
+		if resp == nil {
+			statuses := make([]string, len(e.responses))
+			for i, candidate := range e.responses {
+				statuses[i] = strconv.Itoa(candidate.status)
+			}
+			mockError(w, "%s does not define a %q response, valid values for %s are %s", e.action, s, StatusHeader, strings.Join(statuses, ", "))
+			return
+		}
+	}
+	if resp.bodies == nil {
+		log.Printf("%s %s: %s -> %d", r.Method, r.URL, e.action, resp.status)
+		w.WriteHeader(resp.status)
+		return
+	}
+	view := resp.view
+	if v := r.Header.Get(ViewHeader); v != "" {
+		view = v
+	}
+	body, ok := resp.bodies[view]
+	if !ok {
+		views := make([]string, 0, len(resp.bodies))
+		for v := range resp.bodies {
+			views = append(views, v)
+		}
+		sort.Strings(views)
+		mockError(w, "%s %d response does not define a %q view, valid values for %s are %s", e.action, resp.status, view, ViewHeader, strings.Join(views, ", "))
+		return
+	}
+	log.Printf("%s %s: %s -> %d (%s view)", r.Method, r.URL, e.action, resp.status, view)
+	w.Header().Set("Content-Type", resp.contentType)
+	w.WriteHeader(resp.status)
+	w.Write([]byte(body))
+}
+
+// mockError writes a 400 response describing an invalid mock server control header.
+func mockError(w http.ResponseWriter, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	fmt.Fprintf(w, format+"\n", args...)
+}
+`
@@ -0,0 +1,129 @@
+package genmock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_mock"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("mocktest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genmock.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an action returning a media type", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer, func() {
+						apidsl.Example(1)
+					})
+					apidsl.Attribute("name", design.String, func() {
+						apidsl.Example("merlot")
+					})
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+				apidsl.View("tiny", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NotFound)
+					apidsl.Response(design.OK, bottle)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the mock server", func() {
+			Ω(genErr).Should(BeNil())
+			dir := filepath.Join(testPkg.Abs(), "mock")
+			Ω(files).Should(Equal([]string{dir, filepath.Join(dir, "main.go")}))
+			content, err := ioutil.ReadFile(filepath.Join(dir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`path:   "/bottles/:id",`))
+			Ω(string(content)).Should(ContainSubstring(`contentType: "application/vnd.bottle",`))
+			Ω(string(content)).Should(ContainSubstring(`"default": "{\"id\":1,\"name\":\"merlot\"}",`))
+			Ω(string(content)).Should(ContainSubstring(`"tiny":    "{\"id\":1}",`))
+			Ω(string(content)).Should(ContainSubstring(`const StatusHeader = "X-Mock-Status"`))
+		})
+
+		It("returns the success response first", func() {
+			endpoints, err := genmock.NewGenerator(genmock.API(design.Design)).Endpoints()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(endpoints).Should(HaveLen(1))
+			Ω(endpoints[0].Method).Should(Equal("GET"))
+			Ω(endpoints[0].Responses).Should(HaveLen(2))
+			Ω(endpoints[0].Responses[0].Status).Should(Equal(200))
+			Ω(endpoints[0].Responses[1].Status).Should(Equal(404))
+			Ω(endpoints[0].Responses[1].Bodies).Should(BeNil())
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genmock.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genmock.NewGenerator(
+				genmock.API(args.api),
+				genmock.OutDir(args.outDir),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+		})
+	})
+})
@@ -0,0 +1,227 @@
+package genopenapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+)
+
+// AsyncAPIVersion is the version of the AsyncAPI specification produced by the generator.
+const AsyncAPIVersion = "2.6.0"
+
+// wsBindingVersion is the version of the AsyncAPI WebSockets bindings.
+const wsBindingVersion = "0.1.0"
+
+// AsyncAPI creates the AsyncAPI specification describing the channels of the given API: the message
+// bus topics the actions subscribe or publish their results to and the server events streamed by
+// the actions over WebSocket connections. spec is the OpenAPI specification of the API built by
+// New, the AsyncAPI specification shares its schemas. AsyncAPI returns nil if no action defines a
+// channel.
+func AsyncAPI(api *design.APIDefinition, spec map[string]interface{}) map[string]interface{} {
+	ops := operations(spec)
+	channels := make(map[string]interface{})
+	servers := make(map[string]interface{})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			id := fmt.Sprintf("%s#%s", r.Name, a.Name)
+			opID := id
+			if len(a.Routes) > 1 {
+				opID += "#0"
+			}
+			op := ops[opID]
+			if a.Topic != "" {
+				addOperation(channels, a.Topic, "publish", map[string]interface{}{
+					"operationId": id,
+					"summary":     fmt.Sprintf("Messages dispatched to the %s action of the %s resource.", a.Name, r.Name),
+					"message":     message(id+"#payload", requestContent(op)),
+				})
+			}
+			if a.ResultTopic != "" {
+				addOperation(channels, a.ResultTopic, "subscribe", map[string]interface{}{
+					"operationId": id + "#result",
+					"summary":     fmt.Sprintf("Results of the %s action of the %s resource.", a.Name, r.Name),
+					"message":     message(id+"#result", eventBody(a, op)),
+				})
+			}
+			if a.Subscription != "" && len(a.Routes) > 0 {
+				scheme := "ws"
+				for _, s := range a.EffectiveSchemes() {
+					if s == "wss" || s == "https" {
+						scheme = "wss"
+					}
+				}
+				if api.Host != "" {
+					servers[scheme] = map[string]interface{}{
+						"url":      fmt.Sprintf("%s://%s", scheme, api.Host),
+						"protocol": scheme,
+					}
+				}
+				channel := wsChannel(a.Routes[0])
+				addOperation(channels, channel, "subscribe", map[string]interface{}{
+					"operationId": id + "#" + a.Subscription,
+					"summary":     fmt.Sprintf("Event %q streamed by the %s action of the %s resource.", a.Subscription, a.Name, r.Name),
+					"message":     message(id+"#"+a.Subscription, eventBody(a, op)),
+				})
+				ch := channels[channel].(map[string]interface{})
+				params, bindings := wsBindings(a.Routes[0], op)
+				if len(params) > 0 {
+					ch["parameters"] = params
+				}
+				ch["bindings"] = bindings
+			}
+			return nil
+		})
+	})
+	if len(channels) == 0 {
+		return nil
+	}
+	res := map[string]interface{}{
+		"asyncapi":           AsyncAPIVersion,
+		"defaultContentType": "application/json",
+		"channels":           channels,
+	}
+	info := map[string]interface{}{"title": api.Name, "version": api.Version}
+	if si, ok := spec["info"].(map[string]interface{}); ok {
+		// Copy the OpenAPI info so that it is not modified, AsyncAPI requires a title.
+		info = make(map[string]interface{}, len(si)+1)
+		for k, v := range si {
+			info[k] = v
+		}
+		if title, _ := info["title"].(string); title == "" {
+			info["title"] = api.Name
+		}
+	}
+	res["info"] = info
+	if len(servers) > 0 {
+		res["servers"] = servers
+	}
+	if components, ok := spec["components"].(map[string]interface{}); ok {
+		if schemas, ok := components["schemas"]; ok {
+			res["components"] = map[string]interface{}{"schemas": schemas}
+		}
+	}
+	return res
+}
+
+// operations indexes the operations of the OpenAPI specification by ID.
+func operations(spec map[string]interface{}) map[string]map[string]interface{} {
+	ops := make(map[string]map[string]interface{})
+	paths, _ := spec["paths"].(map[string]interface{})
+	for _, item := range paths {
+		it, _ := item.(map[string]interface{})
+		for _, v := range it {
+			if op, ok := v.(map[string]interface{}); ok {
+				if id, ok := op["operationId"].(string); ok {
+					ops[id] = op
+				}
+			}
+		}
+	}
+	return ops
+}
+
+// addOperation adds the publish or subscribe operation to the channel with the given name. The
+// messages of the operations of different actions bound to the same channel are combined with
+// oneOf.
+func addOperation(channels map[string]interface{}, name, kind string, op map[string]interface{}) {
+	ch, ok := channels[name].(map[string]interface{})
+	if !ok {
+		ch = make(map[string]interface{})
+		channels[name] = ch
+	}
+	prev, ok := ch[kind].(map[string]interface{})
+	if !ok {
+		ch[kind] = op
+		return
+	}
+	msgs, ok := prev["message"].(map[string]interface{})["oneOf"].([]interface{})
+	if !ok {
+		msgs = []interface{}{prev["message"]}
+		delete(prev, "operationId")
+		delete(prev, "summary")
+	}
+	prev["message"] = map[string]interface{}{"oneOf": append(msgs, op["message"])}
+}
+
+// message returns the message whose payload is described by the given content. content maps media
+// types to OpenAPI media type objects as built by requestContent and eventBody.
+func message(name string, content interface{}) map[string]interface{} {
+	msg := map[string]interface{}{"name": name}
+	c, _ := content.(map[string]interface{})
+	if len(c) == 0 {
+		return msg
+	}
+	types := make([]string, 0, len(c))
+	for t := range c {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	ct := types[0]
+	for _, t := range types {
+		if t == "application/json" {
+			ct = t
+			break
+		}
+	}
+	msg["contentType"] = ct
+	if mt, ok := c[ct].(map[string]interface{}); ok {
+		if schema, ok := mt["schema"]; ok {
+			msg["payload"] = schema
+		}
+	}
+	return msg
+}
+
+// requestContent returns the content of the request body of the given OpenAPI operation if any.
+func requestContent(op map[string]interface{}) interface{} {
+	body, _ := op["requestBody"].(map[string]interface{})
+	return body["content"]
+}
+
+// wsChannel returns the name of the channel corresponding to the given WebSocket route.
+func wsChannel(route *design.RouteDefinition) string {
+	return design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(w string) string {
+		return fmt.Sprintf("/{%s}", w[2:])
+	})
+}
+
+// wsBindings returns the parameters and the WebSockets bindings of the channel corresponding to the
+// given WebSocket route. They are built from the parameters of the OpenAPI operation.
+func wsBindings(route *design.RouteDefinition, op map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	var (
+		params = make(map[string]interface{})
+		query  = make(map[string]interface{})
+		req    []interface{}
+	)
+	ps, _ := op["parameters"].([]interface{})
+	for _, p := range ps {
+		param, _ := p.(map[string]interface{})
+		name, _ := param["name"].(string)
+		if name == "" {
+			continue
+		}
+		switch param["in"] {
+		case "path":
+			params[name] = map[string]interface{}{"schema": param["schema"]}
+		case "query":
+			query[name] = param["schema"]
+			if param["required"] == true {
+				req = append(req, name)
+			}
+		}
+	}
+	ws := map[string]interface{}{
+		"method":         strings.ToUpper(route.Verb),
+		"bindingVersion": wsBindingVersion,
+	}
+	if len(query) > 0 {
+		q := map[string]interface{}{"type": "object", "properties": query}
+		if len(req) > 0 {
+			q["required"] = req
+		}
+		ws["query"] = q
+	}
+	return params, map[string]interface{}{"ws": ws}
+}
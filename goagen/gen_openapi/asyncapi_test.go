@@ -0,0 +1,125 @@
+package genopenapi_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AsyncAPI", func() {
+	var spec map[string]interface{}
+
+	BeforeEach(func() {
+		spec = nil
+		dslengine.Reset()
+		ProjectedMediaTypes = make(MediaTypeRoot)
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		oas, err := genopenapi.New(Design)
+		Ω(err).ShouldNot(HaveOccurred())
+		spec = genopenapi.AsyncAPI(Design, oas)
+	})
+
+	Context("with no channel", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("returns nil", func() {
+			Ω(spec).Should(BeNil())
+		})
+	})
+
+	Context("with topics and event streams", func() {
+		BeforeEach(func() {
+			API("test", func() {
+				Host("goa.design")
+				Scheme("https", "wss")
+			})
+			bottle := MediaType("application/vnd.bottle+json", func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Action("create", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Attribute("rating", Integer)
+					})
+					Topic("bottles.create", "bottles.created")
+					Response(Created, bottle)
+				})
+				Action("watch", func() {
+					Routing(GET("/:id/watch"))
+					Scheme("wss")
+					Params(func() {
+						Param("id", Integer)
+						Param("since", String)
+						Required("since")
+					})
+					Subscription("bottle.updated")
+					Response(SwitchingProtocols)
+				})
+			})
+		})
+
+		It("produces an AsyncAPI 2.x specification", func() {
+			Ω(spec).ShouldNot(BeNil())
+			Ω(spec["asyncapi"]).Should(Equal(genopenapi.AsyncAPIVersion))
+			Ω(spec["info"]).Should(HaveKeyWithValue("title", "test"))
+			Ω(spec["components"]).Should(HaveKey("schemas"))
+			Ω(spec["servers"]).Should(HaveKeyWithValue("wss", map[string]interface{}{
+				"url":      "wss://goa.design",
+				"protocol": "wss",
+			}))
+		})
+
+		It("describes the topics", func() {
+			channels := spec["channels"].(map[string]interface{})
+			create := channels["bottles.create"].(map[string]interface{})
+			publish := create["publish"].(map[string]interface{})
+			Ω(publish["operationId"]).Should(Equal("bottle#create"))
+			msg := publish["message"].(map[string]interface{})
+			Ω(msg["contentType"]).Should(Equal("application/json"))
+			Ω(msg).Should(HaveKey("payload"))
+
+			created := channels["bottles.created"].(map[string]interface{})
+			subscribe := created["subscribe"].(map[string]interface{})
+			msg = subscribe["message"].(map[string]interface{})
+			Ω(msg["contentType"]).Should(Equal("application/vnd.bottle+json"))
+			Ω(msg["payload"]).Should(HaveKeyWithValue("$ref", "#/components/schemas/Bottle"))
+		})
+
+		It("describes the event streams with WebSockets bindings", func() {
+			channels := spec["channels"].(map[string]interface{})
+			Ω(channels).Should(HaveKey("/bottles/{id}/watch"))
+			watch := channels["/bottles/{id}/watch"].(map[string]interface{})
+			Ω(watch).Should(HaveKey("subscribe"))
+			Ω(watch["parameters"]).Should(HaveKey("id"))
+			ws := watch["bindings"].(map[string]interface{})["ws"].(map[string]interface{})
+			Ω(ws["method"]).Should(Equal("GET"))
+			query := ws["query"].(map[string]interface{})
+			Ω(query["properties"]).Should(HaveKey("since"))
+			Ω(query["required"]).Should(Equal([]interface{}{"since"}))
+		})
+	})
+})
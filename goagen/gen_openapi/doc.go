@@ -0,0 +1,20 @@
+/*
+Package genopenapi provides a generator for the OpenAPI 3.1 specification of a goa API.
+
+The specification is derived from the Swagger 2.0 specification produced by genswagger: the
+definitions, parameters, responses and security definitions move to the components object, the
+request and response bodies are described by content objects keyed by media type and the schemas
+use the full JSON Schema dialect. Union schemas are rendered with oneOf and attributes with the
+"nullable" metadata list "null" in their type. Actions that publish their results to a message bus
+topic or stream server events are described as webhooks. The outbound requests defined with the
+Webhook DSL are described as callbacks of the operations of their actions.
+
+The generator produces the files openapi/openapi.json and openapi/openapi.yaml. If actions are
+bound to message bus topics with the Topic DSL or stream server events with the Subscription DSL
+the generator also produces the AsyncAPI 2.x specification of the corresponding channels in the
+files openapi/asyncapi.json and openapi/asyncapi.yaml. The topics are described with publish
+operations whose messages are the action payloads, the result topics and event streams with
+subscribe operations whose messages are the action responses. Event streams use the WebSockets
+bindings.
+*/
+package genopenapi
@@ -0,0 +1,129 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of an OpenAPI Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the OpenAPI 3.1 specification generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	genfiles []string              // Generated files
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("openapi", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the OpenAPI specification files.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	s, err := New(g.API)
+	if err != nil {
+		return nil, err
+	}
+
+	openapiDir := filepath.Join(g.OutDir, codegen.OutputDir(g.API, "openapi", "openapi"))
+	os.RemoveAll(openapiDir)
+	if err = os.MkdirAll(openapiDir, 0755); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, openapiDir)
+
+	if err = g.writeSpec(openapiDir, "openapi", s); err != nil {
+		return nil, err
+	}
+	if as := AsyncAPI(g.API, s); as != nil {
+		if err = g.writeSpec(openapiDir, "asyncapi", as); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// writeSpec writes the JSON and YAML representations of the given specification to the files
+// named after name in dir.
+func (g *Generator) writeSpec(dir, name string, s interface{}) error {
+	// JSON
+	rawJSON, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	specFile := filepath.Join(dir, name+".json")
+	if err := ioutil.WriteFile(specFile, rawJSON, 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, specFile)
+
+	// YAML
+	var yamlSource interface{}
+	if err := json.Unmarshal(rawJSON, &yamlSource); err != nil {
+		return err
+	}
+	rawYAML, err := yaml.Marshal(yamlSource)
+	if err != nil {
+		return err
+	}
+	specFile = filepath.Join(dir, name+".yaml")
+	if err := ioutil.WriteFile(specFile, rawYAML, 0644); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, specFile)
+	return nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
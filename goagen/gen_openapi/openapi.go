@@ -0,0 +1,624 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+const (
+	// Version is the version of the OpenAPI specification produced by the generator.
+	Version = "3.1.0"
+
+	// Dialect is the default JSON Schema dialect of the schemas in the generated specification.
+	Dialect = "https://spec.openapis.org/oas/3.1/dialect/base"
+)
+
+// refs maps the Swagger 2.0 reference prefixes to their OpenAPI 3.1 counterparts.
+var refs = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+// flows maps the Swagger 2.0 OAuth2 flow names to the OpenAPI 3.1 names.
+var flows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// New creates the OpenAPI 3.1 specification of the given API. The specification is built from the
+// Swagger 2.0 specification produced by genswagger.
+func New(api *design.APIDefinition) (map[string]interface{}, error) {
+	s, err := genswagger.New(api)
+	if err != nil {
+		return nil, err
+	}
+	// The webhook payloads are not referenced by the Swagger 2.0 specification, add their
+	// schemas to the definitions so that the callbacks may refer to them.
+	for _, w := range apiWebhooks(api) {
+		genschema.TypeSchema(api, w.Payload)
+	}
+	for n, d := range genschema.Definitions {
+		if s.Definitions == nil {
+			s.Definitions = make(map[string]*genschema.JSONSchema)
+		}
+		if _, ok := s.Definitions[n]; !ok {
+			d.Media = nil
+			d.Links = nil
+			s.Definitions[n] = d
+		}
+	}
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var sw map[string]interface{}
+	if err := json.Unmarshal(raw, &sw); err != nil {
+		return nil, err
+	}
+	return Convert(api, sw), nil
+}
+
+// Convert converts the JSON representation of a Swagger 2.0 specification into a OpenAPI 3.1
+// specification. The API definition is used to build the webhooks and the callbacks, it may be nil.
+func Convert(api *design.APIDefinition, sw map[string]interface{}) map[string]interface{} {
+	spec := map[string]interface{}{
+		"openapi":           Version,
+		"jsonSchemaDialect": Dialect,
+	}
+	for _, k := range []string{"info", "tags", "externalDocs", "security"} {
+		if v, ok := sw[k]; ok {
+			spec[k] = v
+		}
+	}
+	for k, v := range sw {
+		if strings.HasPrefix(k, "x-") {
+			spec[k] = v
+		}
+	}
+	host, _ := sw["host"].(string)
+	basePath, _ := sw["basePath"].(string)
+	schemes := stringSlice(sw["schemes"])
+	if srvs := servers(schemes, host, basePath); len(srvs) > 0 {
+		spec["servers"] = srvs
+	}
+	consumes := stringSlice(sw["consumes"])
+	produces := stringSlice(sw["produces"])
+
+	components := make(map[string]interface{})
+	if defs, ok := sw["definitions"].(map[string]interface{}); ok && len(defs) > 0 {
+		components["schemas"] = defs
+	}
+	if params, ok := sw["parameters"].(map[string]interface{}); ok && len(params) > 0 {
+		ps := make(map[string]interface{})
+		for n, p := range params {
+			ps[n] = parameter(p.(map[string]interface{}))
+		}
+		components["parameters"] = ps
+	}
+	if resps, ok := sw["responses"].(map[string]interface{}); ok && len(resps) > 0 {
+		rs := make(map[string]interface{})
+		for n, r := range resps {
+			rs[n] = response(n, r.(map[string]interface{}), produces)
+		}
+		components["responses"] = rs
+	}
+	if secs, ok := sw["securityDefinitions"].(map[string]interface{}); ok && len(secs) > 0 {
+		ss := make(map[string]interface{})
+		for n, s := range secs {
+			ss[n] = securityScheme(s.(map[string]interface{}))
+		}
+		components["securitySchemes"] = ss
+	}
+	if len(components) > 0 {
+		spec["components"] = components
+	}
+
+	paths := make(map[string]interface{})
+	ops := make(map[string]map[string]interface{})
+	if swPaths, ok := sw["paths"].(map[string]interface{}); ok {
+		for p, item := range swPaths {
+			it, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pi := make(map[string]interface{})
+			for k, v := range it {
+				switch k {
+				case "get", "put", "post", "delete", "options", "head", "patch":
+					op := operation(v.(map[string]interface{}), consumes, produces, schemes, host, basePath)
+					if id, ok := op["operationId"].(string); ok {
+						ops[id] = op
+					}
+					pi[k] = op
+				case "parameters":
+					pi[k] = parameters(v)
+				default:
+					pi[k] = v
+				}
+			}
+			paths[p] = pi
+		}
+	}
+	spec["paths"] = paths
+
+	if api != nil {
+		callbacks(api, ops)
+		if hooks := webhooks(api, ops); len(hooks) > 0 {
+			spec["webhooks"] = hooks
+		}
+	}
+
+	return rewrite(spec).(map[string]interface{})
+}
+
+// servers builds the server objects from the Swagger 2.0 schemes, host and base path.
+func servers(schemes []string, host, basePath string) []interface{} {
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	srvs := make([]interface{}, len(schemes))
+	for i, s := range schemes {
+		srvs[i] = map[string]interface{}{"url": fmt.Sprintf("%s://%s%s", s, host, basePath)}
+	}
+	return srvs
+}
+
+// operation converts a Swagger 2.0 operation object. The operation servers are only set when the
+// operation schemes differ from the API schemes.
+func operation(op map[string]interface{}, consumes, produces, schemes []string, host, basePath string) map[string]interface{} {
+	if c := stringSlice(op["consumes"]); len(c) > 0 {
+		consumes = c
+	}
+	if p := stringSlice(op["produces"]); len(p) > 0 {
+		produces = p
+	}
+	res := make(map[string]interface{})
+	var (
+		params []interface{}
+		form   map[string]interface{}
+	)
+	swParams, _ := op["parameters"].([]interface{})
+	for _, p := range swParams {
+		pm := p.(map[string]interface{})
+		switch pm["in"] {
+		case "body":
+			res["requestBody"] = requestBody(pm, consumes)
+		case "formData":
+			if form == nil {
+				form = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+			}
+			name, _ := pm["name"].(string)
+			form["properties"].(map[string]interface{})[name] = parameter(pm)["schema"]
+			if req, _ := pm["required"].(bool); req {
+				r, _ := form["required"].([]interface{})
+				form["required"] = append(r, name)
+			}
+		default:
+			params = append(params, parameter(pm))
+		}
+	}
+	if len(params) > 0 {
+		res["parameters"] = params
+	}
+	if form != nil {
+		mt := "application/x-www-form-urlencoded"
+		for _, c := range consumes {
+			if c == "multipart/form-data" {
+				mt = c
+				break
+			}
+		}
+		res["requestBody"] = map[string]interface{}{
+			"content": map[string]interface{}{mt: map[string]interface{}{"schema": form}},
+		}
+	}
+	if swResps, ok := op["responses"].(map[string]interface{}); ok {
+		resps := make(map[string]interface{})
+		for code, r := range swResps {
+			resps[code] = response(code, r.(map[string]interface{}), produces)
+		}
+		res["responses"] = resps
+	}
+	if opSchemes := stringSlice(op["schemes"]); len(opSchemes) > 0 && strings.Join(opSchemes, ",") != strings.Join(schemes, ",") {
+		res["servers"] = servers(opSchemes, host, basePath)
+	}
+	for k, v := range op {
+		switch k {
+		case "parameters", "responses", "consumes", "produces", "schemes":
+		default:
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// parameters converts a list of Swagger 2.0 parameter objects.
+func parameters(v interface{}) []interface{} {
+	ps, _ := v.([]interface{})
+	res := make([]interface{}, len(ps))
+	for i, p := range ps {
+		res[i] = parameter(p.(map[string]interface{}))
+	}
+	return res
+}
+
+// parameter converts a Swagger 2.0 non body parameter object. The type and validations of the
+// parameter move to its schema.
+func parameter(p map[string]interface{}) map[string]interface{} {
+	if _, ok := p["$ref"]; ok {
+		return p
+	}
+	res := make(map[string]interface{})
+	schema := make(map[string]interface{})
+	for k, v := range p {
+		switch k {
+		case "name", "in", "description", "required", "allowEmptyValue", "schema":
+			res[k] = v
+		case "collectionFormat":
+			in, _ := p["in"].(string)
+			style, explode := style(in, v.(string))
+			res["style"] = style
+			res["explode"] = explode
+		default:
+			if strings.HasPrefix(k, "x-") {
+				res[k] = v
+			} else {
+				schema[k] = v
+			}
+		}
+	}
+	if len(schema) > 0 {
+		res["schema"] = simpleSchema(schema)
+	}
+	return res
+}
+
+// style returns the OpenAPI 3.1 style and explode values corresponding to the given Swagger 2.0
+// collection format.
+func style(in, format string) (string, bool) {
+	switch format {
+	case "ssv":
+		return "spaceDelimited", false
+	case "pipes":
+		return "pipeDelimited", false
+	case "multi":
+		return "form", true
+	}
+	if in == "query" || in == "cookie" {
+		return "form", false
+	}
+	return "simple", false
+}
+
+// simpleSchema converts the type and validations of a Swagger 2.0 parameter, header or items
+// object into a JSON schema.
+func simpleSchema(s map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range s {
+		switch k {
+		case "collectionFormat", "allowEmptyValue":
+		case "items":
+			res[k] = simpleSchema(v.(map[string]interface{}))
+		default:
+			res[k] = v
+		}
+	}
+	if res["type"] == "file" {
+		res["type"] = "string"
+		res["format"] = "binary"
+	}
+	for _, k := range []string{"Maximum", "Minimum"} {
+		if ex, _ := res["exclusive"+k].(bool); ex {
+			res["exclusive"+k] = res[strings.ToLower(k)]
+			delete(res, strings.ToLower(k))
+		} else {
+			delete(res, "exclusive"+k)
+		}
+	}
+	return res
+}
+
+// requestBody converts a Swagger 2.0 body parameter into a request body object.
+func requestBody(p map[string]interface{}, consumes []string) map[string]interface{} {
+	res := map[string]interface{}{"content": content(p["schema"], consumes)}
+	if d, ok := p["description"]; ok {
+		res["description"] = d
+	}
+	if r, _ := p["required"].(bool); r {
+		res["required"] = true
+	}
+	return res
+}
+
+// response converts a Swagger 2.0 response object.
+func response(code string, r map[string]interface{}, produces []string) map[string]interface{} {
+	if _, ok := r["$ref"]; ok {
+		return r
+	}
+	res := make(map[string]interface{})
+	for k, v := range r {
+		switch k {
+		case "schema":
+			res["content"] = content(v, produces)
+		case "headers":
+			hs := make(map[string]interface{})
+			for n, h := range v.(map[string]interface{}) {
+				hm := h.(map[string]interface{})
+				header := make(map[string]interface{})
+				if d, ok := hm["description"]; ok {
+					header["description"] = d
+				}
+				schema := make(map[string]interface{})
+				for hk, hv := range hm {
+					if hk != "description" {
+						schema[hk] = hv
+					}
+				}
+				header["schema"] = simpleSchema(schema)
+				hs[n] = header
+			}
+			res["headers"] = hs
+		default:
+			res[k] = v
+		}
+	}
+	if d, _ := res["description"].(string); d == "" {
+		// The description field is required by OpenAPI 3.1.
+		res["description"] = code
+		if c, err := strconv.Atoi(code); err == nil && http.StatusText(c) != "" {
+			res["description"] = http.StatusText(c)
+		}
+	}
+	return res
+}
+
+// content builds a content object that describes the given schema for each media type.
+func content(schema interface{}, mediaTypes []string) map[string]interface{} {
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+	res := make(map[string]interface{}, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		res[mt] = map[string]interface{}{"schema": schema}
+	}
+	return res
+}
+
+// securityScheme converts a Swagger 2.0 security definition object.
+func securityScheme(s map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{})
+	for k, v := range s {
+		if k == "description" || strings.HasPrefix(k, "x-") {
+			res[k] = v
+		}
+	}
+	switch s["type"] {
+	case "basic":
+		res["type"] = "http"
+		res["scheme"] = "basic"
+	case "apiKey":
+		res["type"] = "apiKey"
+		res["name"] = s["name"]
+		res["in"] = s["in"]
+	case "oauth2":
+		flow := make(map[string]interface{})
+		if u, ok := s["authorizationUrl"]; ok {
+			flow["authorizationUrl"] = u
+		}
+		if u, ok := s["tokenUrl"]; ok {
+			flow["tokenUrl"] = u
+		}
+		scopes, ok := s["scopes"]
+		if !ok {
+			scopes = map[string]interface{}{}
+		}
+		flow["scopes"] = scopes
+		name, _ := s["flow"].(string)
+		if n, ok := flows[name]; ok {
+			name = n
+		}
+		res["type"] = "oauth2"
+		res["flows"] = map[string]interface{}{name: flow}
+	default:
+		res["type"] = s["type"]
+	}
+	return res
+}
+
+// webhooks describes the actions that publish their results to a message bus topic or that stream
+// server events. The webhooks are named after the topic or event and their request body is the
+// body of the first successful response of the action.
+func webhooks(api *design.APIDefinition, ops map[string]map[string]interface{}) map[string]interface{} {
+	hooks := make(map[string]interface{})
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			event := a.Subscription
+			if event == "" {
+				event = a.ResultTopic
+			}
+			if event == "" {
+				return nil
+			}
+			id := fmt.Sprintf("%s#%s", r.Name, a.Name)
+			op := map[string]interface{}{
+				"operationId": fmt.Sprintf("%s#%s", id, event),
+				"description": fmt.Sprintf("Event %q produced by the %s action of the %s resource.", event, a.Name, r.Name),
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{"description": "The event was received successfully."},
+				},
+			}
+			opID := id
+			if len(a.Routes) > 1 {
+				opID += "#0"
+			}
+			if body := eventBody(a, ops[opID]); body != nil {
+				op["requestBody"] = map[string]interface{}{"content": body}
+			}
+			hooks[event] = map[string]interface{}{"post": op}
+			return nil
+		})
+	})
+	return hooks
+}
+
+// callbacks describes the webhooks defined with the Webhook DSL as callbacks of the operations of
+// their actions. The callback URLs are the webhook runtime expressions.
+func callbacks(api *design.APIDefinition, ops map[string]map[string]interface{}) {
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Webhooks) == 0 {
+				return nil
+			}
+			id := fmt.Sprintf("%s#%s", r.Name, a.Name)
+			cbs := make(map[string]interface{})
+			for _, w := range a.Webhooks {
+				op := map[string]interface{}{
+					"operationId": fmt.Sprintf("%s#%s", id, w.Name),
+					"parameters": []interface{}{
+						map[string]interface{}{
+							"name":        "X-Goa-Webhook",
+							"in":          "header",
+							"description": "Name of the webhook.",
+							"required":    true,
+							"schema":      map[string]interface{}{"type": "string"},
+						},
+						map[string]interface{}{
+							"name":        "X-Goa-Signature",
+							"in":          "header",
+							"description": "Timestamp and HMAC-SHA256 signature of the request body, e.g. \"t=1526940000,v1=5257a8...\".",
+							"schema":      map[string]interface{}{"type": "string"},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{"$ref": genschema.TypeSchema(api, w.Payload).Ref},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"2XX": map[string]interface{}{"description": "The webhook request was received successfully."},
+					},
+				}
+				if w.Description != "" {
+					op["description"] = w.Description
+				}
+				cbs[w.Name] = map[string]interface{}{w.URL: map[string]interface{}{"post": op}}
+			}
+			if op, ok := ops[id]; ok {
+				op["callbacks"] = cbs
+			}
+			for i := range a.Routes {
+				if op, ok := ops[fmt.Sprintf("%s#%d", id, i)]; ok {
+					op["callbacks"] = cbs
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// apiWebhooks returns the webhooks defined by the API actions.
+func apiWebhooks(api *design.APIDefinition) []*design.WebhookDefinition {
+	var hooks []*design.WebhookDefinition
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			hooks = append(hooks, a.Webhooks...)
+			return nil
+		})
+	})
+	return hooks
+}
+
+// eventBody returns the content that describes the body of the first successful response of the
+// action, nil if there is none. op is the operation generated for the action, actions with
+// multiple routes are described by the operation of the first route.
+func eventBody(a *design.ActionDefinition, op map[string]interface{}) interface{} {
+	if op == nil {
+		return nil
+	}
+	var resp *design.ResponseDefinition
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		if r.Status >= 200 && r.Status < 300 && r.MediaType != "" && (resp == nil || r.Status < resp.Status) {
+			resp = r
+		}
+		return nil
+	})
+	if resp == nil {
+		return nil
+	}
+	resps, _ := op["responses"].(map[string]interface{})
+	r, _ := resps[strconv.Itoa(resp.Status)].(map[string]interface{})
+	content, _ := r["content"].(map[string]interface{})
+	mt, ok := content[resp.MediaType]
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{resp.MediaType: mt}
+}
+
+// rewrite updates the references to point to the components, renders the unions with oneOf and
+// lists "null" in the type of the nullable schemas.
+func rewrite(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, e := range val {
+			val[k] = rewrite(e)
+		}
+		if ref, ok := val["$ref"].(string); ok {
+			for from, to := range refs {
+				if strings.HasPrefix(ref, from) {
+					val["$ref"] = to + ref[len(from):]
+					break
+				}
+			}
+		}
+		if anyOf, ok := val["anyOf"]; ok {
+			val["oneOf"] = anyOf
+			delete(val, "anyOf")
+		}
+		if nullable, ok := val["x-nullable"].(bool); ok {
+			delete(val, "x-nullable")
+			if t, ok := val["type"].(string); ok && nullable {
+				val["type"] = []interface{}{t, "null"}
+			}
+		}
+		return val
+	case []interface{}:
+		for i, e := range val {
+			val[i] = rewrite(e)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// stringSlice returns the string slice held by the given JSON value.
+func stringSlice(v interface{}) []string {
+	vals, _ := v.([]interface{})
+	res := make([]string, 0, len(vals))
+	for _, v := range vals {
+		if s, ok := v.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,201 @@
+package genopenapi_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var spec map[string]interface{}
+	var newErr error
+
+	BeforeEach(func() {
+		spec = nil
+		newErr = nil
+		dslengine.Reset()
+		ProjectedMediaTypes = make(MediaTypeRoot)
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		spec, newErr = genopenapi.New(Design)
+	})
+
+	Context("with a valid API definition", func() {
+		BeforeEach(func() {
+			basic := BasicAuthSecurity("basic")
+			API("test", func() {
+				Host("goa.design")
+				Scheme("https")
+				BasePath("/api")
+			})
+			bottle := MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("id", Integer)
+					Attribute("rating", Integer, func() {
+						Metadata("nullable")
+					})
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("rating")
+				})
+			})
+			notification := Type("Notification", func() {
+				Attribute("id", Integer)
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Action("list", func() {
+					Routing(GET(""))
+					Params(func() {
+						Param("tags", ArrayOf(String))
+					})
+					Response(OK, CollectionOf(bottle))
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Security(basic)
+					Payload(func() {
+						Attribute("rating", Integer)
+						Required("rating")
+					})
+					Topic("bottles.create", "bottles.created")
+					Webhook("bottleStored", notification, func() {
+						Description("Sent once the bottle is stored")
+						URL("{$request.body#/notify_url}")
+					})
+					Response(Created, bottle)
+				})
+			})
+		})
+
+		It("produces an OpenAPI 3.1 specification", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(spec["openapi"]).Should(Equal(genopenapi.Version))
+			Ω(spec["jsonSchemaDialect"]).Should(Equal(genopenapi.Dialect))
+			Ω(spec).ShouldNot(HaveKey("swagger"))
+			Ω(spec).ShouldNot(HaveKey("definitions"))
+			Ω(spec["servers"]).Should(Equal([]interface{}{
+				map[string]interface{}{"url": "https://goa.design/api"},
+			}))
+		})
+
+		It("moves the definitions to the components", func() {
+			components := spec["components"].(map[string]interface{})
+			Ω(components).Should(HaveKey("schemas"))
+			Ω(components["schemas"]).Should(HaveKey("Bottle"))
+			Ω(components["securitySchemes"]).Should(HaveKeyWithValue("basic",
+				map[string]interface{}{"type": "http", "scheme": "basic"}))
+		})
+
+		It("lists null in the type of nullable attributes", func() {
+			schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+			props := schemas["Bottle"].(map[string]interface{})["properties"].(map[string]interface{})
+			rating := props["rating"].(map[string]interface{})
+			Ω(rating["type"]).Should(Equal([]interface{}{"integer", "null"}))
+			Ω(rating).ShouldNot(HaveKey("x-nullable"))
+			Ω(props["id"].(map[string]interface{})["type"]).Should(Equal("integer"))
+		})
+
+		It("describes the parameters and bodies with schemas", func() {
+			path := spec["paths"].(map[string]interface{})["/bottles"].(map[string]interface{})
+			list := path["get"].(map[string]interface{})
+			tags := list["parameters"].([]interface{})[0].(map[string]interface{})
+			Ω(tags["in"]).Should(Equal("query"))
+			Ω(tags["style"]).Should(Equal("form"))
+			Ω(tags["schema"]).Should(HaveKeyWithValue("type", "array"))
+			Ω(tags).ShouldNot(HaveKey("type"))
+
+			create := path["post"].(map[string]interface{})
+			body := create["requestBody"].(map[string]interface{})
+			Ω(body["required"]).Should(BeTrue())
+			Ω(body["content"]).Should(HaveKey("application/json"))
+			created := create["responses"].(map[string]interface{})["201"].(map[string]interface{})
+			content := created["content"].(map[string]interface{})
+			schema := content["application/vnd.bottle"].(map[string]interface{})["schema"]
+			Ω(schema).Should(HaveKeyWithValue("$ref", "#/components/schemas/Bottle"))
+		})
+
+		It("describes the webhooks as callbacks", func() {
+			path := spec["paths"].(map[string]interface{})["/bottles"].(map[string]interface{})
+			create := path["post"].(map[string]interface{})
+			Ω(create).Should(HaveKey("callbacks"))
+			cb := create["callbacks"].(map[string]interface{})["bottleStored"].(map[string]interface{})
+			Ω(cb).Should(HaveKey("{$request.body#/notify_url}"))
+			post := cb["{$request.body#/notify_url}"].(map[string]interface{})["post"].(map[string]interface{})
+			Ω(post["description"]).Should(Equal("Sent once the bottle is stored"))
+			content := post["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+			schema := content["application/json"].(map[string]interface{})["schema"]
+			Ω(schema).Should(HaveKeyWithValue("$ref", "#/components/schemas/Notification"))
+			Ω(spec["components"].(map[string]interface{})["schemas"]).Should(HaveKey("Notification"))
+
+			list := path["get"].(map[string]interface{})
+			Ω(list).ShouldNot(HaveKey("callbacks"))
+		})
+
+		It("describes the result topics as webhooks", func() {
+			Ω(spec).Should(HaveKey("webhooks"))
+			hook := spec["webhooks"].(map[string]interface{})["bottles.created"].(map[string]interface{})
+			post := hook["post"].(map[string]interface{})
+			body := post["requestBody"].(map[string]interface{})
+			Ω(body["content"]).Should(HaveKey("application/vnd.bottle"))
+		})
+	})
+})
+
+var _ = Describe("Convert", func() {
+	It("renders unions with oneOf", func() {
+		sw := map[string]interface{}{
+			"swagger": "2.0",
+			"paths":   map[string]interface{}{},
+			"definitions": map[string]interface{}{
+				"Pet": map[string]interface{}{
+					"anyOf": []interface{}{
+						map[string]interface{}{"$ref": "#/definitions/Cat"},
+						map[string]interface{}{"$ref": "#/definitions/Dog"},
+					},
+				},
+			},
+		}
+		spec := genopenapi.Convert(nil, sw)
+		schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		Ω(schemas["Pet"]).Should(Equal(map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/Cat"},
+				map[string]interface{}{"$ref": "#/components/schemas/Dog"},
+			},
+		}))
+	})
+
+	It("converts the OAuth2 flows", func() {
+		sw := map[string]interface{}{
+			"paths": map[string]interface{}{},
+			"securityDefinitions": map[string]interface{}{
+				"oauth": map[string]interface{}{
+					"type":     "oauth2",
+					"flow":     "application",
+					"tokenUrl": "http://goa.design/token",
+				},
+			},
+		}
+		spec := genopenapi.Convert(nil, sw)
+		schemes := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+		Ω(schemes["oauth"]).Should(Equal(map[string]interface{}{
+			"type": "oauth2",
+			"flows": map[string]interface{}{
+				"clientCredentials": map[string]interface{}{
+					"tokenUrl": "http://goa.design/token",
+					"scopes":   map[string]interface{}{},
+				},
+			},
+		}))
+	})
+})
@@ -0,0 +1,25 @@
+/*
+Package genpact generates provider side Pact contracts for a goa API and the code that verifies
+that the service implementation satisfies them, see https://docs.pact.io.
+
+The generator writes the contract to "pact/<consumer>-<provider>.json" using the Pact
+specification version 2. The provider is the API and the consumer name is given with the
+--consumer flag. The contract contains one interaction per action route: the request is built
+from the design examples and the expected response is the first success response of the action.
+The response bodies only list the required attributes and match the actual bodies by type so
+that the contract does not depend on the example values.
+
+The generator also writes the "pact" Go package whose Verify function replays the interactions
+against a HTTP handler and reports the responses that do not satisfy the contract. Use it in the
+service tests with the service mux:
+
+	func TestContract(t *testing.T) {
+		service := goa.New("cellar")
+		app.MountBottleController(service, NewBottleController(service))
+		pact.Verify(t, service.Mux)
+	}
+
+The contract file can also be published to a Pact broker so that consumer teams can check their
+expectations against it.
+*/
+package genpact
@@ -0,0 +1,533 @@
+package genpact
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Pact Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the Pact contract generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Consumer string                // Name of the consumer of the contract
+	genfiles []string              // Generated files
+}
+
+type (
+	// Pact is a contract between a consumer and the API following the Pact specification
+	// version 2, see https://github.com/pact-foundation/pact-specification/tree/version-2.
+	Pact struct {
+		Consumer     *Pacticipant           `json:"consumer"`
+		Provider     *Pacticipant           `json:"provider"`
+		Interactions []*Interaction         `json:"interactions"`
+		Metadata     map[string]interface{} `json:"metadata"`
+	}
+
+	// Pacticipant is a party to a contract.
+	Pacticipant struct {
+		Name string `json:"name"`
+	}
+
+	// Interaction is a request and the response the consumer expects from the provider.
+	Interaction struct {
+		Description string        `json:"description"`
+		Request     *PactRequest  `json:"request"`
+		Response    *PactResponse `json:"response"`
+	}
+
+	// PactRequest describes the request of an interaction.
+	PactRequest struct {
+		Method  string            `json:"method"`
+		Path    string            `json:"path"`
+		Query   string            `json:"query,omitempty"`
+		Headers map[string]string `json:"headers,omitempty"`
+		Body    interface{}       `json:"body,omitempty"`
+	}
+
+	// PactResponse describes the response of an interaction. The response body matches the
+	// actual response body if it has the same structure and types.
+	PactResponse struct {
+		Status        int                          `json:"status"`
+		Headers       map[string]string            `json:"headers,omitempty"`
+		Body          interface{}                  `json:"body,omitempty"`
+		MatchingRules map[string]map[string]string `json:"matchingRules,omitempty"`
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, consumer string
+	set := flag.NewFlagSet("pact", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&consumer, "consumer", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Consumer: consumer, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Pact contract and the verification package.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Consumer == "" {
+		g.Consumer = "consumer"
+	}
+
+	outDir := filepath.Join(g.OutDir, "pact")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	p := g.Pact()
+	raw, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s.json", codegen.KebabCase(p.Consumer.Name), codegen.KebabCase(p.Provider.Name))
+	contract := filepath.Join(outDir, strings.Replace(name, " ", "-", -1))
+	if err = ioutil.WriteFile(contract, append(raw, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, contract)
+
+	if err = g.generateVerify(filepath.Join(outDir, "verify.go"), string(raw)); err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// Pact returns the contract describing one interaction per action route. The interaction request
+// is built from the design examples and the response is the first success response of the action.
+func (g *Generator) Pact() *Pact {
+	consumer := g.Consumer
+	if consumer == "" {
+		consumer = "consumer"
+	}
+	p := &Pact{
+		Consumer:     &Pacticipant{Name: consumer},
+		Provider:     &Pacticipant{Name: g.API.Name},
+		Interactions: []*Interaction{},
+		Metadata: map[string]interface{}{
+			"pactSpecification": map[string]string{"version": "2.0.0"},
+		},
+	}
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			resp := g.response(a)
+			if resp == nil {
+				return nil
+			}
+			for _, route := range a.Routes {
+				desc := fmt.Sprintf("%s %s", a.Name, r.Name)
+				if len(a.Routes) > 1 {
+					desc += fmt.Sprintf(" (%s %s)", route.Verb, route.FullPath())
+				}
+				p.Interactions = append(p.Interactions, &Interaction{
+					Description: desc,
+					Request:     g.request(a, route),
+					Response:    resp,
+				})
+			}
+			return nil
+		})
+	})
+	return p
+}
+
+// request builds the request of the interaction for the given action route.
+func (g *Generator) request(a *design.ActionDefinition, route *design.RouteDefinition) *PactRequest {
+	gen := g.API.RandomGenerator()
+	var params design.Object
+	if p := a.AllParams(); p != nil {
+		params = p.Type.ToObject()
+	}
+	req := &PactRequest{Method: route.Verb}
+	req.Path = design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(w string) string {
+		var ex interface{} = w[2:]
+		if att, ok := params[w[2:]]; ok {
+			ex = att.GenerateExample(gen, nil)
+		}
+		return "/" + url.PathEscape(scalar(ex))
+	})
+	if a.QueryParams != nil {
+		query := url.Values{}
+		obj := a.QueryParams.Type.ToObject()
+		for _, n := range sortedNames(obj) {
+			if !a.QueryParams.IsRequired(n) {
+				continue
+			}
+			ex := obj[n].GenerateExample(gen, nil)
+			if vals, ok := ex.([]interface{}); ok {
+				for _, v := range vals {
+					query.Add(n, scalar(v))
+				}
+				continue
+			}
+			query.Add(n, scalar(ex))
+		}
+		req.Query = query.Encode()
+	}
+	for _, h := range []*design.AttributeDefinition{a.Parent.Headers, a.Headers} {
+		if h == nil {
+			continue
+		}
+		obj := h.Type.ToObject()
+		for _, n := range sortedNames(obj) {
+			if !h.IsRequired(n) {
+				continue
+			}
+			if req.Headers == nil {
+				req.Headers = make(map[string]string)
+			}
+			req.Headers[n] = scalar(obj[n].GenerateExample(gen, nil))
+		}
+	}
+	if a.Payload != nil && !a.PayloadMultipart {
+		if req.Headers == nil {
+			req.Headers = make(map[string]string)
+		}
+		req.Headers["Content-Type"] = "application/json"
+		req.Body = jsonable(a.Payload.GenerateExample(gen, nil))
+	}
+	return req
+}
+
+// response builds the response of the interactions of the given action, nil if the action
+// defines no success response.
+func (g *Generator) response(a *design.ActionDefinition) *PactResponse {
+	var success *design.ResponseDefinition
+	a.IterateResponses(func(r *design.ResponseDefinition) error {
+		if r.Status >= 200 && r.Status < 300 && (success == nil || r.Status < success.Status) {
+			success = r
+		}
+		return nil
+	})
+	if success == nil {
+		return nil
+	}
+	resp := &PactResponse{Status: success.Status}
+	gen := g.API.RandomGenerator()
+	var body *design.AttributeDefinition
+	if mt := g.API.MediaTypeWithIdentifier(success.MediaType); mt != nil {
+		view := success.ViewName
+		if view == "" {
+			view = "default"
+		}
+		if p, _, err := mt.Project(view); err == nil {
+			body = p.AttributeDefinition
+		}
+		resp.Headers = map[string]string{"Content-Type": mt.Identifier}
+	} else if success.Type != nil {
+		body = &design.AttributeDefinition{Type: success.Type}
+		if success.MediaType != "" {
+			resp.Headers = map[string]string{"Content-Type": success.MediaType}
+		}
+	}
+	if body != nil {
+		resp.Body = required(body, jsonable(body.GenerateExample(gen, nil)))
+		resp.MatchingRules = map[string]map[string]string{"$.body": {"match": "type"}}
+	}
+	return resp
+}
+
+// generateVerify writes the verification package source file. contract is the JSON
+// representation of the contract.
+func (g *Generator) generateVerify(filename, contract string) (err error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		file.Close()
+		if err == nil {
+			err = file.FormatCode()
+		}
+	}()
+	g.genfiles = append(g.genfiles, filename)
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("mime"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("reflect"),
+		codegen.SimpleImport("testing"),
+	}
+	title := fmt.Sprintf("%s: Pact Contract Verification", g.API.Context())
+	if err = file.WriteHeader(title, "pact", imports); err != nil {
+		return err
+	}
+	return file.ExecuteTemplate("verify", verifyT, nil, map[string]interface{}{"Contract": contract})
+}
+
+// required removes the attributes that are not required from the example value v of the given
+// attribute so that the contract only expects the attributes the provider always returns.
+func required(att *design.AttributeDefinition, v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		if obj := att.Type.ToObject(); obj != nil && !att.Type.IsHash() {
+			res := make(map[string]interface{})
+			for n, e := range actual {
+				if child, ok := obj[n]; ok && att.IsRequired(n) {
+					res[n] = required(child, e)
+				}
+			}
+			return res
+		}
+		if h := att.Type.ToHash(); h != nil {
+			res := make(map[string]interface{}, len(actual))
+			for k, e := range actual {
+				res[k] = required(h.ElemType, e)
+			}
+			return res
+		}
+	case []interface{}:
+		if arr := att.Type.ToArray(); arr != nil {
+			res := make([]interface{}, len(actual))
+			for i, e := range actual {
+				res[i] = required(arr.ElemType, e)
+			}
+			return res
+		}
+	}
+	return v
+}
+
+// sortedNames returns the names of the object attributes in alphabetical order.
+func sortedNames(obj design.Object) []string {
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scalar returns the string representation of an example value.
+func scalar(v interface{}) string {
+	switch actual := v.(type) {
+	case time.Time:
+		return actual.Format(time.RFC3339)
+	case []byte:
+		return string(actual)
+	case nil:
+		return ""
+	}
+	if b, err := json.Marshal(jsonable(v)); err == nil && len(b) > 0 && (b[0] == '{' || b[0] == '[') {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// jsonable converts the hashes with non string keys produced by the example generator so that the
+// result may be encoded in JSON.
+func jsonable(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[scalar(k)] = jsonable(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = jsonable(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = jsonable(e)
+		}
+		return res
+	}
+	return v
+}
+
+const verifyT = `
+// Contract is the Pact contract verified by Verify.
+const Contract = {{ printf "%q" .Contract }}
+
+type (
+	// pact is the subset of a Pact contract used by Verify.
+	pact struct {
+		Interactions []*interaction ` + "`json:\"interactions\"`" + `
+	}
+
+	// interaction is a contract interaction.
+	interaction struct {
+		Description string ` + "`json:\"description\"`" + `
+		Request     struct {
+			Method  string            ` + "`json:\"method\"`" + `
+			Path    string            ` + "`json:\"path\"`" + `
+			Query   string            ` + "`json:\"query\"`" + `
+			Headers map[string]string ` + "`json:\"headers\"`" + `
+			Body    interface{}       ` + "`json:\"body\"`" + `
+		} ` + "`json:\"request\"`" + `
+		Response struct {
+			Status  int               ` + "`json:\"status\"`" + `
+			Headers map[string]string ` + "`json:\"headers\"`" + `
+			Body    interface{}       ` + "`json:\"body\"`" + `
+		} ` + "`json:\"response\"`" + `
+	}
+)
+
+// Verify replays the contract interactions against the given provider handler, typically the
+// service mux, and reports the responses that do not satisfy the contract. The setup functions
+// are called with each request before it is sent, use them to add credentials for example.
+func Verify(t *testing.T, handler http.Handler, setup ...func(*http.Request)) {
+	var p pact
+	if err := json.Unmarshal([]byte(Contract), &p); err != nil {
+		t.Fatalf("invalid contract: %s", err)
+	}
+	for _, i := range p.Interactions {
+		var body io.Reader
+		if i.Request.Body != nil {
+			b, err := json.Marshal(i.Request.Body)
+			if err != nil {
+				t.Fatalf("%s: invalid request body: %s", i.Description, err)
+			}
+			body = bytes.NewReader(b)
+		}
+		u := i.Request.Path
+		if i.Request.Query != "" {
+			u += "?" + i.Request.Query
+		}
+		req := httptest.NewRequest(i.Request.Method, u, body)
+		for k, v := range i.Request.Headers {
+			req.Header.Set(k, v)
+		}
+		for _, s := range setup {
+			s(req)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+
+		if rw.Code != i.Response.Status {
+			t.Errorf("%s: expected status %d, got %d", i.Description, i.Response.Status, rw.Code)
+			continue
+		}
+		for k, v := range i.Response.Headers {
+			actual := rw.Header().Get(k)
+			if k == "Content-Type" {
+				actual, _, _ = mime.ParseMediaType(actual)
+				v, _, _ = mime.ParseMediaType(v)
+			}
+			if actual != v {
+				t.Errorf("%s: expected %s header %q, got %q", i.Description, k, v, actual)
+			}
+		}
+		if i.Response.Body == nil {
+			continue
+		}
+		var actual interface{}
+		if err := json.Unmarshal(rw.Body.Bytes(), &actual); err != nil {
+			t.Errorf("%s: invalid response body: %s", i.Description, err)
+			continue
+		}
+		if err := matchType("$.body", i.Response.Body, actual); err != nil {
+			t.Errorf("%s: %s", i.Description, err)
+		}
+	}
+}
+
+// matchType returns an error if actual does not have the structure and types of expected. The
+// objects may have more fields than expected and the elements of the arrays must all match the
+// first expected element.
+func matchType(path string, expected, actual interface{}) error {
+	switch e := expected.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object, got %v", path, actual)
+		}
+		for k, v := range e {
+			av, ok := a[k]
+			if !ok {
+				return fmt.Errorf("%s: missing field %q", path, k)
+			}
+			if err := matchType(path+"."+k, v, av); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array, got %v", path, actual)
+		}
+		if len(e) == 0 {
+			return nil
+		}
+		for i, av := range a {
+			if err := matchType(fmt.Sprintf("%s[%d]", path, i), e[0], av); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+		return fmt.Errorf("%s: expected a value like %#v, got %#v", path, expected, actual)
+	}
+	return nil
+}
+`
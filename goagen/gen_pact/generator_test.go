@@ -0,0 +1,151 @@
+package genpact_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_pact"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("pacttest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String(), "--consumer=web"}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genpact.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an action returning a media type", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer, func() {
+						apidsl.Example(1)
+					})
+					apidsl.Attribute("name", design.String, func() {
+						apidsl.Example("merlot")
+					})
+					apidsl.Required("id")
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer, func() {
+							apidsl.Example(42)
+						})
+					})
+					apidsl.Response(design.NotFound)
+					apidsl.Response(design.OK, bottle)
+				})
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/:id"))
+					apidsl.Response(design.NotFound)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the contract and the verification package", func() {
+			Ω(genErr).Should(BeNil())
+			dir := filepath.Join(testPkg.Abs(), "pact")
+			Ω(files).Should(Equal([]string{
+				dir,
+				filepath.Join(dir, "web-test-api.json"),
+				filepath.Join(dir, "verify.go"),
+			}))
+
+			content, err := ioutil.ReadFile(files[1])
+			Ω(err).ShouldNot(HaveOccurred())
+			var contract map[string]interface{}
+			Ω(json.Unmarshal(content, &contract)).ShouldNot(HaveOccurred())
+			Ω(contract["consumer"]).Should(Equal(map[string]interface{}{"name": "web"}))
+			Ω(contract["provider"]).Should(Equal(map[string]interface{}{"name": "test api"}))
+			Ω(contract["interactions"]).Should(Equal([]interface{}{
+				map[string]interface{}{
+					"description": "show bottle",
+					"request":     map[string]interface{}{"method": "GET", "path": "/bottles/42"},
+					"response": map[string]interface{}{
+						"status":        200.0,
+						"headers":       map[string]interface{}{"Content-Type": "application/vnd.bottle"},
+						"body":          map[string]interface{}{"id": 1.0},
+						"matchingRules": map[string]interface{}{"$.body": map[string]interface{}{"match": "type"}},
+					},
+				},
+			}))
+
+			content, err = ioutil.ReadFile(files[2])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("package pact"))
+			Ω(string(content)).Should(ContainSubstring("func Verify(t *testing.T, handler http.Handler, setup ...func(*http.Request)) {"))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genpact.Generator
+
+	var args = struct {
+		api      *design.APIDefinition
+		outDir   string
+		consumer string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:   "out_dir",
+		consumer: "web",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genpact.NewGenerator(
+				genpact.API(args.api),
+				genpact.OutDir(args.outDir),
+				genpact.Consumer(args.consumer),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Consumer).Should(Equal(args.consumer))
+		})
+	})
+})
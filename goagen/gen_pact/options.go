@@ -0,0 +1,27 @@
+package genpact
+
+import "github.com/goadesign/goa/design"
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+//Consumer Name of the consumer of the contract
+func Consumer(consumer string) Option {
+	return func(g *Generator) {
+		g.Consumer = consumer
+	}
+}
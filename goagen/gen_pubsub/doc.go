@@ -0,0 +1,13 @@
+/*
+Package genpubsub generates the code that binds the API actions to message bus topics.
+Actions bound to a topic using the Topic DSL are subscribed to the topic by the generated
+Subscribe<Resource>Controller functions: the data of the messages received on the topic is decoded
+into the action payload and the corresponding controller method is called with the action context.
+The response body of the action is published to the result topic if one is defined. The generated
+code relies on a Bus interface that adapters for message buses such as NATS or Kafka implement.
+
+Actions that use the CloudEvents DSL exchange CloudEvents envelopes instead of raw payloads. In
+structured mode the message data is the JSON encoding of the envelope, in binary mode the event
+attributes are written in the message headers which requires a bus that implements HeaderBus.
+*/
+package genpubsub
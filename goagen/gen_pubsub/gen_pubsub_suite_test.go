@@ -0,0 +1,13 @@
+package genpubsub_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenPubSub(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenPubSub Suite")
+}
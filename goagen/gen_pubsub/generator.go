@@ -0,0 +1,493 @@
+package genpubsub
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a PubSub Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+	g.validator = codegen.NewValidator()
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the message bus bindings code generator.
+type Generator struct {
+	API       *design.APIDefinition // The API definition
+	OutDir    string                // Path to output directory
+	genfiles  []string              // Generated files
+	validator *codegen.Validator    // Validation code generator
+	target    string                // Name of the app package
+}
+
+// Resource lists the bindings of the actions of a resource.
+type Resource struct {
+	Name       string     // Resource name
+	Controller string     // Name of the resource controller, e.g. "BottleController"
+	Bindings   []*Binding // Bindings of the resource actions
+}
+
+// Binding describes an action bound to a message bus topic.
+type Binding struct {
+	Resource    string // Resource name
+	Action      string // Action name
+	Name        string // Go name of binding, e.g. "CreateBottle"
+	Topic       string // Topic the action subscribes to
+	ResultTopic string // Topic the action response is published to, may be empty
+	Verb        string // HTTP method of the action route
+	Path        string // Path of the action route
+	CloudEvents string // CloudEvents content mode, empty if the messages are not wrapped
+	Source      string // CloudEvents source of the published events
+	EventType   string // CloudEvents type of the events published to the topic
+	ResultType  string // CloudEvents type of the events published to the result topic
+	Method      string // Name of the controller method implementing the action, e.g. "Create"
+	Payload     string // Type of the action payload in the app package, empty if none
+	Pointer     bool   // Whether the payload is passed by reference
+	Validatable bool   // Whether the payload has validations
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("pubsub", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design, validator: codegen.NewValidator()}
+
+	return g.Generate()
+}
+
+// Generate produces the message bus bindings.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	// The app package may be generated in another directory, see codegen.OutputDir.
+	appDir := filepath.ToSlash(codegen.OutputDir(g.API, "app", "app"))
+	g.target = path.Base(appDir)
+	outPkg, err := codegen.PackagePath(g.OutDir)
+	if err != nil {
+		return
+	}
+	appPkg := path.Join(outPkg, appDir)
+
+	outDir := filepath.Join(g.OutDir, "pubsub")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	filename := filepath.Join(outDir, "pubsub.go")
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, filename)
+	resources := g.resources()
+	cloudEvents := false
+	for _, r := range resources {
+		for _, b := range r.Bindings {
+			if b.CloudEvents != "" {
+				cloudEvents = true
+			}
+		}
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport(appPkg),
+	}
+	if cloudEvents {
+		imports = append(imports,
+			codegen.SimpleImport("encoding/json"),
+			codegen.SimpleImport("fmt"),
+			codegen.SimpleImport("strings"),
+			codegen.SimpleImport("time"),
+			codegen.SimpleImport("github.com/goadesign/goa/uuid"),
+		)
+	}
+	title := fmt.Sprintf("%s: Message Bus Bindings", g.API.Context())
+	if err = file.WriteHeader(title, "pubsub", imports); err != nil {
+		return
+	}
+	data := map[string]interface{}{
+		"Resources":   resources,
+		"Target":      g.target,
+		"ContentType": g.contentType(),
+		"CloudEvents": cloudEvents,
+		"Structured":  design.CloudEventsStructured,
+	}
+	if err = file.ExecuteTemplate("pubsub", pubsubT, nil, data); err != nil {
+		return
+	}
+	if err = file.FormatCode(); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// resources returns the bindings of all the actions that define a topic grouped by resource.
+func (g *Generator) resources() []*Resource {
+	var resources []*Resource
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		res := &Resource{Name: r.Name, Controller: codegen.Goify(r.Name, true) + "Controller"}
+		err := r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Topic == "" || len(a.Routes) == 0 {
+				return nil
+			}
+			b := &Binding{
+				Resource:    r.Name,
+				Action:      a.Name,
+				Name:        codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true),
+				Topic:       a.Topic,
+				ResultTopic: a.ResultTopic,
+				Verb:        a.Routes[0].Verb,
+				Path:        a.Routes[0].FullPath(),
+				CloudEvents: a.CloudEvents,
+				Method:      codegen.Goify(a.Name, true),
+			}
+			if a.Payload != nil {
+				b.Payload = fmt.Sprintf("%s.%s", g.target, codegen.Goify(a.Payload.TypeName, true))
+				b.Pointer = !a.Payload.IsPrimitive() && !a.Payload.IsArray() && !a.Payload.IsHash()
+				validate := g.validator.Code(a.Payload.AttributeDefinition, false, false, false, "payload", "raw", 1, false)
+				b.Validatable = validate != ""
+			}
+			if b.CloudEvents != "" {
+				api, res := eventName(g.API.Name), eventName(r.Name)
+				b.Source = fmt.Sprintf("/%s/%s", api, res)
+				b.EventType = a.EventType
+				if b.EventType == "" {
+					b.EventType = fmt.Sprintf("%s.%s.%s", api, res, eventName(a.Name))
+				}
+				b.ResultType = b.EventType + ".result"
+			}
+			res.Bindings = append(res.Bindings, b)
+			return nil
+		})
+		if len(res.Bindings) > 0 {
+			resources = append(resources, res)
+		}
+		return err
+	})
+	return resources
+}
+
+// eventName returns the snake case version of name used in CloudEvents sources and types.
+func eventName(name string) string {
+	return strings.Join(strings.FieldsFunc(codegen.SnakeCase(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_")
+}
+
+// contentType returns the content type used to decode the message data.
+func (g *Generator) contentType() string {
+	for _, enc := range g.API.Consumes {
+		if len(enc.MIMETypes) > 0 {
+			return enc.MIMETypes[0]
+		}
+	}
+	return "application/json"
+}
+
+const pubsubT = `// Message is a message received from the message bus.
+type Message struct {
+	// Topic is the name of the topic the message was published to.
+	Topic string
+	// Data is the message content.
+	Data []byte
+	// Header contains the message headers if the bus supports them.
+	Header map[string]string
+}
+
+// Bus is the interface implemented by the message bus clients, for example NATS or Kafka
+// adapters.
+type Bus interface {
+	// Subscribe registers handler to be called for each message published to topic.
+	Subscribe(topic string, handler func(*Message)) error
+	// Publish publishes data to topic.
+	Publish(topic string, data []byte) error
+}
+{{ if .CloudEvents }}
+// HeaderBus is the interface implemented by the message bus clients that support message headers.
+// It is required by the actions that use binary CloudEvents.
+type HeaderBus interface {
+	Bus
+	// PublishWithHeader publishes data to topic with the given message headers.
+	PublishWithHeader(topic string, data []byte, header map[string]string) error
+}
+
+// CloudEvent is a CloudEvents envelope, see https://cloudevents.io.
+type CloudEvent struct {
+	SpecVersion     string          ` + "`" + `json:"specversion"` + "`" + `
+	ID              string          ` + "`" + `json:"id"` + "`" + `
+	Source          string          ` + "`" + `json:"source"` + "`" + `
+	Type            string          ` + "`" + `json:"type"` + "`" + `
+	Time            string          ` + "`" + `json:"time,omitempty"` + "`" + `
+	DataContentType string          ` + "`" + `json:"datacontenttype,omitempty"` + "`" + `
+	Data            json.RawMessage ` + "`" + `json:"data,omitempty"` + "`" + `
+	DataBase64      []byte          ` + "`" + `json:"data_base64,omitempty"` + "`" + `
+}
+
+{{ end }}{{ range .Resources }}
+// Subscribe{{ .Controller }} subscribes the {{ .Name }} actions bound to topics to the given bus.
+// The data of the messages received on a topic is decoded into the action payload and the action
+// is run by calling the corresponding ctrl method.
+func Subscribe{{ .Controller }}(service *goa.Service, bus Bus, ctrl {{ $.Target }}.{{ .Controller }}) error {
+	c := service.NewController({{ printf "%q" .Controller }})
+{{ range .Bindings }}	if err := bus.Subscribe({{ printf "%q" .Topic }}, handle{{ .Name }}(c, bus, ctrl)); err != nil {
+		return err
+	}
+{{ end }}	return nil
+}
+{{ $ctrl := .Controller }}{{ range .Bindings }}
+// handle{{ .Name }} returns the message handler that runs the {{ .Resource }} {{ .Action }} action.{{ if .ResultTopic }}
+// The response body is {{ if .CloudEvents }}wrapped in a CloudEvent {{ end }}published to the {{ printf "%q" .ResultTopic }} topic.{{ end }}
+func handle{{ .Name }}(c *goa.Controller, bus Bus, ctrl {{ $.Target }}.{{ $ctrl }}) func(*Message) {
+	return func(m *Message) {
+{{ if .CloudEvents }}		e, {{ if .Payload }}data{{ else }}_{{ end }}, err := readEvent(m, {{ printf "%q" .CloudEvents }})
+		if err != nil {
+			c.Service.LogError("pubsub", "topic", m.Topic, "err", err)
+			return
+		}
+		header := eventHeader(e, {{ printf "%q" $.ContentType }})
+{{ else }}{{ if .Payload }}		data := m.Data
+{{ end }}		header := http.Header{"Content-Type": {{ "{" }}{{ printf "%q" $.ContentType }}{{ "}" }}}
+{{ end }}{{ if .Payload }}		{{ if .Pointer }}payload := &{{ .Payload }}{}{{ else }}var payload {{ .Payload }}{{ end }}
+		if err := c.Service.Decoder.Decode({{ if not .Pointer }}&{{ end }}payload, bytes.NewReader(data), header.Get("Content-Type")); err != nil {
+			c.Service.LogError("pubsub", "topic", m.Topic, "err", err)
+			return
+		}
+{{ if .Validatable }}		if !c.Service.SkipValidation {
+			if err := payload.Validate(); err != nil {
+				c.Service.LogError("pubsub", "topic", m.Topic, "err", err)
+				return
+			}
+		}
+{{ end }}{{ end }}		ctx, rw, err := newContext(c, {{ printf "%q" .Action }}, {{ printf "%q" .Verb }}, {{ printf "%q" .Path }}, header)
+		if err != nil {
+			c.Service.LogError("pubsub", "topic", m.Topic, "err", err)
+			return
+		}
+		rctx, err := {{ $.Target }}.New{{ .Name }}Context(ctx, goa.ContextRequest(ctx).Request, c.Service)
+		if err != nil {
+			c.Service.LogError("pubsub", "topic", m.Topic, "err", err)
+			return
+		}
+{{ if .Payload }}		rctx.Payload = payload
+{{ end }}		if err := ctrl.{{ .Method }}(rctx); err != nil {
+			c.Service.LogError("pubsub", "topic", m.Topic, "err", err)
+			return
+		}
+		if rw.status >= 400 {
+			c.Service.LogError("pubsub", "topic", m.Topic, "status", rw.status, "err", rw.body.String())
+			return
+		}
+{{ if .ResultTopic }}{{ if .CloudEvents }}		re := newEvent({{ printf "%q" .Source }}, {{ printf "%q" .ResultType }}, rw.header.Get("Content-Type"), rw.body.Bytes())
+		if err := publishEvent(bus, {{ printf "%q" .ResultTopic }}, {{ printf "%q" .CloudEvents }}, re); err != nil {
+{{ else }}		if err := bus.Publish({{ printf "%q" .ResultTopic }}, rw.body.Bytes()); err != nil {
+{{ end }}			c.Service.LogError("pubsub", "topic", {{ printf "%q" .ResultTopic }}, "err", err)
+		}
+{{ end }}	}
+}
+{{ end }}{{ end }}{{ range .Resources }}{{ range .Bindings }}
+// Publish{{ .Name }} publishes payload to the {{ printf "%q" .Topic }} topic the {{ .Resource }} {{ .Action }} action subscribes to.{{ if .CloudEvents }}
+// The payload is wrapped in a {{ .CloudEvents }} CloudEvent of type {{ printf "%q" .EventType }}.{{ end }}
+func Publish{{ .Name }}(service *goa.Service, bus Bus, payload interface{}) error {
+	var buf bytes.Buffer
+	if err := service.Encoder.Encode(payload, &buf, {{ printf "%q" $.ContentType }}); err != nil {
+		return err
+	}
+{{ if .CloudEvents }}	e := newEvent({{ printf "%q" .Source }}, {{ printf "%q" .EventType }}, {{ printf "%q" $.ContentType }}, buf.Bytes())
+	return publishEvent(bus, {{ printf "%q" .Topic }}, {{ printf "%q" .CloudEvents }}, e)
+{{ else }}	return bus.Publish({{ printf "%q" .Topic }}, buf.Bytes())
+{{ end }}}
+{{ end }}{{ end }}
+// newContext returns the context used to run the given action of the controller. The context
+// request is built from the action method, path and the given headers, the context response is
+// recorded by the returned writer. The design validation guarantees that path has no parameters.
+func newContext(c *goa.Controller, action, verb, path string, header http.Header) (context.Context, *responseWriter, error) {
+	req, err := http.NewRequest(verb, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header = header
+	rw := &responseWriter{header: make(http.Header)}
+	return goa.NewContext(goa.WithAction(c.Context, action), rw, req, nil), rw, nil
+}
+
+// responseWriter records the response written by an action run from a message.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the response headers.
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+// WriteHeader records the response status code.
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+}
+
+// Write records the response body.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	return rw.body.Write(b)
+}
+{{ if .CloudEvents }}
+// eventHeader returns the request headers of the action run from the CloudEvent e. The event
+// attributes are set in the "Ce-" headers.
+func eventHeader(e *CloudEvent, contentType string) http.Header {
+	if e.DataContentType != "" {
+		contentType = e.DataContentType
+	}
+	header := http.Header{
+		"Content-Type":   {contentType},
+		"Ce-Specversion": {e.SpecVersion},
+		"Ce-Id":          {e.ID},
+		"Ce-Source":      {e.Source},
+		"Ce-Type":        {e.Type},
+	}
+	if e.Time != "" {
+		header.Set("Ce-Time", e.Time)
+	}
+	return header
+}
+
+// newEvent returns a CloudEvent of the given source and type wrapping data.
+func newEvent(source, typ, contentType string, data []byte) *CloudEvent {
+	e := &CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewV4().String(),
+		Source:          source,
+		Type:            typ,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: contentType,
+	}
+	if strings.Contains(contentType, "json") && json.Valid(data) {
+		e.Data = data
+	} else {
+		e.DataBase64 = data
+	}
+	return e
+}
+
+// publishEvent publishes the CloudEvent e to topic using the given content mode.
+func publishEvent(bus Bus, topic, mode string, e *CloudEvent) error {
+	if mode == {{ printf "%q" .Structured }} {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if hb, ok := bus.(HeaderBus); ok {
+			return hb.PublishWithHeader(topic, data, map[string]string{"content-type": "application/cloudevents+json"})
+		}
+		return bus.Publish(topic, data)
+	}
+	hb, ok := bus.(HeaderBus)
+	if !ok {
+		return fmt.Errorf("binary CloudEvents require a bus implementing HeaderBus")
+	}
+	data := []byte(e.Data)
+	if e.DataBase64 != nil {
+		data = e.DataBase64
+	}
+	header := map[string]string{
+		"ce-specversion": e.SpecVersion,
+		"ce-id":          e.ID,
+		"ce-source":      e.Source,
+		"ce-type":        e.Type,
+		"ce-time":        e.Time,
+		"content-type":   e.DataContentType,
+	}
+	return hb.PublishWithHeader(topic, data, header)
+}
+
+// readEvent extracts the CloudEvent and its data from a message using the given content mode.
+func readEvent(m *Message, mode string) (*CloudEvent, []byte, error) {
+	var (
+		e    CloudEvent
+		data []byte
+	)
+	if mode == {{ printf "%q" .Structured }} {
+		if err := json.Unmarshal(m.Data, &e); err != nil {
+			return nil, nil, fmt.Errorf("invalid CloudEvent: %s", err)
+		}
+		data = []byte(e.Data)
+		if e.DataBase64 != nil {
+			data = e.DataBase64
+		}
+	} else {
+		// Header names are case insensitive and some buses use "ce_" prefixes.
+		h := make(map[string]string, len(m.Header))
+		for k, v := range m.Header {
+			h[strings.Replace(strings.ToLower(k), "_", "-", -1)] = v
+		}
+		e = CloudEvent{
+			SpecVersion:     h["ce-specversion"],
+			ID:              h["ce-id"],
+			Source:          h["ce-source"],
+			Type:            h["ce-type"],
+			Time:            h["ce-time"],
+			DataContentType: h["content-type"],
+		}
+		data = m.Data
+	}
+	if e.SpecVersion == "" || e.ID == "" || e.Source == "" || e.Type == "" {
+		return nil, nil, fmt.Errorf("invalid CloudEvent: missing required attributes")
+	}
+	return &e, data, nil
+}
+{{ end }}`
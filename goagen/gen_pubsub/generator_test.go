@@ -0,0 +1,148 @@
+package genpubsub_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_pubsub"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("pubsubtest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genpubsub.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an action bound to a topic", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+					})
+					apidsl.Topic("bottles.create", "bottles.created")
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the bindings", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "pubsub", "pubsub.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("func SubscribeBottleController(service *goa.Service, bus Bus, ctrl app.BottleController) error {"))
+			Ω(string(content)).Should(ContainSubstring(`bus.Subscribe("bottles.create", handleCreateBottle(c, bus, ctrl))`))
+			Ω(string(content)).Should(ContainSubstring("payload := &app.CreateBottlePayload{}"))
+			Ω(string(content)).Should(ContainSubstring("rctx, err := app.NewCreateBottleContext(ctx, goa.ContextRequest(ctx).Request, c.Service)"))
+			Ω(string(content)).Should(ContainSubstring("rctx.Payload = payload"))
+			Ω(string(content)).Should(ContainSubstring("if err := ctrl.Create(rctx); err != nil {"))
+			Ω(string(content)).Should(ContainSubstring(`if err := bus.Publish("bottles.created", rw.body.Bytes()); err != nil {`))
+			Ω(string(content)).ShouldNot(ContainSubstring("httptest"))
+			Ω(string(content)).ShouldNot(ContainSubstring("service.Mux"))
+			Ω(string(content)).Should(ContainSubstring("func PublishCreateBottle(service *goa.Service, bus Bus, payload interface{}) error {"))
+			Ω(string(content)).ShouldNot(ContainSubstring("ShowBottle"))
+			Ω(string(content)).ShouldNot(ContainSubstring("CloudEvent"))
+		})
+	})
+
+	Context("with an action using CloudEvents", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+					})
+					apidsl.Topic("bottles.create", "bottles.created")
+					apidsl.CloudEvents(design.CloudEventsBinary)
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the bindings wrapping the messages in CloudEvents", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "pubsub", "pubsub.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`e, data, err := readEvent(m, "binary")`))
+			Ω(string(content)).Should(ContainSubstring(`re := newEvent("/test_api/bottle", "test_api.bottle.create.result", rw.header.Get("Content-Type"), rw.body.Bytes())`))
+			Ω(string(content)).Should(ContainSubstring(`e := newEvent("/test_api/bottle", "test_api.bottle.create", "application/json", buf.Bytes())`))
+			Ω(string(content)).Should(ContainSubstring("type CloudEvent struct {"))
+			Ω(string(content)).Should(ContainSubstring("type HeaderBus interface {"))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genpubsub.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genpubsub.NewGenerator(
+				genpubsub.API(args.api),
+				genpubsub.OutDir(args.outDir),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+		})
+	})
+})
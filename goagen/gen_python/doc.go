@@ -0,0 +1,17 @@
+/*
+Package genpython generates a Python client package for the API.
+
+The package is written under the "python" directory together with a setup.py script. The API types,
+media types and action payloads are described by dataclasses that know how to decode and encode
+their JSON representation. The Client class exposes one method per action, the path parameters,
+payload and query string parameters of the action map to the method arguments and the method
+returns the decoded body of the first successful response of the action.
+
+Each error response defined in the design maps to an exception class that inherits from
+ServiceError, the client raises the exception corresponding to the response status when the API
+responds with a 4xx or 5xx status.
+
+The client uses the requests library by default, any object exposing a compatible request method
+such as a httpx client may be given instead.
+*/
+package genpython
@@ -0,0 +1,747 @@
+package genpython
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Python Client Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the Python client package generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Package  string                // Name of the generated Python package
+	Timeout  time.Duration         // Timeout used by the client when making requests
+	Scheme   string                // Scheme used by the client
+	Host     string                // Host addressed by the client
+	genfiles []string              // Generated files
+}
+
+type (
+	// Class describes a Python dataclass generated for an object type.
+	Class struct {
+		Name        string   // Class name
+		Description string   // Class description
+		Fields      []*Field // Class fields, required fields first
+	}
+
+	// Field describes a dataclass field or a client method argument.
+	Field struct {
+		Name        string // Python identifier
+		Key         string // Name of the attribute in the design
+		Description string // Field description
+		Type        string // Python type hint
+		Decode      string // Python callable that decodes the JSON value if any
+		Required    bool   // Whether the field is required
+	}
+
+	// Method describes a client method generated for an action.
+	Method struct {
+		Name        string   // Method name, e.g. "show_bottle"
+		Description string   // Method description
+		Verb        string   // HTTP method
+		Path        string   // Python expression that builds the request path
+		Args        []*Field // Method arguments, required arguments first
+		Payload     string   // Name of the payload argument if any
+		Query       []*Field // Query string parameters
+		Result      string   // Python type hint of the result if any
+		Decode      string   // Python callable that decodes the result if any
+		Errors      []*Error // Exceptions raised for the action error responses
+	}
+
+	// Error describes a Python exception generated for a design error response.
+	Error struct {
+		Name     string // Exception class name
+		Response string // Name of the response in the design
+		Status   int    // HTTP status code
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var (
+		outDir, ver, pkg string
+		scheme, host     string
+		timeout          time.Duration
+	)
+	set := flag.NewFlagSet("python", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&pkg, "package", "", "")
+	set.DurationVar(&timeout, "timeout", time.Duration(20)*time.Second, "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Package: pkg, Timeout: timeout, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the Python client package.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if g.Package == "" {
+		g.Package = pyName(g.API.Name)
+	}
+	if g.Timeout == 0 {
+		g.Timeout = 20 * time.Second
+	}
+	if g.Scheme == "" && len(g.API.Schemes) > 0 {
+		g.Scheme = g.API.Schemes[0]
+	}
+	if g.Scheme == "" {
+		g.Scheme = "http"
+	}
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		g.Host = "localhost"
+	}
+
+	outDir := filepath.Join(g.OutDir, "python")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	pkgDir := filepath.Join(outDir, g.Package)
+	if err = os.MkdirAll(pkgDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	b := &modelBuilder{classes: make(map[string]*Class)}
+	methods := b.methods(g.API)
+	names := make([]string, len(b.classes))
+	i := 0
+	for n := range b.classes {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	classes := make([]*Class, len(names))
+	for i, n := range names {
+		classes[i] = b.classes[n]
+	}
+	errs, byStatus := errorClasses(g.API)
+	version := g.API.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+
+	data := map[string]interface{}{
+		"API":            g.API,
+		"Package":        g.Package,
+		"Version":        version,
+		"Scheme":         g.Scheme,
+		"Host":           g.Host,
+		"Timeout":        g.Timeout.Seconds(),
+		"Classes":        classes,
+		"Methods":        methods,
+		"Errors":         errs,
+		"ErrorsByStatus": byStatus,
+	}
+	files := []struct{ path, tmpl string }{
+		{filepath.Join(outDir, "setup.py"), setupT},
+		{filepath.Join(pkgDir, "__init__.py"), initT},
+		{filepath.Join(pkgDir, "models.py"), modelsT},
+		{filepath.Join(pkgDir, "errors.py"), errorsT},
+		{filepath.Join(pkgDir, "client.py"), clientT},
+	}
+	for _, f := range files {
+		if err = g.generateFile(f.path, f.tmpl, data); err != nil {
+			return
+		}
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// generateFile renders the given template into the file with the given path.
+func (g *Generator) generateFile(path, tmpl string, data map[string]interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	g.genfiles = append(g.genfiles, path)
+	t := template.Must(template.New(filepath.Base(path)).Funcs(template.FuncMap{"join": strings.Join}).Parse(tmpl))
+	return t.Execute(f, data)
+}
+
+// modelBuilder computes the Python dataclasses and client methods from the API design.
+type modelBuilder struct {
+	classes map[string]*Class
+}
+
+// methods returns the client methods of all the API actions and records the classes they use.
+func (b *modelBuilder) methods(api *design.APIDefinition) []*Method {
+	var methods []*Method
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 {
+				return nil
+			}
+			name := codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true)
+			m := &Method{
+				Name:        pyName(a.Name + "_" + r.Name),
+				Description: a.Description,
+				Verb:        a.Routes[0].Verb,
+			}
+			if m.Description == "" {
+				m.Description = fmt.Sprintf("%s calls the %s action of the %s resource.", m.Name, a.Name, r.Name)
+			}
+			var required, optional []*Field
+
+			// Path parameters
+			params := a.AllParams()
+			path := a.Routes[0].FullPath()
+			var pathArgs []string
+			for _, match := range design.WildcardRegex.FindAllStringSubmatch(path, -1) {
+				var att *design.AttributeDefinition
+				if params != nil {
+					att = params.Type.ToObject()[match[1]]
+				}
+				if att == nil {
+					att = &design.AttributeDefinition{Type: design.String}
+				}
+				hint, _ := b.typeRef(att, name+codegen.Goify(match[1], true))
+				arg := &Field{Name: argName(match[1]), Key: match[1], Description: att.Description, Type: hint, Required: true}
+				required = append(required, arg)
+				safe := ""
+				if strings.HasPrefix(match[0], "/*") {
+					safe = "/"
+				}
+				pathArgs = append(pathArgs, fmt.Sprintf("quote(str(%s), safe=%q)", arg.Name, safe))
+			}
+			format := design.WildcardRegex.ReplaceAllLiteralString(path, "/{}")
+			m.Path = fmt.Sprintf("%q", format)
+			if len(pathArgs) > 0 {
+				m.Path = fmt.Sprintf("%q.format(%s)", format, strings.Join(pathArgs, ", "))
+			}
+
+			// Payload
+			if a.Payload != nil {
+				hint, _ := b.typeRef(a.Payload.AttributeDefinition, codegen.Goify(a.Payload.TypeName, true))
+				arg := &Field{Name: "payload", Key: "payload", Description: a.Payload.Description, Type: hint, Required: !a.PayloadOptional}
+				if arg.Description == "" {
+					arg.Description = "The request body."
+				}
+				m.Payload = arg.Name
+				if arg.Required {
+					required = append(required, arg)
+				} else {
+					optional = append(optional, arg)
+				}
+			}
+
+			// Query string parameters
+			if a.QueryParams != nil {
+				obj := a.QueryParams.Type.ToObject()
+				for _, n := range sortedKeys(obj) {
+					att := obj[n]
+					hint, _ := b.typeRef(att, name+codegen.Goify(n, true))
+					arg := &Field{Name: argName(n), Key: n, Description: att.Description, Type: hint, Required: a.QueryParams.IsRequired(n)}
+					m.Query = append(m.Query, arg)
+					if arg.Required {
+						required = append(required, arg)
+					} else {
+						optional = append(optional, arg)
+					}
+				}
+			}
+			m.Args = append(required, optional...)
+			for _, arg := range m.Args {
+				if arg.Description == "" {
+					arg.Description = fmt.Sprintf("The %q parameter.", arg.Key)
+				}
+			}
+
+			if att := successResult(api, a); att != nil {
+				m.Result, m.Decode = b.typeRef(att, name+"Result")
+			}
+			a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				if resp.Status >= 400 {
+					m.Errors = append(m.Errors, &Error{Name: errorName(resp.Name), Response: resp.Name, Status: resp.Status})
+				}
+				return nil
+			})
+			methods = append(methods, m)
+			return nil
+		})
+	})
+	return methods
+}
+
+// successResult returns the attribute describing the body of the first successful response of the
+// action, nil if there is none.
+func successResult(api *design.APIDefinition, a *design.ActionDefinition) *design.AttributeDefinition {
+	names := make([]string, len(a.Responses))
+	i := 0
+	for n := range a.Responses {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		r := a.Responses[n]
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		if r.Type != nil {
+			return &design.AttributeDefinition{Type: r.Type}
+		}
+		if mt := api.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+			return &design.AttributeDefinition{Type: mt}
+		}
+	}
+	return nil
+}
+
+// typeRef returns the Python type hint of att and the Python callable that decodes its JSON value,
+// defining the dataclasses it uses as needed. The callable is empty if the JSON value needs no
+// decoding. name is the name given to the class if att is an inline object.
+func (b *modelBuilder) typeRef(att *design.AttributeDefinition, name string) (string, string) {
+	switch t := att.Type.(type) {
+	case design.Primitive:
+		switch t.Kind() {
+		case design.BooleanKind:
+			return "bool", ""
+		case design.IntegerKind:
+			return "int", ""
+		case design.NumberKind:
+			return "float", ""
+		case design.StringKind, design.DateTimeKind, design.UUIDKind:
+			return "str", ""
+		case design.FileKind:
+			return "bytes", ""
+		}
+		return "Any", ""
+	case *design.Array:
+		hint, decode := b.typeRef(t.ElemType, name+"Elem")
+		if decode != "" {
+			decode = fmt.Sprintf("lambda v: [_decode(%s, e) for e in v]", decode)
+		}
+		return fmt.Sprintf("List[%s]", hint), decode
+	case *design.Hash:
+		key, _ := b.typeRef(t.KeyType, name+"Key")
+		hint, decode := b.typeRef(t.ElemType, name+"Elem")
+		if decode != "" {
+			decode = fmt.Sprintf("lambda v: {k: _decode(%s, e) for k, e in v.items()}", decode)
+		}
+		return fmt.Sprintf("Dict[%s, %s]", key, hint), decode
+	case design.Object:
+		cname := b.class(t, att, name)
+		return cname, cname + ".from_dict"
+	case *design.UserTypeDefinition:
+		return b.typeRef(t.AttributeDefinition, codegen.Goify(t.TypeName, true))
+	case *design.MediaTypeDefinition:
+		return b.typeRef(t.AttributeDefinition, codegen.Goify(t.TypeName, true))
+	}
+	return "Any", ""
+}
+
+// class defines the Python dataclass corresponding to the given object and returns its name.
+func (b *modelBuilder) class(o design.Object, att *design.AttributeDefinition, name string) string {
+	if _, ok := b.classes[name]; ok {
+		return name
+	}
+	c := &Class{Name: name, Description: att.Description}
+	if c.Description == "" {
+		c.Description = fmt.Sprintf("%s is a type of the API.", name)
+	}
+	b.classes[name] = c
+	var required, optional []*Field
+	for _, n := range sortedKeys(o) {
+		fatt := o[n]
+		hint, decode := b.typeRef(fatt, name+codegen.Goify(n, true))
+		f := &Field{
+			Name:        fieldName(n),
+			Key:         n,
+			Description: fatt.Description,
+			Type:        hint,
+			Decode:      decode,
+			Required:    att.IsRequired(n),
+		}
+		if f.Required {
+			required = append(required, f)
+		} else {
+			optional = append(optional, f)
+		}
+	}
+	c.Fields = append(required, optional...)
+	return name
+}
+
+// errorClasses returns the exceptions generated for the error responses of the API actions and the
+// exceptions raised for each status code sorted by status code.
+func errorClasses(api *design.APIDefinition) ([]*Error, []*Error) {
+	errs := make(map[string]*Error)
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			return a.IterateResponses(func(resp *design.ResponseDefinition) error {
+				if resp.Status >= 400 {
+					n := errorName(resp.Name)
+					errs[n] = &Error{Name: n, Response: resp.Name, Status: resp.Status}
+				}
+				return nil
+			})
+		})
+	})
+	names := make([]string, len(errs))
+	i := 0
+	for n := range errs {
+		names[i] = n
+		i++
+	}
+	sort.Strings(names)
+	res := make([]*Error, len(names))
+	statuses := make(map[int]*Error)
+	for i, n := range names {
+		res[i] = errs[n]
+		if _, ok := statuses[errs[n].Status]; !ok {
+			statuses[errs[n].Status] = errs[n]
+		}
+	}
+	byStatus := make([]*Error, 0, len(statuses))
+	for _, e := range statuses {
+		byStatus = append(byStatus, e)
+	}
+	sort.Slice(byStatus, func(i, j int) bool { return byStatus[i].Status < byStatus[j].Status })
+	return res, byStatus
+}
+
+// errorName returns the name of the exception raised for the response with the given name.
+func errorName(response string) string {
+	n := codegen.Goify(response, true)
+	if strings.HasSuffix(n, "Error") {
+		return n
+	}
+	return n + "Error"
+}
+
+// keywords lists the Python reserved words.
+var keywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// pyName returns the snake_case Python identifier for the given name.
+func pyName(name string) string {
+	runes := []rune(codegen.Goify(name, true))
+	var b []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b = append(b, '_')
+			}
+		}
+		b = append(b, unicode.ToLower(r))
+	}
+	return string(b)
+}
+
+// fieldName returns the dataclass field name for the given attribute name.
+func fieldName(n string) string {
+	name := pyName(n)
+	if keywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// argName returns the client method argument name for the given parameter name. The names used
+// by the generated method bodies are suffixed with an underscore.
+func argName(n string) string {
+	name := fieldName(n)
+	switch name {
+	case "self", "path", "body", "payload", "kwargs":
+		name += "_"
+	}
+	return name
+}
+
+// sortedKeys returns the names of the object attributes in alphabetical order.
+func sortedKeys(o design.Object) []string {
+	keys := make([]string, len(o))
+	i := 0
+	for n := range o {
+		keys[i] = n
+		i++
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setupT generates the package setup script.
+// template input: map[string]interface{}
+const setupT = `# {{ .API.Context }}: Python Client Setup
+#
+# Code generated by goagen, DO NOT EDIT.
+
+from setuptools import setup
+
+setup(
+    name={{ printf "%q" .Package }},
+    version={{ printf "%q" .Version }},
+    description={{ printf "%q" (printf "%s API client" .API.Name) }},
+    packages=[{{ printf "%q" .Package }}],
+    python_requires=">=3.7",
+    install_requires=["requests"],
+)
+`
+
+// initT generates the package __init__.py file.
+// template input: map[string]interface{}
+const initT = `# {{ .API.Context }}: Python Client
+#
+# Code generated by goagen, DO NOT EDIT.
+
+from .client import Client
+from .errors import ServiceError{{ range .Errors }}, {{ .Name }}{{ end }}
+{{ range .Classes }}from .models import {{ .Name }}
+{{ end }}`
+
+// modelsT generates the dataclasses that describe the API types.
+// template input: map[string]interface{}
+const modelsT = `# {{ .API.Context }}: Python Models
+#
+# Code generated by goagen, DO NOT EDIT.
+
+from __future__ import annotations
+
+import dataclasses
+from dataclasses import dataclass
+from typing import Any, Dict, List, Optional
+
+
+def _decode(fn, value):
+    """_decode applies fn to value unless value is None."""
+    if value is None:
+        return None
+    return fn(value)
+
+
+def _encode(value):
+    """_encode returns the JSON representation of value."""
+    if dataclasses.is_dataclass(value):
+        return value.to_dict()
+    if isinstance(value, list):
+        return [_encode(v) for v in value]
+    if isinstance(value, dict):
+        return {k: _encode(v) for k, v in value.items()}
+    return value
+{{ range .Classes }}
+
+@dataclass
+class {{ .Name }}:
+    """{{ .Description }}{{ if .Fields }}
+
+    Attributes:
+{{ range .Fields }}        {{ .Name }}: {{ if .Description }}{{ .Description }}{{ else }}The {{ printf "%q" .Key }} attribute.{{ end }}
+{{ end }}    {{ end }}"""
+{{ if .Fields }}
+{{ range .Fields }}    {{ .Name }}: {{ if .Required }}{{ .Type }}{{ else }}Optional[{{ .Type }}] = None{{ end }}
+{{ end }}{{ end }}
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> {{ .Name }}:
+        """from_dict builds a {{ .Name }} from its JSON representation."""
+        return cls(
+{{ range .Fields }}            {{ .Name }}={{ if .Decode }}_decode({{ .Decode }}, data.get({{ printf "%q" .Key }})){{ else }}data.get({{ printf "%q" .Key }}){{ end }},
+{{ end }}        )
+
+    def to_dict(self) -> Dict[str, Any]:
+        """to_dict returns the JSON representation of the {{ .Name }}."""
+        fields = (
+{{ range .Fields }}            ({{ printf "%q" .Key }}, self.{{ .Name }}),
+{{ end }}        )
+        return {k: _encode(v) for k, v in fields if v is not None}
+{{ end }}`
+
+// errorsT generates the exceptions raised by the client.
+// template input: map[string]interface{}
+const errorsT = `# {{ .API.Context }}: Python Client Errors
+#
+# Code generated by goagen, DO NOT EDIT.
+
+from typing import Any
+
+
+class ServiceError(Exception):
+    """ServiceError is raised when the API responds with a 4xx or 5xx status. The id, code,
+    detail and meta attributes are set when the response body is a goa error."""
+
+    status = 0
+
+    def __init__(self, status: int, body: Any = None, response: Any = None):
+        data = body if isinstance(body, dict) else {}
+        super().__init__(data.get("detail") or "HTTP status {}".format(status))
+        self.status = status
+        self.body = body
+        self.response = response
+        self.id = data.get("id")
+        self.code = data.get("code")
+        self.detail = data.get("detail")
+        self.meta = data.get("meta")
+{{ range .Errors }}
+
+class {{ .Name }}(ServiceError):
+    """{{ .Name }} is raised when the API responds with the {{ .Response }} response."""
+
+    status = {{ .Status }}
+{{ end }}
+
+_ERRORS = {
+{{ range .ErrorsByStatus }}    {{ .Status }}: {{ .Name }},
+{{ end }}}
+
+
+def error_for(status: int, body: Any = None, response: Any = None) -> ServiceError:
+    """error_for returns the exception corresponding to the given response status."""
+    return _ERRORS.get(status, ServiceError)(status, body, response)
+`
+
+// clientT generates the API client.
+// template input: map[string]interface{}
+const clientT = `# {{ .API.Context }}: Python Client
+#
+# Code generated by goagen, DO NOT EDIT.
+
+from __future__ import annotations
+
+from typing import Any, Dict, List, Optional
+from urllib.parse import quote
+
+from .errors import error_for
+from .models import _decode, _encode
+{{ range .Classes }}from .models import {{ .Name }}
+{{ end }}
+
+def _query(value: Any) -> Any:
+    """_query returns the query string representation of value."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, list):
+        return [_query(v) for v in value]
+    return value
+
+
+class Client:
+    """Client is the {{ .API.Name }} API client.
+
+    The requests are made with a requests session by default, any object exposing a compatible
+    request method such as a httpx client may be used instead. The keyword arguments given to the
+    client methods are given to the session request method.
+    """
+
+    def __init__(
+        self,
+        scheme: str = {{ printf "%q" .Scheme }},
+        host: str = {{ printf "%q" .Host }},
+        timeout: float = {{ .Timeout }},
+        session: Any = None,
+        headers: Optional[Dict[str, str]] = None,
+    ):
+        if session is None:
+            import requests
+
+            session = requests.Session()
+        self.base_url = "{}://{}".format(scheme, host)
+        self.timeout = timeout
+        self.session = session
+        self.headers = dict(headers or {})
+
+    def _request(
+        self,
+        method: str,
+        path: str,
+        payload: Any = None,
+        params: Optional[Dict[str, Any]] = None,
+        **kwargs
+    ) -> Any:
+        """_request sends the request and returns the decoded response body. It raises the
+        exception corresponding to the response status if the status is 4xx or 5xx."""
+        headers = dict(self.headers)
+        headers.update(kwargs.pop("headers", None) or {})
+        headers.setdefault("Accept", "application/json")
+        if payload is not None:
+            kwargs["json"] = _encode(payload)
+        if params:
+            kwargs["params"] = {k: _query(v) for k, v in params.items() if v is not None}
+        kwargs.setdefault("timeout", self.timeout)
+        response = self.session.request(method, self.base_url + path, headers=headers, **kwargs)
+        body = None
+        if response.content:
+            try:
+                body = response.json()
+            except ValueError:
+                body = response.text
+        if response.status_code >= 400:
+            raise error_for(response.status_code, body, response)
+        return body
+{{ range .Methods }}
+    def {{ .Name }}(self{{ range .Args }}, {{ .Name }}: {{ if .Required }}{{ .Type }}{{ else }}Optional[{{ .Type }}] = None{{ end }}{{ end }}, **kwargs) -> {{ if .Result }}{{ .Result }}{{ else }}None{{ end }}:
+        """{{ .Description }}
+{{ if .Args }}
+        Args:
+{{ range .Args }}            {{ .Name }}: {{ .Description }}
+{{ end }}{{ end }}
+        Raises:
+{{ range .Errors }}            {{ .Name }}: if the API responds with the {{ .Response }} response.
+{{ end }}            ServiceError: if the response status is 4xx or 5xx.
+        """
+        path = {{ .Path }}
+        {{ if .Result }}body = {{ end }}self._request({{ printf "%q" .Verb }}, path{{ if .Payload }}, payload={{ .Payload }}{{ end }}{{ if .Query }}, params={{ "{" }}{{ range $i, $q := .Query }}{{ if $i }}, {{ end }}{{ printf "%q" $q.Key }}: {{ $q.Name }}{{ end }}{{ "}" }}{{ end }}, **kwargs)
+{{ if .Result }}        return {{ if .Decode }}_decode({{ .Decode }}, body){{ else }}body{{ end }}
+{{ end }}{{ end }}`
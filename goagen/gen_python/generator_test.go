@@ -0,0 +1,164 @@
+package genpython_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_python"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("pythontest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genpython.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a resource with two actions", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Title("dummy API")
+				apidsl.Host("goa.design")
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("name", design.String)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+					apidsl.Attribute("name")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer)
+					})
+					apidsl.Response(design.OK, bottle)
+					apidsl.Response(design.NotFound)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String)
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.NoContent)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the package", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(6))
+			_, err := os.Stat(filepath.Join(testPkg.Abs(), "python", "test_api", "__init__.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the models", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "python", "test_api", "models.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("class Bottle:\n"))
+			Ω(string(content)).Should(ContainSubstring("    id: Optional[int] = None\n    name: Optional[str] = None\n"))
+			Ω(string(content)).Should(ContainSubstring("class CreateBottlePayload:\n"))
+			Ω(string(content)).Should(ContainSubstring("    name: str\n"))
+		})
+
+		It("generates the client", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "python", "test_api", "client.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`host: str = "goa.design",`))
+			Ω(string(content)).Should(ContainSubstring("    def show_bottle(self, id: int, **kwargs) -> Bottle:\n"))
+			Ω(string(content)).Should(ContainSubstring(`        path = "/bottles/{}".format(quote(str(id), safe=""))`))
+			Ω(string(content)).Should(ContainSubstring("        return _decode(Bottle.from_dict, body)\n"))
+			Ω(string(content)).Should(ContainSubstring("    def create_bottle(self, payload: CreateBottlePayload, **kwargs) -> None:\n"))
+			Ω(string(content)).Should(ContainSubstring(`        self._request("POST", path, payload=payload, **kwargs)`))
+		})
+
+		It("generates the errors", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "python", "test_api", "errors.py"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("class NotFoundError(ServiceError):\n"))
+			Ω(string(content)).Should(ContainSubstring("    404: NotFoundError,\n"))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *genpython.Generator
+
+	var args = struct {
+		api     *design.APIDefinition
+		outDir  string
+		pkg     string
+		timeout time.Duration
+		scheme  string
+		host    string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir:  "out_dir",
+		pkg:     "client",
+		timeout: time.Millisecond * 500,
+		scheme:  "https",
+		host:    "localhost",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = genpython.NewGenerator(
+				genpython.API(args.api),
+				genpython.OutDir(args.outDir),
+				genpython.Package(args.pkg),
+				genpython.Timeout(args.timeout),
+				genpython.Scheme(args.scheme),
+				genpython.Host(args.host),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Package).Should(Equal(args.pkg))
+			Ω(generator.Timeout).Should(Equal(args.timeout))
+			Ω(generator.Scheme).Should(Equal(args.scheme))
+			Ω(generator.Host).Should(Equal(args.host))
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package genpython
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/design"
+)
+
+//Option a generator option definition
+type Option func(*Generator)
+
+//API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+//OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+//Package Name of the generated Python package
+func Package(pkg string) Option {
+	return func(g *Generator) {
+		g.Package = pkg
+	}
+}
+
+//Timeout Timeout used by the Python client when making requests
+func Timeout(timeout time.Duration) Option {
+	return func(g *Generator) {
+		g.Timeout = timeout
+	}
+}
+
+//Scheme Scheme used by the Python client
+func Scheme(scheme string) Option {
+	return func(g *Generator) {
+		g.Scheme = scheme
+	}
+}
+
+//Host addressed by the Python client
+func Host(host string) Option {
+	return func(g *Generator) {
+		g.Host = host
+	}
+}
@@ -3,6 +3,7 @@ package genschema
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/url"
 	"reflect"
 	"sort"
@@ -46,6 +47,7 @@ type (
 		MaxItems             *int          `json:"maxItems,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
+		Nullable             bool          `json:"x-nullable,omitempty"`
 
 		// Union
 		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
@@ -429,6 +431,7 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		MaxItems:             s.MaxItems,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
+		Nullable:             s.Nullable,
 	}
 	for n, p := range s.Properties {
 		js.Properties[n] = p.Dup()
@@ -459,6 +462,7 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.Description = at.Description
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
 	s.ReadOnly = at.IsReadOnly()
+	_, s.Nullable = at.Metadata["nullable"]
 	val := at.Validation
 	if val == nil {
 		return s
@@ -574,12 +578,34 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 		for _, ln := range lnames {
 			var (
 				att  = links[ln]
-				lmt  = att.Type.(*design.MediaTypeDefinition)
-				r    = lmt.Resource
+				lmt  *design.MediaTypeDefinition
 				href string
 			)
-			if r != nil {
-				href = toSchemaHref(api, r.CanonicalAction().Routes[0])
+			if att.Type == design.HALLink {
+				// HAL links are link objects, use the linked attribute media type. mt may be
+				// a projection that does not render the linked attributes, look them up in
+				// the media type it was projected from.
+				src := mt
+				if base, _, err := mime.ParseMediaType(mt.Identifier); err == nil {
+					if orig := api.MediaTypeWithIdentifier(base); orig != nil {
+						src = orig
+					}
+				}
+				if ln == "self" {
+					lmt = src
+				} else if la, ok := src.Type.ToObject()[ln]; ok {
+					lmt, _ = la.Type.(*design.MediaTypeDefinition)
+				}
+				if lmt == nil {
+					continue
+				}
+			} else {
+				lmt = att.Type.(*design.MediaTypeDefinition)
+			}
+			if r := lmt.Resource; r != nil {
+				if ca := r.CanonicalAction(); ca != nil && len(ca.Routes) > 0 {
+					href = toSchemaHref(api, ca.Routes[0])
+				}
 			}
 			sm := NewJSONSchema()
 			sm.Ref = MediaTypeRef(api, lmt, "default")
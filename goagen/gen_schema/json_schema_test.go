@@ -78,4 +78,50 @@ var _ = Describe("TypeSchema", func() {
 		})
 
 	})
+
+	Context("with a media type using HAL links", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+			account := MediaType("application/vnd.account", func() {
+				TypeName("Account")
+				Attributes(func() {
+					Attribute("id", design.Integer)
+				})
+				View("default", func() {
+					Attribute("id")
+				})
+			})
+			MediaType("application/vnd.bottle", func() {
+				TypeName("Bottle")
+				Attributes(func() {
+					Attribute("id", design.Integer)
+					Attribute("account", account)
+				})
+				Links(func() {
+					HAL()
+					Link("account")
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("links")
+				})
+			})
+
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = design.Design.MediaTypes["application/vnd.bottle"]
+		})
+
+		It("describes the links with the linked media types", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Ref).Should(Equal("#/definitions/Bottle"))
+			def := genschema.Definitions["Bottle"]
+			Ω(def).ShouldNot(BeNil())
+			Ω(def.Properties).Should(HaveKey("_links"))
+			Ω(def.Links).Should(HaveLen(2))
+			Ω(def.Links[0].Rel).Should(Equal("account"))
+			Ω(def.Links[0].MediaType).Should(Equal("application/vnd.account"))
+			Ω(def.Links[1].Rel).Should(Equal("self"))
+			Ω(def.Links[1].MediaType).Should(Equal("application/vnd.bottle"))
+		})
+	})
 })
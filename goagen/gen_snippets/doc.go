@@ -0,0 +1,13 @@
+/*
+Package gensnippets generates documentation files listing example invocations of the API actions.
+
+The generator writes one Markdown file per resource under the "snippets" directory. Each action is
+documented with a curl command per route that can be copied and pasted in a shell: the path
+parameters, query string parameters, headers and request body of the command are built from the
+examples defined in the design or generated from the attribute types and validations when there
+are none. Multipart form payloads are sent with the curl -F flag, other payloads are encoded in
+JSON. Credentials required by the action security scheme are read from environment variables (for
+example $TOKEN for JWT and OAuth2 schemes). Actions served over WebSocket are documented with a
+websocat command instead.
+*/
+package gensnippets
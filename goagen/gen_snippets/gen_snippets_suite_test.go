@@ -0,0 +1,13 @@
+package gensnippets_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenSnippets(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenSnippets Suite")
+}
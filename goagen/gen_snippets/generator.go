@@ -0,0 +1,381 @@
+package gensnippets
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+//NewGenerator returns an initialized instance of a Snippets Generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the example invocation snippets generator.
+type Generator struct {
+	API      *design.APIDefinition // The API definition
+	OutDir   string                // Path to output directory
+	Scheme   string                // Scheme used in the snippet URLs
+	Host     string                // Host used in the snippet URLs
+	genfiles []string              // Generated files
+}
+
+type (
+	// Resource describes the snippets of the actions of a resource.
+	Resource struct {
+		Name        string    // Resource name
+		Description string    // Resource description
+		Actions     []*Action // Resource actions
+	}
+
+	// Action describes the snippets of an action.
+	Action struct {
+		Name        string   // Action name
+		Description string   // Action description
+		Commands    []string // Shell commands, one per action route
+		Env         []string // Environment variables read by the commands
+	}
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver, scheme, host string
+	set := flag.NewFlagSet("snippets", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.StringVar(&scheme, "scheme", "", "")
+	set.StringVar(&host, "host", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, Scheme: scheme, Host: host, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the snippets files.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	g.defaultHost()
+
+	outDir := filepath.Join(g.OutDir, "snippets")
+	if err = os.RemoveAll(outDir); err != nil {
+		return
+	}
+	if err = os.MkdirAll(outDir, 0755); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, outDir)
+
+	t := template.Must(template.New("snippets").Funcs(template.FuncMap{"join": strings.Join}).Parse(resourceT))
+	err = g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		res := g.resource(r)
+		if len(res.Actions) == 0 {
+			return nil
+		}
+		path := filepath.Join(outDir, codegen.SnakeCase(r.Name)+".md")
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		g.genfiles = append(g.genfiles, path)
+		return t.Execute(f, map[string]interface{}{"API": g.API, "Resource": res})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.RemoveAll(f)
+	}
+	g.genfiles = nil
+}
+
+// Snippets returns the snippets of the actions of the given resource. The commands use the API
+// host or "localhost:8080" if Host is empty.
+func (g *Generator) Snippets(r *design.ResourceDefinition) *Resource {
+	g.defaultHost()
+	return g.resource(r)
+}
+
+// defaultHost initializes Host with the API host or "localhost:8080" if it is empty.
+func (g *Generator) defaultHost() {
+	if g.Host == "" {
+		g.Host = g.API.Host
+	}
+	if g.Host == "" {
+		g.Host = "localhost:8080"
+	}
+}
+
+// resource computes the snippets of the actions of the given resource.
+func (g *Generator) resource(r *design.ResourceDefinition) *Resource {
+	res := &Resource{Name: r.Name, Description: r.Description}
+	r.IterateActions(func(a *design.ActionDefinition) error {
+		if len(a.Routes) == 0 {
+			return nil
+		}
+		act := &Action{Name: a.Name, Description: a.Description}
+		env := make(map[string]bool)
+		for _, route := range a.Routes {
+			act.Commands = append(act.Commands, g.command(a, route, env))
+		}
+		for v := range env {
+			act.Env = append(act.Env, "$"+v)
+		}
+		sort.Strings(act.Env)
+		res.Actions = append(res.Actions, act)
+		return nil
+	})
+	return res
+}
+
+// command returns the shell command that invokes the given action route. The names of the
+// environment variables read by the command are recorded in env.
+func (g *Generator) command(a *design.ActionDefinition, route *design.RouteDefinition, env map[string]bool) string {
+	gen := g.API.RandomGenerator()
+
+	// URL
+	scheme := g.Scheme
+	if scheme == "" {
+		scheme = a.CanonicalScheme()
+	}
+	var params design.Object
+	if p := a.AllParams(); p != nil {
+		params = p.Type.ToObject()
+	}
+	path := design.WildcardRegex.ReplaceAllStringFunc(route.FullPath(), func(w string) string {
+		var ex interface{} = w[2:]
+		if att, ok := params[w[2:]]; ok {
+			ex = att.GenerateExample(gen, nil)
+		}
+		return "/" + url.PathEscape(scalar(ex))
+	})
+	query := url.Values{}
+	if a.QueryParams != nil {
+		for _, n := range sortedNames(a.QueryParams.Type.ToObject()) {
+			ex := a.QueryParams.Type.ToObject()[n].GenerateExample(gen, nil)
+			if vals, ok := ex.([]interface{}); ok {
+				for _, v := range vals {
+					query.Add(n, scalar(v))
+				}
+				continue
+			}
+			query.Add(n, scalar(ex))
+		}
+	}
+	u := fmt.Sprintf("%s://%s%s", scheme, g.Host, path)
+	if q := query.Encode(); q != "" {
+		u += "?" + q
+	}
+
+	// Credentials
+	var (
+		args   []string
+		suffix string // query string part that references environment variables
+		ws     = a.WebSocket()
+	)
+	if sec := a.Security; sec != nil && sec.Scheme != nil {
+		switch sec.Scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			env["USERNAME"], env["PASSWORD"] = true, true
+			if ws {
+				args = append(args, `--basic-auth "$USERNAME:$PASSWORD"`)
+			} else {
+				args = append(args, `-u "$USERNAME:$PASSWORD"`)
+			}
+		case design.APIKeySecurityKind:
+			env["API_KEY"] = true
+			if sec.Scheme.In == "query" {
+				sep := "?"
+				if strings.Contains(u, "?") {
+					sep = "&"
+				}
+				suffix = sep + url.QueryEscape(sec.Scheme.Name) + "=$API_KEY"
+			} else {
+				args = append(args, fmt.Sprintf(`-H "%s: $API_KEY"`, sec.Scheme.Name))
+			}
+		case design.JWTSecurityKind, design.OAuth2SecurityKind:
+			env["TOKEN"] = true
+			args = append(args, `-H "Authorization: Bearer $TOKEN"`)
+		case design.SessionSecurityKind:
+			env["SESSION"] = true
+			args = append(args, fmt.Sprintf(`-H "Cookie: %s=$SESSION"`, sec.Scheme.Name))
+		case design.MutualTLSSecurityKind:
+			if !ws {
+				args = append(args, "--cert client.crt", "--key client.key")
+			}
+		}
+	}
+
+	// Headers
+	for _, h := range []*design.AttributeDefinition{a.Parent.Headers, a.Headers} {
+		if h == nil {
+			continue
+		}
+		obj := h.Type.ToObject()
+		for _, n := range sortedNames(obj) {
+			ex := obj[n].GenerateExample(gen, nil)
+			if vals, ok := ex.([]interface{}); ok {
+				strs := make([]string, len(vals))
+				for i, v := range vals {
+					strs[i] = scalar(v)
+				}
+				ex = strings.Join(strs, ",")
+			}
+			args = append(args, "-H "+singleQuote(n+": "+scalar(ex)))
+		}
+	}
+
+	if ws {
+		// curl does not speak WebSocket.
+		cmd := append([]string{"websocat " + quoteURL(u, suffix)}, args...)
+		return strings.Join(cmd, " \\\n  ")
+	}
+
+	// Body
+	if a.Payload != nil {
+		ex := jsonable(a.Payload.GenerateExample(gen, nil))
+		if obj, ok := ex.(map[string]interface{}); ok && a.PayloadMultipart {
+			attrs := a.Payload.ToObject()
+			for _, n := range sortedNames(attrs) {
+				if attrs[n].Type.Kind() == design.FileKind {
+					args = append(args, "-F "+singleQuote(n+"=@"+n))
+					continue
+				}
+				if v, ok := obj[n]; ok {
+					args = append(args, "-F "+singleQuote(n+"="+scalar(v)))
+				}
+			}
+		} else if body, err := json.Marshal(ex); err == nil {
+			args = append(args, "-H 'Content-Type: application/json'", "-d "+singleQuote(string(body)))
+		}
+	}
+
+	first := "curl"
+	if route.Verb != "GET" {
+		first += " -X " + route.Verb
+	}
+	first += " " + quoteURL(u, suffix)
+	return strings.Join(append([]string{first}, args...), " \\\n  ")
+}
+
+// sortedNames returns the names of the object attributes in alphabetical order.
+func sortedNames(obj design.Object) []string {
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scalar returns the string representation of an example value.
+func scalar(v interface{}) string {
+	switch actual := v.(type) {
+	case time.Time:
+		return actual.Format(time.RFC3339)
+	case []byte:
+		return string(actual)
+	case nil:
+		return ""
+	}
+	if b, err := json.Marshal(jsonable(v)); err == nil && len(b) > 0 && (b[0] == '{' || b[0] == '[') {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// jsonable converts the hashes with non string keys produced by the example generator so that the
+// result may be encoded in JSON.
+func jsonable(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[scalar(k)] = jsonable(e)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, e := range actual {
+			res[k] = jsonable(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, e := range actual {
+			res[i] = jsonable(e)
+		}
+		return res
+	}
+	return v
+}
+
+// singleQuote quotes s for the shell, the result is not subject to expansion.
+func singleQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// quoteURL quotes the URL u for the shell using double quotes so that the environment variables
+// referenced by suffix are expanded. suffix is appended to u as is.
+func quoteURL(u, suffix string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	return `"` + r.Replace(u) + suffix + `"`
+}
+
+const resourceT = `# {{ .Resource.Name }}
+{{ if .Resource.Description }}
+{{ .Resource.Description }}
+{{ end }}
+Example invocations of the actions of the {{ .Resource.Name }} resource of the {{ .API.Name }} API.
+The parameter values and request bodies are built from the design examples.
+{{ range .Resource.Actions }}
+## {{ .Name }}
+{{ if .Description }}
+{{ .Description }}
+{{ end }}{{ if .Env }}
+The command reads the credentials from the {{ join .Env ", " }} environment variable{{ if gt (len .Env) 1 }}s{{ end }}.
+{{ end }}{{ range .Commands }}
+` + "```sh" + `
+{{ . }}
+` + "```" + `
+{{ end }}{{ end }}`
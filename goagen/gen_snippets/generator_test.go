@@ -0,0 +1,146 @@
+package gensnippets_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_snippets"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("snippetstest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gensnippets.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a resource with actions", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			apidsl.API("test api", func() {
+				apidsl.Host("goa.design")
+				apidsl.Scheme("https")
+			})
+			jwt := apidsl.JWTSecurity("jwt", func() {
+				apidsl.Header("Authorization")
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.BasePath("/bottles")
+				apidsl.Action("show", func() {
+					apidsl.Description("Retrieve a bottle")
+					apidsl.Routing(apidsl.GET("/:id"))
+					apidsl.Params(func() {
+						apidsl.Param("id", design.Integer, func() {
+							apidsl.Example(42)
+						})
+						apidsl.Param("view", design.String, func() {
+							apidsl.Example("full")
+						})
+					})
+					apidsl.Response(design.NoContent)
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST(""))
+					apidsl.Security(jwt)
+					apidsl.Headers(func() {
+						apidsl.Header("X-Request-Id", design.String, func() {
+							apidsl.Example("abc")
+						})
+					})
+					apidsl.Payload(func() {
+						apidsl.Attribute("name", design.String, func() {
+							apidsl.Example("Chateau d'Yquem")
+						})
+						apidsl.Required("name")
+					})
+					apidsl.Response(design.Created)
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		})
+
+		It("generates one file per resource", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			_, err := os.Stat(filepath.Join(testPkg.Abs(), "snippets", "bottle.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("generates the curl commands", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "snippets", "bottle.md"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("# bottle\n"))
+			Ω(string(content)).Should(ContainSubstring("## show\n\nRetrieve a bottle\n"))
+			Ω(string(content)).Should(ContainSubstring("```sh\ncurl \"https://goa.design/bottles/42?view=full\"\n```"))
+			Ω(string(content)).Should(ContainSubstring("curl -X POST \"https://goa.design/bottles\" \\\n"))
+			Ω(string(content)).Should(ContainSubstring(`  -H "Authorization: Bearer $TOKEN" \` + "\n"))
+			Ω(string(content)).Should(ContainSubstring(`  -H 'X-Request-Id: abc' \` + "\n"))
+			Ω(string(content)).Should(ContainSubstring(`  -H 'Content-Type: application/json' \` + "\n"))
+			Ω(string(content)).Should(ContainSubstring(`  -d '{"name":"Chateau d'\''Yquem"}'`))
+			Ω(string(content)).Should(ContainSubstring("the $TOKEN environment variable."))
+		})
+	})
+})
+
+var _ = Describe("NewGenerator", func() {
+	var generator *gensnippets.Generator
+
+	var args = struct {
+		api    *design.APIDefinition
+		outDir string
+		scheme string
+		host   string
+	}{
+		api: &design.APIDefinition{
+			Name: "test api",
+		},
+		outDir: "out_dir",
+		scheme: "https",
+		host:   "localhost",
+	}
+
+	Context("with options all options set", func() {
+		BeforeEach(func() {
+			generator = gensnippets.NewGenerator(
+				gensnippets.API(args.api),
+				gensnippets.OutDir(args.outDir),
+				gensnippets.Scheme(args.scheme),
+				gensnippets.Host(args.host),
+			)
+		})
+
+		It("has all public properties set with expected value", func() {
+			Ω(generator).ShouldNot(BeNil())
+			Ω(generator.API.Name).Should(Equal(args.api.Name))
+			Ω(generator.OutDir).Should(Equal(args.outDir))
+			Ω(generator.Scheme).Should(Equal(args.scheme))
+			Ω(generator.Host).Should(Equal(args.host))
+		})
+	})
+})
@@ -49,6 +49,7 @@ func Generate() (files []string, err error) {
 	set.StringVar(&target, "pkg", "app", "")
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("force", false, "")
+	set.Bool("debug-listener", false, "")
 	set.Bool("notest", false, "")
 	set.Parse(os.Args[1:])
 
@@ -80,7 +81,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 		return nil, err
 	}
 
-	swaggerDir := filepath.Join(g.OutDir, "swagger")
+	swaggerDir := filepath.Join(g.OutDir, codegen.OutputDir(g.API, "swagger", "swagger"))
 	os.RemoveAll(swaggerDir)
 	if err = os.MkdirAll(swaggerDir, 0755); err != nil {
 		return nil, err
@@ -516,6 +516,10 @@ func securityDefsFromDefinition(schemes []*design.SecuritySchemeDefinition) map[
 
 	defs := make(map[string]*SecurityDefinition)
 	for _, scheme := range schemes {
+		if scheme.Kind == design.MutualTLSSecurityKind || scheme.Kind == design.SessionSecurityKind {
+			// Swagger 2.0 cannot describe mutual TLS or cookie authentication
+			continue
+		}
 		def := &SecurityDefinition{
 			Type:             scheme.Type,
 			Description:      scheme.Description,
@@ -919,6 +923,15 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 	}
 
 	params = append(params, paramsFromHeaders(action)...)
+	if action.CSRF {
+		params = append(params, &Parameter{
+			Name:        "X-CSRF-Token",
+			In:          "header",
+			Description: "CSRF token, must match the value of the csrf_token cookie",
+			Required:    true,
+			Type:        "string",
+		})
+	}
 
 	responses := make(map[string]*Response, len(action.Responses))
 	for _, r := range action.Responses {
@@ -1071,6 +1084,22 @@ func computePaths(operation *Operation, s *Swagger, route *design.RouteDefinitio
 }
 
 func applySecurity(operation *Operation, security *design.SecurityDefinition) {
+	if security != nil && security.Scheme.Kind == design.MutualTLSSecurityKind {
+		// Swagger 2.0 cannot describe mutual TLS, document the requirement instead
+		if operation.Description != "" {
+			operation.Description += "\n\n"
+		}
+		operation.Description += fmt.Sprintf("Requires a TLS client certificate verified by the server (`%s` mutual TLS security scheme).", security.Scheme.SchemeName)
+		return
+	}
+	if security != nil && security.Scheme.Kind == design.SessionSecurityKind {
+		// Swagger 2.0 cannot describe cookie authentication, document the requirement instead
+		if operation.Description != "" {
+			operation.Description += "\n\n"
+		}
+		operation.Description += fmt.Sprintf("Requires a valid `%s` session cookie (`%s` session security scheme).", security.Scheme.Name, security.Scheme.SchemeName)
+		return
+	}
 	if security != nil && security.Scheme.Kind != design.NoSecurityKind {
 		if security.Scheme.Kind == design.JWTSecurityKind && len(security.Scopes) > 0 {
 			if operation.Description != "" {
@@ -823,5 +823,126 @@ var _ = Describe("New", func() {
 			})
 
 		})
+
+		Context("with security schemes", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("jwt", func() {
+						Routing(GET("/jwt"))
+						Security("jwt", func() {
+							Scope("read")
+						})
+						Response(NoContent)
+					})
+					Action("mtls", func() {
+						Routing(GET("/mtls"))
+						Security("mtls")
+						Response(NoContent)
+					})
+					Action("session", func() {
+						Routing(GET("/session"))
+						Security("session")
+						Response(NoContent)
+					})
+				})
+				base := Design.DSLFunc
+				Design.DSLFunc = func() {
+					base()
+					JWTSecurity("jwt", func() {
+						Header("Authorization")
+						Scope("read", "Read access")
+					})
+					MutualTLSSecurity("mtls")
+					SessionSecurity("session", func() {
+						Cookie("sid")
+					})
+				}
+			})
+
+			It("documents the security requirements", func() {
+				Ω(swagger.SecurityDefinitions).Should(HaveKey("jwt"))
+				Ω(swagger.SecurityDefinitions).ShouldNot(HaveKey("mtls"))
+				jwt := swagger.Paths["/jwt"].(*genswagger.Path)
+				Ω(jwt.Get.Security).Should(Equal([]map[string][]string{{"jwt": {"read"}}}))
+				Ω(jwt.Get.Description).Should(ContainSubstring("Required security scopes:\n  * `read`"))
+				mtls := swagger.Paths["/mtls"].(*genswagger.Path)
+				Ω(mtls.Get.Security).Should(BeNil())
+				Ω(mtls.Get.Description).Should(ContainSubstring("`mtls` mutual TLS security scheme"))
+				Ω(swagger.SecurityDefinitions).ShouldNot(HaveKey("session"))
+				session := swagger.Paths["/session"].(*genswagger.Path)
+				Ω(session.Get.Security).Should(BeNil())
+				Ω(session.Get.Description).Should(ContainSubstring("valid `sid` session cookie"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with a CSRF protected action", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("update", func() {
+						Routing(PUT("/"))
+						CSRF()
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("documents the CSRF header", func() {
+				p := swagger.Paths["/"].(*genswagger.Path)
+				Ω(p.Put.Parameters).Should(HaveLen(1))
+				Ω(p.Put.Parameters[0].Name).Should(Equal("X-CSRF-Token"))
+				Ω(p.Put.Parameters[0].In).Should(Equal("header"))
+				Ω(p.Put.Parameters[0].Required).Should(BeTrue())
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with a response using a HAL media type", func() {
+			BeforeEach(func() {
+				account := MediaType("application/vnd.account", func() {
+					TypeName("Account")
+					Attributes(func() {
+						Attribute("id", Integer)
+					})
+					View("default", func() {
+						Attribute("id")
+					})
+				})
+				bottle := MediaType("application/vnd.bottle", func() {
+					TypeName("Bottle")
+					Attributes(func() {
+						Attribute("id", Integer)
+						Attribute("account", account)
+					})
+					Links(func() {
+						HAL()
+						Link("account")
+					})
+					View("default", func() {
+						Attribute("id")
+						Attribute("links")
+					})
+				})
+				Resource("bottle", func() {
+					Action("show", func() {
+						Routing(GET("/bottles/:id"))
+						Response(OK, bottle)
+					})
+				})
+			})
+
+			It("renders the links in a _links object", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(swagger.Definitions).Should(HaveKey("Bottle"))
+				Ω(swagger.Definitions["Bottle"].Properties).Should(HaveKey("_links"))
+				Ω(swagger.Definitions).Should(HaveKey("BottleLinks"))
+				Ω(swagger.Definitions["BottleLinks"].Properties).Should(HaveKey("account"))
+				Ω(swagger.Definitions["BottleLinks"].Properties).Should(HaveKey("self"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
 	})
 })
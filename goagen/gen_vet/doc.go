@@ -0,0 +1,21 @@
+/*
+Package genvet looks for definitions of a goa API design that are likely mistakes before they
+become runtime surprises.
+
+The generator does not write any file. The command fails and lists the findings if there are any
+so that it can be used to guard continuous integration pipelines:
+
+	goagen vet -d github.com/goadesign/goa-cellar/design
+
+The checks are:
+
+  - unused-type: user types and media types that are not used by the payload, parameters,
+    headers or responses of any action, directly or through the attributes of other types,
+  - unused-response: responses and response templates defined at the API level with
+    ResponseTemplate that no action returns,
+  - field-collision: attributes of the same object that map to the same Go struct field, taking
+    the "struct:field:name" metadata into account, or to the same JSON key, taking the
+    "struct:tag:json" metadata into account. The parameters and the headers of an action are
+    checked together as they are fields of the same context struct.
+*/
+package genvet
@@ -0,0 +1,67 @@
+package genvet
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+//NewGenerator returns an initialized instance of a design vetter
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the design vetter. It looks for definitions that are likely mistakes because they
+// are never used or because they would silently clash in the generated code.
+type Generator struct {
+	API *design.APIDefinition // The API definition
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var ver string
+	set := flag.NewFlagSet("vet", flag.PanicOnError)
+	set.String("out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate vets the design. It does not write any file and returns an error listing the findings
+// if there are any.
+func (g *Generator) Generate() ([]string, error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	findings := Vet(g.API)
+	if len(findings) == 0 {
+		return nil, nil
+	}
+	lines := make([]string, len(findings))
+	for i, f := range findings {
+		lines[i] = "  - " + f.String()
+	}
+	return nil, fmt.Errorf("%d vet finding(s):\n%s", len(findings), strings.Join(lines, "\n"))
+}
+
+// Cleanup is a no-op, the vetter does not generate files.
+func (g *Generator) Cleanup() {}
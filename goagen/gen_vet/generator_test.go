@@ -0,0 +1,57 @@
+package genvet_test
+
+import (
+	"os"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_vet"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apidsl.API("test api", func() {})
+		apidsl.Resource("bottle", func() {
+			apidsl.Action("delete", func() {
+				apidsl.Routing(apidsl.DELETE("/bottles"))
+				apidsl.Response(design.NoContent)
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=.", "--design=foo", "--version=" + version.String()}
+		files, genErr = genvet.Generate()
+	})
+
+	Context("with a clean design", func() {
+		It("succeeds without generating files", func() {
+			Ω(genErr).ShouldNot(HaveOccurred())
+			Ω(files).Should(BeEmpty())
+		})
+	})
+
+	Context("with findings", func() {
+		BeforeEach(func() {
+			apidsl.Type("orphan", func() {
+				apidsl.Attribute("name", design.String)
+			})
+		})
+
+		It("returns an error listing them", func() {
+			Ω(genErr).Should(HaveOccurred())
+			Ω(genErr.Error()).Should(ContainSubstring("1 vet finding(s)"))
+			Ω(genErr.Error()).Should(ContainSubstring("type orphan: the type is not used by any action [unused-type]"))
+		})
+	})
+})
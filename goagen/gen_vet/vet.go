@@ -0,0 +1,314 @@
+package genvet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// Finding is a design definition that is likely a mistake.
+	Finding struct {
+		// Check is the name of the check that produced the finding.
+		Check string
+		// Location describes the offending design definition.
+		Location string
+		// Message describes the problem.
+		Message string
+	}
+
+	// Check looks for a class of mistakes in the design.
+	Check struct {
+		// Name is the name used in the findings.
+		Name string
+		// Description describes the mistakes detected by the check.
+		Description string
+		// Run returns the locations and messages of the mistakes found in api.
+		Run func(api *design.APIDefinition) []*Finding
+	}
+)
+
+// Checks lists the vet checks in the order they run.
+var Checks = []*Check{
+	{
+		Name:        "unused-type",
+		Description: "user types and media types that are not used by the payload, parameters, headers or responses of any action",
+		Run:         checkUnusedTypes,
+	},
+	{
+		Name:        "unused-response",
+		Description: "responses and response templates defined at the API level that no action returns",
+		Run:         checkUnusedResponses,
+	},
+	{
+		Name:        "field-collision",
+		Description: "attributes of the same object that map to the same Go struct field or JSON key",
+		Run:         checkFieldCollisions,
+	},
+}
+
+// Vet runs all the checks against api and returns the findings sorted by location.
+func Vet(api *design.APIDefinition) []*Finding {
+	var findings []*Finding
+	for _, c := range Checks {
+		for _, f := range c.Run(api) {
+			f.Check = c.Name
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Location < findings[j].Location
+	})
+	return findings
+}
+
+// String returns the text representation of the finding.
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s]", f.Location, f.Message, f.Check)
+}
+
+// checkUnusedTypes implements the "unused-type" check.
+func checkUnusedTypes(api *design.APIDefinition) []*Finding {
+	used := usedTypes(api)
+	var findings []*Finding
+	api.IterateUserTypes(func(u *design.UserTypeDefinition) error {
+		if !used[u] {
+			findings = append(findings, &Finding{
+				Location: "type " + u.TypeName,
+				Message:  "the type is not used by any action",
+			})
+		}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		// Collection media types are created on demand by CollectionOf and the built-in error
+		// media type is always defined.
+		if mt.IsArray() || mt.IsError() || used[mt.UserTypeDefinition] {
+			return nil
+		}
+		findings = append(findings, &Finding{
+			Location: "media type " + mt.Identifier,
+			Message:  "the media type is not used by any action",
+		})
+		return nil
+	})
+	return findings
+}
+
+// checkUnusedResponses implements the "unused-response" check.
+func checkUnusedResponses(api *design.APIDefinition) []*Finding {
+	returned := make(map[string]bool)
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			for n := range a.Responses {
+				returned[n] = true
+			}
+			return nil
+		})
+	})
+	var findings []*Finding
+	api.IterateResponses(func(r *design.ResponseDefinition) error {
+		if !returned[r.Name] {
+			findings = append(findings, &Finding{
+				Location: "response " + r.Name,
+				Message:  "the response is not returned by any action",
+			})
+		}
+		return nil
+	})
+	names := make([]string, 0, len(api.ResponseTemplates))
+	for n := range api.ResponseTemplates {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if !returned[n] {
+			findings = append(findings, &Finding{
+				Location: "response template " + n,
+				Message:  "the response template is not used by any action",
+			})
+		}
+	}
+	return findings
+}
+
+// checkFieldCollisions implements the "field-collision" check.
+func checkFieldCollisions(api *design.APIDefinition) []*Finding {
+	var findings []*Finding
+	check := func(loc string, o design.Object, body bool) {
+		fields := make(map[string]string)
+		keys := make(map[string]string)
+		for _, n := range sortedNames(o) {
+			field := codegen.GoifyAtt(o[n], n, true)
+			if other, ok := fields[field]; ok {
+				findings = append(findings, &Finding{
+					Location: loc,
+					Message:  fmt.Sprintf("attributes %q and %q both map to the Go field %s", other, n, field),
+				})
+			} else {
+				fields[field] = n
+			}
+			if !body {
+				continue
+			}
+			key := jsonKey(o[n], n)
+			if key == "-" {
+				continue
+			}
+			if other, ok := keys[key]; ok {
+				findings = append(findings, &Finding{
+					Location: loc,
+					Message:  fmt.Sprintf("attributes %q and %q both map to the JSON key %q", other, n, key),
+				})
+			} else {
+				keys[key] = n
+			}
+		}
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		rloc := "resource " + r.Name
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			aloc := rloc + " action " + a.Name
+			// The parameters and the headers are fields of the same context struct.
+			inputs := make(design.Object)
+			for _, att := range []*design.AttributeDefinition{a.AllParams(), a.Parent.Headers, a.Headers} {
+				if att == nil {
+					continue
+				}
+				for n, p := range att.Type.ToObject() {
+					inputs[n] = p
+				}
+			}
+			check(aloc+" params and headers", inputs, false)
+			if a.Payload != nil && api.Types[a.Payload.TypeName] == nil {
+				walkObjects(aloc+" payload", "", a.Payload.AttributeDefinition, check)
+			}
+			return nil
+		})
+	})
+	api.IterateUserTypes(func(u *design.UserTypeDefinition) error {
+		walkObjects("type "+u.TypeName, "", u.AttributeDefinition, check)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.IsArray() || mt.IsError() {
+			return nil
+		}
+		walkObjects("media type "+mt.Identifier, "", mt.AttributeDefinition, check)
+		return nil
+	})
+	return findings
+}
+
+// usedTypes returns the user types and media types reachable from the actions of api.
+func usedTypes(api *design.APIDefinition) map[*design.UserTypeDefinition]bool {
+	used := make(map[*design.UserTypeDefinition]bool)
+	var mark func(att *design.AttributeDefinition)
+	mark = func(att *design.AttributeDefinition) {
+		if att == nil {
+			return
+		}
+		switch t := att.Type.(type) {
+		case *design.UserTypeDefinition:
+			if !used[t] {
+				used[t] = true
+				mark(t.AttributeDefinition)
+			}
+		case *design.MediaTypeDefinition:
+			if !used[t.UserTypeDefinition] {
+				used[t.UserTypeDefinition] = true
+				mark(t.AttributeDefinition)
+			}
+		case design.Object:
+			for _, c := range t {
+				mark(c)
+			}
+		case *design.Array:
+			mark(t.ElemType)
+		case *design.Hash:
+			mark(t.KeyType)
+			mark(t.ElemType)
+		}
+	}
+	markMediaType := func(id string) {
+		if mt := api.MediaTypeWithIdentifier(id); mt != nil {
+			mark(&design.AttributeDefinition{Type: mt})
+		}
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		if r.MediaType != "" {
+			markMediaType(r.MediaType)
+		}
+		mark(r.Headers)
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			mark(a.AllParams())
+			mark(a.Headers)
+			if a.Payload != nil {
+				mark(&design.AttributeDefinition{Type: a.Payload})
+			}
+			for _, resp := range a.Responses {
+				mark(resp.Headers)
+				if resp.Type != nil {
+					mark(&design.AttributeDefinition{Type: resp.Type})
+				}
+				if resp.MediaType != "" {
+					markMediaType(resp.MediaType)
+				}
+			}
+			return nil
+		})
+	})
+	return used
+}
+
+// walkObjects calls check with the inline objects defined by att and their location. path is the
+// attribute path of att relative to the definition located at loc. The attributes whose type is a
+// user type are not visited, the user types are checked on their own.
+func walkObjects(loc, path string, att *design.AttributeDefinition, check func(loc string, o design.Object, body bool)) {
+	if att == nil {
+		return
+	}
+	switch t := att.Type.(type) {
+	case design.Object:
+		oloc := loc
+		if path != "" {
+			oloc = loc + " " + path
+		}
+		check(oloc, t, true)
+		for _, n := range sortedNames(t) {
+			cpath := n
+			if path != "" {
+				cpath = path + "." + n
+			}
+			walkObjects(loc, cpath, t[n], check)
+		}
+	case *design.Array:
+		walkObjects(loc, path+"[]", t.ElemType, check)
+	case *design.Hash:
+		walkObjects(loc, path+"{}", t.ElemType, check)
+	}
+}
+
+// jsonKey returns the key of the attribute with the given name in the JSON representation of its
+// parent object, honoring any struct:tag:json metadata.
+func jsonKey(att *design.AttributeDefinition, name string) string {
+	if tag, ok := att.Metadata["struct:tag:json"]; ok && len(tag) > 0 {
+		if key := strings.Split(strings.Join(tag, ","), ",")[0]; key != "" {
+			return key
+		}
+	}
+	return name
+}
+
+// sortedNames returns the attribute names of o in alphabetical order.
+func sortedNames(o design.Object) []string {
+	names := make([]string, 0, len(o))
+	for n := range o {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
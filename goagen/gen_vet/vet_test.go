@@ -0,0 +1,145 @@
+package genvet_test
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_vet"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Vet", func() {
+	var apiDSL func()
+	var findings []string
+
+	BeforeEach(func() {
+		apiDSL = nil
+		dslengine.Reset()
+		apidsl.API("test api", func() {
+			if apiDSL != nil {
+				apiDSL()
+			}
+		})
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Run()
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		findings = nil
+		for _, f := range genvet.Vet(design.Design) {
+			findings = append(findings, f.String())
+		}
+	})
+
+	Context("with a design using all its definitions", func() {
+		BeforeEach(func() {
+			origin := apidsl.Type("origin", func() {
+				apidsl.Attribute("country", design.String)
+			})
+			bottle := apidsl.MediaType("application/vnd.bottle", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+					apidsl.Attribute("origin", origin)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("list", func() {
+					apidsl.Routing(apidsl.GET("/bottles"))
+					apidsl.Response(design.OK, apidsl.CollectionOf(bottle))
+					apidsl.Response(design.BadRequest, design.ErrorMedia)
+				})
+			})
+		})
+
+		It("reports nothing", func() {
+			Ω(findings).Should(BeEmpty())
+		})
+	})
+
+	Context("with unused types and responses", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				apidsl.ResponseTemplate("Conflict", func() {
+					apidsl.Status(409)
+				})
+				apidsl.ResponseTemplate("Gone", func() {
+					apidsl.Status(410)
+				})
+				apidsl.ResponseTemplate("Moved", func(location string) {
+					apidsl.Status(301)
+				})
+			}
+			apidsl.Type("orphan", func() {
+				apidsl.Attribute("name", design.String)
+			})
+			apidsl.MediaType("application/vnd.orphan", func() {
+				apidsl.Attributes(func() {
+					apidsl.Attribute("id", design.Integer)
+				})
+				apidsl.View("default", func() {
+					apidsl.Attribute("id")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Action("delete", func() {
+					apidsl.Routing(apidsl.DELETE("/bottles/:id"))
+					apidsl.Response(design.NoContent)
+					apidsl.Response("Conflict")
+				})
+			})
+		})
+
+		It("reports them", func() {
+			Ω(findings).Should(ConsistOf(
+				"media type application/vnd.orphan: the media type is not used by any action [unused-type]",
+				"response Gone: the response is not returned by any action [unused-response]",
+				"response template Moved: the response template is not used by any action [unused-response]",
+				"type orphan: the type is not used by any action [unused-type]",
+			))
+		})
+	})
+
+	Context("with colliding attribute names", func() {
+		BeforeEach(func() {
+			payload := apidsl.Type("bottle_payload", func() {
+				apidsl.Attribute("vintage_year", design.Integer)
+				apidsl.Attribute("vintageYear", design.Integer)
+				apidsl.Attribute("name", design.String)
+				apidsl.Attribute("label", design.String, func() {
+					apidsl.Metadata("struct:tag:json", "name", "omitempty")
+				})
+				apidsl.Attribute("ignored", design.String, func() {
+					apidsl.Metadata("struct:tag:json", "-")
+				})
+				apidsl.Attribute("hidden", design.String, func() {
+					apidsl.Metadata("struct:tag:json", "-")
+				})
+			})
+			apidsl.Resource("bottle", func() {
+				apidsl.Headers(func() {
+					apidsl.Header("X-Account")
+				})
+				apidsl.Action("create", func() {
+					apidsl.Routing(apidsl.POST("/bottles"))
+					apidsl.Params(func() {
+						apidsl.Param("x_account", design.String)
+					})
+					apidsl.Payload(payload)
+					apidsl.Response(design.Created)
+				})
+			})
+		})
+
+		It("reports the collisions", func() {
+			Ω(findings).Should(ConsistOf(
+				`resource bottle action create params and headers: attributes "X-Account" and "x_account" both map to the Go field XAccount [field-collision]`,
+				`type bottle_payload: attributes "label" and "name" both map to the JSON key "name" [field-collision]`,
+				`type bottle_payload: attributes "vintageYear" and "vintage_year" both map to the Go field VintageYear [field-collision]`,
+			))
+		})
+	})
+})
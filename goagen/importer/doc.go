@@ -0,0 +1,20 @@
+/*
+Package importer produces goa designs from existing API descriptions.
+
+OpenAPI converts an OpenAPI 3.x or Swagger 2.0 document, written in JSON or YAML, into the source
+code of a Go package containing the equivalent goa design:
+
+  - The info, servers, host, schemes and base path of the document are used to define the API.
+  - Each object schema listed in the definitions (Swagger) or in the components (OpenAPI 3) gives
+    a user type named after the schema. Schemas used as response bodies also give a media type
+    whose attributes reference the user type.
+  - Operations are grouped into resources by tag or, for untagged operations, by first path
+    segment. Each operation becomes an action whose name derives from the operation ID and whose
+    route, parameters, headers, payload and responses map the operation.
+
+Constructs that have no equivalent in goa such as cookie parameters, polymorphic schemas or
+security requirements are left out of the design or rendered with the Any type so that the
+generated code always compiles. The result is meant as a starting point to be reviewed and
+completed.
+*/
+package importer
@@ -0,0 +1,765 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// importer holds the state of an OpenAPI document conversion.
+	importer struct {
+		// spec is the OpenAPI document.
+		spec map[string]interface{}
+		// schemas indexes the named schemas of the document.
+		schemas map[string]interface{}
+		// media lists the names of the schemas rendered as media types.
+		media map[string]bool
+		// statuses maps HTTP status codes to goa response names.
+		statuses map[int]string
+		// usesDesign is true if the rendered DSL refers to identifiers of the design package.
+		usesDesign bool
+	}
+
+	// resource lists the rendered actions of a resource.
+	resource struct {
+		name    string
+		actions []string
+		names   map[string]bool
+	}
+)
+
+// methods lists the operation keys of a path item in rendering order.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// OpenAPI returns the Go source code of the design package pkg describing the API defined by the
+// given OpenAPI 3.x or Swagger 2.0 document. The document may be written in JSON or YAML.
+func OpenAPI(doc []byte, pkg string) ([]byte, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(doc, &raw); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %s", err)
+	}
+	spec, ok := normalize(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid OpenAPI document: not an object")
+	}
+	_, isSwagger := spec["swagger"]
+	_, isOpenAPI := spec["openapi"]
+	if !isSwagger && !isOpenAPI {
+		return nil, fmt.Errorf("invalid OpenAPI document: missing openapi or swagger version")
+	}
+	imp := &importer{
+		spec:     spec,
+		schemas:  object(spec, "definitions"),
+		media:    make(map[string]bool),
+		statuses: make(map[int]string),
+	}
+	if isOpenAPI {
+		imp.schemas = object(object(spec, "components"), "schemas")
+	}
+	for name, r := range design.NewAPIDefinition().DefaultResponses {
+		imp.statuses[r.Status] = name
+	}
+	return imp.design(pkg)
+}
+
+// design renders the design package source.
+func (imp *importer) design(pkg string) ([]byte, error) {
+	// Render the resources first to collect the media types.
+	resources := imp.resources()
+
+	var body bytes.Buffer
+	imp.api(&body)
+	for _, name := range sortedKeys(imp.schemas) {
+		if s := imp.objectSchema(imp.schemas[name]); s != nil {
+			fmt.Fprintf(&body, "\n// %s is the %s type.\n", typeVar(name), name)
+			fmt.Fprintf(&body, "var %s = Type(%q, func() {\n", typeVar(name), name)
+			body.WriteString(imp.attributes(s))
+			body.WriteString("})\n")
+		}
+	}
+	for _, name := range sortedKeys(imp.media) {
+		imp.mediaType(&body, name)
+	}
+	for _, r := range resources {
+		fmt.Fprintf(&body, "\nvar _ = Resource(%q, func() {\n", r.name)
+		body.WriteString(strings.Join(r.actions, "\n"))
+		body.WriteString("})\n")
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	if imp.usesDesign {
+		src.WriteString("\t. \"github.com/goadesign/goa/design\"\n")
+	}
+	src.WriteString("\t. \"github.com/goadesign/goa/design/apidsl\"\n)\n")
+	src.Write(body.Bytes())
+	res, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated design: %s", err)
+	}
+	return res, nil
+}
+
+// api renders the API definition.
+func (imp *importer) api(w *bytes.Buffer) {
+	info := object(imp.spec, "info")
+	title := str(info, "title")
+	name := snake(title)
+	if name == "" {
+		name = "api"
+	}
+	fmt.Fprintf(w, "\nvar _ = API(%q, func() {\n", name)
+	if title != "" {
+		fmt.Fprintf(w, "Title(%q)\n", title)
+	}
+	if d := str(info, "description"); d != "" {
+		fmt.Fprintf(w, "Description(%q)\n", d)
+	}
+	if v := str(info, "version"); v != "" {
+		fmt.Fprintf(w, "Version(%q)\n", v)
+	}
+	host, basePath := str(imp.spec, "host"), str(imp.spec, "basePath")
+	schemes := strings.Join(quoteAll(strs(imp.spec["schemes"])), ", ")
+	if servers := list(imp.spec["servers"]); len(servers) > 0 {
+		// Server URLs with variables cannot be expressed in goa.
+		raw := str(toObject(servers[0]), "url")
+		if u, err := url.Parse(raw); err == nil && !strings.Contains(raw, "{") {
+			host, basePath = u.Host, u.Path
+			if u.Scheme != "" {
+				schemes = strconv.Quote(u.Scheme)
+			}
+		}
+	}
+	if host != "" {
+		fmt.Fprintf(w, "Host(%q)\n", host)
+	}
+	if schemes != "" {
+		fmt.Fprintf(w, "Scheme(%s)\n", schemes)
+	}
+	if basePath = strings.TrimSuffix(basePath, "/"); basePath != "" {
+		fmt.Fprintf(w, "BasePath(%q)\n", basePath)
+	}
+	w.WriteString("})\n")
+}
+
+// mediaType renders the media type describing the response bodies defined by the named schema.
+func (imp *importer) mediaType(w *bytes.Buffer, name string) {
+	s := imp.objectSchema(imp.schemas[name])
+	props := sortedKeys(object(s, "properties"))
+	fmt.Fprintf(w, "\n// %s is the media type used to render %s.\n", mediaVar(name), name)
+	fmt.Fprintf(w, "var %s = MediaType(%q, func() {\n", mediaVar(name), mediaIdentifier(name))
+	fmt.Fprintf(w, "TypeName(%q)\n", codegen.Goify(name, true)+"Media")
+	fmt.Fprintf(w, "Reference(%s)\n", typeVar(name))
+	w.WriteString("Attributes(func() {\n")
+	for _, p := range props {
+		fmt.Fprintf(w, "Attribute(%q)\n", p)
+	}
+	if req := strs(s["required"]); len(req) > 0 {
+		fmt.Fprintf(w, "Required(%s)\n", strings.Join(quoteAll(req), ", "))
+	}
+	w.WriteString("})\nView(\"default\", func() {\n")
+	for _, p := range props {
+		fmt.Fprintf(w, "Attribute(%q)\n", p)
+	}
+	w.WriteString("})\n})\n")
+}
+
+// resources renders the actions of all the operations grouped by resource.
+func (imp *importer) resources() []*resource {
+	var (
+		res   []*resource
+		index = make(map[string]*resource)
+		paths = object(imp.spec, "paths")
+	)
+	for _, path := range sortedKeys(paths) {
+		item := toObject(paths[path])
+		for _, method := range methods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := resourceName(op, path)
+			r, ok := index[name]
+			if !ok {
+				r = &resource{name: name, names: make(map[string]bool)}
+				index[name] = r
+				res = append(res, r)
+			}
+			r.actions = append(r.actions, imp.action(r, method, path, item, op))
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+	return res
+}
+
+// action renders the action corresponding to the given operation.
+func (imp *importer) action(r *resource, method, path string, item, op map[string]interface{}) string {
+	base := actionName(op, method, path)
+	name := base
+	for i := 2; r.names[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	r.names[name] = true
+
+	var w bytes.Buffer
+	fmt.Fprintf(&w, "Action(%q, func() {\n", name)
+	desc := str(op, "description")
+	if desc == "" {
+		desc = str(op, "summary")
+	}
+	if desc != "" {
+		fmt.Fprintf(&w, "Description(%q)\n", desc)
+	}
+	fmt.Fprintf(&w, "Routing(%s(%q))\n", strings.ToUpper(method), routePath(path))
+
+	var (
+		params, headers, form []map[string]interface{}
+		body                  map[string]interface{}
+		bodyRequired          bool
+		seen                  = make(map[string]bool)
+	)
+	// Operation parameters override the path item parameters.
+	for _, raw := range append(list(op["parameters"]), list(item["parameters"])...) {
+		p := imp.resolve(toObject(raw))
+		key := str(p, "in") + ":" + str(p, "name")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch str(p, "in") {
+		case "path", "query":
+			params = append(params, p)
+		case "header":
+			headers = append(headers, p)
+		case "formData":
+			form = append(form, p)
+		case "body":
+			body, bodyRequired = toObject(p["schema"]), p["required"] == true
+		}
+	}
+	if rb := imp.resolve(toObject(op["requestBody"])); rb != nil {
+		body, bodyRequired = content(rb), rb["required"] == true
+	}
+	imp.parameters(&w, "Params", "Param", params)
+	imp.parameters(&w, "Headers", "Header", headers)
+	if len(form) > 0 {
+		w.WriteString("Payload(func() {\n")
+		var req []string
+		for _, p := range form {
+			imp.attribute(&w, "Attribute", str(p, "name"), imp.paramSchema(p))
+			if p["required"] == true {
+				req = append(req, str(p, "name"))
+			}
+		}
+		if len(req) > 0 {
+			fmt.Fprintf(&w, "Required(%s)\n", strings.Join(quoteAll(req), ", "))
+		}
+		w.WriteString("})\n")
+	} else if body != nil {
+		imp.payload(&w, body, bodyRequired)
+	}
+
+	responses := object(op, "responses")
+	for _, code := range sortedKeys(responses) {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			// The "default" and range (e.g. "5XX") responses have no goa equivalent.
+			continue
+		}
+		imp.response(&w, status, imp.resolve(toObject(responses[code])))
+	}
+	w.WriteString("})\n")
+	return w.String()
+}
+
+// parameters renders the Params or Headers DSL describing the given parameters.
+func (imp *importer) parameters(w *bytes.Buffer, fn, member string, params []map[string]interface{}) {
+	if len(params) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s(func() {\n", fn)
+	var req []string
+	for _, p := range params {
+		imp.attribute(w, member, str(p, "name"), imp.paramSchema(p))
+		if p["required"] == true && str(p, "in") != "path" {
+			req = append(req, str(p, "name"))
+		}
+	}
+	if len(req) > 0 {
+		fmt.Fprintf(w, "Required(%s)\n", strings.Join(quoteAll(req), ", "))
+	}
+	w.WriteString("})\n")
+}
+
+// payload renders the Payload DSL describing the request body schema.
+func (imp *importer) payload(w *bytes.Buffer, s map[string]interface{}, required bool) {
+	fn := "Payload"
+	if !required {
+		fn = "OptionalPayload"
+	}
+	if name := refName(s); name != "" && imp.objectSchema(imp.schemas[name]) != nil {
+		fmt.Fprintf(w, "%s(%s)\n", fn, typeVar(name))
+		return
+	}
+	if o := imp.objectSchema(s); o != nil && object(o, "properties") != nil {
+		fmt.Fprintf(w, "%s(func() {\n%s})\n", fn, imp.attributes(o))
+		return
+	}
+	fmt.Fprintf(w, "%s(%s)\n", fn, imp.typeExpr(s))
+}
+
+// response renders the Response DSL describing the response with the given status.
+func (imp *importer) response(w *bytes.Buffer, status int, r map[string]interface{}) {
+	s := toObject(r["schema"])
+	if _, ok := r["content"]; ok {
+		s = content(r)
+	}
+	var media string
+	if s != nil {
+		media = `"application/json"`
+		if name := refName(s); name != "" && imp.objectSchema(imp.schemas[name]) != nil {
+			imp.media[name] = true
+			media = mediaVar(name)
+		} else if str(s, "type") == "array" {
+			items := toObject(s["items"])
+			if name := refName(items); name != "" && imp.objectSchema(imp.schemas[name]) != nil {
+				imp.media[name] = true
+				media = "CollectionOf(" + mediaVar(name) + ")"
+			}
+		}
+	}
+	name, ok := imp.statuses[status]
+	imp.usesDesign = imp.usesDesign || ok
+	switch {
+	case ok && media == "":
+		fmt.Fprintf(w, "Response(%s)\n", name)
+	case ok && strings.HasPrefix(media, `"`):
+		fmt.Fprintf(w, "Response(%s, func() {\nMedia(%s)\n})\n", name, media)
+	case ok:
+		fmt.Fprintf(w, "Response(%s, %s)\n", name, media)
+	default:
+		fmt.Fprintf(w, "Response(\"Status%d\", func() {\nStatus(%d)\n", status, status)
+		if media != "" {
+			fmt.Fprintf(w, "Media(%s)\n", media)
+		}
+		w.WriteString("})\n")
+	}
+}
+
+// attributes renders the attributes and required validation of an object schema.
+func (imp *importer) attributes(s map[string]interface{}) string {
+	var w bytes.Buffer
+	if d := str(s, "description"); d != "" {
+		fmt.Fprintf(&w, "Description(%q)\n", d)
+	}
+	props := object(s, "properties")
+	for _, name := range sortedKeys(props) {
+		imp.attribute(&w, "Attribute", name, toObject(props[name]))
+	}
+	if req := strs(s["required"]); len(req) > 0 {
+		fmt.Fprintf(&w, "Required(%s)\n", strings.Join(quoteAll(req), ", "))
+	}
+	return w.String()
+}
+
+// attribute renders a single attribute, parameter or header using the DSL function fn.
+func (imp *importer) attribute(w *bytes.Buffer, fn, name string, s map[string]interface{}) {
+	var dsl bytes.Buffer
+	if d := str(s, "description"); d != "" {
+		fmt.Fprintf(&dsl, "Description(%q)\n", d)
+	}
+	if refName(s) == "" {
+		imp.validations(&dsl, s)
+	}
+	if o := imp.objectSchema(s); o != nil && refName(s) == "" && object(o, "properties") != nil {
+		// Inline object: the child attributes define the type.
+		dsl.WriteString(imp.attributes(o))
+		fmt.Fprintf(w, "%s(%q, func() {\n%s})\n", fn, name, dsl.String())
+		return
+	}
+	if dsl.Len() == 0 {
+		fmt.Fprintf(w, "%s(%q, %s)\n", fn, name, imp.typeExpr(s))
+		return
+	}
+	fmt.Fprintf(w, "%s(%q, %s, func() {\n%s})\n", fn, name, imp.typeExpr(s), dsl.String())
+}
+
+// validations renders the validations and default value of a schema.
+func (imp *importer) validations(w *bytes.Buffer, s map[string]interface{}) {
+	if enum := list(s["enum"]); len(enum) > 0 {
+		vals := make([]string, 0, len(enum))
+		for _, v := range enum {
+			if l, ok := literal(v); ok {
+				vals = append(vals, l)
+			}
+		}
+		if len(vals) > 0 {
+			fmt.Fprintf(w, "Enum(%s)\n", strings.Join(vals, ", "))
+		}
+	}
+	if f := str(s, "format"); f != "" && imp.typeExpr(s) == "String" {
+		for _, supported := range apidsl.SupportedValidationFormats {
+			if f == supported {
+				fmt.Fprintf(w, "Format(%q)\n", f)
+				break
+			}
+		}
+	}
+	if p := str(s, "pattern"); p != "" {
+		fmt.Fprintf(w, "Pattern(%q)\n", p)
+	}
+	for _, v := range []struct{ key, fn string }{
+		{"minLength", "MinLength"}, {"minItems", "MinLength"},
+		{"maxLength", "MaxLength"}, {"maxItems", "MaxLength"},
+	} {
+		if n, ok := s[v.key].(int); ok {
+			fmt.Fprintf(w, "%s(%d)\n", v.fn, n)
+		}
+	}
+	for _, v := range []struct{ key, fn string }{{"minimum", "Minimum"}, {"maximum", "Maximum"}} {
+		if l, ok := literal(s[v.key]); ok {
+			fmt.Fprintf(w, "%s(%s)\n", v.fn, l)
+		}
+	}
+	if d, ok := s["default"]; ok {
+		if l, ok := literal(d); ok {
+			fmt.Fprintf(w, "Default(%s)\n", l)
+		}
+	}
+}
+
+// typeExpr returns the Go expression of the goa data type described by the schema. Named object
+// schemas are referred to by name so that the generated type definitions may be recursive.
+func (imp *importer) typeExpr(s map[string]interface{}) string {
+	expr := imp.dataType(s)
+	if expr != strconv.Quote(refName(s)) {
+		// All the other expressions use the primitive types of the design package.
+		imp.usesDesign = true
+	}
+	return expr
+}
+
+// dataType implements typeExpr.
+func (imp *importer) dataType(s map[string]interface{}) string {
+	if name := refName(s); name != "" {
+		target := toObject(imp.schemas[name])
+		if imp.objectSchema(target) != nil {
+			return strconv.Quote(name)
+		}
+		return imp.typeExpr(target)
+	}
+	if allOf := list(s["allOf"]); len(allOf) == 1 {
+		return imp.typeExpr(toObject(allOf[0]))
+	}
+	switch str(s, "type") {
+	case "string":
+		switch str(s, "format") {
+		case "date-time":
+			return "DateTime"
+		case "uuid":
+			return "UUID"
+		case "binary":
+			return "File"
+		}
+		return "String"
+	case "integer":
+		return "Integer"
+	case "number":
+		return "Number"
+	case "boolean":
+		return "Boolean"
+	case "array":
+		return "ArrayOf(" + imp.typeExpr(toObject(s["items"])) + ")"
+	case "file":
+		return "File"
+	}
+	if _, ok := s["properties"]; !ok {
+		switch ap := s["additionalProperties"].(type) {
+		case map[string]interface{}:
+			return "HashOf(String, " + imp.typeExpr(ap) + ")"
+		case bool:
+			if ap {
+				return "HashOf(String, Any)"
+			}
+		}
+	}
+	return "Any"
+}
+
+// objectSchema returns the schema with the properties of all its allOf members merged in if it
+// describes an object, nil otherwise.
+func (imp *importer) objectSchema(raw interface{}) map[string]interface{} {
+	s := toObject(raw)
+	if s == nil {
+		return nil
+	}
+	if name := refName(s); name != "" {
+		return imp.objectSchema(imp.schemas[name])
+	}
+	allOf := list(s["allOf"])
+	if len(allOf) == 0 {
+		if _, ok := s["properties"]; ok || str(s, "type") == "object" && s["additionalProperties"] == nil {
+			return s
+		}
+		return nil
+	}
+	var (
+		props    = make(map[string]interface{})
+		required []interface{}
+	)
+	merge := func(o map[string]interface{}) {
+		for k, v := range object(o, "properties") {
+			props[k] = v
+		}
+		required = append(required, list(o["required"])...)
+	}
+	for _, m := range allOf {
+		if o := imp.objectSchema(m); o != nil {
+			merge(o)
+		}
+	}
+	merge(s)
+	res := map[string]interface{}{"type": "object", "properties": props, "required": required}
+	if d := str(s, "description"); d != "" {
+		res["description"] = d
+	}
+	return res
+}
+
+// paramSchema returns the schema of a parameter. Swagger 2.0 parameters inline the schema
+// properties while OpenAPI 3 parameters use a schema field.
+func (imp *importer) paramSchema(p map[string]interface{}) map[string]interface{} {
+	s := toObject(p["schema"])
+	if s == nil {
+		s = p
+	}
+	if d := str(p, "description"); d != "" && str(s, "description") == "" {
+		c := make(map[string]interface{}, len(s)+1)
+		for k, v := range s {
+			c[k] = v
+		}
+		c["description"] = d
+		s = c
+	}
+	return s
+}
+
+// resolve returns the object referred to by the local JSON reference of the given object if any,
+// the object itself otherwise. Schema references are kept as is.
+func (imp *importer) resolve(o map[string]interface{}) map[string]interface{} {
+	ref := str(o, "$ref")
+	if !strings.HasPrefix(ref, "#/") {
+		return o
+	}
+	var cur interface{} = imp.spec
+	for _, tok := range strings.Split(ref[2:], "/") {
+		tok = strings.Replace(strings.Replace(tok, "~1", "/", -1), "~0", "~", -1)
+		cur = toObject(cur)[tok]
+	}
+	return imp.resolve(toObject(cur))
+}
+
+// content returns the schema of the JSON content of an OpenAPI 3 request body or response.
+func content(o map[string]interface{}) map[string]interface{} {
+	c := object(o, "content")
+	if len(c) == 0 {
+		return nil
+	}
+	keys := sortedKeys(c)
+	pick := keys[0]
+	for _, k := range keys {
+		if k == "application/json" {
+			pick = k
+			break
+		}
+		if strings.Contains(k, "json") && !strings.Contains(pick, "json") {
+			pick = k
+		}
+	}
+	s := toObject(object(c, pick)["schema"])
+	if s == nil {
+		s = map[string]interface{}{}
+	}
+	return s
+}
+
+// resourceName returns the name of the resource that groups the given operation.
+func resourceName(op map[string]interface{}, path string) string {
+	if tags := strs(op["tags"]); len(tags) > 0 {
+		return snake(tags[0])
+	}
+	for _, seg := range strings.Split(path, "/") {
+		if seg != "" && !strings.HasPrefix(seg, "{") {
+			return snake(seg)
+		}
+	}
+	return "root"
+}
+
+// actionName returns the name of the action implementing the given operation.
+func actionName(op map[string]interface{}, method, path string) string {
+	id := str(op, "operationId")
+	if id == "" {
+		id = method
+		for _, seg := range strings.Split(path, "/") {
+			if strings.HasPrefix(seg, "{") {
+				seg = "by " + strings.Trim(seg, "{}")
+			}
+			id += " " + seg
+		}
+	}
+	return snake(id)
+}
+
+// snake returns the snake_case version of the given identifier, operation ID or title.
+func snake(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = codegen.SnakeCase(w)
+	}
+	return strings.Join(words, "_")
+}
+
+// routePath converts the OpenAPI path template into a goa route path.
+func routePath(path string) string {
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segs[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// refName returns the name of the schema referred to by s if any, the empty string otherwise.
+func refName(s map[string]interface{}) string {
+	ref := str(s, "$ref")
+	for _, prefix := range []string{"#/definitions/", "#/components/schemas/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return ref[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// typeVar returns the name of the variable holding the user type built from the named schema.
+func typeVar(name string) string {
+	return codegen.Goify(name, true) + "Type"
+}
+
+// mediaVar returns the name of the variable holding the media type built from the named schema.
+func mediaVar(name string) string {
+	return codegen.Goify(name, true) + "Media"
+}
+
+// mediaIdentifier returns the identifier of the media type built from the named schema.
+func mediaIdentifier(name string) string {
+	return "application/vnd." + codegen.KebabCase(codegen.Goify(name, true)) + "+json"
+}
+
+// literal returns the Go literal for the given scalar value.
+func literal(v interface{}) (string, bool) {
+	switch actual := v.(type) {
+	case string:
+		return strconv.Quote(actual), true
+	case int:
+		return strconv.Itoa(actual), true
+	case int64:
+		return strconv.FormatInt(actual, 10), true
+	case uint64:
+		return strconv.FormatUint(actual, 10), true
+	case float64:
+		l := strconv.FormatFloat(actual, 'g', -1, 64)
+		if !strings.ContainsAny(l, ".eE") {
+			l += ".0"
+		}
+		return l, true
+	case bool:
+		return strconv.FormatBool(actual), true
+	}
+	return "", false
+}
+
+// normalize converts the maps produced by the YAML decoder into maps keyed by strings.
+func normalize(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			res[fmt.Sprintf("%v", k)] = normalize(val)
+		}
+		return res
+	case []interface{}:
+		for i, val := range actual {
+			actual[i] = normalize(val)
+		}
+	}
+	return v
+}
+
+func toObject(v interface{}) map[string]interface{} {
+	o, _ := v.(map[string]interface{})
+	return o
+}
+
+func object(o map[string]interface{}, key string) map[string]interface{} {
+	return toObject(o[key])
+}
+
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func str(o map[string]interface{}, key string) string {
+	s, _ := o[key].(string)
+	return s
+}
+
+func strs(v interface{}) []string {
+	var res []string
+	for _, e := range list(v) {
+		if s, ok := e.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func quoteAll(vals []string) []string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = strconv.Quote(v)
+	}
+	return res
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch actual := m.(type) {
+	case map[string]interface{}:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	case map[string]bool:
+		for k := range actual {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,236 @@
+package importer_test
+
+import (
+	"go/parser"
+	"go/token"
+
+	"github.com/goadesign/goa/goagen/importer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPI", func() {
+	var doc string
+	var src string
+	var importErr error
+
+	JustBeforeEach(func() {
+		var res []byte
+		res, importErr = importer.OpenAPI([]byte(doc), "design")
+		src = string(res)
+	})
+
+	Context("with an OpenAPI 3 document", func() {
+		BeforeEach(func() {
+			doc = `
+openapi: 3.0.1
+info:
+  title: Pet Store
+  version: "1.0"
+servers:
+  - url: https://petstore.goa.design/v1
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      tags: [pets]
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+      responses:
+        "200":
+          description: pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/Pet"
+    post:
+      operationId: createPet
+      tags: [pets]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/NewPet"
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+  /pets/{petId}:
+    parameters:
+      - $ref: "#/components/parameters/PetID"
+    get:
+      operationId: showPetById
+      tags: [pets]
+      responses:
+        "200":
+          description: pet
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"
+        "404":
+          description: not found
+components:
+  parameters:
+    PetID:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: integer
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          minLength: 1
+        tag:
+          type: string
+          enum: [cat, dog]
+    Pet:
+      allOf:
+        - $ref: "#/components/schemas/NewPet"
+        - type: object
+          required: [id]
+          properties:
+            id:
+              type: integer
+            born:
+              type: string
+              format: date-time
+`
+		})
+
+		It("generates a valid Go design package", func() {
+			Ω(importErr).ShouldNot(HaveOccurred())
+			_, err := parser.ParseFile(token.NewFileSet(), "design.go", src, 0)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(src).Should(ContainSubstring("package design"))
+			Ω(src).Should(ContainSubstring(`. "github.com/goadesign/goa/design/apidsl"`))
+		})
+
+		It("defines the API", func() {
+			Ω(src).Should(ContainSubstring(`API("pet_store", func() {`))
+			Ω(src).Should(ContainSubstring(`Host("petstore.goa.design")`))
+			Ω(src).Should(ContainSubstring(`Scheme("https")`))
+			Ω(src).Should(ContainSubstring(`BasePath("/v1")`))
+		})
+
+		It("defines the types", func() {
+			Ω(src).Should(ContainSubstring(`var NewPetType = Type("NewPet", func() {`))
+			Ω(src).Should(ContainSubstring("MinLength(1)"))
+			Ω(src).Should(ContainSubstring(`Enum("cat", "dog")`))
+			Ω(src).Should(ContainSubstring(`Attribute("born", DateTime)`))
+			Ω(src).Should(ContainSubstring(`Required("name", "id")`))
+		})
+
+		It("defines the media types of the response bodies", func() {
+			Ω(src).Should(ContainSubstring(`var PetMedia = MediaType("application/vnd.pet+json", func() {`))
+			Ω(src).Should(ContainSubstring("Reference(PetType)"))
+			Ω(src).ShouldNot(ContainSubstring("NewPetMedia"))
+		})
+
+		It("maps the operations to actions", func() {
+			Ω(src).Should(ContainSubstring(`Resource("pets", func() {`))
+			Ω(src).Should(ContainSubstring(`Action("list_pets", func() {`))
+			Ω(src).Should(ContainSubstring(`Param("limit", Integer, func() {`))
+			Ω(src).Should(ContainSubstring("Maximum(100)"))
+			Ω(src).Should(ContainSubstring("Response(OK, CollectionOf(PetMedia))"))
+			Ω(src).Should(ContainSubstring("Payload(NewPetType)"))
+			Ω(src).Should(ContainSubstring("Response(Created, PetMedia)"))
+			Ω(src).Should(ContainSubstring(`Routing(GET("/pets/:petId"))`))
+			Ω(src).Should(ContainSubstring(`Param("petId", Integer)`))
+			Ω(src).Should(ContainSubstring("Response(NotFound)"))
+		})
+	})
+
+	Context("with a Swagger 2.0 document", func() {
+		BeforeEach(func() {
+			doc = `{
+	"swagger": "2.0",
+	"info": {"title": "cellar", "version": "1"},
+	"host": "cellar.goa.design",
+	"basePath": "/api",
+	"schemes": ["http", "https"],
+	"paths": {
+		"/bottles/{id}": {
+			"put": {
+				"operationId": "bottle#update",
+				"parameters": [
+					{"name": "id", "in": "path", "required": true, "type": "integer"},
+					{"name": "X-Trace", "in": "header", "type": "string"},
+					{"name": "payload", "in": "body", "schema": {"$ref": "#/definitions/BottlePayload"}}
+				],
+				"responses": {"204": {"description": "updated"}, "299": {"description": "odd"}}
+			}
+		},
+		"/upload": {
+			"post": {
+				"parameters": [
+					{"name": "file", "in": "formData", "type": "file", "required": true}
+				],
+				"responses": {"200": {"description": "ok", "schema": {"type": "string"}}}
+			}
+		}
+	},
+	"definitions": {
+		"BottlePayload": {
+			"type": "object",
+			"properties": {"name": {"type": "string", "pattern": "^[a-z]+$"}}
+		}
+	}
+}`
+		})
+
+		It("generates a valid Go design package", func() {
+			Ω(importErr).ShouldNot(HaveOccurred())
+			_, err := parser.ParseFile(token.NewFileSet(), "design.go", src, 0)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("defines the API", func() {
+			Ω(src).Should(ContainSubstring(`Host("cellar.goa.design")`))
+			Ω(src).Should(ContainSubstring(`Scheme("http", "https")`))
+			Ω(src).Should(ContainSubstring(`BasePath("/api")`))
+		})
+
+		It("maps the operations to actions", func() {
+			Ω(src).Should(ContainSubstring(`Action("bottle_update", func() {`))
+			Ω(src).Should(ContainSubstring(`Routing(PUT("/bottles/:id"))`))
+			Ω(src).Should(ContainSubstring(`Header("X-Trace", String)`))
+			Ω(src).Should(ContainSubstring("OptionalPayload(BottlePayloadType)"))
+			Ω(src).Should(ContainSubstring(`Pattern("^[a-z]+$")`))
+			Ω(src).Should(ContainSubstring("Response(NoContent)"))
+			Ω(src).Should(ContainSubstring(`Response("Status299", func() {`))
+		})
+
+		It("maps the form parameters to the payload attributes", func() {
+			Ω(src).Should(ContainSubstring(`Resource("upload", func() {`))
+			Ω(src).Should(ContainSubstring(`Action("post_upload", func() {`))
+			Ω(src).Should(ContainSubstring(`Attribute("file", File)`))
+			Ω(src).Should(ContainSubstring(`Media("application/json")`))
+		})
+	})
+
+	Context("with a document that is not an OpenAPI document", func() {
+		BeforeEach(func() {
+			doc = `{"info": {"title": "nope"}}`
+		})
+
+		It("returns an error", func() {
+			Ω(importErr).Should(HaveOccurred())
+		})
+	})
+})
@@ -3,13 +3,17 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/importer"
 	"github.com/goadesign/goa/goagen/meta"
+	"github.com/goadesign/goa/goagen/skeleton"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/goadesign/goa/version"
 	"github.com/spf13/cobra"
@@ -38,16 +42,33 @@ the "app" command generates the code that supports the service controllers.
 
 The "bootstrap" command runs the "app", "main", "client" and "swagger" commands generating the
 controllers supporting code and main skeleton code (if not already present) as well as a client
-package and tool and the Swagger specification for the API.
+package and tool and the Swagger specification for the API. The --only flag restricts it to some of
+these commands, e.g. "goagen bootstrap --only swagger" only regenerates the specification. A
+command whose output depends on another command output, such as "main" that imports the package
+generated by "app", requires that command to be selected too or to have been run before.
+
+The --watch flag keeps goagen running after the command completes: the command runs again each time
+the design package changes and goagen prints the errors or the generated files that changed.
+
+Each command records the files it generates in the goagen_manifest.json file of the output
+directory. The files generated by a previous run of the same command with the same design package
+and --pkg flag that are not generated anymore are deleted if they have not been modified since.
+
+The "goagen:out:app", "goagen:out:client", "goagen:out:tool", "goagen:out:swagger" and
+"goagen:out:openapi" API metadata set the directories, relative to the output directory, where the
+corresponding artifacts are generated, e.g. Metadata("goagen:out:app", "internal/transport/rest").
+The generated main and controllers import the app package from that directory.
 `}
 	var (
 		designPkg string
 		debug     bool
+		watch     bool
 	)
 
 	rootCmd.PersistentFlags().StringP("out", "o", ".", "output directory")
 	rootCmd.PersistentFlags().StringVarP(&designPkg, "design", "d", "", "design package import path")
-	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files.")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug mode, does not cleanup temporary files and dumps the data given to the code templates as JSON.")
+	rootCmd.PersistentFlags().BoolVar(&watch, "watch", false, "regenerate each time the design package changes until interrupted")
 
 	// versionCmd implements the "version" command
 	versionCmd := &cobra.Command{
@@ -70,12 +91,12 @@ package and tool and the Swagger specification for the API.
 		Run:   func(c *cobra.Command, _ []string) { files, err = run("genapp", c) },
 	}
 	appCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
-	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers")
+	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers and mocks")
 	rootCmd.AddCommand(appCmd)
 
 	// mainCmd implements the "main" command.
 	var (
-		force, regen bool
+		force, regen, merge, k8s, docker, debugListener bool
 	)
 	mainCmd := &cobra.Command{
 		Use:   "main",
@@ -84,6 +105,10 @@ package and tool and the Swagger specification for the API.
 	}
 	mainCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
 	mainCmd.Flags().BoolVar(&regen, "regen", false, "regenerate scaffolding, maintaining controller implementations")
+	mainCmd.Flags().BoolVar(&merge, "merge", false, "regenerate scaffolding, merging the changes made to the existing files since they were last generated")
+	mainCmd.Flags().BoolVar(&k8s, "k8s", false, "generate Kubernetes manifests and mount a health check endpoint")
+	mainCmd.Flags().BoolVar(&docker, "docker", false, "generate a Dockerfile and a docker-compose file")
+	mainCmd.Flags().BoolVar(&debugListener, "debug-listener", false, "generate a debug listener serving the pprof and expvar endpoints, enabled with --debug-addr")
 	rootCmd.AddCommand(mainCmd)
 
 	// clientCmd implements the "client" command.
@@ -110,11 +135,30 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(swaggerCmd)
 
+	// openapiCmd implements the "openapi" command.
+	openapiCmd := &cobra.Command{
+		Use:   "openapi",
+		Short: "Generate OpenAPI 3.1 specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genopenapi", c) },
+	}
+	rootCmd.AddCommand(openapiCmd)
+
+	// backstageCmd implements the "backstage" command.
+	var definition string
+	backstageCmd := &cobra.Command{
+		Use:   "backstage",
+		Short: "Generate Backstage catalog entity descriptor",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genbackstage", c) },
+	}
+	backstageCmd.Flags().StringVar(&definition, "definition", "", `the path to the API specification relative to the output directory, defaults to the specification generated by the openapi command`)
+	rootCmd.AddCommand(backstageCmd)
+
 	// jsCmd implements the "js" command.
 	var (
 		timeout      = time.Duration(20) * time.Second
 		scheme, host string
 		noexample    bool
+		fetch        bool
 	)
 	jsCmd := &cobra.Command{
 		Use:   "js",
@@ -125,8 +169,112 @@ package and tool and the Swagger specification for the API.
 	jsCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
 	jsCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
 	jsCmd.Flags().BoolVar(&noexample, "noexample", false, `Skip generation of example HTML and controller`)
+	jsCmd.Flags().BoolVar(&fetch, "fetch", false, `Generate a dependency-free ES module client that uses the fetch API instead of axios`)
 	rootCmd.AddCommand(jsCmd)
 
+	// pythonCmd implements the "python" command.
+	var pythonPkg string
+	pythonCmd := &cobra.Command{
+		Use:   "python",
+		Short: "Generate Python client",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genpython", c) },
+	}
+	pythonCmd.Flags().StringVar(&pythonPkg, "package", "", `the name of the generated Python package, defaults to the snake case API name`)
+	pythonCmd.Flags().DurationVar(&timeout, "timeout", timeout, `the duration before the request times out.`)
+	pythonCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
+	pythonCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(pythonCmd)
+
+	// kotlinCmd implements the "kotlin" command.
+	var kotlinPkg string
+	kotlinCmd := &cobra.Command{
+		Use:   "kotlin",
+		Short: "Generate Kotlin client",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genkotlin", c) },
+	}
+	kotlinCmd.Flags().StringVar(&kotlinPkg, "package", "", `the name of the generated Kotlin package, defaults to the lower case API name`)
+	kotlinCmd.Flags().DurationVar(&timeout, "timeout", timeout, `the duration before the request times out.`)
+	kotlinCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
+	kotlinCmd.Flags().StringVar(&host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(kotlinCmd)
+
+	// apigatewayCmd implements the "apigateway" command.
+	apigatewayCmd := &cobra.Command{
+		Use:   "apigateway",
+		Short: "Generate AWS API Gateway specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genapigateway", c) },
+	}
+	apigatewayCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used to proxy the requests to the service, defaults to the scheme defined in the API design if any.`)
+	apigatewayCmd.Flags().StringVar(&host, "host", "", `the service hostname, defaults to the hostname defined in the API design`)
+	rootCmd.AddCommand(apigatewayCmd)
+
+	// diffCmd implements the "diff" command.
+	var base string
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Report the breaking changes between the design and a previously generated specification",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendiff", c) },
+	}
+	diffCmd.Flags().StringVar(&base, "base", "", `path to the Swagger or OpenAPI specification of the previous version of the API, defaults to swagger/swagger.json in the output directory`)
+	rootCmd.AddCommand(diffCmd)
+
+	// lintCmd implements the "lint" command.
+	var lintConfig, lintFormat string
+	lintCmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check the design against naming, documentation, error and pagination conventions",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genlint", c) },
+	}
+	lintCmd.Flags().StringVar(&lintConfig, "config", "", `path to the JSON or YAML file configuring the severity and the options of the lint rules`)
+	lintCmd.Flags().StringVar(&lintFormat, "format", "text", `format of the lint report written to lint/report.txt or lint/report.json, "text" or "json"`)
+	rootCmd.AddCommand(lintCmd)
+
+	// vetCmd implements the "vet" command.
+	vetCmd := &cobra.Command{
+		Use:   "vet",
+		Short: "Report unused types and responses and colliding attribute names in the design",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genvet", c) },
+	}
+	rootCmd.AddCommand(vetCmd)
+
+	// explainCmd implements the "explain" command.
+	var explainAction string
+	explainCmd := &cobra.Command{
+		Use:   "explain RESOURCE.ACTION",
+		Short: "Print an action as the generators see it: routes, final attributes and validations, generated names",
+		Run: func(c *cobra.Command, args []string) {
+			if len(args) != 1 {
+				err = fmt.Errorf("usage: goagen explain RESOURCE.ACTION")
+				return
+			}
+			c.Flags().Set("action", args[0])
+			files, err = run("genexplain", c)
+		},
+	}
+	explainCmd.Flags().StringVar(&explainAction, "action", "", "name of the action to explain, e.g. bottle.show")
+	explainCmd.Flags().MarkHidden("action")
+	rootCmd.AddCommand(explainCmd)
+
+	// snippetsCmd implements the "snippets" command.
+	snippetsCmd := &cobra.Command{
+		Use:   "snippets",
+		Short: "Generate example invocation snippets",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gensnippets", c) },
+	}
+	snippetsCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used in the snippets, defaults to the scheme of each action.`)
+	snippetsCmd.Flags().StringVar(&host, "host", "", `the API hostname used in the snippets, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(snippetsCmd)
+
+	// docsCmd implements the "docs" command.
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate static HTML documentation site",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gendocs", c) },
+	}
+	docsCmd.Flags().StringVar(&scheme, "scheme", "", `the URL scheme used in the example invocations, defaults to the scheme of each action.`)
+	docsCmd.Flags().StringVar(&host, "host", "", `the API hostname used in the example invocations, defaults to the hostname defined in the API design if any`)
+	rootCmd.AddCommand(docsCmd)
+
 	// schemaCmd implements the "schema" command.
 	schemaCmd := &cobra.Command{
 		Use:   "schema",
@@ -135,6 +283,78 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(schemaCmd)
 
+	// pubsubCmd implements the "pubsub" command.
+	pubsubCmd := &cobra.Command{
+		Use:   "pubsub",
+		Short: "Generate message bus bindings",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genpubsub", c) },
+	}
+	rootCmd.AddCommand(pubsubCmd)
+
+	// avroCmd implements the "avro" command.
+	var namespace string
+	avroCmd := &cobra.Command{
+		Use:   "avro",
+		Short: "Generate Avro schemas",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genavro", c) },
+	}
+	avroCmd.Flags().StringVar(&namespace, "namespace", "", `the Avro namespace of the generated schemas, defaults to the snake case API name`)
+	rootCmd.AddCommand(avroCmd)
+
+	// graphCmd implements the "graph" command.
+	var graphFormat string
+	graphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Generate a diagram of the resources, actions and types of the design",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gengraph", c) },
+	}
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", `the diagram format, "dot" (Graphviz) or "mermaid"`)
+	rootCmd.AddCommand(graphCmd)
+
+	// mockCmd implements the "mock" command.
+	mockCmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Generate standalone mock server",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genmock", c) },
+	}
+	rootCmd.AddCommand(mockCmd)
+
+	// pactCmd implements the "pact" command.
+	var consumer string
+	pactCmd := &cobra.Command{
+		Use:   "pact",
+		Short: "Generate Pact contract and verification code",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genpact", c) },
+	}
+	pactCmd.Flags().StringVar(&consumer, "consumer", "consumer", `the name of the consumer of the contract`)
+	rootCmd.AddCommand(pactCmd)
+
+	// jobsCmd implements the "jobs" command.
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Generate asynchronous jobs support",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genjobs", c) },
+	}
+	rootCmd.AddCommand(jobsCmd)
+
+	// graphqlCmd implements the "graphql" command.
+	var noresolvers bool
+	graphqlCmd := &cobra.Command{
+		Use:   "graphql",
+		Short: "Generate GraphQL schema and resolvers",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("gengraphql", c) },
+	}
+	graphqlCmd.Flags().BoolVar(&noresolvers, "noresolvers", false, `Only generate the GraphQL schema, for example to stitch the API into a federated graph`)
+	rootCmd.AddCommand(graphqlCmd)
+
+	// kitCmd implements the "kit" command.
+	kitCmd := &cobra.Command{
+		Use:   "kit",
+		Short: "Generate go-kit endpoint adapters",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genkit", c) },
+	}
+	rootCmd.AddCommand(kitCmd)
+
 	// genCmd implements the "gen" command.
 	var (
 		pkgPath string
@@ -151,32 +371,34 @@ package and tool and the Swagger specification for the API.
 	rootCmd.AddCommand(genCmd)
 
 	// boostrapCmd implements the "bootstrap" command.
+	var only string
+	bootTargets := []*bootstrapTarget{
+		{Name: "app", Cmd: appCmd},
+		{Name: "main", Cmd: mainCmd, Requires: []string{"app"}},
+		{Name: "client", Cmd: clientCmd},
+		{Name: "swagger", Cmd: swaggerCmd},
+	}
 	bootCmd := &cobra.Command{
 		Use:   "bootstrap",
 		Short: `Equivalent to running the "app", "main", "client" and "swagger" commands.`,
 		Run: func(c *cobra.Command, a []string) {
-			appCmd.Run(c, a)
+			var targets []*bootstrapTarget
+			targets, err = selectTargets(bootTargets, only, c.Flag("out").Value.String())
 			if err != nil {
 				return
 			}
-			prev := files
-
-			mainCmd.Run(c, a)
-			if err != nil {
-				return
+			var prev []string
+			for _, t := range targets {
+				t.Cmd.Run(c, a)
+				if err != nil {
+					return
+				}
+				prev = append(prev, files...)
 			}
-			prev = append(prev, files...)
-
-			clientCmd.Run(c, a)
-			if err != nil {
-				return
-			}
-			prev = append(prev, files...)
-
-			swaggerCmd.Run(c, a)
-			files = append(prev, files...)
+			files = prev
 		},
 	}
+	bootCmd.Flags().StringVar(&only, "only", "", `comma separated list of the commands to run among "app", "main", "client" and "swagger", e.g. "client,swagger", defaults to all`)
 	bootCmd.Flags().AddFlagSet(appCmd.Flags())
 	bootCmd.Flags().AddFlagSet(mainCmd.Flags())
 	bootCmd.Flags().AddFlagSet(clientCmd.Flags())
@@ -194,11 +416,47 @@ package and tool and the Swagger specification for the API.
 	}
 	controllerCmd.Flags().BoolVar(&force, "force", false, "overwrite existing files")
 	controllerCmd.Flags().BoolVar(&regen, "regen", false, "regenerate scaffolding, maintaining controller implementations")
+	controllerCmd.Flags().BoolVar(&merge, "merge", false, "regenerate scaffolding, merging the changes made to the existing files since they were last generated")
 	controllerCmd.Flags().StringVar(&res, "res", "", "name of the `resource` to generate the controller for, generate all if not specified")
 	controllerCmd.Flags().StringVar(&pkg, "pkg", "main", "name of the generated controller `package`")
 	controllerCmd.Flags().StringVar(&appPkg, "app-pkg", "app", "`import path` of Go package generated with 'goagen app', may be relative to output")
 	rootCmd.AddCommand(controllerCmd)
 
+	// importCmd implements the "import" command.
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Generate a design package from an existing API description",
+	}
+	var designPkgName string
+	openapiImportCmd := &cobra.Command{
+		Use:   "openapi SPEC",
+		Short: "Generate a design package from an OpenAPI 3.x or Swagger 2.0 document",
+		Run:   func(c *cobra.Command, args []string) { files, err = runImportOpenAPI(c, args, designPkgName) },
+	}
+	openapiImportCmd.Flags().StringVar(&designPkgName, "pkg", "design", "name of the generated design `package`")
+	importCmd.AddCommand(openapiImportCmd)
+	rootCmd.AddCommand(importCmd)
+
+	// initCmd implements the "init" command.
+	initCmd := &cobra.Command{
+		Use:   "init MODULE",
+		Short: "Create the skeleton of a new project: design package, go.mod, Makefile and .gitignore",
+		Run:   func(c *cobra.Command, args []string) { files, err = runInit(c, args) },
+	}
+	rootCmd.AddCommand(initCmd)
+
+	// verifyCmd implements the "verify" command.
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that the files generated in the output directory are up to date with the design",
+		Long: `The verify command computes the hash of the design package sources and compares it with the
+hash recorded in the headers of the Go files generated from the design in the output directory.
+It exits with a non-zero status and lists the stale files if the design changed since they were
+generated, which makes it suitable for pre-commit hooks and CI checks.`,
+		Run: func(c *cobra.Command, _ []string) { err = runVerify(c, designPkg) },
+	}
+	rootCmd.AddCommand(verifyCmd)
+
 	// cmdsCmd implements the commands command
 	// It lists all the commands and flags in JSON to enable shell integrations.
 	cmdsCmd := &cobra.Command{
@@ -219,7 +477,7 @@ package and tool and the Swagger specification for the API.
 		terminatedByUser = true
 	})
 
-	rootCmd.Execute()
+	cmd, _ := rootCmd.ExecuteC()
 
 	if terminatedByUser {
 		cleanup()
@@ -229,20 +487,118 @@ package and tool and the Swagger specification for the API.
 	if err != nil {
 		cleanup()
 		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+		if !watch {
+			os.Exit(1)
+		}
+	} else {
+		rels := make([]string, len(files))
+		cd, _ := os.Getwd()
+		for i, f := range files {
+			r, err := filepath.Rel(cd, f)
+			if err == nil {
+				rels[i] = r
+			} else {
+				rels[i] = f
+			}
+		}
+		fmt.Println(strings.Join(rels, "\n"))
 	}
 
-	rels := make([]string, len(files))
-	cd, _ := os.Getwd()
-	for i, f := range files {
-		r, err := filepath.Rel(cd, f)
-		if err == nil {
-			rels[i] = r
-		} else {
-			rels[i] = f
+	if watch {
+		regenerate := func() ([]string, error) {
+			files, err = nil, nil
+			cmd.Run(cmd, cmd.Flags().Args())
+			return files, err
+		}
+		if werr := runWatch(cmd, designPkg, files, regenerate); werr != nil {
+			fmt.Fprintln(os.Stderr, werr.Error())
+			os.Exit(1)
 		}
 	}
-	fmt.Println(strings.Join(rels, "\n"))
+}
+
+// runWatch runs gen each time the design package changes until the process is interrupted. files
+// lists the outputs of the initial run of cmd.
+func runWatch(cmd *cobra.Command, designPkg string, files []string, gen func() ([]string, error)) error {
+	if cmd == nil || cmd.Run == nil {
+		return fmt.Errorf("--watch requires a generation command")
+	}
+	if designPkg == "" {
+		return fmt.Errorf("--watch requires the design package import path (--design)")
+	}
+	stop := make(chan struct{})
+	go utils.Catch(nil, func() { close(stop) })
+	return meta.NewWatcher(designPkg, gen).Run(files, stop)
+}
+
+// bootstrapTarget is a command run by the bootstrap command.
+type bootstrapTarget struct {
+	// Name is the name of the command.
+	Name string
+	// Cmd is the command.
+	Cmd *cobra.Command
+	// Requires lists the names of the commands whose output the command output depends on.
+	Requires []string
+}
+
+// selectTargets returns the targets whose names are listed in only, a comma separated list, or all
+// the targets if only is empty. It fails if a selected target requires a target that is not
+// selected and that has not been generated before according to the manifest of outDir, e.g. the
+// "main" command generates code that imports the package generated by the "app" command.
+func selectTargets(targets []*bootstrapTarget, only, outDir string) ([]*bootstrapTarget, error) {
+	if only == "" {
+		return targets, nil
+	}
+	names := make(map[string]bool)
+	for _, n := range strings.Split(only, ",") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		found := false
+		for _, t := range targets {
+			if t.Name == n {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown target %q in --only", n)
+		}
+		names[n] = true
+	}
+	var (
+		selected []*bootstrapTarget
+		manifest *meta.Manifest
+	)
+	for _, t := range targets {
+		if !names[t.Name] {
+			continue
+		}
+		for _, r := range t.Requires {
+			if names[r] {
+				continue
+			}
+			if manifest == nil {
+				var err error
+				if manifest, err = meta.ReadManifest(outDir); err != nil {
+					return nil, err
+				}
+			}
+			generated := false
+			for _, e := range manifest.Files {
+				if e.Generator == "gen"+r {
+					generated = true
+					break
+				}
+			}
+			if !generated {
+				return nil, fmt.Errorf("target %q requires %q: add it to --only or run goagen %s first", t.Name, r, r)
+			}
+		}
+		selected = append(selected, t)
+	}
+	return selected, nil
 }
 
 func run(pkg string, c *cobra.Command) ([]string, error) {
@@ -271,10 +627,68 @@ func runGen(c *cobra.Command, args []string) ([]string, error) {
 	return generate(pkgName, pkgPath, c, args)
 }
 
+func runImportOpenAPI(c *cobra.Command, args []string, pkg string) ([]string, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("usage: goagen import openapi SPEC")
+	}
+	doc, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return nil, err
+	}
+	src, err := importer.OpenAPI(doc, pkg)
+	if err != nil {
+		return nil, err
+	}
+	outDir, err := filepath.Abs(filepath.Join(c.Flag("out").Value.String(), pkg))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return nil, err
+	}
+	designFile := filepath.Join(outDir, "design.go")
+	if _, err := os.Stat(designFile); err == nil {
+		return nil, fmt.Errorf("%s already exists, remove it first to import the specification", designFile)
+	}
+	if err := ioutil.WriteFile(designFile, src, 0644); err != nil {
+		return nil, err
+	}
+	return []string{designFile}, nil
+}
+
+func runInit(c *cobra.Command, args []string) ([]string, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("usage: goagen init MODULE")
+	}
+	dir, err := filepath.Abs(filepath.Join(c.Flag("out").Value.String(), path.Base(args[0])))
+	if err != nil {
+		return nil, err
+	}
+	return skeleton.Init(args[0], dir)
+}
+
+func runVerify(c *cobra.Command, designPkg string) error {
+	if designPkg == "" {
+		return fmt.Errorf("missing design package flag (--design)")
+	}
+	hash, err := meta.DesignHash(designPkg)
+	if err != nil {
+		return err
+	}
+	stale, err := meta.Verify(designPkg, hash, c.Flag("out").Value.String())
+	if err != nil {
+		return err
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("generated files are out of date with the design, run goagen again:\n%s", strings.Join(stale, "\n"))
+	}
+	return nil
+}
+
 func generate(pkgName, pkgPath string, c *cobra.Command, args []string) ([]string, error) {
 	m := make(map[string]string)
 	c.Flags().Visit(func(f *pflag.Flag) {
-		if f.Name != "pkg-path" {
+		if f.Name != "pkg-path" && f.Name != "watch" && f.Name != "only" {
 			m[f.Name] = f.Value.String()
 		}
 	})
@@ -79,7 +79,8 @@ func NewGenerator(genfunc string, imports []*codegen.ImportSpec, flags map[strin
 	}, nil
 }
 
-// Generate compiles and runs the generator and returns the generated filenames.
+// Generate compiles and runs the generator and returns the generated filenames. It records the
+// generated files in the manifest of the output directory, see UpdateManifest.
 func (m *Generator) Generate() ([]string, error) {
 	// Sanity checks
 	if os.Getenv("GOPATH") == "" {
@@ -114,8 +115,11 @@ func (m *Generator) Generate() ([]string, error) {
 			os.RemoveAll(tmpDir)
 		}
 	}()
+	var debugDir string
 	if m.debug {
+		debugDir = filepath.Join(tmpDir, "data")
 		fmt.Printf("** Code generator source dir: %s\n", tmpDir)
+		fmt.Printf("** Template data dump dir: %s\n", debugDir)
 	}
 
 	pkgSourcePath, err := codegen.PackageSourcePath(m.DesignPkgPath)
@@ -133,7 +137,11 @@ func (m *Generator) Generate() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	m.generateToolSourceCode(p)
+	hash, err := DesignHash(m.DesignPkgPath)
+	if err != nil {
+		return nil, err
+	}
+	m.generateToolSourceCode(p, hash, debugDir)
 
 	// Compile and run generated tool.
 	if m.debug {
@@ -143,10 +151,23 @@ func (m *Generator) Generate() ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	return m.spawn(genbin)
+	files, err := m.spawn(genbin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the generated files and remove the orphans left by the previous run.
+	removed, err := UpdateManifest(m.OutDir, m.DesignPkgPath, generatorName(m.Genfunc), m.Flags["pkg"], files)
+	if err != nil {
+		return nil, err
+	}
+	if m.debug && len(removed) > 0 {
+		fmt.Printf("** Removed orphaned files:\n%s\n", strings.Join(removed, "\n"))
+	}
+	return files, nil
 }
 
-func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
+func (m *Generator) generateToolSourceCode(pkg *codegen.Package, hash, debugDir string) {
 	file, err := pkg.CreateSourceFile("main.go")
 	if err != nil {
 		panic(err) // bug
@@ -156,6 +177,7 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("github.com/goadesign/goa/dslengine"),
+		codegen.SimpleImport("github.com/goadesign/goa/goagen/codegen"),
 		codegen.NewImport("_", filepath.ToSlash(m.DesignPkgPath)),
 	)
 	file.WriteHeader("Code Generator", "main", imports)
@@ -171,6 +193,8 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 		"Genfunc":       m.Genfunc,
 		"DesignPackage": m.DesignPkgPath,
 		"PkgName":       pkgName,
+		"DesignHash":    hash,
+		"DebugDir":      debugDir,
 	}
 	if err := tmpl.Execute(file, context); err != nil {
 		panic(err) // bug
@@ -210,6 +234,12 @@ func main() {
 	// Now run the secondary DSLs
 	dslengine.FailOnError(dslengine.Run())
 
+	// Record the design hash in the headers of the generated files
+	codegen.DesignHash = {{printf "%q" .DesignHash}}
+{{if .DebugDir}}
+	// Dump the template data to help diagnose the generated code
+	codegen.DebugDir = {{printf "%q" .DebugDir}}
+{{end}}
 	files, err := {{.Genfunc}}()
 	dslengine.FailOnError(err)
 
@@ -0,0 +1,180 @@
+package meta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFile is the name of the generation manifest file written to the output directory.
+const ManifestFile = "goagen_manifest.json"
+
+type (
+	// Manifest lists the files generated in an output directory by the successive goagen
+	// invocations.
+	Manifest struct {
+		// Files lists the generated files sorted by path.
+		Files []*ManifestEntry `json:"files"`
+	}
+
+	// ManifestEntry describes a generated file.
+	ManifestEntry struct {
+		// Path is the path to the file relative to the output directory using slashes.
+		Path string `json:"path"`
+		// Generator is the name of the generator package that produced the file, e.g. "genapp".
+		Generator string `json:"generator"`
+		// Target is the name of the generated package given to the generator with --pkg, empty
+		// if the flag was not set.
+		Target string `json:"target,omitempty"`
+		// Design is the import path of the design package the file was generated from.
+		Design string `json:"design"`
+		// Hash is the hex encoded SHA-256 hash of the file content when it was generated.
+		Hash string `json:"hash"`
+	}
+)
+
+// ReadManifest reads the generation manifest of the output directory dir. It returns an empty
+// manifest if dir does not contain one.
+func ReadManifest(dir string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// UpdateManifest records the files produced by generator in the generation manifest of the output
+// directory dir. target is the name of the generated package and design the import path of the
+// design package. The entries recorded by a previous invocation of the same generator with the same
+// target and design are replaced. The files they list that were not produced again are orphans:
+// they are deleted if goagen generated them (i.e. they start with the "Code generated by goagen"
+// comment) and if they have not been modified since. The other orphans, such as scaffolding files
+// that are not overwritten, are kept in the manifest for as long as they exist. The files recorded
+// by other generators, targets or designs are never deleted. UpdateManifest returns the deleted
+// files.
+func UpdateManifest(dir, design, generator, target string, files []string) ([]string, error) {
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	generated := make(map[string]*ManifestEntry)
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(absDir, abs)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := fileHash(abs)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		generated[rel] = &ManifestEntry{Path: rel, Generator: generator, Target: target, Design: design, Hash: hash}
+	}
+	if len(generated) == 0 && len(m.Files) == 0 {
+		// Commands such as vet or explain do not generate files, do not create a manifest for them.
+		return nil, nil
+	}
+
+	var (
+		entries []*ManifestEntry
+		removed []string
+	)
+	for _, e := range m.Files {
+		if generated[e.Path] != nil {
+			continue
+		}
+		path := filepath.Join(absDir, filepath.FromSlash(e.Path))
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		if e.Generator == generator && e.Target == target && e.Design == design {
+			orphan, err := isUnmodifiedGoagenFile(path, e.Hash)
+			if err != nil {
+				return nil, err
+			}
+			if orphan {
+				if err := os.Remove(path); err != nil {
+					return nil, err
+				}
+				removed = append(removed, path)
+				continue
+			}
+		}
+		entries = append(entries, e)
+	}
+	for _, e := range generated {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+	m.Files = entries
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ManifestFile), append(b, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+// isUnmodifiedGoagenFile returns true if the file at path was generated by goagen and its content
+// hash is hash.
+func isUnmodifiedGoagenFile(path, hash string) (bool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.HasPrefix(b, []byte("// Code generated by goagen")) {
+		return false, nil
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]) == hash, nil
+}
+
+// fileHash returns the hex encoded SHA-256 hash of the content of the file at path.
+func fileHash(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// generatorName returns the name of the generator package of the entry point genfunc, e.g.
+// "genapp" for "genapp.Generate".
+func generatorName(genfunc string) string {
+	if i := strings.LastIndex(genfunc, "."); i > 0 {
+		return genfunc[:i]
+	}
+	return genfunc
+}
@@ -0,0 +1,153 @@
+package meta_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/meta"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateManifest", func() {
+	const generated = "// Code generated by goagen v1.3.1, DO NOT EDIT.\n\npackage app\n"
+
+	var outDir string
+
+	write := func(name, content string) string {
+		path := filepath.Join(outDir, name)
+		Ω(os.MkdirAll(filepath.Dir(path), 0755)).Should(Succeed())
+		Ω(ioutil.WriteFile(path, []byte(content), 0644)).Should(Succeed())
+		return path
+	}
+
+	paths := func(m *meta.Manifest) []string {
+		var ps []string
+		for _, e := range m.Files {
+			ps = append(ps, e.Generator+":"+e.Path)
+		}
+		return ps
+	}
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = ioutil.TempDir("", "manifest")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	It("records the generated files", func() {
+		contexts := write("app/contexts.go", generated)
+		dir := filepath.Join(outDir, "app")
+
+		removed, err := meta.UpdateManifest(outDir, "design", "genapp", "", []string{dir, contexts, "", "missing.go"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(removed).Should(BeEmpty())
+
+		m, err := meta.ReadManifest(outDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(m.Files).Should(HaveLen(1))
+		Ω(m.Files[0].Path).Should(Equal("app/contexts.go"))
+		Ω(m.Files[0].Generator).Should(Equal("genapp"))
+		Ω(m.Files[0].Design).Should(Equal("design"))
+		sum := sha256.Sum256([]byte(generated))
+		Ω(m.Files[0].Hash).Should(Equal(hex.EncodeToString(sum[:])))
+	})
+
+	It("keeps the files of the other generators", func() {
+		contexts := write("app/contexts.go", generated)
+		client := write("client/client.go", generated)
+		_, err := meta.UpdateManifest(outDir, "design", "genapp", "", []string{contexts})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = meta.UpdateManifest(outDir, "design", "genclient", "", []string{client})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		m, err := meta.ReadManifest(outDir)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(paths(m)).Should(Equal([]string{"genapp:app/contexts.go", "genclient:client/client.go"}))
+	})
+
+	Context("with orphaned files", func() {
+		var unmodified, modified, scaffold, deleted string
+
+		BeforeEach(func() {
+			contexts := write("app/contexts.go", generated)
+			unmodified = write("app/test/bottle_testing.go", generated)
+			modified = write("app/test/account_testing.go", generated)
+			scaffold = write("bottle.go", "package main\n")
+			deleted = write("app/test/user_testing.go", generated)
+			_, err := meta.UpdateManifest(outDir, "design", "genapp", "", []string{contexts, unmodified, modified, scaffold, deleted})
+			Ω(err).ShouldNot(HaveOccurred())
+
+			write("app/test/account_testing.go", generated+"\nvar _ = 1\n")
+			Ω(os.Remove(deleted)).Should(Succeed())
+		})
+
+		It("removes the unmodified generated files", func() {
+			contexts := filepath.Join(outDir, "app", "contexts.go")
+			removed, err := meta.UpdateManifest(outDir, "design", "genapp", "", []string{contexts})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(removed).Should(ConsistOf(unmodified))
+			_, err = os.Stat(unmodified)
+			Ω(os.IsNotExist(err)).Should(BeTrue())
+			Ω(modified).Should(BeAnExistingFile())
+			Ω(scaffold).Should(BeAnExistingFile())
+
+			m, err := meta.ReadManifest(outDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(paths(m)).Should(Equal([]string{
+				"genapp:app/contexts.go",
+				"genapp:app/test/account_testing.go",
+				"genapp:bottle.go",
+			}))
+		})
+
+		It("does not remove the files of the other generators", func() {
+			removed, err := meta.UpdateManifest(outDir, "design", "genclient", "", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(removed).Should(BeEmpty())
+			Ω(unmodified).Should(BeAnExistingFile())
+		})
+
+		It("does not remove the files generated for another package", func() {
+			v2 := write("v2/contexts.go", generated)
+			removed, err := meta.UpdateManifest(outDir, "design", "genapp", "v2", []string{v2})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(removed).Should(BeEmpty())
+			Ω(unmodified).Should(BeAnExistingFile())
+
+			m, err := meta.ReadManifest(outDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(paths(m)).Should(ContainElement("genapp:app/test/bottle_testing.go"))
+			Ω(paths(m)).Should(ContainElement("genapp:v2/contexts.go"))
+		})
+
+		It("does not remove the files generated from another design", func() {
+			removed, err := meta.UpdateManifest(outDir, "other", "genapp", "", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(removed).Should(BeEmpty())
+			Ω(unmodified).Should(BeAnExistingFile())
+
+			m, err := meta.ReadManifest(outDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, e := range m.Files {
+				Ω(e.Design).Should(Equal("design"))
+			}
+		})
+	})
+
+	Context("without manifest", func() {
+		It("reads an empty manifest", func() {
+			m, err := meta.ReadManifest(outDir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(m.Files).Should(BeEmpty())
+		})
+	})
+})
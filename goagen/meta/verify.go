@@ -0,0 +1,123 @@
+package meta
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// DesignHash returns the hash of the Go sources of the design package and of its sub-packages.
+// Test files are ignored. The hash only depends on the paths of the sources relative to the design
+// package directory and on their content so that it is the same on all machines.
+func DesignHash(designPkgPath string) (string, error) {
+	dir, err := codegen.PackageSourcePath(designPkgPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid design package import path: %s", err)
+	}
+	sources, err := scanSources(dir)
+	if err != nil {
+		return "", err
+	}
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		if !strings.HasSuffix(path, "_test.go") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\n%d\n", filepath.ToSlash(rel), len(b))
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Verify returns the Go files located in dir and its sub-directories that goagen generated from
+// the given design package and whose headers do not record the given design hash. The files
+// generated by versions of goagen that did not record the hash are returned as well. Verify
+// returns an error if dir contains no file generated from the design package.
+func Verify(designPkgPath, hash, dir string) ([]string, error) {
+	var (
+		stale []string
+		found bool
+	)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".go" {
+			return nil
+		}
+		design, recorded, err := readHeader(path)
+		if err != nil {
+			return err
+		}
+		if design != designPkgPath {
+			return nil
+		}
+		found = true
+		if recorded != hash {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("no file generated from %s found in %s", designPkgPath, dir)
+	}
+	return stale, nil
+}
+
+// readHeader reads the header of a file generated by goagen and returns the design package import
+// path and the design hash it records. It returns empty strings if the file was not generated by
+// goagen.
+func readHeader(path string) (design, hash string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "// Code generated by goagen") {
+		return "", "", scanner.Err()
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "// --design="):
+			design = strings.TrimPrefix(line, "// --design=")
+		case strings.HasPrefix(line, codegen.DesignHashComment):
+			hash = strings.TrimPrefix(line, codegen.DesignHashComment)
+		}
+	}
+	return design, hash, scanner.Err()
+}
@@ -0,0 +1,115 @@
+package meta_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Verify", func() {
+	const designPkgPath = "verify/design"
+
+	var (
+		workspace  *codegen.Workspace
+		designFile string
+		outDir     string
+	)
+
+	writeDesign := func(content string) {
+		Ω(ioutil.WriteFile(designFile, []byte(content), 0644)).Should(Succeed())
+	}
+
+	writeGenerated := func(name, design, hash string) string {
+		header := fmt.Sprintf("// Code generated by goagen v1.3.1, DO NOT EDIT.\n//\n// API \"test\": Application Contexts\n//\n// Command:\n// $ goagen\n// --design=%s\n", design)
+		if hash != "" {
+			header += "//\n" + codegen.DesignHashComment + hash + "\n"
+		}
+		path := filepath.Join(outDir, name)
+		Ω(ioutil.WriteFile(path, []byte(header+"\npackage app\n"), 0644)).Should(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("verify")
+		Ω(err).ShouldNot(HaveOccurred())
+		p, err := workspace.NewPackage(designPkgPath)
+		Ω(err).ShouldNot(HaveOccurred())
+		designFile = filepath.Join(p.Abs(), "design.go")
+		writeDesign("package design")
+		outDir, err = ioutil.TempDir(workspace.Path, "out")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("DesignHash", func() {
+		It("depends on the content of the design sources", func() {
+			hash, err := meta.DesignHash(designPkgPath)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(meta.DesignHash(designPkgPath)).Should(Equal(hash))
+
+			writeDesign("package design\n\nvar _ = 1")
+			Ω(meta.DesignHash(designPkgPath)).ShouldNot(Equal(hash))
+		})
+
+		It("ignores the test files", func() {
+			hash, err := meta.DesignHash(designPkgPath)
+			Ω(err).ShouldNot(HaveOccurred())
+			testFile := filepath.Join(filepath.Dir(designFile), "design_test.go")
+			Ω(ioutil.WriteFile(testFile, []byte("package design"), 0644)).Should(Succeed())
+			Ω(meta.DesignHash(designPkgPath)).Should(Equal(hash))
+		})
+	})
+
+	Context("with files generated from the current design", func() {
+		It("reports no stale file", func() {
+			hash, err := meta.DesignHash(designPkgPath)
+			Ω(err).ShouldNot(HaveOccurred())
+			writeGenerated("contexts.go", designPkgPath, hash)
+
+			Ω(meta.Verify(designPkgPath, hash, outDir)).Should(BeEmpty())
+		})
+	})
+
+	Context("with files generated from a previous version of the design", func() {
+		It("reports the stale files", func() {
+			hash, err := meta.DesignHash(designPkgPath)
+			Ω(err).ShouldNot(HaveOccurred())
+			fresh := writeGenerated("contexts.go", designPkgPath, hash)
+			writeDesign("package design\n\nvar _ = 1")
+			hash, err = meta.DesignHash(designPkgPath)
+			Ω(err).ShouldNot(HaveOccurred())
+			unrecorded := writeGenerated("hrefs.go", designPkgPath, "")
+
+			Ω(meta.Verify(designPkgPath, hash, outDir)).Should(ConsistOf(fresh, unrecorded))
+		})
+	})
+
+	Context("with files generated from another design", func() {
+		It("ignores them", func() {
+			hash, err := meta.DesignHash(designPkgPath)
+			Ω(err).ShouldNot(HaveOccurred())
+			writeGenerated("contexts.go", designPkgPath, hash)
+			writeGenerated("other.go", "other/design", "0000")
+
+			Ω(meta.Verify(designPkgPath, hash, outDir)).Should(BeEmpty())
+		})
+	})
+
+	Context("without files generated from the design", func() {
+		It("returns an error", func() {
+			Ω(ioutil.WriteFile(filepath.Join(outDir, "main.go"), []byte("package main"), 0644)).Should(Succeed())
+
+			_, err := meta.Verify(designPkgPath, "0000", outDir)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,211 @@
+package meta
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// Watcher runs the code generation each time the sources of the design package change. It
+	// implements the goagen watch mode.
+	Watcher struct {
+		// DesignPkgPath is the Go import path to the design package.
+		DesignPkgPath string
+		// Interval is the delay between two scans of the design package sources.
+		Interval time.Duration
+		// Generate runs the code generation and returns the generated files and directories.
+		Generate func() ([]string, error)
+		// Log receives the progress messages and the generation errors.
+		Log io.Writer
+	}
+
+	// source records the state of a design source file.
+	source struct {
+		mod  time.Time
+		size int64
+	}
+
+	// output records the state of a generated file.
+	output struct {
+		sum [sha256.Size]byte
+		mod time.Time
+	}
+)
+
+// NewWatcher returns a watcher that scans the design package sources every 500ms and runs gen
+// when they change. The messages are written to the standard output.
+func NewWatcher(designPkgPath string, gen func() ([]string, error)) *Watcher {
+	return &Watcher{
+		DesignPkgPath: designPkgPath,
+		Interval:      500 * time.Millisecond,
+		Generate:      gen,
+		Log:           os.Stdout,
+	}
+}
+
+// Run watches the Go source files of the design package and of its sub-packages until stop is
+// closed. files lists the outputs of the initial generation. Each time a source file is created,
+// modified or deleted Run runs the generation again and prints either the generation errors or the
+// generated files whose content changed. The modification time of the regenerated files whose
+// content did not change is restored so that only the affected outputs appear modified to build
+// tools and editors.
+func (w *Watcher) Run(files []string, stop <-chan struct{}) error {
+	dir, err := codegen.PackageSourcePath(w.DesignPkgPath)
+	if err != nil {
+		return fmt.Errorf("invalid design package import path: %s", err)
+	}
+	sources, err := scanSources(dir)
+	if err != nil {
+		return err
+	}
+	outputs := scanOutputs(files)
+	fmt.Fprintf(w.Log, "watching %s for changes\n", w.DesignPkgPath)
+
+	// pending records the sources that changed since the last generation, the generation only
+	// runs once two consecutive scans agree so that files being written are not picked up.
+	var pending map[string]source
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+		current, err := scanSources(dir)
+		if err != nil {
+			fmt.Fprintf(w.Log, "error: %s\n", err)
+			continue
+		}
+		if sameSources(sources, current) {
+			pending = nil
+			continue
+		}
+		if pending == nil || !sameSources(pending, current) {
+			pending = current
+			continue
+		}
+		sources, pending = current, nil
+		fmt.Fprintln(w.Log, "design changed, regenerating")
+		files, err := w.Generate()
+		if err != nil {
+			fmt.Fprintf(w.Log, "error: %s\n", strings.TrimSpace(err.Error()))
+			continue
+		}
+		regenerated := scanOutputs(files)
+		changed, removed := restoreUnchanged(outputs, regenerated)
+		outputs = regenerated
+		if len(changed) == 0 && len(removed) == 0 {
+			fmt.Fprintln(w.Log, "no changes")
+			continue
+		}
+		for _, f := range changed {
+			fmt.Fprintf(w.Log, "updated %s\n", relative(f))
+		}
+		for _, f := range removed {
+			fmt.Fprintf(w.Log, "removed %s\n", relative(f))
+		}
+	}
+}
+
+// scanSources returns the state of the Go files located in dir and its sub-directories. The
+// hidden directories, the directories whose name starts with "_", vendor and testdata are
+// skipped like the go tool does.
+func scanSources(dir string) (map[string]source, error) {
+	sources := make(map[string]source)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".go" {
+			sources[path] = source{mod: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return sources, err
+}
+
+// sameSources returns true if a and b record the same files in the same state.
+func sameSources(a, b map[string]source) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, s := range a {
+		o, ok := b[path]
+		if !ok || o.size != s.size || !o.mod.Equal(s.mod) {
+			return false
+		}
+	}
+	return true
+}
+
+// scanOutputs returns the state of the given generated files and of the files located in the
+// given generated directories. The files that cannot be read are ignored.
+func scanOutputs(files []string) map[string]output {
+	outputs := make(map[string]output)
+	for _, f := range files {
+		filepath.Walk(f, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			outputs[path] = output{sum: sha256.Sum256(b), mod: info.ModTime()}
+			return nil
+		})
+	}
+	return outputs
+}
+
+// restoreUnchanged resets the modification time of the regenerated files whose content did not
+// change and returns the sorted lists of the new or modified files and of the removed files.
+func restoreUnchanged(previous, regenerated map[string]output) (changed, removed []string) {
+	for path, o := range regenerated {
+		p, ok := previous[path]
+		if !ok || p.sum != o.sum {
+			changed = append(changed, path)
+			continue
+		}
+		if !p.mod.Equal(o.mod) && os.Chtimes(path, p.mod, p.mod) == nil {
+			regenerated[path] = output{sum: o.sum, mod: p.mod}
+		}
+	}
+	for path := range previous {
+		if _, ok := regenerated[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return
+}
+
+// relative returns the path of f relative to the current directory if possible, f otherwise.
+func relative(f string) string {
+	cd, err := os.Getwd()
+	if err != nil {
+		return f
+	}
+	if r, err := filepath.Rel(cd, f); err == nil {
+		return r
+	}
+	return f
+}
@@ -0,0 +1,138 @@
+package meta_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	sync.Mutex
+	b bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.Lock()
+	defer s.Unlock()
+	return s.b.String()
+}
+
+var _ = Describe("Watcher", func() {
+	var (
+		workspace  *codegen.Workspace
+		designFile string
+		outDir     string
+		genErr     error
+		runs       int
+		mu         sync.Mutex
+		log        *syncBuffer
+		stop       chan struct{}
+		done       chan error
+		past       time.Time
+	)
+
+	generate := func() ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if genErr != nil {
+			return nil, genErr
+		}
+		runs++
+		if err := ioutil.WriteFile(filepath.Join(outDir, "changed.go"), []byte(fmt.Sprintf("// run %d", runs)), 0644); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(filepath.Join(outDir, "same.go"), []byte("// same"), 0644); err != nil {
+			return nil, err
+		}
+		return []string{outDir}, nil
+	}
+
+	touchDesign := func(content string) {
+		Ω(ioutil.WriteFile(designFile, []byte(content), 0644)).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("watch")
+		Ω(err).ShouldNot(HaveOccurred())
+		p, err := workspace.NewPackage("design")
+		Ω(err).ShouldNot(HaveOccurred())
+		designFile = filepath.Join(p.Abs(), "design.go")
+		touchDesign("package design")
+		outDir, err = ioutil.TempDir(workspace.Path, "out")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		genErr = nil
+		runs = 0
+		log = new(syncBuffer)
+		stop = make(chan struct{})
+		done = make(chan error, 1)
+
+		files, err := generate()
+		Ω(err).ShouldNot(HaveOccurred())
+		past = time.Now().Add(-time.Hour).Truncate(time.Second)
+		Ω(os.Chtimes(filepath.Join(outDir, "same.go"), past, past)).Should(Succeed())
+
+		w := meta.NewWatcher("design", generate)
+		w.Interval = 10 * time.Millisecond
+		w.Log = log
+		go func() { done <- w.Run(files, stop) }()
+		Eventually(log.String).Should(ContainSubstring("watching design for changes"))
+	})
+
+	AfterEach(func() {
+		close(stop)
+		Eventually(done).Should(Receive(BeNil()))
+		workspace.Delete()
+	})
+
+	Context("when the design changes", func() {
+		BeforeEach(func() {
+			touchDesign("package design\n\n// changed")
+		})
+
+		It("regenerates and reports the affected outputs only", func() {
+			Eventually(log.String).Should(ContainSubstring("changed.go"))
+			Ω(log.String()).Should(ContainSubstring("design changed, regenerating"))
+			Ω(log.String()).ShouldNot(ContainSubstring("same.go"))
+			info, err := os.Stat(filepath.Join(outDir, "same.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(info.ModTime().Equal(past)).Should(BeTrue())
+		})
+	})
+
+	Context("when the generation fails", func() {
+		BeforeEach(func() {
+			mu.Lock()
+			genErr = errors.New("invalid design")
+			mu.Unlock()
+			touchDesign("package design\n\n// broken")
+		})
+
+		It("prints the error and keeps watching", func() {
+			Eventually(log.String).Should(ContainSubstring("error: invalid design"))
+			mu.Lock()
+			genErr = nil
+			mu.Unlock()
+			touchDesign("package design\n\n// fixed again")
+			Eventually(log.String).Should(ContainSubstring("changed.go"))
+		})
+	})
+})
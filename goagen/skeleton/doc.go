@@ -0,0 +1,16 @@
+/*
+Package skeleton creates the skeleton of a new goa project.
+
+Init writes the following files in the project directory:
+
+  - design/design.go: the design package with a sample API and resource,
+  - go.mod: the Go module definition requiring the goa version that created the project,
+  - Makefile: the "gen", "build", "run" and "clean" targets,
+  - .gitignore: the generation artifacts policy described below.
+
+The code generated by "goagen bootstrap" falls in two categories: the "app", "client", "tool" and
+"swagger" directories are entirely regenerated by "make gen" and are not committed, while the
+main.go and controller files are scaffolding written once and then edited. The scaffolding and
+the ".goagen" directory used by "goagen main --merge" are committed.
+*/
+package skeleton
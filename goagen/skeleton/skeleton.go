@@ -0,0 +1,169 @@
+package skeleton
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/version"
+)
+
+// modulePattern matches the valid module paths.
+var modulePattern = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9_.~]*(/[-a-zA-Z0-9_.~]+)*$`)
+
+// Init creates the skeleton of a project for the Go module with the given path in dir. dir must
+// not exist or be empty. Init returns the paths of the created files.
+func Init(module, dir string) (files []string, err error) {
+	if !modulePattern.MatchString(module) {
+		return nil, fmt.Errorf("invalid module path %q", module)
+	}
+	if entries, err := ioutil.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s already exists and is not empty", dir)
+	}
+	defer func() {
+		if err != nil {
+			for _, f := range files {
+				os.Remove(f)
+			}
+			files = nil
+		}
+	}()
+
+	name := path.Base(module)
+	data := map[string]string{
+		"Module":     module,
+		"Name":       name,
+		"API":        strings.Replace(codegen.KebabCase(name), "_", "-", -1),
+		"Version":    version.String(),
+		"GoVersion":  "1.11",
+		"DesignPath": path.Join(module, "design"),
+	}
+	for _, f := range []struct{ name, tmpl string }{
+		{filepath.Join("design", "design.go"), designT},
+		{"go.mod", goModT},
+		{"Makefile", makefileT},
+		{".gitignore", gitignoreT},
+	} {
+		filename := filepath.Join(dir, f.name)
+		if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			return
+		}
+		if err = render(filename, f.tmpl, data); err != nil {
+			return
+		}
+		files = append(files, filename)
+	}
+	return files, nil
+}
+
+// render executes the template tmpl with data and writes the result to filename.
+func render(filename, tmpl string, data interface{}) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return template.Must(template.New(filepath.Base(filename)).Parse(tmpl)).Execute(f, data)
+}
+
+const designT = `package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("{{ .API }}", func() {
+	Title("The {{ .Name }} API")
+	Description("A sample API created by goagen init, edit this design to describe your own API.")
+	Host("localhost:8080")
+	Scheme("http")
+	BasePath("/api")
+})
+
+var _ = Resource("greeting", func() {
+	Description("The greeting resource says hello.")
+	BasePath("/greetings")
+	Action("show", func() {
+		Description("Greet the given name.")
+		Routing(GET("/:name"))
+		Params(func() {
+			Param("name", String, "Name of the person to greet", func() {
+				MinLength(1)
+			})
+		})
+		Response(OK, GreetingMedia)
+		Response(BadRequest, ErrorMedia)
+	})
+})
+
+// GreetingMedia is the greeting media type.
+var GreetingMedia = MediaType("application/vnd.{{ .API }}.greeting+json", func() {
+	Description("A greeting")
+	Attributes(func() {
+		Attribute("message", String, "The greeting message", func() {
+			Example("Hello, goa!")
+		})
+		Required("message")
+	})
+	View("default", func() {
+		Attribute("message")
+	})
+})
+`
+
+const goModT = `module {{ .Module }}
+
+go {{ .GoVersion }}
+
+require github.com/goadesign/goa {{ .Version }}
+`
+
+const makefileT = `# Makefile for {{ .Name }}
+#
+# Targets:
+# - "gen" generates the code from the design, the edits made to the scaffolding are preserved
+# - "build" compiles the service in bin/{{ .Name }}
+# - "run" starts the service
+# - "clean" removes the generated code and the service binary
+
+DESIGN={{ .DesignPath }}
+
+.PHONY: all gen build run clean
+
+all: gen build
+
+gen:
+	@goagen app -d $(DESIGN)
+	@goagen client -d $(DESIGN)
+	@goagen swagger -d $(DESIGN)
+	@goagen main -d $(DESIGN) --merge
+
+build:
+	@go build -o bin/{{ .Name }} .
+
+run:
+	@go run .
+
+clean:
+	@rm -rf app client tool swagger bin
+`
+
+const gitignoreT = `# Generated by "make gen" from the design.
+/app/
+/client/
+/tool/
+/swagger/
+
+# Build output.
+/bin/
+
+# The scaffolding (main.go, controllers) and the .goagen directory recording its generated
+# version are committed so that "goagen main --merge" can re-apply the edits.
+`
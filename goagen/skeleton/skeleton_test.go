@@ -0,0 +1,71 @@
+package skeleton_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/skeleton"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Init", func() {
+	var dir, module string
+	var files []string
+	var initErr error
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "skeleton")
+		Ω(err).ShouldNot(HaveOccurred())
+		module = "github.com/acme/cellar"
+	})
+
+	JustBeforeEach(func() {
+		files, initErr = skeleton.Init(module, filepath.Join(dir, "cellar"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, "cellar", name))
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(b)
+	}
+
+	It("creates the project skeleton", func() {
+		Ω(initErr).ShouldNot(HaveOccurred())
+		Ω(files).Should(HaveLen(4))
+		Ω(read(filepath.Join("design", "design.go"))).Should(ContainSubstring(`var _ = API("cellar", func() {`))
+		Ω(read("go.mod")).Should(Equal("module github.com/acme/cellar\n\ngo 1.11\n\nrequire github.com/goadesign/goa " + version.String() + "\n"))
+		Ω(read("Makefile")).Should(ContainSubstring("DESIGN=github.com/acme/cellar/design"))
+		Ω(read("Makefile")).Should(ContainSubstring("go build -o bin/cellar ."))
+		Ω(read(".gitignore")).Should(ContainSubstring("/app/\n"))
+	})
+
+	Context("with an existing project", func() {
+		BeforeEach(func() {
+			Ω(os.MkdirAll(filepath.Join(dir, "cellar"), 0755)).Should(Succeed())
+			Ω(ioutil.WriteFile(filepath.Join(dir, "cellar", "main.go"), []byte("package main"), 0644)).Should(Succeed())
+		})
+
+		It("fails", func() {
+			Ω(initErr).Should(HaveOccurred())
+			Ω(read("main.go")).Should(Equal("package main"))
+		})
+	})
+
+	Context("with an invalid module path", func() {
+		BeforeEach(func() {
+			module = "acme cellar"
+		})
+
+		It("fails", func() {
+			Ω(initErr).Should(MatchError(`invalid module path "acme cellar"`))
+		})
+	})
+})
@@ -0,0 +1,504 @@
+package goatest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa"
+	regen "github.com/zach-klippenstein/goregen"
+)
+
+// Schema describes a data structure and its validations. Generate and GenerateInvalid use schemas to
+// produce random values, goagen generates the schemas of the API payload types in the test package.
+type Schema struct {
+	// Type is one of "boolean", "integer", "number", "string", "datetime", "uuid", "any",
+	// "array", "hash" or "object".
+	Type string
+	// Enum lists the allowed values.
+	Enum []interface{}
+	// Format is the format of string values, e.g. "email".
+	Format string
+	// Pattern is the regular expression string values must match.
+	Pattern string
+	// Minimum is the minimum value of numbers.
+	Minimum *float64
+	// Maximum is the maximum value of numbers.
+	Maximum *float64
+	// MinLength is the minimum length of strings and arrays.
+	MinLength *int
+	// MaxLength is the maximum length of strings and arrays.
+	MaxLength *int
+	// Required lists the names of the required object attributes.
+	Required []string
+	// Attributes describes the object attributes. A nil schema denotes a recursive attribute that
+	// is never generated.
+	Attributes map[string]*Schema
+	// Key describes the hash keys.
+	Key *Schema
+	// Elem describes the array elements and the hash values.
+	Elem *Schema
+}
+
+const (
+	// maxDepth is the depth beyond which optional attributes are not generated.
+	maxDepth = 5
+	// maxViolationLength is the maximum length of the values generated to violate a length
+	// validation.
+	maxViolationLength = 256
+	// maxAttempts is the number of candidate strings generated before giving up on a validation.
+	maxAttempts = 20
+	// letters is the alphabet of the generated strings.
+	letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// Float64 returns a pointer to v, it is used to initialize the Minimum and Maximum schema fields.
+func Float64(v float64) *float64 { return &v }
+
+// Int returns a pointer to v, it is used to initialize the MinLength and MaxLength schema fields.
+func Int(v int) *int { return &v }
+
+// Generate returns a random value that satisfies the validations of s. The value has the shape of
+// decoded JSON so that encoding it and decoding the result into the generated type produces a valid
+// instance of the type. Generate produces the same value given the same source state.
+func Generate(r *rand.Rand, s *Schema) interface{} {
+	return generate(r, s, 0)
+}
+
+// GenerateInvalid returns a random value that violates a single validation of s together with the
+// description of the violated rule, e.g. "name: too short". It returns a valid value and an empty
+// description if s defines no validation that can be violated.
+func GenerateInvalid(r *rand.Rand, s *Schema) (interface{}, string) {
+	v := Generate(r, s)
+	var candidates []*violation
+	violations(r, s, "", v, func(nv interface{}) { v = nv }, 0, func(vi *violation) {
+		candidates = append(candidates, vi)
+	})
+	if len(candidates) == 0 {
+		return v, ""
+	}
+	vi := candidates[r.Intn(len(candidates))]
+	vi.apply()
+	return v, vi.rule
+}
+
+// violation is a modification of a generated value that violates a single validation rule.
+type violation struct {
+	rule  string
+	apply func()
+}
+
+// generate produces a valid value for s.
+func generate(r *rand.Rand, s *Schema, depth int) interface{} {
+	if s == nil {
+		return nil
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[r.Intn(len(s.Enum))]
+	}
+	switch s.Type {
+	case "boolean":
+		return r.Intn(2) == 1
+	case "integer":
+		lo, hi := bounds(s, true)
+		return int64(lo) + r.Int63n(int64(hi-lo)+1)
+	case "number":
+		lo, hi := bounds(s, false)
+		return lo + r.Float64()*(hi-lo)
+	case "string":
+		return generateString(r, s)
+	case "datetime":
+		return randomTime(r).Format(time.RFC3339)
+	case "uuid":
+		return randomUUID(r)
+	case "any":
+		return randomString(r, 1+r.Intn(10))
+	case "array":
+		n := generateLength(r, s, 3)
+		if s.Elem == nil {
+			// Recursive definition
+			n = 0
+		}
+		a := make([]interface{}, n)
+		for i := range a {
+			a[i] = generate(r, s.Elem, depth+1)
+		}
+		return a
+	case "hash":
+		m := make(map[string]interface{})
+		n := generateLength(r, s, 3)
+		for i := 0; len(m) < n && i < n*maxAttempts; i++ {
+			m[fmt.Sprintf("%v", generate(r, s.Key, depth+1))] = generate(r, s.Elem, depth+1)
+		}
+		return m
+	case "object":
+		m := make(map[string]interface{})
+		for _, n := range sortedNames(s.Attributes) {
+			att := s.Attributes[n]
+			if att == nil {
+				continue
+			}
+			if !isRequired(s, n) && (depth >= maxDepth || r.Intn(2) == 0) {
+				continue
+			}
+			m[n] = generate(r, att, depth+1)
+		}
+		return m
+	}
+	return nil
+}
+
+// generateString produces a string that satisfies the format, pattern and length validations of s.
+// It falls back to the last candidate if none of the attempts satisfies all the validations.
+func generateString(r *rand.Rand, s *Schema) string {
+	var candidate string
+	for i := 0; i < maxAttempts; i++ {
+		switch {
+		case s.Format != "":
+			candidate = formatValue(r, s.Format)
+		case s.Pattern != "":
+			candidate = patternValue(r, s.Pattern)
+		default:
+			candidate = randomString(r, generateLength(r, s, 10))
+		}
+		if acceptString(s, candidate) {
+			break
+		}
+	}
+	return candidate
+}
+
+// acceptString returns true if v satisfies the string validations of s.
+func acceptString(s *Schema, v string) bool {
+	if s.Format != "" && goa.ValidateFormat(goa.Format(s.Format), v) != nil {
+		return false
+	}
+	if s.Pattern != "" && !goa.ValidatePattern(s.Pattern, v) {
+		return false
+	}
+	l := len([]rune(v))
+	if s.MinLength != nil && l < *s.MinLength || s.MaxLength != nil && l > *s.MaxLength {
+		return false
+	}
+	return true
+}
+
+// formatValue returns a random value that satisfies the given format.
+func formatValue(r *rand.Rand, format string) string {
+	switch format {
+	case "date":
+		return randomTime(r).Format("2006-01-02")
+	case "date-time":
+		return randomTime(r).Format(time.RFC3339)
+	case "uuid":
+		return randomUUID(r)
+	case "email":
+		return fmt.Sprintf("%s@example.com", randomString(r, 1+r.Intn(10)))
+	case "hostname":
+		return fmt.Sprintf("%s.example.com", strings.ToLower(randomString(r, 1+r.Intn(10))))
+	case "ipv4", "ip":
+		return fmt.Sprintf("%d.%d.%d.%d", r.Intn(256), r.Intn(256), r.Intn(256), r.Intn(256))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x:%x", r.Intn(1<<16), r.Intn(1<<16))
+	case "uri":
+		return fmt.Sprintf("http://example.com/%s", randomString(r, r.Intn(10)))
+	case "mac":
+		return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x",
+			r.Intn(256), r.Intn(256), r.Intn(256), r.Intn(256), r.Intn(256), r.Intn(256))
+	case "cidr":
+		return fmt.Sprintf("10.%d.%d.0/24", r.Intn(256), r.Intn(256))
+	case "regexp":
+		return fmt.Sprintf("^%s+$", randomString(r, 1+r.Intn(5)))
+	case "rfc1123":
+		return randomTime(r).Format(time.RFC1123)
+	}
+	return randomString(r, 1+r.Intn(10))
+}
+
+// patternValue returns a random string that matches the given regular expression.
+func patternValue(r *rand.Rand, pattern string) string {
+	gen, err := regen.NewGenerator(pattern, &regen.GeneratorArgs{
+		RngSource:               rand.NewSource(r.Int63()),
+		Flags:                   syntax.Perl,
+		MaxUnboundedRepeatCount: 10,
+	})
+	if err != nil {
+		return ""
+	}
+	return gen.Generate()
+}
+
+// bounds returns the range of the numbers generated for s.
+func bounds(s *Schema, integer bool) (float64, float64) {
+	const (
+		span  = 1000
+		limit = 1 << 53
+	)
+	lo, hi := -float64(span), float64(span)
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		lo, hi = *s.Minimum, *s.Maximum
+	case s.Minimum != nil:
+		lo, hi = *s.Minimum, *s.Minimum+span
+	case s.Maximum != nil:
+		lo, hi = *s.Maximum-span, *s.Maximum
+	}
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+	}
+	lo, hi = math.Max(lo, -limit), math.Min(hi, limit)
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
+
+// generateLength returns a random length that satisfies the length validations of s.
+func generateLength(r *rand.Rand, s *Schema, span int) int {
+	lo, hi := 0, span
+	if s.MinLength != nil {
+		lo = *s.MinLength
+		if s.MaxLength == nil {
+			hi = lo + span
+		}
+	}
+	if s.MaxLength != nil {
+		hi = *s.MaxLength
+	}
+	if hi < lo {
+		return lo
+	}
+	return lo + r.Intn(hi-lo+1)
+}
+
+// violations calls add once for each single validation rule that can be violated by modifying v.
+// set replaces v in its parent.
+func violations(r *rand.Rand, s *Schema, path string, v interface{}, set func(interface{}), depth int, add func(*violation)) {
+	if s == nil || v == nil || depth > maxDepth {
+		return
+	}
+	violate := func(rule string, value interface{}) {
+		add(&violation{rule: strings.TrimPrefix(path+": "+rule, ": "), apply: func() { set(value) }})
+	}
+	if len(s.Enum) > 0 {
+		if invalid, ok := notInEnum(s); ok {
+			violate("not in enum", invalid)
+		}
+	}
+	if s.Type == "string" {
+		if s.Format != "" {
+			violate("invalid format", "(")
+		}
+		if s.Pattern != "" {
+			for _, c := range []string{"", "!", "(", " "} {
+				if !goa.ValidatePattern(s.Pattern, c) {
+					violate("does not match pattern", c)
+					break
+				}
+			}
+		}
+	}
+	if s.Type == "integer" || s.Type == "number" {
+		integer := s.Type == "integer"
+		if s.Minimum != nil {
+			below := *s.Minimum - 1
+			if integer {
+				below = math.Ceil(*s.Minimum) - 1
+			}
+			if math.Abs(below) < 1e15 {
+				violate("below minimum", number(below, integer))
+			}
+		}
+		if s.Maximum != nil {
+			above := *s.Maximum + 1
+			if integer {
+				above = math.Floor(*s.Maximum) + 1
+			}
+			if math.Abs(above) < 1e15 {
+				violate("above maximum", number(above, integer))
+			}
+		}
+	}
+	if s.MinLength != nil && *s.MinLength > 0 && *s.MinLength <= maxViolationLength {
+		if short, ok := withLength(r, s, v, *s.MinLength-1, depth); ok {
+			violate("too short", short)
+		}
+	}
+	if s.MaxLength != nil && *s.MaxLength < maxViolationLength {
+		if long, ok := withLength(r, s, v, *s.MaxLength+1, depth); ok {
+			violate("too long", long)
+		}
+	}
+	switch s.Type {
+	case "object":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, n := range s.Required {
+			att := s.Attributes[n]
+			if _, present := m[n]; !present || att == nil || !detectable(att) {
+				continue
+			}
+			name := n
+			add(&violation{
+				rule:  strings.TrimPrefix(path+"."+name, ".") + ": missing",
+				apply: func() { delete(m, name) },
+			})
+		}
+		for _, n := range sortedNames(s.Attributes) {
+			e, ok := m[n]
+			if !ok {
+				continue
+			}
+			name := n
+			violations(r, s.Attributes[n], strings.TrimPrefix(path+"."+n, "."), e,
+				func(nv interface{}) { m[name] = nv }, depth+1, add)
+		}
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok || len(a) == 0 {
+			return
+		}
+		violations(r, s.Elem, path+"[0]", a[0], func(nv interface{}) { a[0] = nv }, depth+1, add)
+	}
+}
+
+// detectable returns true if omitting a required attribute described by s is detected by the
+// validations of the generated types. Missing primitive values other than strings decode into zero
+// values.
+func detectable(s *Schema) bool {
+	switch s.Type {
+	case "string", "array", "hash", "object":
+		return true
+	}
+	return false
+}
+
+// notInEnum returns a value of the schema type that is not one of the enum values.
+func notInEnum(s *Schema) (interface{}, bool) {
+	switch s.Type {
+	case "string":
+		candidate := "x"
+		for inEnum(s.Enum, candidate) {
+			candidate += "x"
+		}
+		return candidate, true
+	case "integer", "number":
+		max := math.Inf(-1)
+		for _, v := range s.Enum {
+			f, ok := toFloat(v)
+			if !ok {
+				return nil, false
+			}
+			max = math.Max(max, f)
+		}
+		if math.Abs(max) >= 1e15 {
+			return nil, false
+		}
+		return number(math.Floor(max)+1, s.Type == "integer"), true
+	}
+	return nil, false
+}
+
+// inEnum returns true if v is one of values.
+func inEnum(values []interface{}, v interface{}) bool {
+	for _, e := range values {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// withLength returns a string or an array derived from v with the given length.
+func withLength(r *rand.Rand, s *Schema, v interface{}, l, depth int) (interface{}, bool) {
+	switch s.Type {
+	case "string":
+		return strings.Repeat("a", l), true
+	case "array":
+		a, ok := v.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		res := make([]interface{}, l)
+		for i := range res {
+			if i < len(a) {
+				res[i] = a[i]
+			} else {
+				res[i] = generate(r, s.Elem, depth+1)
+			}
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// number returns f as an int64 if integer is true, as is otherwise.
+func number(f float64, integer bool) interface{} {
+	if integer {
+		return int64(f)
+	}
+	return f
+}
+
+// toFloat converts the numeric enum values to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// isRequired returns true if the object attribute with the given name is required.
+func isRequired(s *Schema, name string) bool {
+	for _, n := range s.Required {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedNames returns the names of the object attributes sorted alphabetically so that the values
+// only depend on the state of the random source.
+func sortedNames(atts map[string]*Schema) []string {
+	names := make([]string, 0, len(atts))
+	for n := range atts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// randomString returns a string of n random letters.
+func randomString(r *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// randomTime returns a random time between 1970 and 2033 with a precision of one second.
+func randomTime(r *rand.Rand) time.Time {
+	return time.Unix(r.Int63n(2e9), 0).UTC()
+}
+
+// randomUUID returns a random version 4 UUID.
+func randomUUID(r *rand.Rand) string {
+	b := make([]byte, 16)
+	r.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
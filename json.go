@@ -0,0 +1,193 @@
+package goa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// hexDigits contains the digits used to escape characters in JSON strings.
+const hexDigits = "0123456789abcdef"
+
+// AppendJSONString appends the JSON encoding of s to b. The encoding is identical to the one
+// produced by encoding/json including the escaping of the HTML characters. The AppendJSON
+// methods generated by goagen for the media types use the AppendJSON functions to encode the
+// responses without reflection.
+func AppendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\b':
+				b = append(b, '\\', 'b')
+			case '\f':
+				b = append(b, '\\', 'f')
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// AppendJSONFloat appends the JSON encoding of f to b using the same format as encoding/json.
+// It returns an error if f is not a number or is infinite.
+func AppendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return b, errors.New("json: unsupported value: " + strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+// AppendJSONTime appends the JSON encoding of t to b, that is t formatted with RFC 3339 and
+// quoted. It returns an error if the year of t is outside of the [0,9999] range.
+func AppendJSONTime(b []byte, t time.Time) ([]byte, error) {
+	if y := t.Year(); y < 0 || y >= 10000 {
+		return b, errors.New("json: time year outside of range [0,9999]")
+	}
+	b = append(b, '"')
+	b = t.AppendFormat(b, time.RFC3339Nano)
+	return append(b, '"'), nil
+}
+
+// AppendJSONValue appends the JSON encoding of v produced by encoding/json to b. The generated
+// AppendJSON methods use it for the values whose encoding cannot be computed at generation time
+// such as the values of attributes of type Any or of user types.
+func AppendJSONValue(b []byte, v interface{}) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return b, err
+	}
+	return append(b, js...), nil
+}
+
+// AppendJSONArray appends the JSON array made of the n elements encoded by elem to b. elem appends
+// the encoding of the element at the given index to the slice it is given. If n is greater than or
+// equal to threshold and threshold is positive the elements are encoded concurrently in chunks by
+// up to GOMAXPROCS goroutines, each in its own buffer, and the buffers are then appended in order.
+// This speeds up the encoding of large collections at the cost of the extra copy. A panic raised by
+// elem in one of the goroutines is returned as an error.
+func AppendJSONArray(b []byte, n, threshold int, elem func(b []byte, i int) ([]byte, error)) ([]byte, error) {
+	workers := runtime.GOMAXPROCS(0)
+	if threshold <= 0 || n < threshold || workers < 2 {
+		b, err := appendJSONElems(append(b, '['), 0, n, elem)
+		if err != nil {
+			return b, err
+		}
+		return append(b, ']'), nil
+	}
+	if workers > n {
+		workers = n
+	}
+	var (
+		size = (n + workers - 1) / workers
+		bufs = make([][]byte, workers)
+		errs = make([]error, workers)
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		start, end := w*size, (w+1)*size
+		if end > n {
+			end = n
+		}
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			defer func() {
+				// The caller cannot recover a panic raised in this goroutine, report it instead.
+				if p := recover(); p != nil {
+					buf := make([]byte, 64<<10)
+					buf = buf[:runtime.Stack(buf, false)]
+					errs[w] = fmt.Errorf("panic: %v\n%s", p, buf)
+				}
+			}()
+			bufs[w], errs[w] = appendJSONElems(nil, start, end, elem)
+		}(w, start, end)
+	}
+	wg.Wait()
+	b = append(b, '[')
+	for w, buf := range bufs {
+		if errs[w] != nil {
+			return b, errs[w]
+		}
+		if buf == nil {
+			continue
+		}
+		if w > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, buf...)
+	}
+	return append(b, ']'), nil
+}
+
+// appendJSONElems appends the comma separated encodings of the elements with index in [start,end)
+// to b.
+func appendJSONElems(b []byte, start, end int, elem func(b []byte, i int) ([]byte, error)) ([]byte, error) {
+	var err error
+	for i := start; i < end; i++ {
+		if i > start {
+			b = append(b, ',')
+		}
+		if b, err = elem(b, i); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,182 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AppendJSON", func() {
+	Context("AppendJSONString", func() {
+		strs := []string{
+			"",
+			"foo",
+			`quote " and backslash \`,
+			"control \b\f\n\r\t\x00\x1f",
+			"<html> & friends",
+			"unicode é 世界 \u2028 \u2029",
+			"invalid \xff utf-8",
+		}
+
+		It("produces the same output as encoding/json", func() {
+			for _, s := range strs {
+				expected, err := json.Marshal(s)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(goa.AppendJSONString(nil, s))).Should(Equal(string(expected)))
+			}
+		})
+
+		It("appends to the given slice", func() {
+			Ω(string(goa.AppendJSONString([]byte("["), "a"))).Should(Equal(`["a"`))
+		})
+	})
+
+	Context("AppendJSONFloat", func() {
+		floats := []float64{0, 1, -1.5, 3.14159, 1e-7, 1e20, 1e21, 123456789e30, -2.5e-10}
+
+		It("produces the same output as encoding/json", func() {
+			for _, f := range floats {
+				expected, err := json.Marshal(f)
+				Ω(err).ShouldNot(HaveOccurred())
+				b, err := goa.AppendJSONFloat(nil, f)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(Equal(string(expected)))
+			}
+		})
+
+		It("rejects invalid numbers", func() {
+			_, err := goa.AppendJSONFloat(nil, math.NaN())
+			Ω(err).Should(HaveOccurred())
+			_, err = goa.AppendJSONFloat(nil, math.Inf(1))
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("AppendJSONTime", func() {
+		It("produces the same output as encoding/json", func() {
+			t := time.Date(2016, 1, 2, 3, 4, 5, 6, time.UTC)
+			expected, err := json.Marshal(t)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := goa.AppendJSONTime(nil, t)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(string(expected)))
+		})
+
+		It("rejects years out of range", func() {
+			_, err := goa.AppendJSONTime(nil, time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("AppendJSONValue", func() {
+		It("uses encoding/json", func() {
+			b, err := goa.AppendJSONValue([]byte(","), map[string]interface{}{"b": 1, "a": []string{"x"}})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(`,{"a":["x"],"b":1}`))
+		})
+	})
+})
+
+var _ = Describe("AppendJSONArray", func() {
+	var n, threshold int
+	var elem func([]byte, int) ([]byte, error)
+	var b []byte
+	var err error
+
+	BeforeEach(func() {
+		elem = func(b []byte, i int) ([]byte, error) {
+			return strconv.AppendInt(b, int64(i), 10), nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		b, err = goa.AppendJSONArray([]byte("x"), n, threshold, elem)
+	})
+
+	expected := func(n int) string {
+		elems := make([]int, n)
+		for i := range elems {
+			elems[i] = i
+		}
+		js, _ := json.Marshal(elems)
+		return "x" + string(js)
+	}
+
+	Context("with an empty array", func() {
+		BeforeEach(func() {
+			n, threshold = 0, 1
+		})
+
+		It("encodes an empty array", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal("x[]"))
+		})
+	})
+
+	Context("below the threshold", func() {
+		BeforeEach(func() {
+			n, threshold = 10, 100
+		})
+
+		It("encodes the elements in order", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(expected(10)))
+		})
+	})
+
+	Context("above the threshold", func() {
+		var procs int
+
+		BeforeEach(func() {
+			n, threshold = 1001, 10
+			procs = runtime.GOMAXPROCS(4)
+		})
+
+		AfterEach(func() {
+			runtime.GOMAXPROCS(procs)
+		})
+
+		It("encodes the elements in order", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(Equal(expected(1001)))
+		})
+
+		Context("with an element that fails to encode", func() {
+			BeforeEach(func() {
+				elem = func(b []byte, i int) ([]byte, error) {
+					if i == 500 {
+						return b, errors.New("boom")
+					}
+					return strconv.AppendInt(b, int64(i), 10), nil
+				}
+			})
+
+			It("returns the error", func() {
+				Ω(err).Should(MatchError("boom"))
+			})
+		})
+
+		Context("with an element that panics", func() {
+			BeforeEach(func() {
+				elem = func(b []byte, i int) ([]byte, error) {
+					if i == 500 {
+						panic("boom")
+					}
+					return strconv.AppendInt(b, int64(i), 10), nil
+				}
+			})
+
+			It("returns the panic as an error", func() {
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(HavePrefix("panic: boom\n"))
+			})
+		})
+	})
+})
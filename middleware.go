@@ -1,8 +1,15 @@
 package goa
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"runtime"
+	"sync"
+	"time"
 
 	"context"
 )
@@ -53,6 +60,243 @@ func NewMiddleware(m interface{}) (mw Middleware, err error) {
 	return
 }
 
+// TimeoutHandler returns a handler that runs h with a context that is canceled after the given
+// timeout. h runs in its own goroutine and writes its response to a buffer, the buffered response
+// is sent once h returns. If the timeout expires first then TimeoutHandler returns an ErrTimeout
+// error right away, which results in a 504 Gateway Timeout response, and the writes made by h
+// afterwards fail with http.ErrHandlerTimeout. The cancellation is also propagated to h through the
+// context so that it may stop processing.
+//
+// Handlers that hijack the connection, flush the response or stream it with io.Copy take over the
+// underlying writer: the response is no longer buffered and TimeoutHandler waits for h to return
+// instead of sending a 504 response once the timeout expires.
+func TimeoutHandler(h Handler, timeout time.Duration) Handler {
+	return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		nctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		tw := &timeoutWriter{w: rw, raw: rw, header: cloneHeader(rw.Header())}
+		resp := &ResponseData{ResponseWriter: tw}
+		outer := ContextResponse(ctx)
+		if outer != nil {
+			resp.Service = outer.Service
+			if rw == http.ResponseWriter(outer) {
+				tw.raw = outer.ResponseWriter
+			}
+		}
+		nctx = context.WithValue(nctx, respKey, resp)
+
+		done := make(chan error, 1)
+		panicked := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					tw.mu.Lock()
+					defer tw.mu.Unlock()
+					if tw.timedOut {
+						// Nobody is waiting for h anymore, do not lose the panic.
+						buf := make([]byte, 64<<10)
+						buf = buf[:runtime.Stack(buf, false)]
+						LogError(nctx, "panic after timeout", "err", fmt.Sprint(p), "stack", string(buf))
+						return
+					}
+					panicked <- p
+				}
+			}()
+			done <- h(nctx, resp, req)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case err := <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			if outer != nil {
+				outer.ErrorCode = resp.ErrorCode
+				outer.Body = resp.Body
+			}
+			if tw.passthrough {
+				// h already wrote its response to rw.
+				return err
+			}
+			if tw.status == 0 && nctx.Err() == context.DeadlineExceeded {
+				// h gave up on the expired context before the timer case was selected.
+				return ErrTimeout("request timed out", "timeout", timeout.String())
+			}
+			w := rw
+			if outer != nil && rw == http.ResponseWriter(outer) {
+				// resp already recorded the status and length, do not record them twice.
+				w = outer.ResponseWriter
+				outer.Status, outer.Length = resp.Status, resp.Length
+			}
+			tw.send(w)
+			return err
+		case <-nctx.Done():
+			tw.mu.Lock()
+			if tw.passthrough {
+				tw.mu.Unlock()
+				// h took over the writer, it is too late to send a timeout response.
+				select {
+				case p := <-panicked:
+					panic(p)
+				case err := <-done:
+					return err
+				}
+			}
+			select {
+			case p := <-panicked:
+				tw.mu.Unlock()
+				panic(p)
+			default:
+			}
+			tw.timedOut = true
+			tw.mu.Unlock()
+			if nctx.Err() != context.DeadlineExceeded {
+				return nctx.Err()
+			}
+			return ErrTimeout("request timed out", "timeout", timeout.String())
+		}
+	}
+}
+
+// timeoutWriter buffers the response written by the handler run by TimeoutHandler. It passes the
+// writes through to the underlying writer once the handler hijacks the connection, flushes or
+// streams the response.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	w           http.ResponseWriter // writer the response is sent to
+	raw         http.ResponseWriter // w or the writer wrapped by w if w is the context response
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	timedOut    bool
+	passthrough bool
+}
+
+// Header returns the response headers.
+func (tw *timeoutWriter) Header() http.Header { return tw.header }
+
+// Write buffers b, it fails with http.ErrHandlerTimeout once the timeout expired.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.passthrough {
+		return tw.w.Write(b)
+	}
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+	return tw.buf.Write(b)
+}
+
+// WriteHeader records the response status code.
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	if tw.passthrough {
+		tw.w.WriteHeader(status)
+		return
+	}
+	if tw.status != 0 {
+		return
+	}
+	tw.status = status
+}
+
+// ReadFrom sends the buffered response then copies src to the underlying writer so that large
+// responses are not buffered in memory.
+func (tw *timeoutWriter) ReadFrom(src io.Reader) (int64, error) {
+	tw.mu.Lock()
+	if tw.timedOut {
+		tw.mu.Unlock()
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.passthrough {
+		if tw.status == 0 {
+			tw.status = http.StatusOK
+		}
+		tw.pass()
+	}
+	tw.mu.Unlock()
+	return io.Copy(tw.w, src)
+}
+
+// Flush sends the buffered response and flushes the underlying writer, see http.Flusher.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.pass()
+	if f, ok := tw.raw.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack lets the handler take over the connection, see http.Hijacker.
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+	hj, ok := tw.raw.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	conn, brw, err := hj.Hijack()
+	if err == nil {
+		tw.passthrough = true
+	}
+	return conn, brw, err
+}
+
+// pass sends the buffered response to the underlying writer and switches to pass-through mode.
+// tw.mu must be held.
+func (tw *timeoutWriter) pass() {
+	if tw.passthrough {
+		return
+	}
+	tw.send(tw.w)
+	tw.header = tw.w.Header()
+	tw.passthrough = true
+}
+
+// send writes the buffered headers, status and body to w. tw.mu must be held.
+func (tw *timeoutWriter) send(w http.ResponseWriter) {
+	dst := w.Header()
+	for k := range dst {
+		delete(dst, k)
+	}
+	for k, vv := range tw.header {
+		dst[k] = vv
+	}
+	if tw.status != 0 {
+		w.WriteHeader(tw.status)
+	}
+	if tw.buf.Len() > 0 {
+		w.Write(tw.buf.Bytes())
+		tw.buf.Reset()
+	}
+}
+
+// cloneHeader returns a copy of h.
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, vv := range h {
+		c[k] = append([]string(nil), vv...)
+	}
+	return c
+}
+
 // handlerToMiddleware creates a middleware from a raw handler.
 // The middleware calls the handler and either breaks the middleware chain if the handler returns
 // an error by also returning the error or calls the next handler in the chain otherwise.
@@ -14,7 +14,11 @@ import (
 // understands instances of goa.ServiceError and returns the status and response body embodied in
 // them, it turns other Go error types into a 500 internal error response.
 // If verbose is false the details of internal errors is not included in HTTP responses.
-// If you use github.com/pkg/errors then wrapping the error will allow a trace to be printed to the logs
+// If you use github.com/pkg/errors then wrapping the error will allow a trace to be printed to the logs.
+//
+// ErrorHandler unwraps errors wrapped with github.com/pkg/errors or fmt.Errorf("%w") to find the
+// goa.ServiceError that describes the response. The wrapped error is logged together with the
+// ID of the service error so that the response can be correlated with the logs.
 func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
@@ -30,13 +34,19 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 				respBody = err
 				goa.ContextResponse(ctx).ErrorCode = err.Token()
 				rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+				if cause != e && status != http.StatusInternalServerError {
+					// Log the wrapping error so its context is not lost
+					goa.LogInfo(ctx, "wrapped error", "err", fmt.Sprintf("%+v", e), "id", err.Token())
+				}
 			} else {
 				respBody = e.Error()
 				rw.Header().Set("Content-Type", "text/plain")
 			}
 			if status == http.StatusInternalServerError {
-				reqID := ctx.Value(reqIDKey)
-				if reqID == nil {
+				var reqID interface{}
+				if errID := goa.ContextResponse(ctx).ErrorCode; errID != "" {
+					reqID = errID
+				} else if reqID = ctx.Value(reqIDKey); reqID == nil {
 					reqID = shortID()
 					ctx = context.WithValue(ctx, reqIDKey, reqID)
 				}
@@ -58,26 +68,38 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 }
 
 // Cause returns the underlying cause of the error, if possible.
-// An error value has a cause if it implements the following
-// interface:
+// An error value has a cause if it implements one of the following
+// interfaces:
 //
 //     type causer interface {
 //            Cause() error
 //     }
 //
-// If the error does not implement Cause, the original error will
-// be returned. If the error is nil, nil will be returned without further
-// investigation.
+//     type wrapper interface {
+//            Unwrap() error
+//     }
+//
+// The first error in the chain that implements goa.ServiceError is
+// returned. If there is none then the innermost error is returned. If
+// the error is nil, nil will be returned without further investigation.
 func cause(e error) error {
 	type causer interface {
 		Cause() error
 	}
+	type wrapper interface {
+		Unwrap() error
+	}
 	for {
-		cause, ok := e.(causer)
-		if !ok {
+		if _, ok := e.(goa.ServiceError); ok {
 			break
 		}
-		c := cause.Cause()
+		var c error
+		switch actual := e.(type) {
+		case causer:
+			c = actual.Cause()
+		case wrapper:
+			c = actual.Unwrap()
+		}
 		if c == nil {
 			break
 		}
@@ -194,4 +194,33 @@ var _ = Describe("ErrorHandler", func() {
 			Ω(data).Should(ContainSubstring("error_handler_test.go"))
 		})
 	})
+
+	Context("with a handler returning a fmt wrapped error", func() {
+		var gerr error
+		var logger *testLogger
+
+		BeforeEach(func() {
+			logger = new(testLogger)
+			service = newService(logger)
+			gerr = goa.NewErrorClass("code", 418)("teapot")
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return fmt.Errorf("brewing: %w", gerr)
+			}
+		})
+
+		It("maps the wrapped goa error to the HTTP response", func() {
+			var decoded errorResponse
+			Ω(rw.Status).Should(Equal(418))
+			Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ErrorMediaIdentifier}))
+			err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(decoded.ID).Should(Equal(gerr.(goa.ServiceError).Token()))
+		})
+
+		It("logs the wrapping error with the error ID", func() {
+			Ω(logger.InfoEntries).Should(HaveLen(1))
+			Ω(logger.InfoEntries[0].Data[1]).Should(ContainSubstring("brewing: "))
+			Ω(logger.InfoEntries[0].Data[3]).Should(Equal(gerr.(goa.ServiceError).Token()))
+		})
+	})
 })
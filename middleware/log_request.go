@@ -19,7 +19,8 @@ import (
 // LogRequest creates a request logger middleware.
 // This middleware is aware of the RequestID middleware and if registered after it leverages the
 // request ID for logging.
-// If verbose is true then the middlware logs the request and response bodies.
+// If verbose is true then the middlware logs the request and response bodies, the values of the
+// payload attributes marked as sensitive in the design are hidden.
 func LogRequest(verbose bool, sensitiveHeaders ...string) goa.Middleware {
 	var suppressed map[string]struct{}
 	if len(sensitiveHeaders) > 0 {
@@ -85,7 +86,8 @@ func LogRequest(verbose bool, sensitiveHeaders ...string) goa.Middleware {
 						goa.LogInfo(ctx, "payload", logCtx...)
 					} else {
 						// Not the most efficient but this is used for debugging
-						js, err := json.Marshal(r.Payload)
+						payload, _ := goa.Redact(r.Payload)
+						js, err := json.Marshal(payload)
 						if err != nil {
 							js = []byte("<invalid JSON>")
 						}
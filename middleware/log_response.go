@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/goadesign/goa"
@@ -13,11 +14,24 @@ import (
 // are logged elsewhere (i.e. by the LogRequest middleware).
 type loggingResponseWriter struct {
 	http.ResponseWriter
-	ctx context.Context
+	ctx      context.Context
+	redacted bool
 }
 
-// Write will write raw data to logger and response writer.
+// Write will write raw data to logger and response writer. The response body is logged as JSON
+// with the sensitive values hidden instead if the value sent by the service contains any.
 func (lrw *loggingResponseWriter) Write(buf []byte) (int, error) {
+	if body, ok := goa.Redact(goa.ContextResponse(lrw.ctx).Body); ok {
+		if !lrw.redacted {
+			js, err := json.Marshal(body)
+			if err != nil {
+				js = []byte("<invalid JSON>")
+			}
+			goa.LogInfo(lrw.ctx, "response", "body", string(js))
+			lrw.redacted = true
+		}
+		return lrw.ResponseWriter.Write(buf)
+	}
 	goa.LogInfo(lrw.ctx, "response", "body", string(buf))
 	return lrw.ResponseWriter.Write(buf)
 }
@@ -1,8 +1,12 @@
 package goa_test
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"time"
 
 	"context"
 
@@ -152,3 +156,198 @@ var _ = Describe("NewMiddleware", func() {
 
 	})
 })
+
+var _ = Describe("TimeoutHandler", func() {
+	var handler goa.Handler
+	var timeout time.Duration
+	var ctx context.Context
+	var rw http.ResponseWriter
+	var hErr error
+	var service *goa.Service
+	var req *http.Request
+
+	BeforeEach(func() {
+		service = goa.New("test")
+		ctrl := service.NewController("foo")
+		var err error
+		req, err = http.NewRequest("GET", "/goo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		rw = new(TestResponseWriter)
+		ctx = goa.NewContext(ctrl.Context, rw, req, nil)
+		timeout = 10 * time.Millisecond
+	})
+
+	JustBeforeEach(func() {
+		hErr = goa.TimeoutHandler(handler, timeout)(ctx, rw, nil)
+	})
+
+	Context("with a handler that completes in time", func() {
+		BeforeEach(func() {
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				_, ok := ctx.Deadline()
+				Ω(ok).Should(BeTrue())
+				return nil
+			}
+		})
+
+		It("sets a deadline and returns the handler result", func() {
+			Ω(hErr).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a handler that exceeds the timeout", func() {
+		BeforeEach(func() {
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+		})
+
+		It("returns a timeout error", func() {
+			Ω(hErr).Should(HaveOccurred())
+			se, ok := hErr.(goa.ServiceError)
+			Ω(ok).Should(BeTrue())
+			Ω(se.ResponseStatus()).Should(Equal(http.StatusGatewayTimeout))
+		})
+	})
+
+	Context("with a handler that ignores the context", func() {
+		var handlerDone chan struct{}
+
+		BeforeEach(func() {
+			handlerDone = make(chan struct{})
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				defer close(handlerDone)
+				time.Sleep(10 * timeout)
+				rw.WriteHeader(http.StatusOK)
+				rw.Write([]byte("late"))
+				return nil
+			}
+		})
+
+		It("returns a timeout error without waiting for the handler", func() {
+			Ω(handlerDone).ShouldNot(BeClosed())
+			Ω(hErr).Should(HaveOccurred())
+			se, ok := hErr.(goa.ServiceError)
+			Ω(ok).Should(BeTrue())
+			Ω(se.ResponseStatus()).Should(Equal(http.StatusGatewayTimeout))
+
+			Eventually(handlerDone).Should(BeClosed())
+			Ω(rw.(*TestResponseWriter).Status).Should(Equal(0))
+			Ω(rw.(*TestResponseWriter).Body).Should(BeEmpty())
+		})
+	})
+
+	Context("with a handler that writes a response", func() {
+		BeforeEach(func() {
+			rw.(*TestResponseWriter).ParentHeader = http.Header{}
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				rw.Header().Set("Content-Type", "text/plain")
+				rw.WriteHeader(http.StatusCreated)
+				rw.Write([]byte("ok"))
+				return nil
+			}
+		})
+
+		It("sends the buffered response", func() {
+			Ω(hErr).ShouldNot(HaveOccurred())
+			Ω(rw.Header().Get("Content-Type")).Should(Equal("text/plain"))
+			Ω(rw.(*TestResponseWriter).Status).Should(Equal(http.StatusCreated))
+			Ω(string(rw.(*TestResponseWriter).Body)).Should(Equal("ok"))
+		})
+	})
+
+	Context("with a handler that panics after the timeout", func() {
+		var logger *errorLogger
+
+		BeforeEach(func() {
+			logger = &errorLogger{msgs: make(chan string, 1)}
+			service.WithLogger(logger)
+			ctx = goa.NewContext(service.Context, rw, req, nil)
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				time.Sleep(10 * timeout)
+				panic("boom")
+			}
+		})
+
+		It("logs the panic", func() {
+			Ω(hErr).Should(HaveOccurred())
+			Eventually(logger.msgs).Should(Receive(Equal("panic after timeout")))
+		})
+	})
+
+	Context("with a handler that flushes the response", func() {
+		var rec *httptest.ResponseRecorder
+
+		BeforeEach(func() {
+			rec = httptest.NewRecorder()
+			rw = rec
+			ctx = goa.NewContext(service.Context, rw, req, nil)
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				rw.Write([]byte("a"))
+				goa.ContextResponse(ctx).ResponseWriter.(http.Flusher).Flush()
+				Ω(rec.Flushed).Should(BeTrue())
+				Ω(rec.Body.String()).Should(Equal("a"))
+				time.Sleep(2 * timeout)
+				rw.Write([]byte("b"))
+				return nil
+			}
+		})
+
+		It("streams the response past the timeout", func() {
+			Ω(hErr).ShouldNot(HaveOccurred())
+			Ω(rec.Code).Should(Equal(http.StatusOK))
+			Ω(rec.Body.String()).Should(Equal("ab"))
+		})
+	})
+
+	Context("with a handler that hijacks the connection", func() {
+		var hw *hijackWriter
+
+		BeforeEach(func() {
+			hw = &hijackWriter{TestResponseWriter: &TestResponseWriter{ParentHeader: http.Header{}}}
+			rw = hw
+			ctx = goa.NewContext(service.Context, rw, req, nil)
+			handler = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				_, _, err := goa.ContextResponse(ctx).ResponseWriter.(http.Hijacker).Hijack()
+				if err != nil {
+					return err
+				}
+				time.Sleep(2 * timeout)
+				return nil
+			}
+		})
+
+		It("hands over the connection", func() {
+			Ω(hErr).ShouldNot(HaveOccurred())
+			Ω(hw.hijacked).Should(BeTrue())
+			Ω(hw.Status).Should(Equal(0))
+		})
+	})
+})
+
+// errorLogger records the messages logged at the error level.
+type errorLogger struct {
+	msgs chan string
+}
+
+func (l *errorLogger) Info(msg string, keyvals ...interface{}) {}
+
+func (l *errorLogger) Error(msg string, keyvals ...interface{}) {
+	l.msgs <- msg
+}
+
+func (l *errorLogger) New(keyvals ...interface{}) goa.LogAdapter {
+	return l
+}
+
+// hijackWriter is a response writer that supports hijacking.
+type hijackWriter struct {
+	*TestResponseWriter
+	hijacked bool
+}
+
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
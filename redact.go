@@ -0,0 +1,133 @@
+package goa
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue is the value that replaces sensitive values in redacted data.
+const RedactedValue = "<hidden>"
+
+// Redact returns a copy of v suitable for logging where the values of the struct fields tagged
+// with `sensitive:"true"` are replaced with RedactedValue. goagen sets the tag on the fields of
+// the attributes that define the "sensitive" metadata. Structs are copied into maps indexed by
+// the field JSON names. Redact returns v and false if v does not contain any sensitive field.
+func Redact(v interface{}) (interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	res, redacted := redact(reflect.ValueOf(v))
+	if !redacted {
+		return v, false
+	}
+	return res, true
+}
+
+// redact returns the redacted copy of v and whether any value was hidden.
+func redact(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return redact(v.Elem())
+	case reflect.Struct:
+		res := make(map[string]interface{}, v.NumField())
+		var redacted bool
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue // unexported
+			}
+			name, omitEmpty := jsonFieldName(f)
+			if name == "-" {
+				continue
+			}
+			fv := v.Field(i)
+			if omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			if f.Tag.Get("sensitive") == "true" {
+				res[name] = RedactedValue
+				redacted = true
+				continue
+			}
+			val, r := redact(fv)
+			res[name] = val
+			redacted = redacted || r
+		}
+		return res, redacted
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, false
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface(), false
+		}
+		res := make([]interface{}, v.Len())
+		var redacted bool
+		for i := 0; i < v.Len(); i++ {
+			val, r := redact(v.Index(i))
+			res[i] = val
+			redacted = redacted || r
+		}
+		return res, redacted
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, false
+		}
+		res := make(map[string]interface{}, v.Len())
+		var redacted bool
+		for _, k := range v.MapKeys() {
+			val, r := redact(v.MapIndex(k))
+			res[fmt.Sprintf("%v", k.Interface())] = val
+			redacted = redacted || r
+		}
+		return res, redacted
+	case reflect.Invalid:
+		return nil, false
+	default:
+		return v.Interface(), false
+	}
+}
+
+// jsonFieldName returns the name of the field in the JSON representation of its struct and
+// whether the field is omitted when empty.
+func jsonFieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+	elems := strings.Split(tag, ",")
+	name := elems[0]
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range elems[1:] {
+		if opt == "omitempty" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+// isEmptyValue mirrors the encoding/json definition of empty values.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
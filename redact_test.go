@@ -0,0 +1,39 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type redactCredentials struct {
+	User     string  `json:"user"`
+	Password *string `json:"password,omitempty" sensitive:"true"`
+}
+
+type redactPayload struct {
+	Name        string               `json:"name"`
+	Credentials []*redactCredentials `json:"credentials,omitempty"`
+}
+
+var _ = Describe("Redact", func() {
+	It("hides the sensitive values", func() {
+		pass := "secret"
+		p := &redactPayload{Name: "n", Credentials: []*redactCredentials{{User: "u", Password: &pass}}}
+		v, ok := goa.Redact(p)
+		Ω(ok).Should(BeTrue())
+		Ω(v).Should(Equal(map[string]interface{}{
+			"name": "n",
+			"credentials": []interface{}{
+				map[string]interface{}{"user": "u", "password": goa.RedactedValue},
+			},
+		}))
+	})
+
+	It("returns values with no sensitive field unchanged", func() {
+		p := &redactPayload{Name: "n"}
+		v, ok := goa.Redact(p)
+		Ω(ok).Should(BeFalse())
+		Ω(v).Should(BeIdenticalTo(p))
+	})
+})
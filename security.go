@@ -1,6 +1,11 @@
 package goa
 
-import "context"
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"strings"
+)
 
 // Location is the enum defining where the value of key based security schemes should be read:
 // either a HTTP request header or a URL querystring value
@@ -27,6 +32,29 @@ func WithRequiredScopes(ctx context.Context, scopes []string) context.Context {
 	return context.WithValue(ctx, securityScopesKey, scopes)
 }
 
+// MutualTLSSecurity represents the mutual TLS security scheme where clients authenticate using
+// TLS client certificates verified by the server.
+type MutualTLSSecurity struct {
+	// Description of the security scheme
+	Description string
+}
+
+// ContextGrantedScopes extracts the scopes granted to the request credentials from the given
+// context.
+func ContextGrantedScopes(ctx context.Context) []string {
+	if s := ctx.Value(grantedScopesKey); s != nil {
+		return s.([]string)
+	}
+	return nil
+}
+
+// WithGrantedScopes builds a context containing the scopes granted to the request credentials.
+// The auth functions given to NewJWTMiddleware and NewOAuth2Middleware use it to record the scopes
+// listed in the token claims so that the middleware may compare them with the required scopes.
+func WithGrantedScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, grantedScopesKey, scopes)
+}
+
 // OAuth2Security represents the `oauth2` security scheme. It is instantiated by the generated code
 // accordingly to the use of the different `*Security()` DSL functions and `Security()` in the
 // design.
@@ -75,3 +103,193 @@ type JWTSecurity struct {
 	// Scopes defines a list of scopes for the security scheme, along with their description.
 	Scopes map[string]string
 }
+
+// JWTAuthFunc is the function implemented by the user to authenticate requests using a JWT security
+// scheme. It is called with the token extracted from the request and returns the context used to
+// run the action, typically augmented with the token claims.
+type JWTAuthFunc func(ctx context.Context, token string, scheme *JWTSecurity) (context.Context, error)
+
+// NewJWTMiddleware creates a middleware that extracts the JWT from the request header or querystring
+// as described by scheme and authenticates it with auth. The "Bearer" prefix is stripped from header
+// values. Requests that do not contain a token are rejected with ErrUnauthorized, errors returned by
+// auth that are not ServiceError are also mapped to ErrUnauthorized. The scopes granted by auth via
+// WithGrantedScopes must include all the scopes required by the action, ErrInsufficientScope is
+// returned otherwise.
+func NewJWTMiddleware(scheme *JWTSecurity, auth JWTAuthFunc) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			token := extractSecret(req, scheme.In, scheme.Name, "Bearer ")
+			if token == "" {
+				return ErrUnauthorized("missing token")
+			}
+			ctx, err := auth(ctx, token, scheme)
+			if err != nil {
+				return authError(err)
+			}
+			if err := checkScopes(ctx); err != nil {
+				return err
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// OAuth2AuthFunc is the function implemented by the user to validate the access tokens of requests
+// using an OAuth2 security scheme. It is called with the access token extracted from the request and
+// returns the context used to run the action, typically augmented with the token information.
+type OAuth2AuthFunc func(ctx context.Context, token string, scheme *OAuth2Security) (context.Context, error)
+
+// NewOAuth2Middleware creates a middleware that extracts the bearer access token from the request
+// "Authorization" header or "access_token" querystring value (RFC 6750) and validates it with auth.
+// Requests that do not contain a token are rejected with ErrUnauthorized, errors returned by auth
+// that are not ServiceError are also mapped to ErrUnauthorized. The scopes granted by auth via
+// WithGrantedScopes must include all the scopes required by the action, ErrInsufficientScope is
+// returned otherwise.
+func NewOAuth2Middleware(scheme *OAuth2Security, auth OAuth2AuthFunc) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			token := extractSecret(req, LocHeader, "Authorization", "Bearer ")
+			if token == "" {
+				token = extractSecret(req, LocQuery, "access_token", "")
+			}
+			if token == "" {
+				return ErrUnauthorized("missing access token")
+			}
+			ctx, err := auth(ctx, token, scheme)
+			if err != nil {
+				return authError(err)
+			}
+			if err := checkScopes(ctx); err != nil {
+				return err
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// APIKeyAuthFunc is the function implemented by the user to validate the keys of requests using an
+// API key security scheme. It is called with the key extracted from the request and returns the
+// context used to run the action.
+type APIKeyAuthFunc func(ctx context.Context, key string, scheme *APIKeySecurity) (context.Context, error)
+
+// NewAPIKeyMiddleware creates a middleware that extracts the API key from the request header or
+// querystring as described by scheme and validates it with auth. Requests that do not contain a key
+// are rejected with ErrUnauthorized, errors returned by auth that are not ServiceError are also
+// mapped to ErrUnauthorized.
+func NewAPIKeyMiddleware(scheme *APIKeySecurity, auth APIKeyAuthFunc) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			key := extractSecret(req, scheme.In, scheme.Name, "")
+			if key == "" {
+				return ErrUnauthorized("missing API key")
+			}
+			ctx, err := auth(ctx, key, scheme)
+			if err != nil {
+				return authError(err)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// BasicAuthFunc is the function implemented by the user to validate the credentials of requests
+// using a basic auth security scheme. It is called with the username and password read from the
+// request and returns the context used to run the action.
+type BasicAuthFunc func(ctx context.Context, user, pass string, scheme *BasicAuthSecurity) (context.Context, error)
+
+// NewBasicAuthMiddleware creates a middleware that reads the username and password from the request
+// "Authorization" header and validates them with auth. Requests that do not contain credentials are
+// rejected with ErrUnauthorized, errors returned by auth that are not ServiceError are also mapped
+// to ErrUnauthorized. The "WWW-Authenticate" header is set on the responses to unauthorized requests.
+func NewBasicAuthMiddleware(scheme *BasicAuthSecurity, auth BasicAuthFunc) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			user, pass, ok := req.BasicAuth()
+			if !ok {
+				rw.Header().Set("WWW-Authenticate", "Basic")
+				return ErrUnauthorized("missing credentials")
+			}
+			ctx, err := auth(ctx, user, pass, scheme)
+			if err != nil {
+				rw.Header().Set("WWW-Authenticate", "Basic")
+				return authError(err)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// MutualTLSAuthFunc is the function implemented by the user to authorize requests using a mutual
+// TLS security scheme. It is called with the verified client certificate and returns the context
+// used to run the action. The certificate common name and subject alternative names are available
+// via cert.Subject.CommonName, cert.DNSNames, cert.EmailAddresses etc.
+type MutualTLSAuthFunc func(ctx context.Context, cert *x509.Certificate, scheme *MutualTLSSecurity) (context.Context, error)
+
+// NewMutualTLSMiddleware creates a middleware that calls auth with the client certificate verified
+// during the TLS handshake. The server TLS configuration must verify the client certificates, for
+// example by setting ClientAuth to tls.RequireAndVerifyClientCert. Requests made without a verified
+// client certificate are rejected with ErrUnauthorized, errors returned by auth that are not
+// ServiceError are also mapped to ErrUnauthorized.
+func NewMutualTLSMiddleware(scheme *MutualTLSSecurity, auth MutualTLSAuthFunc) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+				return ErrUnauthorized("missing verified client certificate")
+			}
+			ctx, err := auth(ctx, req.TLS.VerifiedChains[0][0], scheme)
+			if err != nil {
+				return authError(err)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// checkScopes returns ErrInsufficientScope if the scopes granted to the request credentials do not
+// include all the scopes required by the action.
+func checkScopes(ctx context.Context) error {
+	required := ContextRequiredScopes(ctx)
+	if len(required) == 0 {
+		return nil
+	}
+	granted := make(map[string]bool)
+	for _, s := range ContextGrantedScopes(ctx) {
+		granted[s] = true
+	}
+	var missing []string
+	for _, s := range required {
+		if !granted[s] {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return ErrInsufficientScope("missing required scopes", "required", required, "missing", missing)
+	}
+	return nil
+}
+
+// extractSecret returns the value of the request header or querystring value with the given name.
+// prefix is stripped from header values if present, the comparison is case insensitive. The
+// "Authorization" header is used if name is empty.
+func extractSecret(req *http.Request, in Location, name, prefix string) string {
+	if in == LocQuery {
+		return req.URL.Query().Get(name)
+	}
+	if name == "" {
+		name = "Authorization"
+	}
+	val := req.Header.Get(name)
+	if prefix != "" && len(val) > len(prefix) && strings.EqualFold(val[:len(prefix)], prefix) {
+		val = val[len(prefix):]
+	}
+	return val
+}
+
+// authError maps the errors returned by the user auth functions to ErrUnauthorized unless they are
+// already service errors.
+func authError(err error) error {
+	if _, ok := err.(ServiceError); ok {
+		return err
+	}
+	return ErrUnauthorized(err)
+}
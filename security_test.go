@@ -0,0 +1,328 @@
+package goa_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type authKey struct{}
+
+var _ = Describe("NewJWTMiddleware", func() {
+	var scheme *goa.JWTSecurity
+	var auth goa.JWTAuthFunc
+	var req *http.Request
+	var token string
+	var handled bool
+	var err error
+
+	BeforeEach(func() {
+		scheme = &goa.JWTSecurity{In: goa.LocHeader, Name: "Authorization"}
+		token = ""
+		handled = false
+		auth = func(ctx context.Context, t string, s *goa.JWTSecurity) (context.Context, error) {
+			token = t
+			return context.WithValue(ctx, authKey{}, t), nil
+		}
+		req, _ = http.NewRequest("GET", "/", nil)
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			handled = true
+			Ω(ctx.Value(authKey{})).Should(Equal(token))
+			return nil
+		}
+		err = goa.NewJWTMiddleware(scheme, auth)(h)(context.Background(), httptest.NewRecorder(), req)
+	})
+
+	Context("with a bearer token in the header", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer foo")
+		})
+
+		It("authenticates the token", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(token).Should(Equal("foo"))
+			Ω(handled).Should(BeTrue())
+		})
+	})
+
+	Context("with a token in the querystring", func() {
+		BeforeEach(func() {
+			scheme = &goa.JWTSecurity{In: goa.LocQuery, Name: "token"}
+			req, _ = http.NewRequest("GET", "/?token=foo", nil)
+		})
+
+		It("authenticates the token", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(token).Should(Equal("foo"))
+		})
+	})
+
+	Context("with no token", func() {
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+			Ω(handled).Should(BeFalse())
+		})
+	})
+
+	Context("with required scopes", func() {
+		var granted []string
+
+		BeforeEach(func() {
+			granted = nil
+			req.Header.Set("Authorization", "Bearer foo")
+			auth = func(ctx context.Context, t string, s *goa.JWTSecurity) (context.Context, error) {
+				token = t
+				ctx = context.WithValue(ctx, authKey{}, t)
+				return goa.WithGrantedScopes(ctx, granted), nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			handled = false
+			h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				handled = true
+				return nil
+			}
+			ctx := goa.WithRequiredScopes(context.Background(), []string{"read", "write"})
+			err = goa.NewJWTMiddleware(scheme, auth)(h)(ctx, httptest.NewRecorder(), req)
+		})
+
+		Context("granted by the token", func() {
+			BeforeEach(func() {
+				granted = []string{"write", "read", "admin"}
+			})
+
+			It("runs the handler", func() {
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(handled).Should(BeTrue())
+			})
+		})
+
+		Context("not granted by the token", func() {
+			BeforeEach(func() {
+				granted = []string{"read"}
+			})
+
+			It("returns a 403", func() {
+				Ω(err).Should(HaveOccurred())
+				Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(403))
+				Ω(handled).Should(BeFalse())
+			})
+		})
+	})
+
+	Context("with an auth function returning an error", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer foo")
+			auth = func(ctx context.Context, t string, s *goa.JWTSecurity) (context.Context, error) {
+				return nil, errors.New("invalid token")
+			}
+		})
+
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+			Ω(handled).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("NewOAuth2Middleware", func() {
+	var req *http.Request
+	var token string
+	var err error
+
+	BeforeEach(func() {
+		token = ""
+		req, _ = http.NewRequest("GET", "/", nil)
+	})
+
+	JustBeforeEach(func() {
+		auth := func(ctx context.Context, t string, s *goa.OAuth2Security) (context.Context, error) {
+			token = t
+			return ctx, nil
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error { return nil }
+		err = goa.NewOAuth2Middleware(&goa.OAuth2Security{}, auth)(h)(context.Background(), httptest.NewRecorder(), req)
+	})
+
+	Context("with a bearer token in the header", func() {
+		BeforeEach(func() {
+			req.Header.Set("Authorization", "Bearer foo")
+		})
+
+		It("validates the token", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(token).Should(Equal("foo"))
+		})
+	})
+
+	Context("with a token in the querystring", func() {
+		BeforeEach(func() {
+			req, _ = http.NewRequest("GET", "/?access_token=foo", nil)
+		})
+
+		It("validates the token", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(token).Should(Equal("foo"))
+		})
+	})
+
+	Context("with no token", func() {
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+})
+
+var _ = Describe("NewAPIKeyMiddleware", func() {
+	var scheme *goa.APIKeySecurity
+	var req *http.Request
+	var key string
+	var err error
+
+	BeforeEach(func() {
+		key = ""
+		scheme = &goa.APIKeySecurity{In: goa.LocHeader, Name: "X-API-Key"}
+		req, _ = http.NewRequest("GET", "/", nil)
+	})
+
+	JustBeforeEach(func() {
+		auth := func(ctx context.Context, k string, s *goa.APIKeySecurity) (context.Context, error) {
+			key = k
+			return ctx, nil
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error { return nil }
+		err = goa.NewAPIKeyMiddleware(scheme, auth)(h)(context.Background(), httptest.NewRecorder(), req)
+	})
+
+	Context("with a key in the header", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Key", "foo")
+		})
+
+		It("validates the key", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(key).Should(Equal("foo"))
+		})
+	})
+
+	Context("with a key in the querystring", func() {
+		BeforeEach(func() {
+			scheme = &goa.APIKeySecurity{In: goa.LocQuery, Name: "key"}
+			req, _ = http.NewRequest("GET", "/?key=foo", nil)
+		})
+
+		It("validates the key", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(key).Should(Equal("foo"))
+		})
+	})
+
+	Context("with no key", func() {
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+})
+
+var _ = Describe("NewBasicAuthMiddleware", func() {
+	var req *http.Request
+	var rw *httptest.ResponseRecorder
+	var user, pass string
+	var err error
+
+	BeforeEach(func() {
+		user, pass = "", ""
+		req, _ = http.NewRequest("GET", "/", nil)
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		auth := func(ctx context.Context, u, p string, s *goa.BasicAuthSecurity) (context.Context, error) {
+			user, pass = u, p
+			return ctx, nil
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error { return nil }
+		err = goa.NewBasicAuthMiddleware(&goa.BasicAuthSecurity{}, auth)(h)(context.Background(), rw, req)
+	})
+
+	Context("with credentials", func() {
+		BeforeEach(func() {
+			req.SetBasicAuth("user", "pass")
+		})
+
+		It("validates the credentials", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(user).Should(Equal("user"))
+			Ω(pass).Should(Equal("pass"))
+		})
+	})
+
+	Context("with no credentials", func() {
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+			Ω(rw.Header().Get("WWW-Authenticate")).Should(Equal("Basic"))
+		})
+	})
+})
+
+var _ = Describe("NewMutualTLSMiddleware", func() {
+	var req *http.Request
+	var cert *x509.Certificate
+	var err error
+
+	BeforeEach(func() {
+		cert = nil
+		req, _ = http.NewRequest("GET", "/", nil)
+	})
+
+	JustBeforeEach(func() {
+		auth := func(ctx context.Context, c *x509.Certificate, s *goa.MutualTLSSecurity) (context.Context, error) {
+			cert = c
+			return ctx, nil
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error { return nil }
+		err = goa.NewMutualTLSMiddleware(&goa.MutualTLSSecurity{}, auth)(h)(context.Background(), httptest.NewRecorder(), req)
+	})
+
+	Context("with a verified client certificate", func() {
+		var clientCert *x509.Certificate
+
+		BeforeEach(func() {
+			clientCert = &x509.Certificate{Subject: pkix.Name{CommonName: "client"}}
+			req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}}
+		})
+
+		It("authorizes the certificate", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(cert).Should(Equal(clientCert))
+		})
+	})
+
+	Context("with no client certificate", func() {
+		BeforeEach(func() {
+			req.TLS = &tls.ConnectionState{}
+		})
+
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+})
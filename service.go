@@ -2,9 +2,11 @@ package goa
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
@@ -39,6 +41,10 @@ type (
 		Decoder *HTTPDecoder
 		// Response body encoder
 		Encoder *HTTPEncoder
+		// SkipValidation disables the validation of request payloads performed by the
+		// generated code once decoded. Decoding is unaffected. This should only be set for
+		// trusted deployments where the cost of validation matters.
+		SkipValidation bool
 
 		middleware []Middleware       // Middleware chain
 		cancel     context.CancelFunc // Service context cancel signal trigger
@@ -231,13 +237,21 @@ func (service *Service) NewController(name string) *Controller {
 }
 
 // Send serializes the given body matching the request Accept header against the service
-// encoders. It uses the default service encoder if no match is found.
+// encoders. It uses the default service encoder if no match is found. The response Content-Type
+// header is set to the negotiated content type unless it already denotes the same encoding.
 func (service *Service) Send(ctx context.Context, code int, body interface{}) error {
 	r := ContextResponse(ctx)
 	if r == nil {
 		return fmt.Errorf("no response data in context")
 	}
+	if req := ContextRequest(ctx); req != nil {
+		ct := service.Encoder.Negotiate(req.Header.Get("Accept"))
+		if ct != "" && !sameEncoding(r.Header().Get("Content-Type"), ct) {
+			r.Header().Set("Content-Type", ct)
+		}
+	}
 	r.WriteHeader(code)
+	r.Body = body
 	return service.EncodeResponse(ctx, body)
 }
 
@@ -254,17 +268,79 @@ func (service *Service) DecodeRequest(req *http.Request, v interface{}) error {
 	defer body.Close()
 
 	if err := service.Decoder.Decode(v, body, contentType); err != nil {
+		DecodeFailures.Add(1)
 		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
 	}
 
 	return nil
 }
 
+// DecodeRequestArray decodes a request body consisting of a JSON array one element at a time so
+// that the raw body of large arrays is never held in memory in full. It calls elem for each element
+// of the array with a function that decodes the element into the provided value. elem may stop the
+// decoding early by returning an error, e.g. when the element fails validation or when the array
+// contains too many elements. Request bodies with no Content-Type or whose Content-Type is not JSON
+// are decoded in full into v using the HTTP decoder, elem is not called in this case.
+func (service *Service) DecodeRequestArray(req *http.Request, v interface{}, elem func(decode func(interface{}) error) error) error {
+	body, contentType := req.Body, req.Header.Get("Content-Type")
+	if !isJSON(contentType) {
+		return service.DecodeRequest(req, v)
+	}
+	defer body.Close()
+
+	fail := func(err error) error {
+		DecodeFailures.Add(1)
+		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
+	}
+	dec := json.NewDecoder(body)
+	tok, err := dec.Token()
+	if err != nil {
+		return fail(err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fail(fmt.Errorf("expected JSON array, got %v", tok))
+	}
+	decode := func(v interface{}) error {
+		if err := dec.Decode(v); err != nil {
+			return fail(err)
+		}
+		return nil
+	}
+	for dec.More() {
+		if err := elem(decode); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+// isJSON returns true if the request Content-Type header value contentType denotes a JSON body.
+// Request bodies with no Content-Type are left to the default decoder.
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	return contentType == "application/json" || suffixType(contentType) == "application/json"
+}
+
 // EncodeResponse uses the HTTP encoder to marshal and write the response body based on the request
 // Accept header.
 func (service *Service) EncodeResponse(ctx context.Context, v interface{}) error {
 	accept := ContextRequest(ctx).Header.Get("Accept")
-	return service.Encoder.Encode(v, ContextResponse(ctx), accept)
+	err := service.Encoder.Encode(v, ContextResponse(ctx), accept)
+	if err != nil {
+		EncodeFailures.Add(1)
+	}
+	return err
 }
 
 // ServeFiles replies to the request with the contents of the named file or directory. See
@@ -333,6 +409,9 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 					msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
 					err = ErrRequestBodyTooLarge(msg)
 				} else {
+					if isValidationError(err) {
+						ValidationFailures.Add(1)
+					}
 					err = ErrBadRequest(err)
 				}
 				ctx = WithError(ctx, err)
@@ -257,9 +257,12 @@ var _ = Describe("Service", func() {
 			})
 
 			Context("with an invalid payload", func() {
+				var failures int64
+
 				BeforeEach(func() {
 					r.Body = ioutil.NopCloser(bytes.NewBuffer([]byte("not json")))
 					r.ContentLength = 8
+					failures = goa.DecodeFailures.Value()
 				})
 
 				It("triggers the error handler", func() {
@@ -267,6 +270,10 @@ var _ = Describe("Service", func() {
 					Ω(string(rw.(*TestResponseWriter).Body)).Should(ContainSubstring("failed to decode"))
 				})
 
+				It("counts the decode failure", func() {
+					Ω(goa.DecodeFailures.Value()).Should(Equal(failures + 1))
+				})
+
 				Context("then a valid payload", func() {
 					It("then succeeds", func() {
 						var err error
@@ -279,6 +286,24 @@ var _ = Describe("Service", func() {
 				})
 			})
 
+			Context("with a payload that fails validation", func() {
+				var failures int64
+
+				BeforeEach(func() {
+					r.Body = ioutil.NopCloser(bytes.NewBuffer([]byte("{}")))
+					r.ContentLength = 2
+					unmarshaler = func(c context.Context, service *goa.Service, req *http.Request) error {
+						return goa.MissingAttributeError("payload", "name")
+					}
+					failures = goa.ValidationFailures.Value()
+				})
+
+				It("counts the validation failure", func() {
+					Ω(rw.(*TestResponseWriter).Status).Should(Equal(400))
+					Ω(goa.ValidationFailures.Value()).Should(Equal(failures + 1))
+				})
+			})
+
 			Context("and middleware", func() {
 				middlewareCalled := false
 
@@ -377,6 +402,104 @@ var _ = Describe("Service", func() {
 		})
 	})
 
+	Describe("DecodeRequestArray", func() {
+		var body string
+		var contentType string
+		var max int
+		var elems []int
+		var payload []int
+		var decodeErr error
+
+		BeforeEach(func() {
+			body = "[1, 2, 3]"
+			contentType = "application/json"
+			max = 0
+			elems = nil
+			payload = nil
+		})
+
+		JustBeforeEach(func() {
+			req, err := http.NewRequest("POST", "/foo", bytes.NewBufferString(body))
+			Ω(err).ShouldNot(HaveOccurred())
+			req.Header.Set("Content-Type", contentType)
+			decodeErr = s.DecodeRequestArray(req, &payload, func(decode func(interface{}) error) error {
+				var elem int
+				if err := decode(&elem); err != nil {
+					return err
+				}
+				if max > 0 && len(elems) == max {
+					return fmt.Errorf("too many elements")
+				}
+				elems = append(elems, elem)
+				return nil
+			})
+		})
+
+		It("decodes the elements one at a time", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(elems).Should(Equal([]int{1, 2, 3}))
+			Ω(payload).Should(BeNil())
+		})
+
+		Context("with an element callback that fails", func() {
+			BeforeEach(func() {
+				body = "[1, 2, 3, invalid"
+				max = 2
+			})
+
+			It("stops decoding", func() {
+				Ω(decodeErr).Should(MatchError("too many elements"))
+				Ω(elems).Should(Equal([]int{1, 2}))
+			})
+		})
+
+		Context("with a body that is not an array", func() {
+			BeforeEach(func() {
+				body = `{"foo": 1}`
+			})
+
+			It("fails", func() {
+				Ω(decodeErr).Should(HaveOccurred())
+				Ω(elems).Should(BeEmpty())
+			})
+		})
+
+		Context("with a null body", func() {
+			BeforeEach(func() {
+				body = "null"
+			})
+
+			It("decodes no element", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(elems).Should(BeEmpty())
+			})
+		})
+
+		Context("with no Content-Type", func() {
+			BeforeEach(func() {
+				contentType = ""
+			})
+
+			It("decodes the body in full with the default decoder", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(elems).Should(BeEmpty())
+				Ω(payload).Should(Equal([]int{1, 2, 3}))
+			})
+		})
+
+		Context("with a body that is not JSON", func() {
+			BeforeEach(func() {
+				contentType = "application/x-custom"
+			})
+
+			It("decodes the body in full", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(elems).Should(BeEmpty())
+				Ω(payload).Should(Equal([]int{1, 2, 3}))
+			})
+		})
+	})
+
 	Describe("FileHandler", func() {
 		const publicPath = "github.com/goadesign/goa/public"
 
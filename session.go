@@ -0,0 +1,190 @@
+package goa
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+type (
+	// SessionSecurity represents the session cookie security scheme where clients authenticate
+	// using a cookie holding the session created when they logged in.
+	SessionSecurity struct {
+		// Description of the security scheme
+		Description string
+		// Name is the name of the session cookie.
+		Name string
+		// TTL is the session lifetime, sessions are renewed when used after half their lifetime.
+		TTL time.Duration
+	}
+
+	// Session holds the state of an authenticated client session.
+	Session struct {
+		// Principal identifies the authenticated client, e.g. the user ID.
+		Principal string `json:"principal"`
+		// Values contains arbitrary session data.
+		Values map[string]interface{} `json:"values,omitempty"`
+		// Expires is the session expiry time, sessions with a zero expiry time do not expire.
+		Expires time.Time `json:"expires"`
+	}
+
+	// SessionStore encodes sessions into cookie values and decodes them back. Implementations
+	// must sign or encrypt the sessions so that clients cannot forge them, they may also store
+	// the sessions server side and only encode an identifier in the cookie.
+	SessionStore interface {
+		// Encode returns the cookie value encoding the session.
+		Encode(s *Session) (string, error)
+		// Decode returns the session encoded in the cookie value.
+		Decode(value string) (*Session, error)
+	}
+
+	// SessionAuthFunc is the function implemented by the user to authorize requests using a
+	// session security scheme. It is called with the session decoded from the request cookie
+	// and returns the context used to run the action.
+	SessionAuthFunc func(ctx context.Context, session *Session, scheme *SessionSecurity) (context.Context, error)
+
+	// cookieStore is a SessionStore that encrypts the sessions in the cookie values.
+	cookieStore struct {
+		aead cipher.AEAD
+	}
+)
+
+// ContextSession extracts the session from the given context, nil if there is none.
+func ContextSession(ctx context.Context) *Session {
+	if s := ctx.Value(sessionKey); s != nil {
+		return s.(*Session)
+	}
+	return nil
+}
+
+// WithSession builds a context containing the given session.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey, s)
+}
+
+// NewSessionMiddleware creates a middleware that decodes the session from the request cookie
+// described by scheme using store and makes it available to the action through ContextSession, the
+// session principal is recorded with WithPrincipal. auth is called with the session if not nil.
+// Sessions used after half their lifetime, or that have no expiry time while the scheme has a TTL,
+// are renewed and the cookie updated. Requests with no session cookie or with an invalid or expired
+// session are rejected with ErrUnauthorized.
+func NewSessionMiddleware(scheme *SessionSecurity, store SessionStore, auth SessionAuthFunc) Middleware {
+	return func(h Handler) Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			cookie, err := req.Cookie(scheme.Name)
+			if err != nil || cookie.Value == "" {
+				return ErrUnauthorized("missing session")
+			}
+			s, err := store.Decode(cookie.Value)
+			if err != nil {
+				return ErrUnauthorized("invalid session")
+			}
+			now := time.Now()
+			if !s.Expires.IsZero() && !s.Expires.After(now) {
+				return ErrUnauthorized("session expired")
+			}
+			if scheme.TTL > 0 && (s.Expires.IsZero() || s.Expires.Sub(now) < scheme.TTL/2) {
+				s.Expires = now.Add(scheme.TTL)
+				if err := SetSession(rw, scheme, store, s); err != nil {
+					return err
+				}
+			}
+			ctx = WithPrincipal(WithSession(ctx, s), s.Principal)
+			if auth != nil {
+				if ctx, err = auth(ctx, s, scheme); err != nil {
+					return authError(err)
+				}
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// SetSession encodes the session with store and writes the session cookie described by scheme.
+// The session expiry is set using the scheme TTL if zero, the session does not expire and the
+// cookie lasts for the browser session if the scheme has no TTL. Call it from the login action.
+func SetSession(rw http.ResponseWriter, scheme *SessionSecurity, store SessionStore, s *Session) error {
+	if s.Expires.IsZero() && scheme.TTL > 0 {
+		s.Expires = time.Now().Add(scheme.TTL)
+	}
+	val, err := store.Encode(s)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(rw, &http.Cookie{
+		Name:     scheme.Name,
+		Value:    val,
+		Path:     "/",
+		Expires:  s.Expires,
+		Secure:   true,
+		HttpOnly: true,
+	})
+	return nil
+}
+
+// ClearSession deletes the session cookie described by scheme. Call it from the logout action.
+func ClearSession(rw http.ResponseWriter, scheme *SessionSecurity) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     scheme.Name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+	})
+}
+
+// NewCookieStore returns a SessionStore that encrypts and authenticates the sessions stored in the
+// cookie values using AES-GCM. key must be 16, 24 or 32 bytes long.
+func NewCookieStore(key []byte) (SessionStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &cookieStore{aead: aead}, nil
+}
+
+// Encode encrypts the JSON representation of the session.
+func (c *cookieStore) Encode(s *Session) (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, b, nil)), nil
+}
+
+// Decode decrypts the session encoded in the cookie value.
+func (c *cookieStore) Decode(value string) (*Session, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	ns := c.aead.NonceSize()
+	if len(b) < ns {
+		return nil, errors.New("invalid session cookie")
+	}
+	plain, err := c.aead.Open(nil, b[:ns], b[ns:], nil)
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(plain, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
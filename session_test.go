@@ -0,0 +1,116 @@
+package goa_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewSessionMiddleware", func() {
+	var scheme *goa.SessionSecurity
+	var store goa.SessionStore
+	var req *http.Request
+	var rw *httptest.ResponseRecorder
+	var session *goa.Session
+	var err error
+
+	BeforeEach(func() {
+		var serr error
+		store, serr = goa.NewCookieStore([]byte("0123456789abcdef"))
+		Ω(serr).ShouldNot(HaveOccurred())
+		scheme = &goa.SessionSecurity{Name: "session", TTL: time.Hour}
+		session = nil
+		req, _ = http.NewRequest("GET", "/", nil)
+		rw = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			session = goa.ContextSession(ctx)
+			return nil
+		}
+		err = goa.NewSessionMiddleware(scheme, store, nil)(h)(context.Background(), rw, req)
+	})
+
+	setCookie := func(s *goa.Session) {
+		w := httptest.NewRecorder()
+		Ω(goa.SetSession(w, scheme, store, s)).ShouldNot(HaveOccurred())
+		resp := http.Response{Header: w.Header()}
+		req.AddCookie(resp.Cookies()[0])
+	}
+
+	Context("with a valid session", func() {
+		BeforeEach(func() {
+			setCookie(&goa.Session{Principal: "user", Expires: time.Now().Add(time.Hour)})
+		})
+
+		It("exposes the session", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(session).ShouldNot(BeNil())
+			Ω(session.Principal).Should(Equal("user"))
+			Ω(rw.Header().Get("Set-Cookie")).Should(BeEmpty())
+		})
+	})
+
+	Context("with a session past half its lifetime", func() {
+		BeforeEach(func() {
+			setCookie(&goa.Session{Principal: "user", Expires: time.Now().Add(time.Minute)})
+		})
+
+		It("renews the session", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(session.Expires).Should(BeTemporally(">", time.Now().Add(50*time.Minute)))
+			Ω(rw.Header().Get("Set-Cookie")).ShouldNot(BeEmpty())
+		})
+	})
+
+	Context("with a scheme with no TTL", func() {
+		BeforeEach(func() {
+			scheme.TTL = 0
+			setCookie(&goa.Session{Principal: "user"})
+		})
+
+		It("exposes the session", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(session).ShouldNot(BeNil())
+			Ω(session.Principal).Should(Equal("user"))
+			Ω(session.Expires.IsZero()).Should(BeTrue())
+			Ω(rw.Header().Get("Set-Cookie")).Should(BeEmpty())
+		})
+	})
+
+	Context("with an expired session", func() {
+		BeforeEach(func() {
+			setCookie(&goa.Session{Principal: "user", Expires: time.Now().Add(-time.Minute)})
+		})
+
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+			Ω(session).Should(BeNil())
+		})
+	})
+
+	Context("with a forged session", func() {
+		BeforeEach(func() {
+			req.AddCookie(&http.Cookie{Name: "session", Value: "forged"})
+		})
+
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+
+	Context("with no session", func() {
+		It("returns a 401", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(401))
+		})
+	})
+})
@@ -132,8 +132,6 @@ func ValidateFormat(f Format, val string) error {
 }
 
 // knownPatterns records the compiled patterns.
-// TBD: refactor all this so that the generated code initializes the map on start to get rid of the
-// need for a RW mutex.
 var knownPatterns = make(map[string]*regexp.Regexp)
 
 // knownPatternsLock is the mutex used to access knownPatterns
@@ -141,6 +139,8 @@ var knownPatternsLock = &sync.RWMutex{}
 
 // ValidatePattern returns an error if val does not match the regular expression p.
 // It makes an effort to minimize the number of times the regular expression needs to be compiled.
+// The code generated by goagen does not use it anymore: it matches the values against package-level
+// variables initialized with the compiled regular expressions instead.
 func ValidatePattern(p string, val string) bool {
 	knownPatternsLock.RLock()
 	r, ok := knownPatterns[p]
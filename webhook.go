@@ -0,0 +1,172 @@
+package goa
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader is the name of the header that holds the signature of the webhook
+	// requests, e.g. "t=1526940000,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd".
+	WebhookSignatureHeader = "X-Goa-Signature"
+	// WebhookNameHeader is the name of the header that holds the name of the webhook.
+	WebhookNameHeader = "X-Goa-Webhook"
+)
+
+// ErrInvalidWebhookSignature is the error returned by VerifyWebhookSignature when the signature
+// of a webhook request is missing, malformed, expired or does not match the request body.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+type (
+	// WebhookSender sends the webhook requests described in the design with the Webhook DSL.
+	// The request bodies are JSON encoded and signed with the secret shared with the consumer,
+	// see VerifyWebhookSignature. The deliveries that fail because of a network error, a 5xx
+	// or a 429 response are retried with an exponential backoff.
+	WebhookSender struct {
+		// Client is the HTTP client used to send the requests, http.DefaultClient if nil.
+		Client *http.Client
+		// Secret is the key used to compute the HMAC-SHA256 signature of the requests. The
+		// requests are not signed if empty.
+		Secret []byte
+		// MaxRetries is the maximum number of times a failed delivery is retried.
+		MaxRetries int
+		// Backoff is the delay before the first retry, it doubles after each retry.
+		Backoff time.Duration
+	}
+
+	// WebhookError is the error returned by WebhookSender.Send when the consumer responds with
+	// a status code other than 2xx.
+	WebhookError struct {
+		// Name of the webhook
+		Name string
+		// Status is the HTTP status code of the last response.
+		Status int
+		// Body is the body of the last response.
+		Body []byte
+	}
+)
+
+// NewWebhookSender returns a webhook sender that signs the requests with the given secret and
+// retries the failed deliveries 3 times, waiting 1s, 2s and 4s.
+func NewWebhookSender(secret []byte) *WebhookSender {
+	return &WebhookSender{Secret: secret, MaxRetries: 3, Backoff: time.Second}
+}
+
+// Send posts the JSON representation of body to url. It returns once the consumer acknowledges
+// the request with a 2xx response, the retries are exhausted or ctx is done.
+func (s *WebhookSender) Send(ctx context.Context, url, name string, body interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	delay := s.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.deliver(ctx, client, url, name, payload)
+		if err == nil || !retry || attempt >= s.MaxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// deliver makes one delivery attempt. It returns true if the delivery failed and may be retried.
+func (s *WebhookSender) deliver(ctx context.Context, client *http.Client, url, name string, payload []byte) (bool, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookNameHeader, name)
+	if len(s.Secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(s.Secret, time.Now(), payload))
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, &WebhookError{Name: name, Status: resp.StatusCode, Body: respBody}
+}
+
+// Error returns the error message.
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("webhook %s: consumer responded with status %d", e.Name, e.Status)
+}
+
+// SignWebhook returns the value of the WebhookSignatureHeader header for the given body sent at
+// the given time: the Unix timestamp and the hex encoded HMAC-SHA256 of the timestamp followed by a
+// dot and the body.
+func SignWebhook(secret []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, webhookMAC(secret, ts, body))
+}
+
+// VerifyWebhookSignature checks that the value of the WebhookSignatureHeader header of a webhook
+// request matches the request body. Signatures older than tolerance are rejected to prevent replay
+// attacks, a zero tolerance disables the check.
+func VerifyWebhookSignature(secret []byte, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			ts = kv[1]
+		case "v1":
+			sig = kv[1]
+		}
+	}
+	if ts == "" || sig == "" {
+		return ErrInvalidWebhookSignature
+	}
+	if tolerance > 0 {
+		secs, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return ErrInvalidWebhookSignature
+		}
+		if age := time.Since(time.Unix(secs, 0)); age > tolerance || age < -tolerance {
+			return ErrInvalidWebhookSignature
+		}
+	}
+	if !hmac.Equal([]byte(sig), []byte(webhookMAC(secret, ts, body))) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// webhookMAC computes the hex encoded signature of the body sent at the given timestamp.
+func webhookMAC(secret []byte, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,118 @@
+package goa_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebhookSender", func() {
+	var statuses []int
+	var requests []*http.Request
+	var bodies [][]byte
+	var server *httptest.Server
+	var sender *goa.WebhookSender
+	var err error
+
+	BeforeEach(func() {
+		statuses = nil
+		requests = nil
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			b, _ := ioutil.ReadAll(req.Body)
+			requests = append(requests, req)
+			bodies = append(bodies, b)
+			status := 200
+			if len(statuses) >= len(requests) {
+				status = statuses[len(requests)-1]
+			}
+			rw.WriteHeader(status)
+		}))
+		sender = goa.NewWebhookSender([]byte("secret"))
+		sender.Backoff = time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	JustBeforeEach(func() {
+		err = sender.Send(context.Background(), server.URL, "created", map[string]string{"id": "42"})
+	})
+
+	It("posts the signed JSON body", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(requests).Should(HaveLen(1))
+		Ω(requests[0].Method).Should(Equal("POST"))
+		Ω(requests[0].Header.Get("Content-Type")).Should(Equal("application/json"))
+		Ω(requests[0].Header.Get(goa.WebhookNameHeader)).Should(Equal("created"))
+		var body map[string]string
+		Ω(json.Unmarshal(bodies[0], &body)).Should(Succeed())
+		Ω(body).Should(Equal(map[string]string{"id": "42"}))
+		sig := requests[0].Header.Get(goa.WebhookSignatureHeader)
+		Ω(goa.VerifyWebhookSignature([]byte("secret"), sig, bodies[0], time.Minute)).Should(Succeed())
+		Ω(goa.VerifyWebhookSignature([]byte("other"), sig, bodies[0], time.Minute)).Should(Equal(goa.ErrInvalidWebhookSignature))
+	})
+
+	Context("with transient failures", func() {
+		BeforeEach(func() {
+			statuses = []int{503, 429}
+		})
+
+		It("retries the delivery", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(requests).Should(HaveLen(3))
+		})
+	})
+
+	Context("with a failure that persists", func() {
+		BeforeEach(func() {
+			statuses = []int{500, 500, 500, 500, 500}
+		})
+
+		It("gives up after the maximum number of retries", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(*goa.WebhookError).Status).Should(Equal(500))
+			Ω(requests).Should(HaveLen(4))
+		})
+	})
+
+	Context("with a client error", func() {
+		BeforeEach(func() {
+			statuses = []int{404}
+		})
+
+		It("does not retry", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(*goa.WebhookError).Status).Should(Equal(404))
+			Ω(requests).Should(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("VerifyWebhookSignature", func() {
+	var secret = []byte("secret")
+	var body = []byte(`{"id":"42"}`)
+
+	It("rejects expired signatures", func() {
+		sig := goa.SignWebhook(secret, time.Now().Add(-time.Hour), body)
+		Ω(goa.VerifyWebhookSignature(secret, sig, body, time.Minute)).Should(Equal(goa.ErrInvalidWebhookSignature))
+		Ω(goa.VerifyWebhookSignature(secret, sig, body, 0)).Should(Succeed())
+	})
+
+	It("rejects tampered bodies", func() {
+		sig := goa.SignWebhook(secret, time.Now(), body)
+		Ω(goa.VerifyWebhookSignature(secret, sig, []byte(`{"id":"43"}`), time.Minute)).Should(Equal(goa.ErrInvalidWebhookSignature))
+	})
+
+	It("rejects malformed headers", func() {
+		Ω(goa.VerifyWebhookSignature(secret, "garbage", body, time.Minute)).Should(Equal(goa.ErrInvalidWebhookSignature))
+	})
+})